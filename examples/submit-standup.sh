@@ -33,11 +33,11 @@ echo ""
 echo "Method 4: With error handling"
 RESULT=$(standup-bot --json '{"yesterday": ["Task A"], "today": ["Task B"]}' --output json 2>&1)
 
-if echo "$RESULT" | grep -q '"success": true'; then
-    PR_URL=$(echo "$RESULT" | grep -o '"pr_url": "[^"]*"' | cut -d'"' -f4)
+if ! echo "$RESULT" | grep -q '"kind": "Error"'; then
+    PR_URL=$(echo "$RESULT" | grep -o '"url": "[^"]*"' | cut -d'"' -f4)
     echo "✅ Standup submitted successfully!"
     echo "📎 Pull Request: $PR_URL"
 else
-    ERROR=$(echo "$RESULT" | grep -o '"error": "[^"]*"' | cut -d'"' -f4)
+    ERROR=$(echo "$RESULT" | grep -o '"message": "[^"]*"' | cut -d'"' -f4)
     echo "❌ Failed to submit standup: $ERROR"
 fi
\ No newline at end of file
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/standup-bot/standup-bot/pkg/ai"
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+// checkAIAllowed returns an error when the team has turned off AI features in
+// the standup repository's shared config
+func checkAIAllowed(cfg *config.Config) error {
+	team, err := config.LoadTeamConfig(cfg.LocalRepoPath)
+	if err != nil {
+		return err
+	}
+	if team.DisableAI {
+		return fmt.Errorf("AI features are disabled for this team (disableAI in %s)", config.TeamConfigFile)
+	}
+	return nil
+}
+
+// newAIProvider creates the configured AI provider. It returns ai.ErrDisabled
+// unless the config opts in, and fails if the team has turned AI off.
+func newAIProvider(cfg *config.Config) (ai.Provider, error) {
+	if err := checkAIAllowed(cfg); err != nil {
+		return nil, err
+	}
+
+	kind, err := cfg.GetAIProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := ai.Options{Kind: kind}
+	if cfg.AI != nil {
+		opts.Model = cfg.AI.Model
+		opts.Endpoint = cfg.AI.Endpoint
+		opts.Temperature = cfg.AI.Temperature
+	}
+	return ai.New(opts)
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/ai"
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+func TestNewAIProvider(t *testing.T) {
+	repoPath := t.TempDir()
+
+	// Off unless the config opts in
+	cfg := &config.Config{LocalRepoPath: repoPath}
+	if _, err := newAIProvider(cfg); !errors.Is(err, ai.ErrDisabled) {
+		t.Errorf("newAIProvider() error = %v, want ai.ErrDisabled", err)
+	}
+
+	cfg.AI = &config.AIConfig{Provider: "ollama", Model: "llama3.1"}
+	if _, err := newAIProvider(cfg); err != nil {
+		t.Errorf("newAIProvider() error = %v", err)
+	}
+
+	// The team's shared config overrides personal settings
+	if err := os.WriteFile(filepath.Join(repoPath, config.TeamConfigFile), []byte("disableAI: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newAIProvider(cfg); err == nil {
+		t.Error("newAIProvider() should fail when the team disables AI")
+	}
+	if err := checkAIAllowed(cfg); err == nil {
+		t.Error("checkAIAllowed() should fail when the team disables AI")
+	}
+}
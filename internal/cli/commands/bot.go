@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// BotOptions configures a shared bot instance
+type BotOptions struct {
+	// MCPAddr is the listen address of the MCP server
+	MCPAddr string
+	// WebhookAddr is the listen address of the issue webhook
+	WebhookAddr string
+	// Force records issues even if the standup repository has uncommitted
+	// non-standup changes
+	Force bool
+}
+
+// RunBot runs one shared instance for a team, e.g. in a container: the MCP
+// server over HTTP when STANDUP_BOT_MCP_TOKEN is set and the issue webhook
+// when STANDUP_BOT_WEBHOOK_SECRET is set. It never prompts, authenticates
+// with a token in GH_TOKEN instead of 'gh auth login', and clones the
+// standup repositories on first start. Teams with a meeting calendar get
+// their reminders posted to STANDUP_BOT_SLACK_WEBHOOK. cfgs holds the main repository's
+// configuration followed by each team's.
+func RunBot(cfgs []*config.Config, opts BotOptions) error {
+	serveMCP := os.Getenv(MCPTokenEnv) != ""
+	serveWebhook := os.Getenv(WebhookSecretEnv) != ""
+	if !serveMCP && !serveWebhook {
+		return fmt.Errorf("nothing to serve: set %s for the MCP server, %s for the issue webhook, or both", MCPTokenEnv, WebhookSecretEnv)
+	}
+	SetNonInteractive(true)
+
+	gitClient := git.NewClient()
+	for _, cfg := range cfgs {
+		if err := prepareBot(gitClient, cfg); err != nil {
+			return err
+		}
+	}
+
+	for _, cfg := range cfgs {
+		if remindsFromBot(cfg) {
+			go runReminders(cfg, botReminderInterval)
+		}
+	}
+
+	errChan := make(chan error, 2)
+	servers := 0
+	if serveMCP {
+		servers++
+		go func() {
+			errChan <- RunMCPServer(MCPServerOptions{Transport: "http", Addr: opts.MCPAddr})
+		}()
+	}
+	if serveWebhook {
+		servers++
+		go func() {
+			errChan <- RunIssueWebhook(cfgs, opts.WebhookAddr, opts.Force)
+		}()
+	}
+
+	// Both servers stop on SIGTERM; if one fails the bot exits with it
+	for ; servers > 0; servers-- {
+		if err := <-errChan; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareBot lets git push with the token gh is given and clones the
+// standup repository if the volume doesn't have it yet
+func prepareBot(gitClient *git.Client, cfg *config.Config) error {
+	forge, _ := cfg.GetForge()
+	if !cfg.HasRemoteURL() && (forge == types.ForgeAuto || forge == types.ForgeGitHub) && ghTokenSet() {
+		if err := gitClient.SetupGitCredentials(); err != nil {
+			return err
+		}
+	}
+	return setupRepository(cfg)
+}
+
+// ghTokenSet reports whether gh is given a token instead of a login
+func ghTokenSet() bool {
+	for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN"} {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+func TestRunBotRequiresSomethingToServe(t *testing.T) {
+	t.Setenv(MCPTokenEnv, "")
+	t.Setenv(WebhookSecretEnv, "")
+	err := RunBot(nil, BotOptions{})
+	if err == nil || !strings.Contains(err.Error(), MCPTokenEnv) || !strings.Contains(err.Error(), WebhookSecretEnv) {
+		t.Errorf("RunBot() error = %v, want both variables named", err)
+	}
+}
+
+func TestPrepareBot(t *testing.T) {
+	repoPath := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repoPath, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Repository: "myorg/standups", Name: "standup-bot", LocalRepoPath: repoPath}
+	failing := git.NewClientWithRunner(&fakeRunner{err: errors.New("exit status 1")})
+
+	// Without a token, gh's own login is used and the clone is reused
+	for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN"} {
+		t.Setenv(name, "")
+	}
+	if err := prepareBot(failing, cfg); err != nil {
+		t.Errorf("prepareBot() error = %v", err)
+	}
+
+	t.Setenv("GH_TOKEN", "ghp_token")
+	if err := prepareBot(failing, cfg); err == nil || !strings.Contains(err.Error(), "git credentials") {
+		t.Errorf("prepareBot() error = %v, want the gh auth setup-git failure", err)
+	}
+
+	// Raw git URLs use git's own credentials
+	cfg.Repository = "git@example.com:myorg/standups.git"
+	if err := prepareBot(failing, cfg); err != nil {
+		t.Errorf("prepareBot() error = %v for a git URL", err)
+	}
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// RunBrag prints a markdown brag document of a user's accomplishments. The
+// user "me" (or an empty user) is the configured name; since and until are
+// optional YYYY-MM-DD dates. With summarize, the configured AI provider adds
+// a summary at the top.
+func RunBrag(cfg *config.Config, user, since, until string, summarize bool) error {
+	if user == "" || strings.EqualFold(user, "me") {
+		user = cfg.Name
+	}
+
+	sinceDate, err := parseOptionalDate(since)
+	if err != nil {
+		return fmt.Errorf("invalid --since date: %w", err)
+	}
+	untilDate, err := parseOptionalDate(until)
+	if err != nil {
+		return fmt.Errorf("invalid --until date: %w", err)
+	}
+
+	entries, err := standupbot.NewManager(cfg).LoadEntries(user)
+	if err != nil {
+		return fmt.Errorf("failed to load standups for %s: %w", user, err)
+	}
+
+	accomplishments := standup.Accomplishments(entries, sinceDate, untilDate)
+	document := standup.FormatBrag(user, accomplishments, sinceDate)
+
+	if summarize && len(accomplishments) > 0 {
+		summary, err := summarizeBrag(cfg, document)
+		if err != nil {
+			return err
+		}
+		title, body, _ := strings.Cut(document, "\n\n")
+		document = fmt.Sprintf("%s\n\n## Summary\n\n%s\n\n%s", title, summary, body)
+	}
+
+	fmt.Print(document)
+	return nil
+}
+
+// summarizeBrag asks the configured AI provider to summarize a brag document
+func summarizeBrag(cfg *config.Config, document string) (string, error) {
+	provider, err := newAIProvider(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	summary, err := provider.Summarize(context.Background(), document)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize accomplishments: %w", err)
+	}
+	return summary, nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date, returning the zero time for ""
+func parseOptionalDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
@@ -1,16 +1,27 @@
 package commands
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/standup-bot/standup-bot/pkg/config"
 	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/render"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
 )
 
 // RunConfiguration handles the configuration setup workflow
 func RunConfiguration(cfgManager *config.Manager) error {
+	if !canPrompt() {
+		return inputRequired("configuration", fmt.Sprintf("write %s, set %s and %s, or run 'standup-bot --config' in a terminal", cfgManager.ConfigFile(), config.EnvRepository, config.EnvName))
+	}
+
 	fmt.Println("Welcome to Standup Bot!")
 	
 	cfg, err := collectConfigurationInput()
@@ -32,19 +43,20 @@ func RunConfiguration(cfgManager *config.Manager) error {
 	return nil
 }
 
-// collectConfigurationInput prompts the user for configuration values
+// collectConfigurationInput prompts the user for configuration values. Whole
+// lines are read, so names can contain spaces.
 func collectConfigurationInput() (*config.Config, error) {
+	reader := bufio.NewReader(os.Stdin)
+
 	// Get repository
-	fmt.Print("GitHub Repository (e.g., org/standup-repo): ")
-	var repo string
-	if _, err := fmt.Scanln(&repo); err != nil {
+	repo, err := promptLine(reader, "Repository (e.g., org/standup-repo or git@github.com:org/standup-repo.git): ")
+	if err != nil {
 		return nil, fmt.Errorf("failed to read repository: %w", err)
 	}
 
 	// Get user name
-	fmt.Print("Your Name: ")
-	var name string
-	if _, err := fmt.Scanln(&name); err != nil {
+	name, err := promptLine(reader, "Your Name: ")
+	if err != nil {
 		return nil, fmt.Errorf("failed to read name: %w", err)
 	}
 
@@ -55,31 +67,175 @@ func collectConfigurationInput() (*config.Config, error) {
 	return cfg, nil
 }
 
-// setupRepository clones the repository if it doesn't exist
-func setupRepository(cfg *config.Config) error {
+// promptLine prints a prompt and reads one line of input
+func promptLine(reader *bufio.Reader, prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ConfigSettings are the values 'config set' changes. Empty fields keep
+// their current value.
+type ConfigSettings struct {
+	Repository string
+	Name       string
+	Path       string
+	Forge      string
+	BaseBranch string
+}
+
+// RunConfigSet updates the configuration from flags, so setup can be
+// scripted. The result is fully validated and the repository cloned, or
+// checked to be reachable, before anything is saved.
+func RunConfigSet(cfgManager *config.Manager, settings ConfigSettings) error {
+	cfg, err := cfgManager.Read()
+	if errors.Is(err, config.ErrConfigNotFound) {
+		cfg = config.DefaultConfig()
+	} else if err != nil {
+		return err
+	}
+
+	if settings.Repository != "" {
+		cfg.Repository = settings.Repository
+	}
+	if settings.Name != "" {
+		cfg.Name = settings.Name
+	}
+	if settings.Path != "" {
+		cfg.LocalRepoPath = settings.Path
+	}
+	if settings.Forge != "" {
+		cfg.Forge = settings.Forge
+	}
+	if settings.BaseBranch != "" {
+		cfg.BaseBranch = settings.BaseBranch
+	}
+	cfg.LocalRepoPath = expandPath(cfg.LocalRepoPath)
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
 	gitClient := git.NewClient()
-	
-	// Check GitHub CLI is installed
-	if err := gitClient.CheckGHInstalled(); err != nil {
+	if gitClient.RepositoryExists(cfg.LocalRepoPath) {
+		if err := gitClient.RemoteReachable(cfg.LocalRepoPath); err != nil {
+			return fmt.Errorf("repository at %s can't reach its remote: %w", cfg.LocalRepoPath, err)
+		}
+	} else if err := setupRepository(cfg); err != nil {
 		return err
 	}
 
-	// Check authentication
-	if err := gitClient.CheckAuthenticated(); err != nil {
+	if err := cfgManager.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	fmt.Printf("Configuration saved to %s\n", cfgManager.ConfigFile())
+	return nil
+}
+
+// RunConfigShow prints the configuration file's settings. They are shown
+// even when invalid, along with the problem.
+func RunConfigShow(cfgManager *config.Manager, outputFormat string) error {
+	cfg, err := cfgManager.Read()
+	if errors.Is(err, config.ErrConfigNotFound) {
+		return fmt.Errorf("no configuration at %s: run 'standup-bot config set' or 'standup-bot --config'", cfgManager.ConfigFile())
+	}
+	if err != nil {
 		return err
 	}
 
+	if IsMachineOutput(outputFormat) {
+		return render.Print(os.Stdout, renderFormat(outputFormat), cfg)
+	}
+
+	fmt.Printf("# %s\n", cfgManager.ConfigFile())
+	if err := render.Print(os.Stdout, render.YAML, cfg); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Printf("\n⚠️  This configuration is invalid: %v\n", err)
+	}
+	return nil
+}
+
+// RunConfigEdit opens the configuration file in $VISUAL or $EDITOR, then
+// validates the result
+func RunConfigEdit(cfgManager *config.Manager) error {
+	if !canPrompt() {
+		return inputRequired("an editor", "use 'standup-bot config set' instead")
+	}
+	if !cfgManager.Exists() {
+		return fmt.Errorf("no configuration at %s: run 'standup-bot config set' or 'standup-bot --config'", cfgManager.ConfigFile())
+	}
+
+	cmd := exec.Command("sh", "-c", editorCommand()+` "$1"`, "sh", cfgManager.ConfigFile())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, console.out, console.err
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+
+	if _, err := cfgManager.Load(); err != nil {
+		return fmt.Errorf("%w; run 'standup-bot config edit' again to fix it", err)
+	}
+	fmt.Println("Configuration is valid.")
+	return nil
+}
+
+// editorCommand returns the user's editor, falling back to vi. It may include
+// arguments, such as "code --wait".
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(name); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// setupRepository clones the repository if it doesn't exist
+func setupRepository(cfg *config.Config) error {
+	gitClient := git.NewClient()
+
 	// Expand tilde in local repo path for actual operations
 	expandedPath := expandPath(cfg.LocalRepoPath)
+	if gitClient.RepositoryExists(expandedPath) {
+		return nil
+	}
 
-	// Clone repository if needed
-	if !gitClient.RepositoryExists(expandedPath) {
-		fmt.Println("Cloning repository...")
-		if err := gitClient.CloneRepository(cfg.Repository, expandedPath); err != nil {
-			return fmt.Errorf("failed to clone repository: %w", err)
+	// Raw git URLs are cloned with git's own SSH or HTTPS credentials
+	opts := standupbot.CloneOptions(cfg)
+	clone := func() error { return gitClient.CloneURL(cfg.Repository, expandedPath, opts) }
+	if cfg.HasRemoteURL() {
+		if err := gitClient.CheckGitInstalled(); err != nil {
+			return err
 		}
-		fmt.Println("Repository cloned successfully!")
+	} else {
+		// Check the forge's tools are installed and authenticated
+		provider, err := standupbot.NewForge(gitClient, cfg)
+		if err != nil {
+			return err
+		}
+		if err := provider.CheckAvailable(); err != nil {
+			return err
+		}
+		clone = func() error { return provider.Clone(expandedPath, opts) }
+	}
+
+	fmt.Println("Cloning repository...")
+	spinner := progressSpinner("")
+	if spinner != nil {
+		spinner.Start()
+	}
+	err := clone()
+	if spinner != nil {
+		spinner.Stop()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to clone repository: %w", err)
 	}
+	fmt.Println("Repository cloned successfully!")
 
 	return nil
 }
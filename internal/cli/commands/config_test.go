@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+func TestRunConfigSet(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A clone whose origin is a local bare repository, so the reachability
+	// check needs no network
+	remote := filepath.Join(home, "remote.git")
+	clone := filepath.Join(home, "standups")
+	for _, args := range [][]string{{"init", "--bare", remote}, {"clone", remote, clone}} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+
+	if err := RunConfigSet(cfgManager, ConfigSettings{Repository: "org/standups", Name: "Jane/Doe", Path: clone}); err == nil {
+		t.Fatal("RunConfigSet() should reject an invalid name")
+	}
+	if cfgManager.Exists() {
+		t.Fatal("an invalid configuration shouldn't be saved")
+	}
+
+	if err := RunConfigSet(cfgManager, ConfigSettings{Repository: "org/standups", Name: "Jane Doe", Path: clone}); err != nil {
+		t.Fatalf("RunConfigSet() error = %v", err)
+	}
+	// Later calls only change what they're given
+	if err := RunConfigSet(cfgManager, ConfigSettings{BaseBranch: "develop"}); err != nil {
+		t.Fatalf("RunConfigSet() error = %v", err)
+	}
+
+	cfg, err := cfgManager.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "Jane Doe" || cfg.Repository != "org/standups" || cfg.LocalRepoPath != clone || cfg.BaseBranch != "develop" {
+		t.Errorf("saved config = %+v", cfg)
+	}
+}
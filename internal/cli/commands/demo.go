@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// demoUsers are the teammates in the demo repository
+var demoUsers = []string{"alice", "bob", "carol", "dave"}
+
+// demoUser is the name the demo submits under
+const demoUser = "you"
+
+// demoDays is how far back the generated history goes
+const demoDays = 14
+
+// demoWork is the pool generated standups draw from
+var demoWork = []string{
+	"[Billing] Fixed rounding in invoice totals",
+	"[Auth] Rotated the token signing keys",
+	"Reviewed PRs",
+	"[Mobile] Shipped offline mode to beta testers",
+	"[Search] Tuned relevance for short queries",
+	"Paired on the flaky integration tests",
+	"[Billing] Added retries to the payment webhook",
+	"[Auth] Reviewed the SSO login flow",
+	"Updated the on-call runbook",
+	"[Mobile] Fixed a startup crash on Android",
+	"[Search] Added synonyms to the index",
+}
+
+// demoBlockers are the generated blockers, mostly none
+var demoBlockers = []string{"None", "None", "Waiting on design review", "None", "None", "Staging is down", "None"}
+
+// DemoOptions configures the demo
+type DemoOptions struct {
+	// JSON is the standup to submit, as for --json; the demo asks for one
+	// interactively when it is empty
+	JSON string
+	// Keep leaves the sandbox on disk when the demo ends
+	Keep bool
+}
+
+// RunDemo walks through the standup workflow against a throwaway local
+// repository with generated teammates and history. Nothing outside the
+// sandbox is touched, including the user's config.
+func RunDemo(opts DemoOptions) error {
+	dir, err := os.MkdirTemp("", "standup-bot-demo-")
+	if err != nil {
+		return fmt.Errorf("failed to create demo directory: %w", err)
+	}
+	if opts.Keep {
+		defer fmt.Printf("\nThe demo repository is kept at %s\n", filepath.Join(dir, "standups"))
+	} else {
+		defer os.RemoveAll(dir)
+	}
+
+	fmt.Println("🧪 Setting up a sandbox team with two weeks of standups...")
+	today := time.Now()
+	cfg, err := setupDemoRepository(git.NewClient(), dir, today)
+	if err != nil {
+		return err
+	}
+
+	// Scripted runs (--json) and non-interactive runs don't stop between steps
+	scanner := bufio.NewScanner(os.Stdin)
+	step := func(title string) {
+		if opts.JSON == "" && canPrompt() {
+			fmt.Print("\nPress Enter to continue...")
+			scanner.Scan()
+		}
+		fmt.Printf("\n=== %s ===\n\n", title)
+	}
+
+	step("1. Submit your standup")
+	if err := RunStandup(cfg, StandupOptions{Direct: true, JSONInput: opts.JSON}); err != nil {
+		return err
+	}
+
+	step("2. Check who has submitted")
+	status, err := demoStatus(standupbot.NewManager(cfg), cfg.Today())
+	if err != nil {
+		return err
+	}
+	fmt.Print(status)
+
+	step("3. Read today's standups, as they appear in the daily PR")
+	fmt.Print(standupbot.FormatDailyPRBody(cfg.LocalRepoPath, cfg.Today()))
+
+	step("4. Compile a teammate's brag document")
+	if err := RunBrag(cfg, demoUsers[0], "", "", false); err != nil {
+		return err
+	}
+
+	step("5. Merge")
+	fmt.Println(`The sandbox has no GitHub, so this demo used the direct commit workflow.
+With the default pull request workflow everyone's standups collect in one
+daily PR, and at the end of the day anyone runs:
+
+  standup-bot --merge
+
+Run 'standup-bot --config' to set up your team's repository.`)
+
+	return nil
+}
+
+// setupDemoRepository creates a bare "remote" and a clone of it under dir,
+// seeded with generated standups, and returns a config pointing at them
+func setupDemoRepository(gitClient *git.Client, dir string, today time.Time) (*config.Config, error) {
+	origin := filepath.Join(dir, "origin.git")
+	if err := gitClient.InitBareRepository(origin); err != nil {
+		return nil, err
+	}
+
+	cfg := &config.Config{
+		Repository:    "file://" + filepath.ToSlash(origin),
+		Name:          demoUser,
+		LocalRepoPath: filepath.Join(dir, "standups"),
+	}
+	if err := gitClient.CloneURL(cfg.Repository, cfg.LocalRepoPath, git.CloneOptions{}); err != nil {
+		return nil, err
+	}
+	if err := gitClient.CreateBranch(cfg.LocalRepoPath, config.DefaultBaseBranch); err != nil {
+		return nil, err
+	}
+
+	// Commit as a demo identity, so the demo works before git is set up
+	for key, value := range map[string]string{"user.name": "Standup Bot Demo", "user.email": "demo@example.com", "commit.gpgsign": "false"} {
+		if err := gitClient.SetLocalConfig(cfg.LocalRepoPath, key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := seedDemoHistory(standupbot.NewManager(cfg), today); err != nil {
+		return nil, err
+	}
+	if err := gitClient.CommitAndPush(cfg.LocalRepoPath, "Add demo standups", "stand-ups"); err != nil {
+		return nil, fmt.Errorf("failed to commit demo standups: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// seedDemoHistory saves standups for every teammate on each weekday of the
+// last two weeks, except that the last teammate hasn't submitted today. The
+// content is generated from fixed pools so every run looks the same.
+func seedDemoHistory(manager *standup.Manager, today time.Time) error {
+	for daysAgo := demoDays - 1; daysAgo >= 0; daysAgo-- {
+		day := today.AddDate(0, 0, -daysAgo)
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+
+		for i, user := range demoUsers {
+			if daysAgo == 0 && i == len(demoUsers)-1 {
+				continue
+			}
+			n := daysAgo*len(demoUsers) + i
+			entry := &standup.Entry{
+				Date:      day,
+				Yesterday: []string{demoWork[n%len(demoWork)], demoWork[(n+3)%len(demoWork)]},
+				Today:     []string{demoWork[(n+5)%len(demoWork)]},
+				Blockers:  demoBlockers[n%len(demoBlockers)],
+			}
+			if err := manager.SaveEntry(entry, user); err != nil {
+				return fmt.Errorf("failed to save demo standup: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// demoStatus lists who has submitted a standup for the day
+func demoStatus(manager *standup.Manager, day time.Time) (string, error) {
+	users, err := manager.Users()
+	if err != nil {
+		return "", fmt.Errorf("failed to list users: %w", err)
+	}
+
+	status := fmt.Sprintf("Standups for %s:\n", day.Format("2006-01-02"))
+	for _, user := range users {
+		submitted, err := manager.HasEntry(user, day)
+		if err != nil {
+			return "", fmt.Errorf("failed to check %s's standup: %w", user, err)
+		}
+		mark := "⏳"
+		if submitted {
+			mark = "✅"
+		}
+		status += fmt.Sprintf("  %s %s\n", mark, user)
+	}
+	return status, nil
+}
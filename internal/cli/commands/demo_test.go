@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestSeedDemoHistory(t *testing.T) {
+	manager := standup.NewManager(t.TempDir())
+	// A Wednesday, so the two weeks span two weekends
+	today := time.Date(2024, 1, 17, 0, 0, 0, 0, time.Local)
+
+	if err := seedDemoHistory(manager, today); err != nil {
+		t.Fatalf("seedDemoHistory() error = %v", err)
+	}
+
+	entries, err := manager.LoadEntries("alice")
+	if err != nil {
+		t.Fatalf("LoadEntries() error = %v", err)
+	}
+	if len(entries) != 10 {
+		t.Errorf("alice has %d standups, want one per weekday (10)", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Date.Weekday() == time.Saturday || entry.Date.Weekday() == time.Sunday {
+			t.Errorf("standup generated on a weekend: %s", entry.Date.Format("2006-01-02"))
+		}
+	}
+
+	status, err := demoStatus(manager, today)
+	if err != nil {
+		t.Fatalf("demoStatus() error = %v", err)
+	}
+	if !strings.Contains(status, "✅ alice") || !strings.Contains(status, "⏳ dave") {
+		t.Errorf("demoStatus() = %s, want dave still to submit", status)
+	}
+}
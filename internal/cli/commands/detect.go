@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// DetectLocalRepository returns the root of the checkout containing dir if it
+// is a standup repository: either a clone of the configured repository or any
+// repository with the team settings file, config.TeamConfigFile. The
+// configured LocalRepoPath itself is not reported, since no override is
+// needed for it.
+func DetectLocalRepository(gitClient *git.Client, cfg *config.Config, dir string) (string, bool) {
+	root, err := gitClient.RepositoryRoot(dir)
+	if err != nil || root == "" {
+		return "", false
+	}
+
+	if filepath.Clean(root) == filepath.Clean(cfg.LocalRepoPath) {
+		return "", false
+	}
+
+	if config.HasRepoSettings(root) {
+		return root, true
+	}
+
+	url, err := gitClient.RemoteURL(root)
+	if err != nil {
+		return "", false
+	}
+
+	remote, err := types.ParseRepositoryURL(url)
+	if err != nil {
+		return "", false
+	}
+
+	configured, err := cfg.GetRepository()
+	if err != nil {
+		return "", false
+	}
+
+	if strings.EqualFold(remote.String(), configured.String()) {
+		return root, true
+	}
+
+	return "", false
+}
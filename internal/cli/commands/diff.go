@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+	"github.com/standup-bot/standup-bot/pkg/ui"
+)
+
+// RunDiff prints how a user's standup for a day changed from commit to
+// commit. The user "me" (or an empty user) is the configured name; date is
+// YYYY-MM-DD and defaults to today.
+func RunDiff(cfg *config.Config, user, date string) error {
+	if user == "" || strings.EqualFold(user, "me") {
+		user = cfg.Name
+	}
+	day, err := parseOptionalDate(date)
+	if err != nil {
+		return fmt.Errorf("invalid --date: %w", err)
+	}
+	if day.IsZero() {
+		day = cfg.Today()
+	}
+
+	revisions, err := standupbot.New(cfg).EntryHistory(user, day)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		fmt.Printf("%s has no standup for %s.\n", user, day.Format("2006-01-02"))
+		return nil
+	}
+	writeHistory(os.Stdout, revisions, !plain && ui.IsTerminal(os.Stdout))
+	return nil
+}
+
+// writeHistory writes each revision with what it changed from the one
+// before, in color if asked
+func writeHistory(w io.Writer, revisions []standupbot.Revision, color bool) {
+	style := func(s, text string) string {
+		if !color {
+			return text
+		}
+		return s + text + styleReset
+	}
+
+	previous := ""
+	for i, revision := range revisions {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if revision.Commit == nil {
+			fmt.Fprintln(w, style(styleBold, "Uncommitted changes"))
+		} else {
+			c := revision.Commit
+			fmt.Fprintf(w, "%s %s  %s  %s\n", style(styleBold, "commit "+c.ShortHash()), c.Time.Format("2006-01-02 15:04"), c.Author, c.Subject)
+		}
+		if revision.Source == "" {
+			fmt.Fprintln(w, style(styleDim, "(standup removed)"))
+		}
+
+		for _, line := range standupbot.DiffLines(previous, revision.Source) {
+			switch line[0] {
+			case '+':
+				fmt.Fprintln(w, style(styleGreen, line))
+			case '-':
+				fmt.Fprintln(w, style(styleRed, line))
+			default:
+				fmt.Fprintln(w, line)
+			}
+		}
+		previous = revision.Source
+	}
+}
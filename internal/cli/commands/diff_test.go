@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+func TestWriteHistory(t *testing.T) {
+	commit := &git.Revision{Hash: "1a2b3c4d5e6f", Time: time.Date(2024, 1, 15, 9, 2, 0, 0, time.Local), Author: "Alice", Subject: "[Standup] alice - 2024-01-15"}
+	revisions := []standupbot.Revision{
+		{Commit: commit, Source: "## 2024-01-15\n\n- Wrte tests\n"},
+		{Source: "## 2024-01-15\n\n- Write tests\n"},
+	}
+
+	var out bytes.Buffer
+	writeHistory(&out, revisions, false)
+	want := `commit 1a2b3c4 2024-01-15 09:02  Alice  [Standup] alice - 2024-01-15
++## 2024-01-15
++
++- Wrte tests
+
+Uncommitted changes
+ ## 2024-01-15
+ 
+-- Wrte tests
++- Write tests
+`
+	if out.String() != want {
+		t.Errorf("writeHistory() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	writeHistory(&out, revisions, true)
+	if !strings.Contains(out.String(), styleRed+"-- Wrte tests"+styleReset) || !strings.Contains(out.String(), styleGreen+"+- Write tests"+styleReset) {
+		t.Errorf("colored output should mark removed lines red and added lines green:\n%q", out.String())
+	}
+}
@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/render"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// Doctor check statuses
+const (
+	checkPass = "pass"
+	checkFail = "fail"
+	checkWarn = "warn"
+	checkSkip = "skip"
+)
+
+// DoctorCheck is the result of a single environment check
+type DoctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// DoctorReport is the result of all environment checks
+type DoctorReport struct {
+	Healthy bool          `json:"healthy"`
+	Checks  []DoctorCheck `json:"checks"`
+}
+
+// RunDoctor checks the environment and prints a checklist with fix
+// suggestions, or the report as JSON or YAML
+func RunDoctor(cfgManager *config.Manager, outputFormat string) error {
+	gitClient := git.NewClient()
+	report := runDoctorChecks(cfgManager, gitClient, func(cfg *config.Config) (forge.Provider, error) {
+		return standupbot.NewForge(gitClient, cfg)
+	})
+
+	goos, goarch := currentPlatform()
+	report = withPlatformCheck(report, git.NewRunner(), goos, goarch)
+
+	if IsMachineOutput(outputFormat) {
+		return render.Print(os.Stdout, renderFormat(outputFormat), report)
+	}
+
+	failed := 0
+	for _, check := range report.Checks {
+		fmt.Printf("%s %s: %s\n", checkIcon(check.Status), check.Name, check.Message)
+		if check.Fix != "" {
+			fmt.Printf("   → %s\n", check.Fix)
+		}
+		if check.Status == checkFail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	fmt.Println("\nEverything looks good!")
+	return nil
+}
+
+// checkIcon returns the checklist marker for a status
+func checkIcon(status string) string {
+	switch status {
+	case checkPass:
+		return "✅"
+	case checkFail:
+		return "❌"
+	case checkWarn:
+		return "⚠️ "
+	}
+	return "➖"
+}
+
+// runDoctorChecks runs every check. Checks that depend on a failed one are
+// reported as skipped.
+func runDoctorChecks(cfgManager *config.Manager, gitClient *git.Client, newProvider func(*config.Config) (forge.Provider, error)) DoctorReport {
+	var checks []DoctorCheck
+	add := func(name, status, message, fix string) {
+		checks = append(checks, DoctorCheck{Name: name, Status: status, Message: message, Fix: fix})
+	}
+
+	// Git
+	if err := gitClient.CheckGitInstalled(); err != nil {
+		add("git", checkFail, err.Error(), "Install or upgrade git from https://git-scm.com/")
+	} else if version, err := gitClient.ToolVersion("git"); err != nil {
+		add("git", checkFail, err.Error(), "Install git from https://git-scm.com/")
+	} else {
+		add("git", checkPass, version, "")
+	}
+
+	// Configuration
+	cfg, err := cfgManager.Load()
+	if err != nil {
+		add("config", checkFail, err.Error(), "Run 'standup-bot --config' to set up")
+		for _, name := range []string{"forge", "repository", "branch", "write access", "network"} {
+			add(name, checkSkip, "skipped: configuration is not valid", "")
+		}
+		return newDoctorReport(checks)
+	}
+	add("config", checkPass, fmt.Sprintf("%s (repository %s)", cfgManager.ConfigFile(), cfg.Repository), "")
+
+	// Forge tools and authentication
+	provider, err := newProvider(cfg)
+	switch {
+	case err != nil:
+		add("forge", checkFail, err.Error(), "Set \"forge\" to github, gitlab, or bitbucket in the config")
+	case cfg.HasRemoteURL():
+		if err := provider.CheckAvailable(); err != nil {
+			add("forge", checkWarn, err.Error(), "Only needed for the pull request workflow")
+		} else {
+			add("forge", checkPass, fmt.Sprintf("%s tools are installed and authenticated", provider.Name()), "")
+		}
+	default:
+		if err := provider.CheckAvailable(); err != nil {
+			add("forge", checkFail, err.Error(), "")
+		} else {
+			add("forge", checkPass, fmt.Sprintf("%s tools are installed and authenticated", provider.Name()), "")
+		}
+	}
+
+	// Clone state
+	if !gitClient.RepositoryExists(cfg.LocalRepoPath) {
+		add("repository", checkFail, fmt.Sprintf("no clone found at %s", cfg.LocalRepoPath), "Run 'standup-bot --config' to clone the repository")
+		for _, name := range []string{"branch", "write access", "network"} {
+			add(name, checkSkip, "skipped: repository is not cloned", "")
+		}
+		return newDoctorReport(checks)
+	}
+	add("repository", checkPass, fmt.Sprintf("cloned at %s", cfg.LocalRepoPath), "")
+
+	// Branch divergence
+	ahead, behind, err := gitClient.Divergence(cfg.LocalRepoPath)
+	switch {
+	case err != nil:
+		add("branch", checkWarn, "current branch has no upstream", "Run 'git push -u origin HEAD' in the repository, or switch to main")
+	case ahead > 0 && behind > 0:
+		add("branch", checkFail, fmt.Sprintf("diverged from upstream (%d ahead, %d behind)", ahead, behind), "Rebase onto the upstream branch, or reset it if the local commits aren't needed")
+	case ahead > 0:
+		add("branch", checkWarn, fmt.Sprintf("%d commit(s) not pushed", ahead), "Push the branch or run standup-bot again")
+	case behind > 0:
+		add("branch", checkPass, fmt.Sprintf("%d commit(s) behind upstream; they are pulled on the next run", behind), "")
+	default:
+		add("branch", checkPass, "up to date with upstream", "")
+	}
+
+	// Write permissions
+	if err := checkWritable(cfg.LocalRepoPath); err != nil {
+		add("write access", checkFail, err.Error(), fmt.Sprintf("Check the ownership and permissions of %s", cfg.LocalRepoPath))
+	} else {
+		add("write access", checkPass, "repository is writable", "")
+	}
+
+	// Network reachability
+	if err := gitClient.RemoteReachable(cfg.LocalRepoPath); err != nil {
+		add("network", checkFail, err.Error(), "Check your network connection and git credentials")
+	} else {
+		add("network", checkPass, "origin is reachable", "")
+	}
+
+	return newDoctorReport(checks)
+}
+
+// withPlatformCheck prepends the platform check to a report. An architecture
+// mismatch is a warning since the binary still works.
+func withPlatformCheck(report DoctorReport, runner git.CommandRunner, goos, goarch string) DoctorReport {
+	check := DoctorCheck{Name: "platform", Status: checkPass, Message: fmt.Sprintf("%s/%s", goos, goarch)}
+	if err := checkPlatform(runner, goos, goarch); err != nil {
+		check.Status = checkWarn
+		check.Message = err.Error()
+		check.Fix = "Download the darwin_arm64 archive from the releases page, or reinstall with an arm64 Homebrew"
+	}
+	return newDoctorReport(append([]DoctorCheck{check}, report.Checks...))
+}
+
+// newDoctorReport builds a report, which is healthy when no check failed
+func newDoctorReport(checks []DoctorCheck) DoctorReport {
+	healthy := true
+	for _, check := range checks {
+		if check.Status == checkFail {
+			healthy = false
+		}
+	}
+	return DoctorReport{Healthy: healthy, Checks: checks}
+}
+
+// checkWritable verifies a file can be created in dir
+func checkWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".standup-bot-doctor-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s: %w", dir, err)
+	}
+	file.Close()
+	return os.Remove(filepath.Clean(file.Name()))
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// stubProvider is a forge provider whose availability check returns err
+type stubProvider struct {
+	forge.Provider
+	err error
+}
+
+func (s *stubProvider) Name() string          { return "GitHub" }
+func (s *stubProvider) CheckAvailable() error { return s.err }
+
+func TestRunDoctorChecks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	newProvider := func(*config.Config) (forge.Provider, error) {
+		return &stubProvider{}, nil
+	}
+
+	// Without a config, dependent checks are skipped
+	report := runDoctorChecks(cfgManager, git.NewClientWithRunner(&fakeRunner{}), newProvider)
+	if report.Healthy {
+		t.Error("report should be unhealthy without a config")
+	}
+	if got := checkStatus(report, "config"); got != checkFail {
+		t.Errorf("config status = %s, want fail", got)
+	}
+	if got := checkStatus(report, "network"); got != checkSkip {
+		t.Errorf("network status = %s, want skip", got)
+	}
+
+	repoPath := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repoPath, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfgManager.Save(&config.Config{Repository: "org/standups", Name: "Alice", LocalRepoPath: repoPath}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	report = runDoctorChecks(cfgManager, git.NewClientWithRunner(&fakeRunner{output: []byte("0\t0\n")}), newProvider)
+	if !report.Healthy {
+		t.Errorf("report should be healthy, got %+v", report.Checks)
+	}
+	for _, name := range []string{"git", "config", "forge", "repository", "branch", "write access", "network"} {
+		if got := checkStatus(report, name); got != checkPass {
+			t.Errorf("%s status = %s, want pass", name, got)
+		}
+	}
+
+	// A failing forge check is reported with its error
+	failing := func(*config.Config) (forge.Provider, error) {
+		return &stubProvider{err: errors.New("not authenticated with GitHub")}, nil
+	}
+	report = runDoctorChecks(cfgManager, git.NewClientWithRunner(&fakeRunner{output: []byte("0\t0\n")}), failing)
+	if report.Healthy || checkStatus(report, "forge") != checkFail {
+		t.Errorf("forge failure should make the report unhealthy, got %+v", report.Checks)
+	}
+}
+
+// checkStatus returns the status of the named check, or ""
+func checkStatus(report DoctorReport, name string) string {
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check.Status
+		}
+	}
+	return ""
+}
+
+func TestWithPlatformCheck(t *testing.T) {
+	report := DoctorReport{Healthy: true, Checks: []DoctorCheck{{Name: "git", Status: checkPass}}}
+
+	// Native builds pass without asking the OS
+	got := withPlatformCheck(report, &fakeRunner{err: errors.New("not called")}, "linux", "amd64")
+	if got.Checks[0].Name != "platform" || got.Checks[0].Status != checkPass || len(got.Checks) != 2 {
+		t.Errorf("withPlatformCheck() on linux = %+v", got.Checks)
+	}
+
+	// An Intel build translated by Rosetta is a warning
+	got = withPlatformCheck(report, &fakeRunner{output: []byte("1\n")}, "darwin", "amd64")
+	if got.Checks[0].Status != checkWarn || !strings.Contains(got.Checks[0].Message, "Rosetta") {
+		t.Errorf("withPlatformCheck() under Rosetta = %+v", got.Checks[0])
+	}
+	if !got.Healthy {
+		t.Error("a platform warning should not make the report unhealthy")
+	}
+
+	got = withPlatformCheck(report, &fakeRunner{output: []byte("0\n")}, "darwin", "amd64")
+	if got.Checks[0].Status != checkPass {
+		t.Errorf("withPlatformCheck() on an Intel Mac = %+v", got.Checks[0])
+	}
+}
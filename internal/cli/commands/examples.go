@@ -0,0 +1,224 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// exampleContext holds the values examples are filled in with: the user's
+// settings when they are configured, placeholders otherwise
+type exampleContext struct {
+	Name       string
+	Repository string
+	Forge      types.ForgeKind
+	BaseBranch string
+	Template   []types.SectionSpec
+	// Binary is how this binary is invoked, as a path for cron
+	Binary string
+	// Configured is false when the values are placeholders
+	Configured bool
+}
+
+// exampleTopic is a set of examples for one task
+type exampleTopic struct {
+	Name     string
+	Summary  string
+	Generate func(ctx exampleContext) string
+}
+
+// exampleTopics lists the topics in the order 'examples' shows them
+var exampleTopics = []exampleTopic{
+	{Name: "json", Summary: "Submit a standup from JSON, a file or a script", Generate: jsonExamples},
+	{Name: "cron", Summary: "Submit and merge on a schedule with cron", Generate: cronExamples},
+	{Name: "ci", Summary: "Merge the daily PR from a scheduled CI job", Generate: ciExamples},
+}
+
+// RunExamples prints the examples for a topic, or the list of topics when
+// topic is empty
+func RunExamples(cfgManager *config.Manager, topic string, out io.Writer) error {
+	ctx := newExampleContext(cfgManager)
+
+	if topic == "" {
+		fmt.Fprintln(out, "Examples are filled in with your settings. Show one with 'standup-bot examples <topic>':")
+		fmt.Fprintln(out)
+		for _, t := range exampleTopics {
+			fmt.Fprintf(out, "  %-6s %s\n", t.Name, t.Summary)
+		}
+		return nil
+	}
+
+	for _, t := range exampleTopics {
+		if strings.EqualFold(t.Name, topic) {
+			if !ctx.Configured {
+				fmt.Fprintln(out, "# standup-bot isn't configured, so these use placeholders.")
+				fmt.Fprintln(out, "# Run 'standup-bot --config' to fill in your settings.")
+				fmt.Fprintln(out)
+			}
+			fmt.Fprint(out, t.Generate(ctx))
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown topic '%s' (valid: %s)", topic, exampleTopicNames())
+}
+
+// exampleTopicNames returns the topic names for error messages
+func exampleTopicNames() string {
+	names := make([]string, len(exampleTopics))
+	for i, t := range exampleTopics {
+		names[i] = t.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// newExampleContext reads the user's config, falling back to placeholders
+func newExampleContext(cfgManager *config.Manager) exampleContext {
+	ctx := exampleContext{
+		Name:       "Your Name",
+		Repository: "your-org/standups",
+		Forge:      types.ForgeGitHub,
+		BaseBranch: config.DefaultBaseBranch,
+		Binary:     "standup-bot",
+	}
+	if path, err := os.Executable(); err == nil {
+		ctx.Binary = path
+	}
+
+	cfg, err := cfgManager.Load()
+	if err != nil {
+		return ctx
+	}
+	ctx.Configured = true
+	ctx.Name = cfg.Name
+	ctx.Repository = cfg.Repository
+	ctx.BaseBranch = cfg.GetBaseBranch()
+	ctx.Template = cfg.Template
+	if kind, err := cfg.GetForge(); err == nil && kind != types.ForgeAuto {
+		ctx.Forge = kind
+	} else {
+		ctx.Forge = types.DetectForgeKind(cfg.Repository)
+	}
+	return ctx
+}
+
+// exampleJSON returns a standup as JSON, with the extra sections of the
+// user's template
+func exampleJSON(ctx exampleContext, indent string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "{\n%s  \"yesterday\": [\"Fixed the login redirect\", \"Reviewed PRs\"],\n", indent)
+	fmt.Fprintf(&b, "%s  \"today\": [\"Write tests for the billing export\"],\n", indent)
+	fmt.Fprintf(&b, "%s  \"blockers\": \"None\"", indent)
+
+	var sections []string
+	for _, s := range ctx.Template {
+		if types.IsStandardSection(s.Name) {
+			continue
+		}
+		value := `["..."]`
+		if s.Single {
+			value = `"..."`
+		}
+		sections = append(sections, fmt.Sprintf("%s    %q: %s", indent, s.Name, value))
+	}
+	if len(sections) > 0 {
+		fmt.Fprintf(&b, ",\n%s  \"sections\": {\n%s\n%s  }", indent, strings.Join(sections, ",\n"), indent)
+	}
+
+	fmt.Fprintf(&b, "\n%s}", indent)
+	return b.String()
+}
+
+// jsonExamples shows the ways to submit a standup as JSON
+func jsonExamples(ctx exampleContext) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Submit as %s to %s from a file\n", ctx.Name, ctx.Repository)
+	fmt.Fprintf(&b, "cat > standup.json <<'EOF'\n%s\nEOF\n", exampleJSON(ctx, ""))
+	b.WriteString("standup-bot --json standup.json\n\n")
+
+	b.WriteString("# Or pipe it in, with machine-readable output for scripts\n")
+	b.WriteString("cat standup.json | standup-bot --json - --output json\n\n")
+
+	b.WriteString("# Commit straight to the repository instead of the daily PR\n")
+	b.WriteString("standup-bot --direct --json standup.json\n")
+
+	if len(ctx.Template) > 0 {
+		b.WriteString("\n# \"sections\" holds your team template's extra sections; replace \"...\"\n")
+	}
+	return b.String()
+}
+
+// cronExamples shows crontab entries for scheduled submissions and merges
+func cronExamples(ctx exampleContext) string {
+	var b strings.Builder
+	b.WriteString("# Add these with 'crontab -e'. Times are in the machine's local time.\n\n")
+
+	b.WriteString("# Submit ~/standup.json at 09:30 on weekdays\n")
+	fmt.Fprintf(&b, "30 9 * * 1-5 %s --json $HOME/standup.json --output json >> $HOME/.standup-bot/cron.log 2>&1\n\n", ctx.Binary)
+
+	fmt.Fprintf(&b, "# Merge the team's daily PR into %s at 17:00 on weekdays\n", ctx.BaseBranch)
+	fmt.Fprintf(&b, "0 17 * * 1-5 %s --merge >> $HOME/.standup-bot/cron.log 2>&1\n\n", ctx.Binary)
+
+	b.WriteString("# Cron has no terminal, so runs must not prompt: use --json, and add\n")
+	b.WriteString("# --force if the repository may have uncommitted non-standup changes.\n")
+	return b.String()
+}
+
+// ciExamples shows a scheduled CI job that merges the daily PR
+func ciExamples(ctx exampleContext) string {
+	settings := fmt.Sprintf(`{"repository": %q, "name": "ci", "localRepoPath": "/tmp/standups"}`, ctx.Repository)
+
+	var b strings.Builder
+	switch ctx.Forge {
+	case types.ForgeGitLab:
+		b.WriteString("# .gitlab-ci.yml in any project; schedule it in CI/CD > Schedules for 17:00 on weekdays\n")
+		b.WriteString("# GITLAB_TOKEN must be a CI/CD variable with api scope on the standup repository\n")
+		b.WriteString("merge-standups:\n")
+		b.WriteString("  image: golang:1.23\n")
+		b.WriteString("  rules:\n")
+		b.WriteString("    - if: $CI_PIPELINE_SOURCE == \"schedule\"\n")
+		b.WriteString("  script:\n")
+		b.WriteString("    - go install github.com/standup-bot/standup-bot/cmd/standup-bot@latest\n")
+		b.WriteString("    - go install gitlab.com/gitlab-org/cli/cmd/glab@latest\n")
+		b.WriteString("    - mkdir -p ~/.standup-bot\n")
+		fmt.Fprintf(&b, "    - echo '%s' > ~/.standup-bot/config.json\n", settings)
+		fmt.Fprintf(&b, "    - glab repo clone %s /tmp/standups\n", ctx.Repository)
+		b.WriteString("    - standup-bot --merge\n")
+	case types.ForgeGitHub:
+		b.WriteString("# .github/workflows/merge-standups.yml in any repository\n")
+		b.WriteString("# STANDUP_TOKEN must be a secret with write access to the standup repository\n")
+		b.WriteString("name: Merge standups\n")
+		b.WriteString("on:\n")
+		b.WriteString("  schedule:\n")
+		b.WriteString("    - cron: \"0 17 * * 1-5\"  # UTC\n")
+		b.WriteString("  workflow_dispatch:\n")
+		b.WriteString("jobs:\n")
+		b.WriteString("  merge:\n")
+		b.WriteString("    runs-on: ubuntu-latest\n")
+		b.WriteString("    env:\n")
+		b.WriteString("      GH_TOKEN: ${{ secrets.STANDUP_TOKEN }}\n")
+		b.WriteString("    steps:\n")
+		b.WriteString("      - uses: actions/setup-go@v5\n")
+		b.WriteString("        with:\n")
+		b.WriteString("          go-version: \"1.23\"\n")
+		b.WriteString("      - run: go install github.com/standup-bot/standup-bot/cmd/standup-bot@latest\n")
+		b.WriteString("      - run: |\n")
+		b.WriteString("          mkdir -p ~/.standup-bot\n")
+		fmt.Fprintf(&b, "          echo '%s' > ~/.standup-bot/config.json\n", settings)
+		fmt.Fprintf(&b, "          gh repo clone %s /tmp/standups\n", ctx.Repository)
+		b.WriteString("          gh auth setup-git\n")
+		b.WriteString("      - run: standup-bot --merge\n")
+	default:
+		fmt.Fprintf(&b, "# A scheduled job for %s: run this script at 17:00 on weekdays\n", ctx.Forge)
+		b.WriteString("# with the forge's credentials in the environment\n")
+		b.WriteString("go install github.com/standup-bot/standup-bot/cmd/standup-bot@latest\n")
+		b.WriteString("mkdir -p ~/.standup-bot\n")
+		fmt.Fprintf(&b, "echo '%s' > ~/.standup-bot/config.json\n", settings)
+		fmt.Fprintf(&b, "git clone %s /tmp/standups\n", ctx.Repository)
+		b.WriteString("standup-bot --merge\n")
+	}
+	return b.String()
+}
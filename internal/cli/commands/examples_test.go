@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+func TestExamplesUseSettings(t *testing.T) {
+	ctx := exampleContext{
+		Name:       "alice",
+		Repository: "acme/standups",
+		Forge:      types.ForgeGitHub,
+		BaseBranch: "develop",
+		Template:   []types.SectionSpec{{Name: "Today"}, {Name: "Learnings"}, {Name: "Focus %", Single: true}},
+		Binary:     "/usr/local/bin/standup-bot",
+		Configured: true,
+	}
+
+	tests := []struct {
+		topic string
+		want  []string
+	}{
+		{topic: "json", want: []string{"alice", "acme/standups", `"Learnings": ["..."]`, `"Focus %": "..."`}},
+		{topic: "cron", want: []string{"/usr/local/bin/standup-bot --merge", "into develop"}},
+		{topic: "ci", want: []string{"gh repo clone acme/standups", `"repository": "acme/standups"`}},
+	}
+
+	for _, tt := range tests {
+		for _, topic := range exampleTopics {
+			if topic.Name != tt.topic {
+				continue
+			}
+			text := topic.Generate(ctx)
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("%s examples should contain %q:\n%s", tt.topic, want, text)
+				}
+			}
+		}
+	}
+
+	ctx.Forge = types.ForgeGitLab
+	if text := ciExamples(ctx); !strings.Contains(text, "glab repo clone acme/standups") {
+		t.Errorf("GitLab CI example = %s", text)
+	}
+}
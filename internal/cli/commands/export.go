@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// RunExport writes everyone's standups dated within since and until
+// (optional YYYY-MM-DD dates) as one row per user, date, section and item,
+// to out or stdout when out is empty
+func RunExport(cfg *config.Config, format, since, until, out string) error {
+	if format != "csv" {
+		return fmt.Errorf("invalid --format '%s': only 'csv' is supported", format)
+	}
+
+	sinceDate, err := parseOptionalDate(since)
+	if err != nil {
+		return fmt.Errorf("invalid --since date: %w", err)
+	}
+	untilDate, err := parseOptionalDate(until)
+	if err != nil {
+		return fmt.Errorf("invalid --until date: %w", err)
+	}
+
+	entries, err := standupbot.NewManager(cfg).LoadAllEntries()
+	if err != nil {
+		return err
+	}
+	rows := standup.ExportRows(entries, sinceDate, untilDate)
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := standup.WriteCSV(w, rows); err != nil {
+		return err
+	}
+
+	if out != "" {
+		fmt.Printf("✅ Exported %d items to %s\n", len(rows), out)
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// actionCommand is the slash command a standup comment may start with
+const actionCommand = "/standup"
+
+// errNotAStandup reports a comment on a daily issue that isn't a standup,
+// which is left alone
+var errNotAStandup = errors.New("the comment isn't a standup")
+
+// actionEvent is the part of a GitHub Actions event payload gh-action reads
+type actionEvent struct {
+	// Inputs are the workflow_dispatch inputs
+	Inputs  map[string]string `json:"inputs"`
+	Comment *struct {
+		Body      string    `json:"body"`
+		CreatedAt time.Time `json:"created_at"`
+		User      struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"comment"`
+	Issue *struct {
+		Title  string `json:"title"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"issue"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+// GHActionOptions configures a gh-action run
+type GHActionOptions struct {
+	// Direct commits to the base branch instead of the daily pull request
+	Direct bool
+	// Force proceeds even if the repository has uncommitted non-standup changes
+	Force bool
+}
+
+// RunGHAction records a standup from the GitHub Actions event that started
+// the workflow: an issue comment, or the inputs of a workflow_dispatch. It
+// writes the pr-number and file-path outputs, and reports a failure as an
+// error annotation. Without a configuration, the workflow's repository,
+// checkout and actor are used.
+func RunGHAction(cfgManager *config.Manager, opts GHActionOptions) error {
+	err := runGHAction(cfgManager, opts, os.Getenv)
+	if err != nil {
+		fmt.Print(actionAnnotation("error", "Standup not recorded", err.Error()))
+	}
+	return err
+}
+
+// runGHAction is RunGHAction reading the workflow's environment from getenv
+func runGHAction(cfgManager *config.Manager, opts GHActionOptions, getenv func(string) string) error {
+	if getenv("GITHUB_ACTIONS") != "true" {
+		return fmt.Errorf("gh-action runs in GitHub Actions workflows; use 'standup-bot --json' elsewhere")
+	}
+	if !cfgManager.Configured() {
+		for key, value := range map[string]string{
+			config.EnvRepository: getenv("GITHUB_REPOSITORY"),
+			config.EnvRepoPath:   getenv("GITHUB_WORKSPACE"),
+			config.EnvName:       getenv("GITHUB_ACTOR"),
+		} {
+			os.Setenv(key, value)
+		}
+	}
+	cfg, err := cfgManager.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	payload, err := os.ReadFile(getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		return fmt.Errorf("failed to read the workflow event: %w", err)
+	}
+	name, entry, err := actionEntry(standupbot.NewManager(cfg), cfg, getenv("GITHUB_EVENT_NAME"), payload)
+	if errors.Is(err, errNotAStandup) {
+		fmt.Println("The comment isn't a standup; nothing to record.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cfg.Name = name
+
+	result, err := standupbot.New(cfg).Submit(entry, standupbot.Options{Direct: opts.Direct, Force: opts.Force})
+	if err != nil {
+		return err
+	}
+
+	outputs := map[string]string{"file-path": result.FilePath, "already-submitted": fmt.Sprint(result.AlreadySubmitted)}
+	if rel, err := filepath.Rel(cfg.LocalRepoPath, result.FilePath); err == nil {
+		outputs["file-path"] = filepath.ToSlash(rel)
+	}
+	if result.PR != nil {
+		outputs["pr-number"] = result.PR.Number
+	}
+	if path := getenv("GITHUB_OUTPUT"); path != "" {
+		if err := writeActionOutputs(path, outputs); err != nil {
+			return err
+		}
+	}
+
+	if result.AlreadySubmitted {
+		fmt.Printf("✅ %s's standup for %s was already recorded; nothing changed\n", name, entry.Date.Format("2006-01-02"))
+		return nil
+	}
+	fmt.Printf("✅ Recorded %s's standup for %s\n", name, entry.Date.Format("2006-01-02"))
+	return nil
+}
+
+// actionEntry reads the standup and whose it is from an event. A comment
+// holds JSON input or "### Yesterday" sections like the issue form, and
+// may start with /standup. A comment on a daily issue is for the issue's
+// day, and other comments there are ignored with errNotAStandup.
+// workflow_dispatch takes a json input, or yesterday and today inputs with
+// one item per line and blockers.
+func actionEntry(manager *standup.Manager, cfg *config.Config, eventName string, payload []byte) (string, *standup.Entry, error) {
+	var event actionEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return "", nil, fmt.Errorf("invalid workflow event: %w", err)
+	}
+
+	var name string
+	var entry *standup.Entry
+	var err error
+	switch eventName {
+	case "issue_comment":
+		if event.Comment == nil {
+			return "", nil, fmt.Errorf("the issue_comment event has no comment")
+		}
+		day, daily := event.dailyIssueDay()
+		if daily && !isStandupComment(event.Comment.Body) {
+			return "", nil, errNotAStandup
+		}
+		name, entry, err = commentEntry(manager, cfg, event.Comment.Body)
+		if err != nil {
+			return "", nil, err
+		}
+		if name == "" {
+			name = event.Comment.User.Login
+		}
+		entry.Date = types.StandupDay(event.Comment.CreatedAt.Local(), cfg.DayCutoffHour)
+		if daily {
+			entry.Date = day
+		}
+	case "workflow_dispatch":
+		name = event.Inputs["name"]
+		if input := strings.TrimSpace(event.Inputs["json"]); input != "" {
+			entry, err = actionJSONEntry(input, cfg.Template)
+		} else {
+			entry, err = standup.JSONInput{
+				Yesterday: inputLines(event.Inputs["yesterday"]),
+				Today:     inputLines(event.Inputs["today"]),
+				Blockers:  strings.TrimSpace(event.Inputs["blockers"]),
+			}.Entry(cfg.Template)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if name == "" {
+			name = event.Sender.Login
+		}
+		entry.Date = cfg.Today()
+	default:
+		return "", nil, fmt.Errorf("unsupported event '%s': run gh-action on issue_comment or workflow_dispatch", eventName)
+	}
+
+	if _, err := types.NewUserName(name); err != nil {
+		return "", nil, fmt.Errorf("invalid name '%s': %w", name, err)
+	}
+	return name, entry, nil
+}
+
+// dailyIssueDay returns the day of the daily issue commented on, or false if
+// the comment is on another issue
+func (e actionEvent) dailyIssueDay() (time.Time, bool) {
+	if e.Issue == nil {
+		return time.Time{}, false
+	}
+	for _, label := range e.Issue.Labels {
+		if label.Name == standup.DailyIssueLabel {
+			return standup.ParseDailyIssueTitle(e.Issue.Title)
+		}
+	}
+	return time.Time{}, false
+}
+
+// isStandupComment reports whether a comment looks like a standup rather
+// than conversation: it starts with /standup, JSON or a fence, or has
+// "### " sections
+func isStandupComment(body string) bool {
+	body = strings.TrimSpace(body)
+	for _, prefix := range []string{actionCommand, "{", "```"} {
+		if strings.HasPrefix(body, prefix) {
+			return true
+		}
+	}
+	return strings.HasPrefix(body, "### ") || strings.Contains(body, "\n### ")
+}
+
+// commentEntry parses a standup comment, returning the Name it gives if any.
+// The standup may be in a fence, as copied from a daily issue.
+func commentEntry(manager *standup.Manager, cfg *config.Config, body string) (string, *standup.Entry, error) {
+	body = strings.TrimSpace(body)
+	body = strings.TrimSpace(strings.TrimPrefix(body, actionCommand))
+	if fenced, ok := strings.CutPrefix(body, "```"); ok {
+		// Drop the fence's language, e.g. json or markdown
+		_, fenced, _ = strings.Cut(fenced, "\n")
+		body = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(fenced), "```"))
+	}
+	if strings.HasPrefix(body, "{") {
+		entry, err := actionJSONEntry(body, cfg.Template)
+		return "", entry, err
+	}
+	return manager.ParseIssueForm(body)
+}
+
+// actionJSONEntry parses JSON input. Unlike --json, it never reads a file
+// named by the input, since anyone who can comment controls it.
+func actionJSONEntry(input string, template []types.SectionSpec) (*standup.Entry, error) {
+	var data standup.JSONInput
+	if err := json.Unmarshal([]byte(input), &data); err != nil {
+		return nil, fmt.Errorf("invalid JSON input: %w", err)
+	}
+	return data.Entry(template)
+}
+
+// inputLines splits a multi-line input into items, dropping list markers
+func inputLines(input string) []string {
+	var items []string
+	for _, line := range strings.Split(input, "\n") {
+		item := strings.TrimSpace(line)
+		item = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(item, "- "), "* "))
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// writeActionOutputs appends step outputs to the $GITHUB_OUTPUT file
+func writeActionOutputs(path string, outputs map[string]string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write outputs: %w", err)
+	}
+	defer f.Close()
+	for _, key := range []string{"pr-number", "file-path"} {
+		if _, err := fmt.Fprintf(f, "%s=%s\n", key, outputs[key]); err != nil {
+			return fmt.Errorf("failed to write outputs: %w", err)
+		}
+	}
+	return nil
+}
+
+// actionEscaper escapes workflow command data, which ends at a newline
+var actionEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+// actionAnnotation formats a workflow command that annotates the run, e.g.
+// "::error title=...::message"
+func actionAnnotation(level, title, message string) string {
+	title = strings.NewReplacer(":", "%3A", ",", "%2C").Replace(actionEscaper.Replace(title))
+	return fmt.Sprintf("::%s title=%s::%s\n", level, title, actionEscaper.Replace(message))
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestActionEntry(t *testing.T) {
+	manager := standup.NewManager(t.TempDir())
+	cfg := &config.Config{}
+
+	tests := []struct {
+		name      string
+		event     string
+		payload   string
+		wantName  string
+		wantToday string
+		wantDate  string
+		wantErr   string
+	}{
+		{name: "comment sections", event: "issue_comment",
+			payload:  `{"comment":{"body":"/standup\n### Today\n\n- Ship it\n","created_at":"2024-01-15T09:00:00Z","user":{"login":"alice"}}}`,
+			wantName: "alice", wantToday: "Ship it"},
+		{name: "comment JSON in a fence", event: "issue_comment",
+			payload:  "{\"comment\":{\"body\":\"```json\\n{\\\"today\\\": [\\\"Review\\\"]}\\n```\",\"user\":{\"login\":\"bob\"}}}",
+			wantName: "bob", wantToday: "Review"},
+		{name: "comment names someone", event: "issue_comment",
+			payload:  `{"comment":{"body":"### Name\n\nCarol\n\n### Today\n\nPlan","user":{"login":"bob"}}}`,
+			wantName: "Carol", wantToday: "Plan"},
+		{name: "comment in a markdown fence", event: "issue_comment",
+			payload:  "{\"comment\":{\"body\":\"```markdown\\n### Today\\n\\nDemo\\n```\",\"user\":{\"login\":\"bob\"}}}",
+			wantName: "bob", wantToday: "Demo"},
+		{name: "daily issue comment", event: "issue_comment",
+			payload: `{"issue":{"title":"Standups for 2024-01-12","labels":[{"name":"standup-daily"}]},` +
+				`"comment":{"body":"### Today\n\nShip","created_at":"2024-01-15T09:00:00Z","user":{"login":"alice"}}}`,
+			wantName: "alice", wantToday: "Ship", wantDate: "2024-01-12"},
+		{name: "conversation on a daily issue", event: "issue_comment",
+			payload: `{"issue":{"title":"Standups for 2024-01-12","labels":[{"name":"standup-daily"}]},` +
+				`"comment":{"body":"Nice work!","user":{"login":"bob"}}}`,
+			wantErr: "isn't a standup"},
+		{name: "empty comment", event: "issue_comment",
+			payload: `{"comment":{"body":"thanks!","user":{"login":"bob"}}}`, wantErr: "at least one"},
+		{name: "dispatch lines", event: "workflow_dispatch",
+			payload:  `{"inputs":{"yesterday":"- Fixed bug","today":"Write tests\nDeploy","blockers":""},"sender":{"login":"dave"}}`,
+			wantName: "dave", wantToday: "Write tests"},
+		{name: "dispatch JSON", event: "workflow_dispatch",
+			payload:  `{"inputs":{"json":"{\"today\":[\"Pair\"]}","name":"Erin"},"sender":{"login":"dave"}}`,
+			wantName: "Erin", wantToday: "Pair"},
+		{name: "dispatch JSON never reads files", event: "workflow_dispatch",
+			payload: `{"inputs":{"json":"/etc/passwd"},"sender":{"login":"dave"}}`, wantErr: "invalid JSON"},
+		{name: "other event", event: "push", payload: `{}`, wantErr: "unsupported event"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, entry, err := actionEntry(manager, cfg, tt.event, []byte(tt.payload))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("actionEntry() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("actionEntry() error = %v", err)
+			}
+			if name != tt.wantName || len(entry.Today) == 0 || entry.Today[0] != tt.wantToday {
+				t.Errorf("actionEntry() = %q, %+v; want %q with today %q", name, entry, tt.wantName, tt.wantToday)
+			}
+			if date := entry.Date.Format("2006-01-02"); tt.wantDate != "" && date != tt.wantDate {
+				t.Errorf("actionEntry() date = %s, want %s", date, tt.wantDate)
+			}
+		})
+	}
+}
+
+func TestRunGHActionOutsideActions(t *testing.T) {
+	err := runGHAction(nil, GHActionOptions{}, func(string) string { return "" })
+	if err == nil || !strings.Contains(err.Error(), "GitHub Actions") {
+		t.Errorf("runGHAction() error = %v, want it to need Actions", err)
+	}
+}
+
+func TestWriteActionOutputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(path, []byte("earlier=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeActionOutputs(path, map[string]string{"pr-number": "12", "file-path": "stand-ups/alice.md"}); err != nil {
+		t.Fatalf("writeActionOutputs() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "earlier=1\npr-number=12\nfile-path=stand-ups/alice.md\n"; string(data) != want {
+		t.Errorf("outputs = %q, want %q", data, want)
+	}
+}
+
+func TestActionAnnotation(t *testing.T) {
+	got := actionAnnotation("error", "Standup: not recorded", "failed to push\n100% broken")
+	if want := "::error title=Standup%3A not recorded::failed to push%0A100%25 broken\n"; got != want {
+		t.Errorf("actionAnnotation() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// healthProbes answer the liveness (/healthz) and readiness (/readyz)
+// probes of the long-running servers, e.g. from Kubernetes. Both reply with
+// a report shaped like doctor's, 200 when healthy and 503 otherwise.
+type healthProbes struct {
+	newManager  func() (*config.Manager, error)
+	gitClient   *git.Client
+	newProvider func(*config.Config) (forge.Provider, error)
+}
+
+// newHealthProbes creates probes that check the user's configuration
+func newHealthProbes() *healthProbes {
+	gitClient := git.NewClient()
+	return &healthProbes{
+		newManager: config.NewManager,
+		gitClient:  gitClient,
+		newProvider: func(cfg *config.Config) (forge.Provider, error) {
+			return standupbot.NewForge(gitClient, cfg)
+		},
+	}
+}
+
+// register adds the probes to mux. They need no token, since probes
+// can't send one.
+func (p *healthProbes) register(mux *http.ServeMux) {
+	mux.Handle("/healthz", p.handler(p.liveness))
+	mux.Handle("/readyz", p.handler(p.readiness))
+}
+
+// handler serves a report, with 503 when it's unhealthy
+func (p *healthProbes) handler(check func() (DoctorReport, *config.Config)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report, _ := check()
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// liveness checks the configuration is valid and the repositories, the
+// main one and each team's, are cloned, which only change when someone
+// edits the deployment
+func (p *healthProbes) liveness() (DoctorReport, *config.Config) {
+	var checks []DoctorCheck
+	add := func(name, status, message string) {
+		checks = append(checks, DoctorCheck{Name: name, Status: status, Message: message})
+	}
+
+	cfgManager, err := p.newManager()
+	if err != nil {
+		add("config", checkFail, err.Error())
+		return newDoctorReport(checks), nil
+	}
+	cfg, err := cfgManager.Load()
+	if err != nil {
+		add("config", checkFail, err.Error())
+		return newDoctorReport(checks), nil
+	}
+	add("config", checkPass, fmt.Sprintf("repository %s", cfg.Repository))
+
+	if !p.gitClient.RepositoryExists(cfg.LocalRepoPath) {
+		add("repository", checkFail, fmt.Sprintf("no clone found at %s", cfg.LocalRepoPath))
+		return newDoctorReport(checks), nil
+	}
+	add("repository", checkPass, fmt.Sprintf("cloned at %s", cfg.LocalRepoPath))
+
+	for _, id := range cfg.TeamIDs() {
+		name := fmt.Sprintf("team %s", id)
+		teamCfg, err := cfgManager.LoadTeam(id)
+		switch {
+		case err != nil:
+			add(name, checkFail, err.Error())
+		case !p.gitClient.RepositoryExists(teamCfg.LocalRepoPath):
+			add(name, checkFail, fmt.Sprintf("no clone found at %s", teamCfg.LocalRepoPath))
+		default:
+			add(name, checkPass, fmt.Sprintf("cloned at %s", teamCfg.LocalRepoPath))
+		}
+	}
+	return newDoctorReport(checks), cfg
+}
+
+// readiness adds to the liveness checks that the forge's tools are
+// authenticated and its API answers, or for a raw git URL that the remote
+// is reachable
+func (p *healthProbes) readiness() (DoctorReport, *config.Config) {
+	report, cfg := p.liveness()
+	if cfg == nil {
+		return report, nil
+	}
+
+	checks := report.Checks
+	if cfg.HasRemoteURL() {
+		if err := p.gitClient.RemoteReachable(cfg.LocalRepoPath); err != nil {
+			checks = append(checks, DoctorCheck{Name: "network", Status: checkFail, Message: err.Error()})
+		} else {
+			checks = append(checks, DoctorCheck{Name: "network", Status: checkPass, Message: "origin is reachable"})
+		}
+		return newDoctorReport(checks), cfg
+	}
+
+	provider, err := p.newProvider(cfg)
+	if err == nil {
+		err = provider.CheckAvailable()
+	}
+	if err != nil {
+		checks = append(checks, DoctorCheck{Name: "forge", Status: checkFail, Message: err.Error()})
+	} else {
+		checks = append(checks, DoctorCheck{Name: "forge", Status: checkPass, Message: fmt.Sprintf("%s is reachable and authenticated", provider.Name())})
+	}
+	return newDoctorReport(checks), cfg
+}
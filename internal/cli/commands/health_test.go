@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+func TestHealthProbes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	providerErr := errors.New("not authenticated with GitHub")
+	probes := &healthProbes{
+		newManager: config.NewManager,
+		gitClient:  git.NewClientWithRunner(&fakeRunner{}),
+		newProvider: func(*config.Config) (forge.Provider, error) {
+			return &stubProvider{err: providerErr}, nil
+		},
+	}
+	mux := http.NewServeMux()
+	probes.register(mux)
+
+	probe := func(path string) (int, DoctorReport) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var report DoctorReport
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("%s: invalid report: %v", path, err)
+		}
+		return rec.Code, report
+	}
+
+	// Without a config, neither probe passes
+	for _, path := range []string{"/healthz", "/readyz"} {
+		if code, report := probe(path); code != http.StatusServiceUnavailable || checkStatus(report, "config") != checkFail {
+			t.Errorf("%s without config: %d %+v", path, code, report)
+		}
+	}
+
+	repoPath := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repoPath, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfgManager, _ := config.NewManager()
+	if err := cfgManager.Save(&config.Config{Repository: "org/standups", Name: "Alice", LocalRepoPath: repoPath}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Alive, but not ready while gh isn't authenticated
+	if code, report := probe("/healthz"); code != http.StatusOK || checkStatus(report, "repository") != checkPass {
+		t.Errorf("/healthz: %d %+v", code, report)
+	}
+	if code, report := probe("/readyz"); code != http.StatusServiceUnavailable || checkStatus(report, "forge") != checkFail {
+		t.Errorf("/readyz: %d %+v", code, report)
+	}
+
+	providerErr = nil
+	if code, report := probe("/readyz"); code != http.StatusOK || !report.Healthy {
+		t.Errorf("/readyz once authenticated: %d %+v", code, report)
+	}
+
+	// Every team's repository must be cloned too
+	t.Setenv(config.EnvTeams, "web=org/web-standups")
+	if code, report := probe("/healthz"); code != http.StatusServiceUnavailable || checkStatus(report, "team web") != checkFail {
+		t.Errorf("/healthz without the team's clone: %d %+v", code, report)
+	}
+}
@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// ImportOptions are the flags of 'import'
+type ImportOptions struct {
+	// Format is standup.ImportSlack, ImportCSV or ImportMarkdown; detected
+	// from the path when empty
+	Format string
+	// User is whose standups they are, replacing the authors in the archive
+	User string
+	// Authors renames authors of the archive, e.g. Slack usernames to
+	// standup names
+	Authors map[string]string
+	// DryRun lists what would be imported without saving anything
+	DryRun bool
+	// Force proceeds even if the repository has uncommitted non-standup changes
+	Force bool
+}
+
+// RunImport records the standups of an archive from another tool in the
+// standup repository, in one commit to the base branch. Days a member
+// already has a standup for are kept as they are.
+func RunImport(cfg *config.Config, path string, opts ImportOptions) error {
+	format := opts.Format
+	if format == "" {
+		detected, err := standup.DetectImportFormat(path)
+		if err != nil {
+			return err
+		}
+		format = detected
+	}
+
+	manager := standupbot.NewManager(cfg)
+	imported, err := readArchive(manager, path, format, opts.User)
+	if err != nil {
+		return err
+	}
+	imported = renameAuthors(imported, opts)
+	if len(imported) == 0 {
+		fmt.Printf("No standups found in %s.\n", path)
+		return nil
+	}
+
+	if opts.DryRun {
+		writeImportSummary(imported)
+		return nil
+	}
+
+	gitClient := standupbot.NewGitClient(cfg)
+	if !cfg.Standalone() {
+		if err := standupbot.ValidateDirectEnvironment(gitClient, cfg); err != nil {
+			return err
+		}
+		if err := standupbot.CheckWorkTree(gitClient, cfg.LocalRepoPath, opts.Force, false); err != nil {
+			return err
+		}
+		if err := checkoutBaseBranch(gitClient, cfg); err != nil {
+			return err
+		}
+	}
+
+	recorded, skipped := 0, 0
+	users := make(map[string]bool)
+	var paths []string
+	changed := make(map[string]bool)
+	for _, item := range imported {
+		exists, err := manager.HasEntry(item.User, item.Entry.Date)
+		if err != nil {
+			return err
+		}
+		if exists {
+			skipped++
+			continue
+		}
+		if err := manager.SaveEntry(item.Entry, item.User); err != nil {
+			return fmt.Errorf("failed to save %s's standup for %s: %w", item.User, item.Entry.Date.Format("2006-01-02"), err)
+		}
+		recorded++
+		users[item.User] = true
+		if path := manager.UserPath(item.User); !changed[path] {
+			changed[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	if recorded > 0 && !cfg.Standalone() {
+		message := fmt.Sprintf("Import %d standups from %s", recorded, format)
+		err := gitClient.CommitAndPush(cfg.LocalRepoPath, message, paths...)
+		if err != nil && !errors.Is(err, git.ErrNoChangesToCommit) {
+			return fmt.Errorf("failed to commit the imported standups: %w", err)
+		}
+	}
+
+	if recorded > 0 {
+		fmt.Printf("✅ Imported %d standup(s) for %d teammate(s)\n", recorded, len(users))
+	}
+	if skipped > 0 {
+		fmt.Printf("Skipped %d day(s) that already had a standup.\n", skipped)
+	}
+	return nil
+}
+
+// readArchive reads the standups of an archive in the given format
+func readArchive(manager *standup.Manager, path, format, user string) ([]standup.ImportedEntry, error) {
+	switch format {
+	case standup.ImportSlack:
+		return manager.ImportSlack(path)
+	case standup.ImportCSV, standup.ImportMarkdown:
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if format == standup.ImportCSV {
+			return manager.ImportCSV(f)
+		}
+		return manager.ImportMarkdown(f, user)
+	}
+	return nil, fmt.Errorf("invalid --format '%s': expected '%s', '%s' or '%s'", format, standup.ImportSlack, standup.ImportCSV, standup.ImportMarkdown)
+}
+
+// renameAuthors applies --user and --author to the imported standups, and
+// leaves out those whose author can't be a standup name
+func renameAuthors(imported []standup.ImportedEntry, opts ImportOptions) []standup.ImportedEntry {
+	var kept []standup.ImportedEntry
+	for _, item := range imported {
+		if opts.User != "" {
+			item.User = opts.User
+		} else if name, ok := opts.Authors[item.User]; ok {
+			item.User = name
+		}
+		if _, err := types.NewUserName(item.User); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping %s's standup for %s: %v\n", item.User, item.Entry.Date.Format("2006-01-02"), err)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// importedUsers returns the authors of the imported standups, sorted
+func importedUsers(imported []standup.ImportedEntry) []string {
+	seen := make(map[string]bool)
+	var users []string
+	for _, item := range imported {
+		if !seen[item.User] {
+			seen[item.User] = true
+			users = append(users, item.User)
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+// writeImportSummary prints how many standups each author has in the
+// archive, and the days they span
+func writeImportSummary(imported []standup.ImportedEntry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER\tSTANDUPS\tFROM\tTO")
+	for _, user := range importedUsers(imported) {
+		var count int
+		var first, last string
+		for _, item := range imported {
+			if item.User != user {
+				continue
+			}
+			day := item.Entry.Date.Format("2006-01-02")
+			if count == 0 {
+				first = day
+			}
+			last = day
+			count++
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", user, count, first, last)
+	}
+	w.Flush()
+	fmt.Printf("\n%d standups would be imported; run without --dry-run to record them.\n", len(imported))
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+func TestRunImport(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "geekbot.csv")
+	csv := "Date,Member,Yesterday,Today,Blockers\n" +
+		"2024-01-15,jdoe,Fixed the login bug,Ship the release,No\n" +
+		"2024-01-16,jdoe,Shipped it,Retro,No\n" +
+		"2024-01-16,bad/name,Item,Item,No\n"
+	if err := os.WriteFile(archive, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Name: "alice", LocalRepoPath: t.TempDir(), Storage: &config.StorageConfig{Backend: config.StorageDir}}
+	opts := ImportOptions{Authors: map[string]string{"jdoe": "Jane"}}
+
+	if err := RunImport(cfg, archive, ImportOptions{Authors: opts.Authors, DryRun: true}); err != nil {
+		t.Fatalf("RunImport() dry run error = %v", err)
+	}
+	if users, _ := standupbot.NewManager(cfg).Users(); len(users) != 0 {
+		t.Fatalf("dry run recorded standups for %v", users)
+	}
+
+	// A day already recorded is kept as it is
+	manager := standupbot.NewManager(cfg)
+	recorded := &standup.Entry{Date: time.Date(2024, 1, 16, 0, 0, 0, 0, time.Local), Yesterday: []string{"Recorded by hand"}, Blockers: "None"}
+	if err := manager.SaveEntry(recorded, "Jane"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunImport(cfg, archive, opts); err != nil {
+		t.Fatalf("RunImport() error = %v", err)
+	}
+	entries, err := manager.LoadEntries("Jane")
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("LoadEntries() = %+v, %v, want two standups", entries, err)
+	}
+	if entries[0].Yesterday[0] != "Recorded by hand" || entries[1].Yesterday[0] != "Fixed the login bug" {
+		t.Errorf("LoadEntries() = %+v, want the recorded day kept and the other imported", entries)
+	}
+
+	err = RunImport(cfg, archive, ImportOptions{Format: "xml"})
+	if err == nil || !strings.Contains(err.Error(), "invalid --format") {
+		t.Errorf("RunImport() error = %v, want invalid --format", err)
+	}
+}
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// RunIndexRebuild discards the local index of the standup history and builds
+// it again from every standup file
+func RunIndexRebuild(cfg *config.Config) error {
+	path := standupbot.IndexPath(cfg)
+	if path == "" {
+		return fmt.Errorf("no standup repository at %s to index; run 'standup-bot --config' to clone it", cfg.LocalRepoPath)
+	}
+
+	count, err := standupbot.NewManager(cfg).RebuildIndex()
+	if err != nil {
+		return fmt.Errorf("failed to rebuild the index: %w", err)
+	}
+	fmt.Printf("✅ Indexed %d standups in %s\n", count, path)
+	return nil
+}
@@ -0,0 +1,547 @@
+package commands
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// WebhookSecretEnv holds the secret GitHub signs webhook deliveries with
+const WebhookSecretEnv = "STANDUP_BOT_WEBHOOK_SECRET"
+
+// maxWebhookPayloadSize limits the size of a webhook delivery; GitHub caps
+// payloads at 25MB but issue events are far smaller
+const maxWebhookPayloadSize = 1 << 20
+
+// webhookQueueSize is how many issues, or refreshes, can wait
+const webhookQueueSize = 64
+
+// webhookReplayWindow is how long handled deliveries are remembered, to
+// reject them if they are sent again
+const webhookReplayWindow = 24 * time.Hour
+
+// issueEvent is the part of a GitHub "issues" webhook payload the listener reads
+type issueEvent struct {
+	Action     string `json:"action"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Label *struct {
+		Name string `json:"name"`
+	} `json:"label"`
+	Issue struct {
+		Number    int       `json:"number"`
+		Body      string    `json:"body"`
+		State     string    `json:"state"`
+		CreatedAt time.Time `json:"created_at"`
+		User      struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"issue"`
+}
+
+// issueCommentEvent is the part of a GitHub "issue_comment" webhook payload
+// the listener reads
+type issueCommentEvent struct {
+	Action     string `json:"action"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Issue struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		State  string `json:"state"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"issue"`
+	Comment struct {
+		Body string `json:"body"`
+		User struct {
+			Login string `json:"login"`
+			Type  string `json:"type"`
+		} `json:"user"`
+	} `json:"comment"`
+}
+
+// dailyComment returns the standup of an event that commented one on an
+// open daily issue. Conversation and bots' comments, such as the listener's
+// own replies, are skipped.
+func (e issueCommentEvent) dailyComment() (dailyComment, bool) {
+	if e.Action != "created" || e.Issue.State != "open" || e.Comment.User.Type == "Bot" || !isStandupComment(e.Comment.Body) {
+		return dailyComment{}, false
+	}
+	labeled := false
+	for _, label := range e.Issue.Labels {
+		labeled = labeled || label.Name == standup.DailyIssueLabel
+	}
+	date, ok := standup.ParseDailyIssueTitle(e.Issue.Title)
+	if !labeled || !ok {
+		return dailyComment{}, false
+	}
+	return dailyComment{Issue: e.Issue.Number, Date: date, Author: e.Comment.User.Login, Body: e.Comment.Body}, true
+}
+
+// pushEvent is the part of a GitHub "push" webhook payload the listener reads
+type pushEvent struct {
+	Ref        string `json:"ref"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// standupDay returns the day of a daily standup branch that was pushed to
+func (e pushEvent) standupDay(location *time.Location) (time.Time, bool) {
+	branch, ok := strings.CutPrefix(e.Ref, "refs/heads/")
+	if !ok || e.Deleted {
+		return time.Time{}, false
+	}
+	return standupbot.BranchDate(branch, location)
+}
+
+// pullRequestEvent is the part of a GitHub "pull_request" webhook payload
+// the listener reads
+type pullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Merged  bool   `json:"merged"`
+		HTMLURL string `json:"html_url"`
+		Head    struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// mergedDay returns the day of a daily pull request that was merged
+func (e pullRequestEvent) mergedDay(location *time.Location) (time.Time, bool) {
+	if e.Action != "closed" || !e.PullRequest.Merged {
+		return time.Time{}, false
+	}
+	return standupbot.BranchDate(e.PullRequest.Head.Ref, location)
+}
+
+// deliveryLog remembers the deliveries handled recently
+type deliveryLog struct {
+	mu     sync.Mutex
+	window time.Duration
+	seen   map[string]time.Time
+	now    func() time.Time
+}
+
+// newDeliveryLog creates a log that forgets deliveries after window
+func newDeliveryLog(window time.Duration) *deliveryLog {
+	return &deliveryLog{window: window, seen: make(map[string]time.Time), now: time.Now}
+}
+
+// claim records a delivery, returning false if it was already handled
+func (l *deliveryLog) claim(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for seen, at := range l.seen {
+		if now.Sub(at) >= l.window {
+			delete(l.seen, seen)
+		}
+	}
+	if _, ok := l.seen[id]; ok {
+		return false
+	}
+	l.seen[id] = now
+	return true
+}
+
+// release forgets a delivery that wasn't handled, so it can be sent again
+func (l *deliveryLog) release(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.seen, id)
+}
+
+// webhookQueues holds the work waiting for one standup repository. It is
+// done one item at a time, since it shares the local clone.
+type webhookQueues struct {
+	issues chan git.Issue
+	// comments holds standups commented on daily issues
+	comments chan dailyComment
+	// refreshes holds the days whose pull request description to rebuild
+	refreshes chan time.Time
+	// merges holds the days whose merged standups to email and export
+	merges chan time.Time
+	// location is the repository's configured time zone, which the days of
+	// daily branches are in
+	location *time.Location
+}
+
+// newWebhookQueues creates empty queues
+func newWebhookQueues() *webhookQueues {
+	return &webhookQueues{
+		issues:    make(chan git.Issue, webhookQueueSize),
+		comments:  make(chan dailyComment, webhookQueueSize),
+		refreshes: make(chan time.Time, webhookQueueSize),
+		merges:    make(chan time.Time, webhookQueueSize),
+		location:  time.Local,
+	}
+}
+
+// githubWebhook receives GitHub events: it queues open standup issues and
+// standups commented on daily issues for recording, pushes to a daily branch
+// for a description refresh, and merged daily pull requests for the email
+// digest and exports, and announces the merges
+type githubWebhook struct {
+	secret []byte
+	queues *webhookQueues
+	// teams holds the queues of other teams' standup repositories, by
+	// lowercase "owner/name"; events from any other repository go to queues
+	teams map[string]*webhookQueues
+	seen  *deliveryLog
+	// announce posts that a daily pull request was merged
+	announce func(number, url string, date time.Time) error
+}
+
+// newGitHubWebhook creates a listener that checks deliveries against secret
+func newGitHubWebhook(secret string) *githubWebhook {
+	return &githubWebhook{
+		secret:   []byte(secret),
+		queues:   newWebhookQueues(),
+		teams:    make(map[string]*webhookQueues),
+		seen:     newDeliveryLog(webhookReplayWindow),
+		announce: standupbot.AnnounceMerge,
+	}
+}
+
+// route gives a team's repository its own queues and returns them
+func (h *githubWebhook) route(repository string) *webhookQueues {
+	queues := newWebhookQueues()
+	h.teams[strings.ToLower(repository)] = queues
+	return queues
+}
+
+// queuesFor returns the queues of the repository an event came from
+func (h *githubWebhook) queuesFor(repository string) *webhookQueues {
+	if queues, ok := h.teams[strings.ToLower(repository)]; ok {
+		return queues
+	}
+	return h.queues
+}
+
+// ServeHTTP handles a webhook delivery. Work that touches the repository is
+// done after the response, since GitHub gives up on deliveries that take
+// over ten seconds. A delivery that was already handled is rejected, so a
+// captured one can't be replayed.
+func (h *githubWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookPayloadSize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	signature := r.Header.Get("X-Hub-Signature-256")
+	if !h.validSignature(signature, body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	if event == "ping" {
+		fmt.Fprintln(w, "pong")
+		return
+	}
+
+	// The signature covers the whole payload, so it identifies the delivery
+	// even if the delivery ID header is changed
+	if !h.seen.claim(signature) {
+		http.Error(w, "delivery already handled", http.StatusConflict)
+		return
+	}
+	status, message := h.handle(event, body)
+	if status >= 300 {
+		// GitHub may redeliver it
+		h.seen.release(signature)
+		http.Error(w, message, status)
+		return
+	}
+	w.WriteHeader(status)
+	fmt.Fprintln(w, message)
+}
+
+// handle acts on an event, returning the response status and message
+func (h *githubWebhook) handle(eventName string, body []byte) (int, string) {
+	switch eventName {
+	case "issues":
+		var event issueEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return http.StatusBadRequest, "invalid payload"
+		}
+		issue, ok := event.standupIssue()
+		if !ok {
+			return http.StatusOK, "ignored: not a new standup issue"
+		}
+		return enqueue(h.queuesFor(event.Repository.FullName).issues, issue, fmt.Sprintf("queued issue #%d", issue.Number))
+
+	case "issue_comment":
+		var event issueCommentEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return http.StatusBadRequest, "invalid payload"
+		}
+		comment, ok := event.dailyComment()
+		if !ok {
+			return http.StatusOK, "ignored: not a standup on a daily issue"
+		}
+		return enqueue(h.queuesFor(event.Repository.FullName).comments, comment, fmt.Sprintf("queued a comment on issue #%d", comment.Issue))
+
+	case "push":
+		var event pushEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return http.StatusBadRequest, "invalid payload"
+		}
+		queues := h.queuesFor(event.Repository.FullName)
+		date, ok := event.standupDay(queues.location)
+		if !ok {
+			return http.StatusOK, "ignored: not a push to a daily standup branch"
+		}
+		git.InvalidateCache()
+		return enqueue(queues.refreshes, date, fmt.Sprintf("queued a refresh of the pull request for %s", date.Format("2006-01-02")))
+
+	case "pull_request":
+		var event pullRequestEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return http.StatusBadRequest, "invalid payload"
+		}
+		queues := h.queuesFor(event.Repository.FullName)
+		date, ok := event.mergedDay(queues.location)
+		if !ok {
+			return http.StatusOK, "ignored: not a merged daily pull request"
+		}
+		git.InvalidateCache()
+		err := h.announce(strconv.Itoa(event.Number), event.PullRequest.HTMLURL, date)
+		metrics.recordRequest("webhook_merged", err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not announce the merge of #%d: %v\n", event.Number, err)
+			return http.StatusBadGateway, "failed to announce the merge"
+		}
+		return enqueue(queues.merges, date, fmt.Sprintf("handled the merge of #%d", event.Number))
+	}
+	return http.StatusOK, "ignored: unsupported event"
+}
+
+// enqueue adds work to a queue without waiting
+func enqueue[T any](queue chan T, work T, message string) (int, string) {
+	select {
+	case queue <- work:
+		return http.StatusAccepted, message
+	default:
+		// GitHub can redeliver it once the queue drains
+		return http.StatusServiceUnavailable, "too much work waiting"
+	}
+}
+
+// validSignature checks the sha256 HMAC GitHub sends as "sha256=<hex>"
+func (h *githubWebhook) validSignature(header string, body []byte) bool {
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// standupIssue returns the issue of an event that opened a standup issue or
+// added the standup label to an open one
+func (e issueEvent) standupIssue() (git.Issue, bool) {
+	if e.Issue.State != "open" {
+		return git.Issue{}, false
+	}
+	switch e.Action {
+	case "opened":
+		labeled := false
+		for _, label := range e.Issue.Labels {
+			labeled = labeled || label.Name == standup.IssueLabel
+		}
+		if !labeled {
+			return git.Issue{}, false
+		}
+	case "labeled":
+		if e.Label == nil || e.Label.Name != standup.IssueLabel {
+			return git.Issue{}, false
+		}
+	default:
+		return git.Issue{}, false
+	}
+
+	issue := git.Issue{Number: e.Issue.Number, Body: e.Issue.Body, CreatedAt: e.Issue.CreatedAt}
+	issue.Author.Login = e.Issue.User.Login
+	return issue, true
+}
+
+// RunIssueWebhook listens for GitHub webhooks. It records each standup
+// issue as soon as it is opened, closing it with a confirmation, and each
+// standup commented on a daily issue, replying with the outcome. It rebuilds
+// the daily pull request's description when its branch is pushed to, and
+// announces merged daily pull requests on Slack and emails their digest.
+// Issues opened while the listener was down are imported on startup. The
+// first configuration is the main repository's; events from the
+// repositories of the others, one per team, are handled there.
+func RunIssueWebhook(cfgs []*config.Config, addr string, force bool) error {
+	secret := os.Getenv(WebhookSecretEnv)
+	if secret == "" {
+		return fmt.Errorf("%s must be set to the secret of the repository's webhook", WebhookSecretEnv)
+	}
+
+	webhook := newGitHubWebhook(secret)
+	for i, cfg := range cfgs {
+		if err := RunIssueImport(cfg, force); err != nil {
+			return err
+		}
+		queues := webhook.queues
+		if i > 0 {
+			repo, err := cfg.GetRepository()
+			if err != nil {
+				return err
+			}
+			queues = webhook.route(repo.String())
+		}
+		queues.location = cfg.Location()
+		go processWebhookQueues(cfg, queues, force)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", webhook)
+	mux.Handle("/metrics", metrics)
+	newHealthProbes().register(mux)
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	errChan := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errChan <- err
+		}
+	}()
+	fmt.Fprintf(os.Stderr, "Listening for GitHub events on %s (POST /webhook, metrics at /metrics)...\n", addr)
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("webhook server error: %w", err)
+	case <-sigChan:
+		fmt.Fprintln(os.Stderr, "Shutting down webhook server...")
+		return server.Close()
+	}
+}
+
+// processWebhookQueues works through a repository's queues one item at a
+// time, since they share the local repository. GitHub sends both "opened"
+// and "labeled" for an issue created from the form, so each issue is
+// handled once.
+func processWebhookQueues(cfg *config.Config, queues *webhookQueues, force bool) {
+	gitClient := standupbot.NewGitClient(cfg)
+	manager := standupbot.NewManager(cfg)
+	handled := make(map[int]bool)
+
+	for {
+		select {
+		case issue := <-queues.issues:
+			if handled[issue.Number] {
+				continue
+			}
+			ctx, end := startRequest(context.Background(), "webhook_issue")
+			gitClient := gitClient.WithContext(ctx)
+			if err := checkoutBaseBranch(gitClient, cfg); err != nil {
+				end(err)
+				fmt.Fprintf(os.Stderr, "⚠️  Issue #%d: %v\n", issue.Number, err)
+				continue
+			}
+			_, err := recordIssue(gitClient, cfg, manager, issue)
+			end(err)
+			metrics.recordRequest("webhook_issue", err)
+			if err != nil {
+				// The issue stays open for 'issues import' or the next startup
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+				continue
+			}
+			handled[issue.Number] = true
+
+		case comment := <-queues.comments:
+			ctx, end := startRequest(context.Background(), "webhook_comment")
+			err := recordDailyComment(ctx, gitClient.WithContext(ctx), cfg, comment, force)
+			end(err)
+			metrics.recordRequest("webhook_comment", err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			}
+
+		case date := <-queues.merges:
+			ctx, end := startRequest(context.Background(), "webhook_merged")
+			// The digest and exports are read from the base branch, with the
+			// merge pulled
+			if err := checkoutBaseBranch(gitClient.WithContext(ctx), cfg); err != nil {
+				end(err)
+				fmt.Fprintf(os.Stderr, "⚠️  Could not pull the merge for %s: %v\n", date.Format("2006-01-02"), err)
+				continue
+			}
+			bot := requestBot(ctx, cfg)
+			var digestErr error
+			if cfg.Digest != nil {
+				_, digestErr = bot.SendDigest(date)
+				metrics.recordRequest("webhook_digest", digestErr)
+				if digestErr != nil {
+					fmt.Fprintf(os.Stderr, "⚠️  Could not email the digest for %s: %v\n", date.Format("2006-01-02"), digestErr)
+				}
+			}
+			pages, err := bot.ExportStandups(date)
+			if len(pages) > 0 || err != nil {
+				metrics.recordRequest("webhook_export", err)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Could not export the standups for %s: %v\n", date.Format("2006-01-02"), err)
+			}
+			end(errors.Join(digestErr, err))
+
+		case date := <-queues.refreshes:
+			ctx, end := startRequest(context.Background(), "webhook_refresh")
+			_, err := requestBot(ctx, cfg).RefreshPullRequest(date, standupbot.Options{Force: force})
+			end(err)
+			metrics.recordRequest("webhook_refresh", err)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Could not refresh the pull request for %s: %v\n", date.Format("2006-01-02"), err)
+			}
+		}
+	}
+}
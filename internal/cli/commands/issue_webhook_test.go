@@ -0,0 +1,275 @@
+package commands
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssueWebhook(t *testing.T) {
+	const secret = "s3cret"
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	opened := `{"action":"opened","issue":{"number":7,"body":"### Today\n\nShip","state":"open",` +
+		`"created_at":"2024-01-15T09:00:00Z","user":{"login":"alice"},"labels":[{"name":"standup"}]}}`
+
+	tests := []struct {
+		name       string
+		event      string
+		body       string
+		signature  string
+		wantStatus int
+		wantQueued bool
+	}{
+		{name: "standup issue opened", event: "issues", body: opened, wantStatus: http.StatusAccepted, wantQueued: true},
+		{name: "bad signature", event: "issues", body: opened, signature: "sha256=00", wantStatus: http.StatusUnauthorized},
+		{name: "ping", event: "ping", body: `{}`, wantStatus: http.StatusOK},
+		{name: "other event", event: "release", body: `{}`, wantStatus: http.StatusOK},
+		{name: "issue without label", event: "issues",
+			body:       `{"action":"opened","issue":{"number":8,"state":"open","labels":[{"name":"bug"}]}}`,
+			wantStatus: http.StatusOK},
+		{name: "standup label added", event: "issues",
+			body:       `{"action":"labeled","label":{"name":"standup"},"issue":{"number":9,"state":"open","user":{"login":"bob"}}}`,
+			wantStatus: http.StatusAccepted, wantQueued: true},
+		{name: "closed issue", event: "issues",
+			body:       `{"action":"labeled","label":{"name":"standup"},"issue":{"number":10,"state":"closed"}}`,
+			wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := newGitHubWebhook(secret)
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(tt.body))
+			req.Header.Set("X-GitHub-Event", tt.event)
+			req.Header.Set("X-Hub-Signature-256", sign(tt.body))
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature-256", tt.signature)
+			}
+			rec := httptest.NewRecorder()
+
+			webhook.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if queued := len(webhook.queues.issues) == 1; queued != tt.wantQueued {
+				t.Errorf("queued = %v, want %v", queued, tt.wantQueued)
+			}
+		})
+	}
+
+	webhook := newGitHubWebhook(secret)
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(opened))
+	req.Header.Set("X-GitHub-Event", "issues")
+	req.Header.Set("X-Hub-Signature-256", sign(opened))
+	webhook.ServeHTTP(httptest.NewRecorder(), req)
+
+	issue := <-webhook.queues.issues
+	if issue.Number != 7 || issue.Author.Login != "alice" || issue.CreatedAt.Day() != 15 || !strings.Contains(issue.Body, "Ship") {
+		t.Errorf("queued issue = %+v", issue)
+	}
+}
+
+func TestIssueWebhookRoutesTeams(t *testing.T) {
+	const secret = "s3cret"
+	webhook := newGitHubWebhook(secret)
+	web := webhook.route("org/web-standups")
+
+	deliver := func(repository string) {
+		body := `{"action":"opened","repository":{"full_name":"` + repository + `"},` +
+			`"issue":{"number":7,"state":"open","labels":[{"name":"standup"}]}}`
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", "issues")
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		webhook.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	deliver("Org/Web-Standups")
+	if len(web.issues) != 1 || len(webhook.queues.issues) != 0 {
+		t.Errorf("team issue queued in team = %d, main = %d; want it in the team's queue", len(web.issues), len(webhook.queues.issues))
+	}
+	deliver("org/standups")
+	if len(web.issues) != 1 || len(webhook.queues.issues) != 1 {
+		t.Errorf("main issue queued in team = %d, main = %d; want it in the main queue", len(web.issues), len(webhook.queues.issues))
+	}
+}
+
+// deliverEvent sends a signed event to a webhook, returning the response
+func deliverEvent(webhook *githubWebhook, secret, event, body string) *httptest.ResponseRecorder {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	webhook.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGitHubWebhookDailyComments(t *testing.T) {
+	const secret = "s3cret"
+	event := func(title, state, login, userType, body string) string {
+		payload, _ := json.Marshal(map[string]any{
+			"action": "created",
+			"issue": map[string]any{"number": 12, "title": title, "state": state,
+				"labels": []map[string]string{{"name": "standup-daily"}}},
+			"comment": map[string]any{"body": body, "user": map[string]string{"login": login, "type": userType}},
+		})
+		return string(payload)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantQueued bool
+	}{
+		{name: "standup", body: event("Standups for 2024-01-15", "open", "alice", "User", "### Today\n\nShip"), wantQueued: true},
+		{name: "conversation", body: event("Standups for 2024-01-15", "open", "alice", "User", "Nice!")},
+		{name: "bot reply", body: event("Standups for 2024-01-15", "open", "standup-bot", "Bot", "/standup ### Today")},
+		{name: "closed issue", body: event("Standups for 2024-01-15", "closed", "alice", "User", "### Today\n\nShip")},
+		{name: "other issue", body: event("Flaky tests", "open", "alice", "User", "### Today\n\nShip")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := newGitHubWebhook(secret)
+			rec := deliverEvent(webhook, secret, "issue_comment", tt.body)
+			if queued := len(webhook.queues.comments) == 1; queued != tt.wantQueued {
+				t.Fatalf("queued = %v, want %v (%d %s)", queued, tt.wantQueued, rec.Code, rec.Body.String())
+			}
+			if tt.wantQueued {
+				comment := <-webhook.queues.comments
+				if comment.Issue != 12 || comment.Author != "alice" || comment.Date.Format("2006-01-02") != "2024-01-15" {
+					t.Errorf("queued comment = %+v", comment)
+				}
+			}
+		})
+	}
+}
+
+func TestGitHubWebhookPush(t *testing.T) {
+	const secret = "s3cret"
+	tests := []struct {
+		name       string
+		body       string
+		wantQueued bool
+	}{
+		{name: "daily branch", body: `{"ref":"refs/heads/standup/2024-01-15"}`, wantQueued: true},
+		{name: "base branch", body: `{"ref":"refs/heads/main"}`},
+		{name: "tag", body: `{"ref":"refs/tags/standup/2024-01-15"}`},
+		{name: "deleted branch", body: `{"ref":"refs/heads/standup/2024-01-15","deleted":true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := newGitHubWebhook(secret)
+			rec := deliverEvent(webhook, secret, "push", tt.body)
+			if queued := len(webhook.queues.refreshes) == 1; queued != tt.wantQueued {
+				t.Fatalf("queued = %v, want %v (%d %s)", queued, tt.wantQueued, rec.Code, rec.Body.String())
+			}
+			if tt.wantQueued {
+				if date := <-webhook.queues.refreshes; date.Format("2006-01-02") != "2024-01-15" {
+					t.Errorf("queued date = %v, want 2024-01-15", date)
+				}
+			}
+		})
+	}
+}
+
+func TestGitHubWebhookPullRequestMerged(t *testing.T) {
+	const secret = "s3cret"
+	merged := func(number int, ref string, wasMerged bool) string {
+		return fmt.Sprintf(`{"action":"closed","number":%d,"pull_request":{"merged":%v,`+
+			`"html_url":"https://github.com/org/standups/pull/%d","head":{"ref":"%s"}}}`, number, wasMerged, number, ref)
+	}
+
+	webhook := newGitHubWebhook(secret)
+	var announced []string
+	webhook.announce = func(number, url string, date time.Time) error {
+		announced = append(announced, number+" "+url+" "+date.Format("2006-01-02"))
+		return nil
+	}
+
+	if rec := deliverEvent(webhook, secret, "pull_request", merged(3, "standup/2024-01-15", true)); rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want %d (%s)", rec.Code, http.StatusAccepted, rec.Body.String())
+	}
+	deliverEvent(webhook, secret, "pull_request", merged(4, "standup/2024-01-16", false))
+	deliverEvent(webhook, secret, "pull_request", merged(5, "feature", true))
+
+	want := []string{"3 https://github.com/org/standups/pull/3 2024-01-15"}
+	if !reflect.DeepEqual(announced, want) {
+		t.Errorf("announced = %q, want %q", announced, want)
+	}
+	if len(webhook.queues.merges) != 1 {
+		t.Errorf("queued %d merges, want 1", len(webhook.queues.merges))
+	} else if date := <-webhook.queues.merges; date.Format("2006-01-02") != "2024-01-15" {
+		t.Errorf("queued digest for %v, want 2024-01-15", date)
+	}
+
+	webhook.announce = func(string, string, time.Time) error { return errors.New("slack is down") }
+	if rec := deliverEvent(webhook, secret, "pull_request", merged(6, "standup/2024-01-17", true)); rec.Code != http.StatusBadGateway {
+		t.Errorf("status when the announcement fails = %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+}
+
+func TestGitHubWebhookRejectsReplays(t *testing.T) {
+	const secret = "s3cret"
+	body := `{"ref":"refs/heads/standup/2024-01-15"}`
+	webhook := newGitHubWebhook(secret)
+
+	if rec := deliverEvent(webhook, secret, "push", body); rec.Code != http.StatusAccepted {
+		t.Fatalf("first delivery status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if rec := deliverEvent(webhook, secret, "push", body); rec.Code != http.StatusConflict {
+		t.Errorf("replayed delivery status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if len(webhook.queues.refreshes) != 1 {
+		t.Errorf("queued %d refreshes, want 1", len(webhook.queues.refreshes))
+	}
+
+	// A delivery turned away because the queue was full may be redelivered
+	for len(webhook.queues.refreshes) < webhookQueueSize {
+		webhook.queues.refreshes <- time.Time{}
+	}
+	full := `{"ref":"refs/heads/standup/2024-01-16"}`
+	if rec := deliverEvent(webhook, secret, "push", full); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status with a full queue = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	<-webhook.queues.refreshes
+	if rec := deliverEvent(webhook, secret, "push", full); rec.Code != http.StatusAccepted {
+		t.Errorf("redelivery status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+}
+
+func TestDeliveryLogForgetsOldDeliveries(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	log := newDeliveryLog(time.Hour)
+	log.now = func() time.Time { return now }
+
+	if !log.claim("a") || log.claim("a") {
+		t.Fatal("claim() should accept a delivery once")
+	}
+	now = now.Add(time.Hour)
+	if !log.claim("a") {
+		t.Error("claim() rejected a delivery older than the window")
+	}
+}
+
+func TestRunIssueWebhookRequiresSecret(t *testing.T) {
+	t.Setenv(WebhookSecretEnv, "")
+	if err := RunIssueWebhook(nil, ":0", false); err == nil || !strings.Contains(err.Error(), WebhookSecretEnv) {
+		t.Errorf("RunIssueWebhook() error = %v, want a missing secret error", err)
+	}
+}
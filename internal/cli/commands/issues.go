@@ -0,0 +1,273 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// RunIssueTemplate writes the standup issue form to the base branch of the
+// standup repository and pushes it
+func RunIssueTemplate(cfg *config.Config, force bool) error {
+	gitClient := standupbot.NewGitClient(cfg)
+
+	if err := standupbot.ValidateDirectEnvironment(gitClient, cfg); err != nil {
+		return err
+	}
+	if err := standupbot.CheckWorkTree(gitClient, cfg.LocalRepoPath, force, canPrompt()); err != nil {
+		return err
+	}
+
+	// GitHub only reads issue forms from the default branch
+	if err := checkoutBaseBranch(gitClient, cfg); err != nil {
+		return err
+	}
+
+	form, err := standupbot.NewManager(cfg).IssueForm()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(cfg.LocalRepoPath, standup.IssueFormPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(standup.IssueFormPath), err)
+	}
+	if err := os.WriteFile(path, form, 0644); err != nil {
+		return fmt.Errorf("failed to write issue form: %w", err)
+	}
+
+	err = gitClient.CommitAndPush(cfg.LocalRepoPath, "Add standup issue form", path)
+	if errors.Is(err, git.ErrNoChangesToCommit) {
+		fmt.Println("✅ The standup issue form is already up to date.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Added %s. Teammates can now submit standups with New issue > Standup,\n", standup.IssueFormPath)
+	fmt.Println("including from the GitHub mobile app. Run 'standup-bot issues import' in CI to record them.")
+	return nil
+}
+
+// RunIssueImport records the standups submitted through the issue form:
+// each open issue is committed as its author's entry and closed with a
+// comment. Issues that can't be read are closed with the reason, so the
+// submitter can try again. Meant to run in CI.
+func RunIssueImport(cfg *config.Config, force bool) error {
+	gitClient := standupbot.NewGitClient(cfg)
+
+	provider, err := standupbot.ValidateEnvironment(gitClient, cfg)
+	if err != nil {
+		return err
+	}
+	if kind := provider.Name(); kind != "GitHub" {
+		return fmt.Errorf("issue form submissions need GitHub, but the repository is on %s", kind)
+	}
+	if err := standupbot.CheckWorkTree(gitClient, cfg.LocalRepoPath, force, false); err != nil {
+		return err
+	}
+	if err := checkoutBaseBranch(gitClient, cfg); err != nil {
+		return err
+	}
+
+	issues, err := gitClient.ListIssues(cfg.LocalRepoPath, standup.IssueLabel)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Println("No standup issues to import.")
+		return nil
+	}
+
+	manager := standupbot.NewManager(cfg)
+	recorded := 0
+	for _, issue := range issues {
+		ok, err := recordIssue(gitClient, cfg, manager, issue)
+		if err != nil {
+			return err
+		}
+		if ok {
+			recorded++
+		}
+	}
+
+	fmt.Printf("Imported %d of %d standup issue(s).\n", recorded, len(issues))
+	return nil
+}
+
+// RunIssueDaily opens today's daily issue, which teammates comment their
+// standups on, and closes the daily issues of earlier days. Meant to run on
+// a schedule in CI.
+func RunIssueDaily(cfg *config.Config) error {
+	gitClient := standupbot.NewGitClient(cfg)
+
+	provider, err := standupbot.ValidateEnvironment(gitClient, cfg)
+	if err != nil {
+		return err
+	}
+	if kind := provider.Name(); kind != "GitHub" {
+		return fmt.Errorf("daily issues need GitHub, but the repository is on %s", kind)
+	}
+
+	issues, err := gitClient.ListIssues(cfg.LocalRepoPath, standup.DailyIssueLabel)
+	if err != nil {
+		return err
+	}
+	today := cfg.Today().Format("2006-01-02")
+	opened := false
+	for _, issue := range issues {
+		date, ok := standup.ParseDailyIssueTitle(issue.Title)
+		if !ok {
+			continue
+		}
+		if day := date.Format("2006-01-02"); day >= today {
+			opened = opened || day == today
+			continue
+		}
+		comment := fmt.Sprintf("Closing, since comments are no longer recorded here. Today's standups go in the issue for %s.", today)
+		if err := gitClient.CloseIssue(cfg.LocalRepoPath, issue.Number, comment); err != nil {
+			return err
+		}
+	}
+	if opened {
+		fmt.Println("✅ Today's daily issue is already open.")
+		return nil
+	}
+
+	body := standupbot.NewManager(cfg).DailyIssueBody(cfg.Today())
+	if err := gitClient.CreateIssue(cfg.LocalRepoPath, standup.DailyIssueTitle(cfg.Today()), body, standup.DailyIssueLabel); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Opened %q. Teammates can comment their standups on it.\n", standup.DailyIssueTitle(cfg.Today()))
+	return nil
+}
+
+// dailyComment is a comment on a daily issue
+type dailyComment struct {
+	Issue  int
+	Date   time.Time
+	Author string
+	Body   string
+}
+
+// recordDailyComment records the standup in a comment on a daily issue for
+// the issue's day, through the day's pull request, and replies with the
+// outcome. A comment that can't be read gets the reason in the reply, so
+// the submitter can post a corrected one.
+func recordDailyComment(ctx context.Context, gitClient *git.Client, cfg *config.Config, comment dailyComment, force bool) error {
+	name, entry, err := commentEntry(standupbot.NewManager(cfg), cfg, comment.Body)
+	if err == nil && name == "" {
+		name = comment.Author
+	}
+	if err == nil {
+		if _, nameErr := types.NewUserName(name); nameErr != nil {
+			err = fmt.Errorf("invalid name '%s': %w", name, nameErr)
+		}
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Comment on issue #%d: %v\n", comment.Issue, err)
+		reply := fmt.Sprintf("@%s Couldn't record this standup: %v\n\nPlease post a corrected comment.", comment.Author, err)
+		return gitClient.CommentOnIssue(cfg.LocalRepoPath, comment.Issue, reply)
+	}
+	entry.Date = comment.Date
+
+	submitter := *cfg
+	submitter.Name = name
+	result, err := requestBot(ctx, &submitter).Submit(entry, standupbot.Options{Force: force})
+	metrics.recordSubmission(name, err)
+	if err != nil {
+		reply := fmt.Sprintf("@%s Couldn't record this standup, please try again later: %v", comment.Author, err)
+		if replyErr := gitClient.CommentOnIssue(cfg.LocalRepoPath, comment.Issue, reply); replyErr != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", replyErr)
+		}
+		return fmt.Errorf("failed to record standup from a comment on issue #%d: %w", comment.Issue, err)
+	}
+
+	reply := fmt.Sprintf("Recorded %s's standup for %s.", name, entry.Date.Format("2006-01-02"))
+	if result.PR != nil {
+		reply = fmt.Sprintf("Recorded %s's standup for %s in #%s.", name, entry.Date.Format("2006-01-02"), result.PR.Number)
+	}
+	if result.AlreadySubmitted {
+		reply = fmt.Sprintf("%s's standup for %s was already recorded; nothing changed.", name, entry.Date.Format("2006-01-02"))
+	}
+	if err := gitClient.CommentOnIssue(cfg.LocalRepoPath, comment.Issue, reply); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Issue #%d: recorded %s's standup from a comment\n", comment.Issue, name)
+	return nil
+}
+
+// recordIssue commits the standup in an issue and closes the issue with a
+// confirmation. An issue that can't be read is closed with the reason and
+// reported as not recorded. When committing fails the issue stays open, so
+// the next run retries it.
+func recordIssue(gitClient *git.Client, cfg *config.Config, manager *standup.Manager, issue git.Issue) (bool, error) {
+	name, entry, err := issueEntry(manager, issue, cfg.DayCutoffHour)
+	if err != nil {
+		fmt.Printf("⚠️  Issue #%d: %v\n", issue.Number, err)
+		comment := fmt.Sprintf("Couldn't record this standup: %v\n\nPlease submit a new one.", err)
+		return false, gitClient.CloseIssue(cfg.LocalRepoPath, issue.Number, comment)
+	}
+
+	standupbot.LinkJiraIssues(cfg, entry)
+	if err := manager.SaveEntry(entry, name); err != nil {
+		return false, fmt.Errorf("failed to save standup from issue #%d: %w", issue.Number, err)
+	}
+	commitMessage := standupbot.CommitMessage(cfg, manager, entry, name, true) + fmt.Sprintf("\n\nSubmitted in #%d", issue.Number)
+	err = gitClient.CommitAndPush(cfg.LocalRepoPath, commitMessage, manager.UserPath(name))
+	if errors.Is(err, git.ErrNoChangesToCommit) {
+		err = nil
+	}
+	metrics.recordSubmission(name, err)
+	standupbot.CountSubmission(err)
+	if err != nil {
+		return false, fmt.Errorf("failed to record standup from issue #%d: %w", issue.Number, err)
+	}
+	standupbot.EscalateBlockers(cfg, gitClient, manager, name, entry)
+
+	comment := fmt.Sprintf("Recorded %s's standup for %s.", name, entry.Date.Format("2006-01-02"))
+	if err := gitClient.CloseIssue(cfg.LocalRepoPath, issue.Number, comment); err != nil {
+		return false, err
+	}
+	fmt.Printf("✅ Issue #%d: recorded %s's standup\n", issue.Number, name)
+	return true, nil
+}
+
+// issueEntry reads the entry from an issue created with the standup form.
+// The entry is dated by when the issue was opened, and belongs to the Name
+// given in the form or else the issue's author.
+func issueEntry(manager *standup.Manager, issue git.Issue, cutoffHour int) (string, *standup.Entry, error) {
+	name, entry, err := manager.ParseIssueForm(issue.Body)
+	if err != nil {
+		return "", nil, err
+	}
+	if name == "" {
+		name = issue.Author.Login
+	}
+	if _, err := types.NewUserName(name); err != nil {
+		return "", nil, fmt.Errorf("invalid name '%s': %w", name, err)
+	}
+
+	entry.Date = types.StandupDay(issue.CreatedAt.Local(), cutoffHour)
+	return name, entry, nil
+}
+
+// checkoutBaseBranch switches to the base branch and syncs it
+func checkoutBaseBranch(gitClient *git.Client, cfg *config.Config) error {
+	if err := gitClient.SwitchToBranch(cfg.LocalRepoPath, cfg.GetBaseBranch()); err != nil {
+		return fmt.Errorf("failed to switch to %s branch: %w", cfg.GetBaseBranch(), err)
+	}
+	if err := gitClient.SyncRepository(cfg.LocalRepoPath, cfg.GetBaseBranch()); err != nil {
+		return fmt.Errorf("failed to sync repository: %w", err)
+	}
+	return nil
+}
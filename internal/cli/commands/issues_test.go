@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestIssueEntry(t *testing.T) {
+	manager := standup.NewManager(t.TempDir())
+
+	issue := git.Issue{Number: 3, Body: "### Today\n\nShip billing\n", CreatedAt: time.Date(2024, 1, 16, 1, 30, 0, 0, time.Local)}
+	issue.Author.Login = "alice"
+
+	// Opened before the 4am cutoff, so it counts for the previous day
+	name, entry, err := issueEntry(manager, issue, 4)
+	if err != nil {
+		t.Fatalf("issueEntry() error = %v", err)
+	}
+	if name != "alice" || entry.Date.Format("2006-01-02") != "2024-01-15" {
+		t.Errorf("issueEntry() = %s on %s, want alice on 2024-01-15", name, entry.Date.Format("2006-01-02"))
+	}
+
+	issue.Body += "\n### Name\n\n../../etc\n"
+	if _, _, err := issueEntry(manager, issue, 0); err == nil {
+		t.Error("issueEntry() should reject names that aren't safe file names")
+	}
+}
+
+// recordingRunner records the commands it is asked to run
+type recordingRunner struct {
+	commands []string
+}
+
+func (r *recordingRunner) Run(name string, args ...string) ([]byte, error) {
+	r.commands = append(r.commands, name+" "+strings.Join(args, " "))
+	return nil, nil
+}
+
+func (r *recordingRunner) RunInDir(dir, name string, args ...string) ([]byte, error) {
+	return r.Run(name, args...)
+}
+
+func TestRecordDailyCommentRepliesWithProblems(t *testing.T) {
+	runner := &recordingRunner{}
+	cfg := &config.Config{LocalRepoPath: t.TempDir()}
+	comment := dailyComment{Issue: 12, Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local), Author: "alice", Body: "### Blockers\n\nNone"}
+
+	if err := recordDailyComment(context.Background(), git.NewClientWithRunner(runner), cfg, comment, false); err != nil {
+		t.Fatalf("recordDailyComment() error = %v", err)
+	}
+	if len(runner.commands) != 1 || !strings.HasPrefix(runner.commands[0], "gh issue comment 12 --body @alice Couldn't record this standup") {
+		t.Errorf("ran %q, want a reply explaining the problem", runner.commands)
+	}
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/render"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// RunLeaderboard ranks everyone by their current streak of working days with
+// a standup, as a table, JSON or markdown. Streaks come from the repository,
+// so every teammate sees the same board.
+func RunLeaderboard(cfg *config.Config, format string) error {
+	switch format {
+	case "", statsTable, statsJSON, statsYAML, statsMarkdown:
+	default:
+		return fmt.Errorf("invalid --format '%s': expected '%s', '%s', '%s' or '%s'", format, statsTable, statsJSON, statsYAML, statsMarkdown)
+	}
+
+	manager := standupbot.NewManager(cfg)
+	entries, err := manager.LoadAllEntries()
+	if err != nil {
+		return err
+	}
+	absences, err := manager.TeamAbsences()
+	if err != nil {
+		return err
+	}
+
+	stats := standup.ComputeStats(entries, absences, time.Time{}, time.Time{}, cfg.Today())
+	ranked := standup.RankStreaks(stats.Users)
+
+	switch format {
+	case statsJSON, statsYAML:
+		return render.Print(os.Stdout, render.Format(format), ranked)
+	case statsMarkdown:
+		fmt.Print(formatLeaderboardMarkdown(ranked))
+	default:
+		writeLeaderboardTable(os.Stdout, ranked)
+	}
+	return nil
+}
+
+// writeLeaderboardTable prints one row per user, best streak first
+func writeLeaderboardTable(out io.Writer, ranked []standup.Stats) {
+	if len(ranked) == 0 {
+		fmt.Fprintln(out, "No standups yet.")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tUSER\tSTREAK\tLONGEST\tSTANDUPS")
+	for i, s := range ranked {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\n", i+1, s.User, s.CurrentStreak, s.LongestStreak, s.Days)
+	}
+	w.Flush()
+}
+
+// formatLeaderboardMarkdown renders the leaderboard as a markdown table
+func formatLeaderboardMarkdown(ranked []standup.Stats) string {
+	var b strings.Builder
+	b.WriteString("## Standup Streaks\n\n")
+	if len(ranked) == 0 {
+		b.WriteString("No standups yet.\n")
+		return b.String()
+	}
+
+	b.WriteString("| Rank | User | Streak | Longest | Standups |\n")
+	b.WriteString("|---:|---|---:|---:|---:|\n")
+	for i, s := range ranked {
+		fmt.Fprintf(&b, "| %d | %s | %d | %d | %d |\n", i+1, s.User, s.CurrentStreak, s.LongestStreak, s.Days)
+	}
+	return b.String()
+}
+
+// streakMessage celebrates a streak of two or more working days, or returns
+// "" for shorter ones
+func streakMessage(current, longest int) string {
+	if current < 2 {
+		return ""
+	}
+	if current >= longest {
+		return fmt.Sprintf("🔥 %d working days in a row, your longest streak yet!", current)
+	}
+	return fmt.Sprintf("🔥 %d working days in a row (longest: %d)", current, longest)
+}
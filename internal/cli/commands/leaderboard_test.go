@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestFormatLeaderboardMarkdown(t *testing.T) {
+	md := formatLeaderboardMarkdown([]standup.Stats{
+		{User: "bob", Days: 20, CurrentStreak: 5, LongestStreak: 8},
+		{User: "alice", Days: 12, CurrentStreak: 0, LongestStreak: 6},
+	})
+	for _, want := range []string{"| 1 | bob | 5 | 8 | 20 |", "| 2 | alice | 0 | 6 | 12 |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	if md := formatLeaderboardMarkdown(nil); !strings.Contains(md, "No standups yet.") {
+		t.Errorf("empty leaderboard = %q", md)
+	}
+}
+
+func TestStreakMessage(t *testing.T) {
+	tests := []struct {
+		current, longest int
+		want             string
+	}{
+		{current: 1, longest: 5, want: ""},
+		{current: 3, longest: 5, want: "🔥 3 working days in a row (longest: 5)"},
+		{current: 5, longest: 5, want: "🔥 5 working days in a row, your longest streak yet!"},
+	}
+	for _, tt := range tests {
+		if got := streakMessage(tt.current, tt.longest); got != tt.want {
+			t.Errorf("streakMessage(%d, %d) = %q, want %q", tt.current, tt.longest, got, tt.want)
+		}
+	}
+}
+
+func TestRunLeaderboardInvalidFormat(t *testing.T) {
+	err := RunLeaderboard(&config.Config{}, "xml")
+	if err == nil || !strings.Contains(err.Error(), "invalid --format") {
+		t.Errorf("RunLeaderboard() error = %v, want invalid --format", err)
+	}
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+// RunLog prints the transcript of the last run with lastRun, or lists the
+// recent runs, newest first, with the command each ran and whether it failed
+func RunLog(cfgManager *config.Manager, lastRun bool) error {
+	return writeLog(os.Stdout, cfgManager, lastRun)
+}
+
+// writeLog writes the last run's transcript or the list of recent runs to w
+func writeLog(w io.Writer, cfgManager *config.Manager, lastRun bool) error {
+	logsDir := filepath.Join(cfgManager.ConfigDir(), logsDirName)
+	transcripts := recentTranscripts(logsDir, maxTranscripts)
+	if len(transcripts) == 0 {
+		return fmt.Errorf("no runs recorded yet in %s", logsDir)
+	}
+
+	if lastRun {
+		data, err := os.ReadFile(transcripts[0])
+		if err != nil {
+			return fmt.Errorf("failed to read transcript: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	for _, path := range transcripts {
+		command, failed := summarizeTranscript(path)
+		status := "ok"
+		if failed {
+			status = "failed"
+		}
+		fmt.Fprintf(w, "%-32s %-6s %s\n", filepath.Base(path), status, command)
+	}
+	fmt.Fprintf(w, "\nTranscripts are in %s; 'standup-bot log --last-run' prints the newest.\n", logsDir)
+	return nil
+}
+
+// summarizeTranscript returns the command line a transcript recorded and
+// whether the run ended in an error
+func summarizeTranscript(path string) (string, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	var command string
+	var failed bool
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if rest, ok := strings.CutPrefix(line, "# "); ok && command == "" {
+			command = rest
+		}
+		if fields := strings.Fields(line); len(fields) > 1 && fields[1] == "ERROR" {
+			failed = true
+		}
+	}
+	return command, failed
+}
@@ -0,0 +1,357 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/metoro-io/mcp-golang/transport"
+)
+
+// MCPTokenEnv holds the bearer token that HTTP clients of the MCP server must send
+const MCPTokenEnv = "STANDUP_BOT_MCP_TOKEN"
+
+// maxMCPMessageSize limits the size of a posted JSON-RPC message
+const maxMCPMessageSize = 1 << 20
+
+// mcpHTTPTransport serves MCP over HTTP. Clients either POST JSON-RPC
+// messages to /mcp and get the response in the reply, or open an SSE stream
+// at /sse and POST to the endpoint it announces, receiving responses as
+// events. Every request must carry the bearer token.
+type mcpHTTPTransport struct {
+	addr   string
+	token  string
+	server *http.Server
+
+	mu           sync.Mutex
+	handler      func(ctx context.Context, message *transport.BaseJsonRpcMessage)
+	errorHandler func(error)
+	closeHandler func()
+
+	// Requests from all clients share the server, so each is given a unique
+	// id while pending and the client's own id is restored on the response
+	nextID   transport.RequestId
+	pending  map[transport.RequestId]pendingMCPRequest
+	sessions map[string]*sseSession
+}
+
+// pendingMCPRequest is a request waiting for the server's response
+type pendingMCPRequest struct {
+	clientID transport.RequestId
+	reply    chan *transport.BaseJsonRpcMessage
+}
+
+// sseSession is an open SSE stream
+type sseSession struct {
+	events chan []byte
+	done   chan struct{}
+}
+
+// newMCPHTTPTransport creates a transport listening on addr
+func newMCPHTTPTransport(addr, token string) *mcpHTTPTransport {
+	return &mcpHTTPTransport{
+		addr:     addr,
+		token:    token,
+		pending:  make(map[transport.RequestId]pendingMCPRequest),
+		sessions: make(map[string]*sseSession),
+	}
+}
+
+// Start serves HTTP until the transport is closed
+func (t *mcpHTTPTransport) Start(ctx context.Context) error {
+	t.mu.Lock()
+	t.server = &http.Server{Addr: t.addr, Handler: t.routes()}
+	server := t.server
+	t.mu.Unlock()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Send delivers a response to the client waiting for it. Server-initiated
+// messages are dropped, since plain HTTP clients have no channel for them.
+func (t *mcpHTTPTransport) Send(ctx context.Context, message *transport.BaseJsonRpcMessage) error {
+	var id transport.RequestId
+	switch message.Type {
+	case transport.BaseMessageTypeJSONRPCResponseType:
+		id = message.JsonRpcResponse.Id
+	case transport.BaseMessageTypeJSONRPCErrorType:
+		id = message.JsonRpcError.Id
+	default:
+		return nil
+	}
+
+	t.mu.Lock()
+	request, ok := t.pending[id]
+	delete(t.pending, id)
+	t.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no pending request with id %d", id)
+	}
+
+	if message.JsonRpcResponse != nil {
+		message.JsonRpcResponse.Id = request.clientID
+	}
+	if message.JsonRpcError != nil {
+		message.JsonRpcError.Id = request.clientID
+	}
+	request.reply <- message
+	return nil
+}
+
+// Close stops the HTTP server
+func (t *mcpHTTPTransport) Close() error {
+	t.mu.Lock()
+	server, closeHandler := t.server, t.closeHandler
+	t.mu.Unlock()
+
+	var err error
+	if server != nil {
+		err = server.Close()
+	}
+	if closeHandler != nil {
+		closeHandler()
+	}
+	return err
+}
+
+// SetCloseHandler sets the callback for when the transport is closed
+func (t *mcpHTTPTransport) SetCloseHandler(handler func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closeHandler = handler
+}
+
+// SetErrorHandler sets the callback for transport errors
+func (t *mcpHTTPTransport) SetErrorHandler(handler func(error)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errorHandler = handler
+}
+
+// SetMessageHandler sets the callback for received messages
+func (t *mcpHTTPTransport) SetMessageHandler(handler func(ctx context.Context, message *transport.BaseJsonRpcMessage)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handler = handler
+}
+
+// routes returns the HTTP handler. Everything but the health probes needs
+// the bearer token.
+func (t *mcpHTTPTransport) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", t.handlePost)
+	mux.HandleFunc("/sse", t.handleSSE)
+	mux.HandleFunc("/messages", t.handleSessionPost)
+	mux.Handle("/metrics", metrics)
+
+	public := http.NewServeMux()
+	newHealthProbes().register(public)
+	public.Handle("/", t.authorize(mux))
+	return public
+}
+
+// authorize rejects requests without the bearer token
+func (t *mcpHTTPTransport) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(t.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handlePost answers a JSON-RPC message in the HTTP response
+func (t *mcpHTTPTransport) handlePost(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMCPMessageSize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	reply, err := t.receive(r.Context(), body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if reply == nil {
+		// Notifications have no response
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	select {
+	case message := <-reply:
+		data, err := json.Marshal(message)
+		if err != nil {
+			t.reportError(fmt.Errorf("failed to marshal MCP response: %w", err))
+			http.Error(w, "failed to marshal response", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	case <-r.Context().Done():
+	}
+}
+
+// handleSSE opens an event stream. The first event announces the endpoint to
+// post messages to; responses follow as "message" events.
+func (t *mcpHTTPTransport) handleSSE(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		http.Error(w, "failed to create session", http.StatusInternalServerError)
+		return
+	}
+	session := &sseSession{events: make(chan []byte, 16), done: make(chan struct{})}
+
+	t.mu.Lock()
+	t.sessions[id] = session
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.sessions, id)
+		t.mu.Unlock()
+		close(session.done)
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprintf(w, "event: endpoint\ndata: /messages?sessionId=%s\n\n", id)
+	flusher.Flush()
+
+	for {
+		select {
+		case data := <-session.events:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleSessionPost accepts a message for an SSE session; its response is
+// sent on the session's stream
+func (t *mcpHTTPTransport) handleSessionPost(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	t.mu.Lock()
+	session, ok := t.sessions[r.URL.Query().Get("sessionId")]
+	t.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMCPMessageSize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	// The response outlives this HTTP request, so don't tie it to its context
+	reply, err := t.receive(context.Background(), body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if reply != nil {
+		go func() {
+			message := <-reply
+			data, err := json.Marshal(message)
+			if err != nil {
+				t.reportError(fmt.Errorf("failed to marshal MCP response: %w", err))
+				return
+			}
+			select {
+			case session.events <- data:
+			case <-session.done:
+			}
+		}()
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// receive hands a message to the server. For requests it returns a channel
+// that receives the response; notifications return nil.
+func (t *mcpHTTPTransport) receive(ctx context.Context, body []byte) (<-chan *transport.BaseJsonRpcMessage, error) {
+	t.mu.Lock()
+	handler := t.handler
+	t.mu.Unlock()
+	if handler == nil {
+		return nil, fmt.Errorf("server is not ready")
+	}
+
+	var request transport.BaseJSONRPCRequest
+	if err := json.Unmarshal(body, &request); err == nil {
+		reply := make(chan *transport.BaseJsonRpcMessage, 1)
+
+		t.mu.Lock()
+		t.nextID++
+		id := t.nextID
+		t.pending[id] = pendingMCPRequest{clientID: request.Id, reply: reply}
+		t.mu.Unlock()
+
+		request.Id = id
+		handler(ctx, transport.NewBaseMessageRequest(&request))
+		return reply, nil
+	}
+
+	var notification transport.BaseJSONRPCNotification
+	if err := json.Unmarshal(body, &notification); err == nil {
+		handler(ctx, transport.NewBaseMessageNotification(&notification))
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("invalid JSON-RPC message")
+}
+
+// reportError passes an error to the server's error handler
+func (t *mcpHTTPTransport) reportError(err error) {
+	t.mu.Lock()
+	handler := t.errorHandler
+	t.mu.Unlock()
+	if handler != nil {
+		handler(err)
+	}
+}
+
+// newSessionID returns a random SSE session id
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
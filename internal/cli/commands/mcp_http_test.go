@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/metoro-io/mcp-golang/transport"
+)
+
+// newEchoTransport returns a transport whose server answers every request
+// with its method name
+func newEchoTransport(t *testing.T) (*mcpHTTPTransport, *httptest.Server) {
+	t.Helper()
+
+	tr := newMCPHTTPTransport("", "secret")
+	tr.SetMessageHandler(func(ctx context.Context, message *transport.BaseJsonRpcMessage) {
+		if message.Type != transport.BaseMessageTypeJSONRPCRequestType {
+			return
+		}
+		result, _ := json.Marshal(message.JsonRpcRequest.Method)
+		go tr.Send(ctx, transport.NewBaseMessageResponse(&transport.BaseJSONRPCResponse{
+			Id:      message.JsonRpcRequest.Id,
+			Jsonrpc: "2.0",
+			Result:  result,
+		}))
+	})
+
+	server := httptest.NewServer(tr.routes())
+	t.Cleanup(server.Close)
+	return tr, server
+}
+
+func mcpRequest(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestMCPHTTPAuthorization(t *testing.T) {
+	_, server := newEchoTransport(t)
+
+	for _, token := range []string{"", "wrong"} {
+		resp := mcpRequest(t, http.MethodPost, server.URL+"/mcp", token, `{"jsonrpc": "2.0", "id": 1, "method": "ping"}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("token %q: status = %d, want 401", token, resp.StatusCode)
+		}
+	}
+}
+
+func TestMCPHTTPPost(t *testing.T) {
+	tr, server := newEchoTransport(t)
+
+	resp := mcpRequest(t, http.MethodPost, server.URL+"/mcp", "secret", `{"jsonrpc": "2.0", "id": 7, "method": "tools/list"}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var response struct {
+		ID     int    `json:"id"`
+		Result string `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.ID != 7 || response.Result != "tools/list" {
+		t.Errorf("response = %+v, want the client's id and the echoed method", response)
+	}
+	tr.mu.Lock()
+	pending := len(tr.pending)
+	tr.mu.Unlock()
+	if pending != 0 {
+		t.Errorf("%d requests still pending", pending)
+	}
+
+	// Notifications are accepted without a response
+	notify := mcpRequest(t, http.MethodPost, server.URL+"/mcp", "secret", `{"jsonrpc": "2.0", "method": "notifications/initialized"}`)
+	notify.Body.Close()
+	if notify.StatusCode != http.StatusAccepted {
+		t.Errorf("notification status = %d, want 202", notify.StatusCode)
+	}
+}
+
+func TestMCPHTTPSSE(t *testing.T) {
+	_, server := newEchoTransport(t)
+
+	stream := mcpRequest(t, http.MethodGet, server.URL+"/sse", "secret", "")
+	defer stream.Body.Close()
+	events := bufio.NewReader(stream.Body)
+
+	// The first event announces where to post messages
+	endpoint := readSSEData(t, events, "endpoint")
+	if !strings.HasPrefix(endpoint, "/messages?sessionId=") {
+		t.Fatalf("endpoint = %q", endpoint)
+	}
+
+	post := mcpRequest(t, http.MethodPost, server.URL+endpoint, "secret", `{"jsonrpc": "2.0", "id": 3, "method": "ping"}`)
+	io.Copy(io.Discard, post.Body)
+	post.Body.Close()
+	if post.StatusCode != http.StatusAccepted {
+		t.Fatalf("post status = %d, want 202", post.StatusCode)
+	}
+
+	message := readSSEData(t, events, "message")
+	if !strings.Contains(message, `"id":3`) || !strings.Contains(message, `"result":"ping"`) {
+		t.Errorf("message = %s", message)
+	}
+
+	unknown := mcpRequest(t, http.MethodPost, server.URL+"/messages?sessionId=nope", "secret", `{"jsonrpc": "2.0", "id": 1, "method": "ping"}`)
+	unknown.Body.Close()
+	if unknown.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want 404", unknown.StatusCode)
+	}
+}
+
+// readSSEData reads the next event, which must have the given name, and
+// returns its data
+func readSSEData(t *testing.T, r *bufio.Reader, event string) string {
+	t.Helper()
+
+	var name, data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if name != event {
+				t.Fatalf("event = %q, want %q", name, event)
+			}
+			return data
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestNewMCPTransport(t *testing.T) {
+	if _, err := newMCPTransport(MCPServerOptions{}); err != nil {
+		t.Errorf("stdio transport error = %v", err)
+	}
+
+	t.Setenv(MCPTokenEnv, "")
+	if _, err := newMCPTransport(MCPServerOptions{Transport: "http", Addr: ":0"}); err == nil {
+		t.Error("http transport should require a token")
+	}
+
+	t.Setenv(MCPTokenEnv, "secret")
+	if _, err := newMCPTransport(MCPServerOptions{Transport: "http", Addr: ":0"}); err != nil {
+		t.Errorf("http transport error = %v", err)
+	}
+
+	if _, err := newMCPTransport(MCPServerOptions{Transport: "websocket"}); err == nil {
+		t.Error("unknown transports should be rejected")
+	}
+}
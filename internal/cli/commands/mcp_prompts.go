@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// DailyStandupInterviewArgs represents arguments for the daily_standup_interview prompt
+type DailyStandupInterviewArgs struct {
+	Team string `json:"team" jsonschema:"description=Team whose standup repository to use (default: the main repository)"`
+}
+
+// WeeklySummaryArgs represents arguments for the weekly_summary prompt
+type WeeklySummaryArgs struct {
+	Since string `json:"since" jsonschema:"description=First day to summarize in YYYY-MM-DD format (default: 6 days before today)"`
+	Team  string `json:"team" jsonschema:"description=Team whose standup repository to use (default: the main repository)"`
+}
+
+// registerMCPPrompts registers the prompts that walk an assistant through
+// standup workflows
+func registerMCPPrompts(server *mcp.Server) error {
+	if err := server.RegisterPrompt(
+		"daily_standup_interview",
+		"Interview the user about their day, then submit their standup",
+		handleDailyStandupInterview,
+	); err != nil {
+		return fmt.Errorf("failed to register daily_standup_interview prompt: %w", err)
+	}
+
+	if err := server.RegisterPrompt(
+		"weekly_summary",
+		"Summarize the user's standups for the past week",
+		handleWeeklySummary,
+	); err != nil {
+		return fmt.Errorf("failed to register weekly_summary prompt: %w", err)
+	}
+
+	return nil
+}
+
+// handleDailyStandupInterview handles the daily_standup_interview prompt
+func handleDailyStandupInterview(args DailyStandupInterviewArgs) (*mcp.PromptResponse, error) {
+	cfg, err := loadMCPConfig(args.Team)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := standupbot.NewManager(cfg).LoadEntries(cfg.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load standups: %w", err)
+	}
+
+	text := dailyStandupInterviewPrompt(cfg.Name, previousEntry(entries, cfg.Today()))
+	return mcp.NewPromptResponse(
+		"Daily standup interview",
+		mcp.NewPromptMessage(mcp.NewTextContent(text), mcp.RoleUser),
+	), nil
+}
+
+// handleWeeklySummary handles the weekly_summary prompt
+func handleWeeklySummary(args WeeklySummaryArgs) (*mcp.PromptResponse, error) {
+	cfg, err := loadMCPConfig(args.Team)
+	if err != nil {
+		return nil, err
+	}
+
+	today := cfg.Today()
+	since, err := parseOptionalDate(args.Since)
+	if err != nil {
+		return nil, fmt.Errorf("invalid since date: %w", err)
+	}
+	if since.IsZero() {
+		since = today.AddDate(0, 0, -6)
+	}
+
+	entries, err := standupbot.NewManager(cfg).LoadEntries(cfg.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load standups: %w", err)
+	}
+
+	text := weeklySummaryPrompt(cfg.Name, entries, since, today)
+	return mcp.NewPromptResponse(
+		"Weekly summary",
+		mcp.NewPromptMessage(mcp.NewTextContent(text), mcp.RoleUser),
+	), nil
+}
+
+// loadMCPConfig loads the configuration for an MCP request, routed to the
+// standup repository of team when one is named
+func loadMCPConfig(team string) (*config.Config, error) {
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfg, err := cfgManager.LoadTeam(team)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// previousEntry returns the most recent entry before today, if any
+func previousEntry(entries []standup.Entry, today time.Time) *standup.Entry {
+	var previous *standup.Entry
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Date.Format("2006-01-02") >= today.Format("2006-01-02") {
+			continue
+		}
+		if previous == nil || entry.Date.After(previous.Date) {
+			previous = entry
+		}
+	}
+	return previous
+}
+
+// dailyStandupInterviewPrompt builds the instructions for collecting a
+// standup conversationally. The previous entry's plans give the assistant
+// something to follow up on.
+func dailyStandupInterviewPrompt(userName string, previous *standup.Entry) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Help %s write today's standup by interviewing them, one question at a time.\n\n", userName)
+
+	if previous != nil && len(previous.Today) > 0 {
+		fmt.Fprintf(&b, "In their last standup (%s) they planned to:\n", previous.Date.Format("2006-01-02"))
+		for _, item := range previous.Today {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+		b.WriteString("\nStart by asking how those went.\n\n")
+	}
+
+	b.WriteString(`1. Ask what they got done since their last standup.
+2. Ask what they plan to work on today.
+3. Ask whether anything is blocking them.
+
+Keep each item short and in their words. Don't invent work they didn't mention.
+
+Then call submit_standup with yesterday, today and blockers (use "None" if
+nothing is blocking them). It returns a draft: show it to them and only call
+submit_standup again with confirm=true once they approve it. If they already
+submitted today, use update_standup to add to their standup instead.
+`)
+
+	return b.String()
+}
+
+// weeklySummaryPrompt builds the instructions for summarizing a user's
+// standups between since and until, with the standups included
+func weeklySummaryPrompt(userName string, entries []standup.Entry, since, until time.Time) string {
+	var b strings.Builder
+
+	from, to := since.Format("2006-01-02"), until.Format("2006-01-02")
+	fmt.Fprintf(&b, "Summarize %s's standups from %s to %s for a weekly update.\n\n", userName, from, to)
+
+	// Entries are newest first; the summary reads better oldest first
+	var days []string
+	for i := len(entries) - 1; i >= 0; i-- {
+		day := entries[i].Date.Format("2006-01-02")
+		if day < from || day > to {
+			continue
+		}
+		days = append(days, fmt.Sprintf("## %s\n\n%s", day, standupbot.FormatSlackEntry(standup.MarkdownEntry(&entries[i]))))
+	}
+
+	if len(days) == 0 {
+		b.WriteString("They have no standups in this period. Tell them so and offer to start today's with the daily_standup_interview prompt.\n")
+		return b.String()
+	}
+
+	b.WriteString(`Group the work by theme or project, lead with what shipped, and call out
+blockers that are still open. Only use what's in the standups below.
+
+`)
+	b.WriteString(strings.Join(days, "\n\n"))
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	mcp "github.com/metoro-io/mcp-golang"
+	"github.com/metoro-io/mcp-golang/transport/stdio"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestRegisterMCPPrompts(t *testing.T) {
+	server := mcp.NewServer(stdio.NewStdioServerTransport())
+	if err := registerMCPPrompts(server); err != nil {
+		t.Fatalf("registerMCPPrompts() error = %v", err)
+	}
+	for _, name := range []string{"daily_standup_interview", "weekly_summary"} {
+		if !server.CheckPromptRegistered(name) {
+			t.Errorf("prompt %s is not registered", name)
+		}
+	}
+}
+
+func TestDailyStandupInterviewPrompt(t *testing.T) {
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	entries := []standup.Entry{
+		{Date: today, Today: []string{"Already submitted"}},
+		{Date: today.AddDate(0, 0, -3), Today: []string{"Ship billing"}},
+		{Date: today.AddDate(0, 0, -4), Today: []string{"Older plan"}},
+	}
+
+	text := dailyStandupInterviewPrompt("alice", previousEntry(entries, today))
+	for _, want := range []string{"alice", "2024-01-12", "- Ship billing", "submit_standup", "confirm=true", "update_standup"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("prompt should contain %q:\n%s", want, text)
+		}
+	}
+	for _, unwanted := range []string{"Already submitted", "Older plan"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("prompt should only follow up on the previous standup, found %q", unwanted)
+		}
+	}
+
+	if text := dailyStandupInterviewPrompt("alice", nil); strings.Contains(text, "planned to") {
+		t.Errorf("prompt without a previous standup should not mention plans:\n%s", text)
+	}
+}
+
+func TestWeeklySummaryPrompt(t *testing.T) {
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	entries := []standup.Entry{
+		{Date: today, Yesterday: []string{"Fixed invoices"}, Blockers: "None"},
+		{Date: today.AddDate(0, 0, -2), Yesterday: []string{"Rotated keys"}, Blockers: "Waiting on security review"},
+		{Date: today.AddDate(0, 0, -10), Yesterday: []string{"Last month"}, Blockers: "None"},
+	}
+
+	text := weeklySummaryPrompt("alice", entries, today.AddDate(0, 0, -6), today)
+	earlier, later := strings.Index(text, "## 2024-01-13"), strings.Index(text, "## 2024-01-15")
+	if earlier < 0 || later < earlier {
+		t.Errorf("standups should be included oldest first:\n%s", text)
+	}
+	if !strings.Contains(text, "Waiting on security review") || strings.Contains(text, "Last month") {
+		t.Errorf("prompt should include only the week's standups:\n%s", text)
+	}
+
+	text = weeklySummaryPrompt("alice", nil, today.AddDate(0, 0, -6), today)
+	if !strings.Contains(text, "no standups") {
+		t.Errorf("prompt for an empty week = %s", text)
+	}
+}
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
@@ -8,10 +10,10 @@ import (
 	"time"
 
 	mcp "github.com/metoro-io/mcp-golang"
+	"github.com/metoro-io/mcp-golang/transport"
 	"github.com/metoro-io/mcp-golang/transport/stdio"
-	"github.com/standup-bot/standup-bot/pkg/config"
-	"github.com/standup-bot/standup-bot/pkg/git"
 	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
 )
 
 // SubmitStandupArgs represents arguments for submit_standup tool
@@ -20,30 +22,89 @@ type SubmitStandupArgs struct {
 	Today     []string `json:"today" jsonschema:"required,description=List of tasks planned for today"`
 	Blockers  string   `json:"blockers" jsonschema:"description=Any blockers or impediments (default: None)"`
 	Direct    bool     `json:"direct" jsonschema:"description=Use direct commit workflow instead of PR workflow (default: false)"`
+	Force     bool     `json:"force" jsonschema:"description=Proceed even if the standup repository has uncommitted non-standup changes (default: false)"`
+	Confirm   bool     `json:"confirm" jsonschema:"description=Set only after the user has reviewed and approved the draft; without it the draft is returned but not submitted (default: false)"`
+	Team      string   `json:"team" jsonschema:"description=Team whose standup repository to use (default: the main repository)"`
 }
 
 // CreateStandupPRArgs represents arguments for create_standup_pr tool
 type CreateStandupPRArgs struct {
-	Merge bool `json:"merge" jsonschema:"description=Whether to merge the PR after creation (default: false)"`
+	Merge bool   `json:"merge" jsonschema:"description=Whether to merge the PR after creation (default: false)"`
+	Team  string `json:"team" jsonschema:"description=Team whose standup repository to use (default: the main repository)"`
 }
 
 // GetStandupStatusArgs represents arguments for get_standup_status tool
-type GetStandupStatusArgs struct{}
+type GetStandupStatusArgs struct {
+	Team string `json:"team" jsonschema:"description=Team whose standup repository to use (default: the main repository)"`
+}
+
+// UpdateStandupArgs represents arguments for update_standup tool
+type UpdateStandupArgs struct {
+	Yesterday []string `json:"yesterday" jsonschema:"description=Completed tasks to add to today's standup"`
+	Today     []string `json:"today" jsonschema:"description=Planned tasks to add to today's standup"`
+	Blockers  string   `json:"blockers" jsonschema:"description=New blockers, replacing the current ones (use 'None' to clear them)"`
+	Append    bool     `json:"append" jsonschema:"description=Add the items as a timestamped update under today's standup instead of merging them into it (default: false)"`
+	Direct    bool     `json:"direct" jsonschema:"description=Use direct commit workflow instead of PR workflow (default: false)"`
+	Force     bool     `json:"force" jsonschema:"description=Proceed even if the standup repository has uncommitted non-standup changes (default: false)"`
+	Confirm   bool     `json:"confirm" jsonschema:"description=Set only after the user has reviewed and approved the draft; without it the draft is returned but not submitted (default: false)"`
+	Team      string   `json:"team" jsonschema:"description=Team whose standup repository to use (default: the main repository)"`
+}
+
+// GetTeamStandupsArgs represents arguments for get_team_standups tool
+type GetTeamStandupsArgs struct {
+	Date string `json:"date" jsonschema:"description=Day to read in YYYY-MM-DD format (default: today)"`
+	Team string `json:"team" jsonschema:"description=Team whose standups to read (default: the main repository)"`
+}
+
+// TeamStandups is the get_team_standups result
+type TeamStandups struct {
+	Date     string                `json:"date"`
+	Standups []standup.StoredEntry `json:"standups"`
+}
+
+// MCPServerOptions configures how the MCP server is reached
+type MCPServerOptions struct {
+	// Transport is "stdio" (the default) or "http"
+	Transport string
+	// Addr is the listen address for the http transport
+	Addr string
+}
+
+// newMCPTransport creates the transport selected by opts. The http transport
+// requires a bearer token in STANDUP_BOT_MCP_TOKEN.
+func newMCPTransport(opts MCPServerOptions) (transport.Transport, error) {
+	switch opts.Transport {
+	case "", "stdio":
+		return stdio.NewStdioServerTransport(), nil
+	case "http":
+		token := os.Getenv(MCPTokenEnv)
+		if token == "" {
+			return nil, fmt.Errorf("%s must be set to serve MCP over HTTP", MCPTokenEnv)
+		}
+		return newMCPHTTPTransport(opts.Addr, token), nil
+	}
+	return nil, fmt.Errorf("invalid transport '%s': expected 'stdio' or 'http'", opts.Transport)
+}
 
 // RunMCPServer starts the MCP server
-func RunMCPServer() error {
-	// Create MCP server with stdio transport
+func RunMCPServer(opts MCPServerOptions) error {
+	serverTransport, err := newMCPTransport(opts)
+	if err != nil {
+		return err
+	}
+
+	// Create MCP server
 	server := mcp.NewServer(
-		stdio.NewStdioServerTransport(),
+		serverTransport,
 		mcp.WithName("standup-bot-mcp"),
 		mcp.WithVersion("1.0.0"),
 	)
 
 	// Register submit_standup tool
-	err := server.RegisterTool(
+	err = server.RegisterTool(
 		"submit_standup",
 		"Submit daily standup with yesterday's accomplishments, today's plans, and blockers",
-		handleSubmitStandup,
+		instrumentTool("submit_standup", handleSubmitStandup),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to register submit_standup tool: %w", err)
@@ -53,7 +114,7 @@ func RunMCPServer() error {
 	err = server.RegisterTool(
 		"create_standup_pr",
 		"Create a pull request with standup entries for the day",
-		handleCreateStandupPR,
+		instrumentTool("create_standup_pr", handleCreateStandupPR),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to register create_standup_pr tool: %w", err)
@@ -63,18 +124,47 @@ func RunMCPServer() error {
 	err = server.RegisterTool(
 		"get_standup_status",
 		"Check if today's standup has been completed",
-		handleGetStandupStatus,
+		instrumentTool("get_standup_status", handleGetStandupStatus),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to register get_standup_status tool: %w", err)
 	}
 
+	// Register update_standup tool
+	err = server.RegisterTool(
+		"update_standup",
+		"Add items to today's standup without overwriting it, or with append a timestamped update under it, then re-commit and update the PR",
+		instrumentTool("update_standup", handleUpdateStandup),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register update_standup tool: %w", err)
+	}
+
+	// Register get_team_standups tool
+	err = server.RegisterTool(
+		"get_team_standups",
+		"Get every team member's standup for a day as structured JSON",
+		instrumentTool("get_team_standups", handleGetTeamStandups),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register get_team_standups tool: %w", err)
+	}
+
+	// Register the workflow prompts
+	if err := registerMCPPrompts(server); err != nil {
+		return err
+	}
+
 	// Set up signal handling
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	// Log to stderr to avoid interfering with stdio transport
-	fmt.Fprintln(os.Stderr, "Starting standup-bot MCP server...")
+	if opts.Transport == "http" {
+		fmt.Fprintf(os.Stderr, "Starting standup-bot MCP server on %s (POST /mcp, SSE at /sse)...\n", opts.Addr)
+	} else {
+		fmt.Fprintln(os.Stderr, "Starting standup-bot MCP server...")
+	}
 
 	// Start server in a goroutine
 	errChan := make(chan error, 1)
@@ -90,94 +180,104 @@ func RunMCPServer() error {
 		return fmt.Errorf("MCP server error: %w", err)
 	case <-sigChan:
 		fmt.Fprintln(os.Stderr, "Shutting down MCP server...")
+		serverTransport.Close()
 		return nil
 	}
 }
 
+// instrumentTool counts a tool's calls by outcome for /metrics, and traces
+// each call as a trace of its own
+func instrumentTool[T any](name string, handler func(context.Context, T) (*mcp.ToolResponse, error)) func(context.Context, T) (*mcp.ToolResponse, error) {
+	return func(ctx context.Context, args T) (*mcp.ToolResponse, error) {
+		ctx, end := startRequest(ctx, "mcp "+name)
+		response, err := handler(ctx, args)
+		end(err)
+		metrics.recordRequest(name, err)
+		return response, err
+	}
+}
+
 // handleSubmitStandup handles the submit_standup tool
-func handleSubmitStandup(args SubmitStandupArgs) (*mcp.ToolResponse, error) {
+func handleSubmitStandup(ctx context.Context, args SubmitStandupArgs) (*mcp.ToolResponse, error) {
 	// Set default blockers if empty
 	if args.Blockers == "" {
 		args.Blockers = "None"
 	}
 
-	// Create standup entry
+	// Load configuration, routed to the requested team
+	cfg, err := loadMCPConfig(args.Team)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkAIAllowed(cfg); err != nil {
+		return nil, err
+	}
+
+	// Create standup entry, marked as drafted by the assistant
 	entry := &standup.Entry{
-		Date:      time.Now(),
-		Yesterday: args.Yesterday,
-		Today:     args.Today,
-		Blockers:  args.Blockers,
+		Date:       cfg.Today(),
+		Yesterday:  args.Yesterday,
+		Today:      args.Today,
+		Blockers:   args.Blockers,
+		AIAssisted: true,
 	}
 
-	// Load configuration
-	cfgManager, err := config.NewManager()
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize config manager: %w", err)
+	// Nothing is submitted until the user has approved the draft
+	if !args.Confirm {
+		return draftResponse(standupbot.NewManager(cfg), cfg.Name, entry, "submit_standup"), nil
 	}
 
-	cfg, err := cfgManager.Load()
+	result, err := requestBot(ctx, cfg).Submit(entry, standupbot.Options{Direct: args.Direct, Force: args.Force})
+	metrics.recordSubmission(cfg.Name, err)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load configuration: %w", err)
+		return nil, err
 	}
 
-	// Submit standup
-	var result string
-	if args.Direct {
-		err = submitStandupDirect(cfg, entry)
-		if err != nil {
-			return nil, err
-		}
-		result = fmt.Sprintf("Standup submitted successfully via direct commit for %s", entry.Date.Format("2006-01-02"))
-	} else {
-		prInfo, err := submitStandupPR(cfg, entry)
-		if err != nil {
-			return nil, err
-		}
-		result = fmt.Sprintf("Standup submitted successfully via PR #%s for %s", prInfo.Number, entry.Date.Format("2006-01-02"))
+	message := fmt.Sprintf("Standup submitted successfully via direct commit for %s", entry.Date.Format("2006-01-02"))
+	if result.PR != nil {
+		message = fmt.Sprintf("Standup submitted successfully via PR #%s for %s", result.PR.Number, entry.Date.Format("2006-01-02"))
+	}
+	if result.AlreadySubmitted {
+		message = fmt.Sprintf("Standup already submitted for %s; nothing changed", entry.Date.Format("2006-01-02"))
 	}
 
 	return mcp.NewToolResponse(
-		mcp.NewTextContent(result),
+		mcp.NewTextContent(withPending(message, result)),
 	), nil
 }
 
 // handleCreateStandupPR handles the create_standup_pr tool
-func handleCreateStandupPR(args CreateStandupPRArgs) (*mcp.ToolResponse, error) {
-	// Load configuration
-	cfgManager, err := config.NewManager()
+func handleCreateStandupPR(ctx context.Context, args CreateStandupPRArgs) (*mcp.ToolResponse, error) {
+	// Load configuration, routed to the requested team
+	cfg, err := loadMCPConfig(args.Team)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize config manager: %w", err)
+		return nil, err
 	}
 
-	cfg, err := cfgManager.Load()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load configuration: %w", err)
-	}
+	bot := requestBot(ctx, cfg)
+	date := cfg.Today()
 
-	gitClient := git.NewClient()
-	
-	// Validate environment
-	if err := validateEnvironment(gitClient, cfg); err != nil {
-		return nil, err
+	if !args.Merge {
+		pr, err := bot.PullRequest(date)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResponse(
+			mcp.NewTextContent(fmt.Sprintf("Standup PR #%s exists for %s", pr.Number, date.Format("2006-01-02"))),
+		), nil
 	}
 
-	// Check if there's a PR for today
-	date := time.Now()
-	branchName := fmt.Sprintf("standup/%s", date.Format("2006-01-02"))
-	
-	prExists, prNumber := gitClient.PRExistsForBranch(cfg.LocalRepoPath, branchName)
-	if !prExists {
-		return nil, fmt.Errorf("no standup PR found for today (%s)", date.Format("2006-01-02"))
+	start := time.Now()
+	merged, err := bot.Merge(standupbot.Options{})
+	metrics.recordMerge(time.Since(start), err)
+	if err != nil {
+		return nil, err
 	}
 
-	result := fmt.Sprintf("Standup PR #%s exists for %s", prNumber, date.Format("2006-01-02"))
-
-	// Merge if requested
-	if args.Merge {
-		if err := gitClient.MergePullRequestByNumber(cfg.LocalRepoPath, prNumber); err != nil {
-			return nil, fmt.Errorf("failed to merge PR: %w", err)
-		}
-		result += " and has been merged"
+	result := fmt.Sprintf("Standup PR #%s for %s has been merged", merged.PR.Number, date.Format("2006-01-02"))
+	for _, warning := range merged.Warnings {
+		result += fmt.Sprintf(" (warning: %s)", warning)
 	}
 
 	return mcp.NewToolResponse(
@@ -186,120 +286,143 @@ func handleCreateStandupPR(args CreateStandupPRArgs) (*mcp.ToolResponse, error)
 }
 
 // handleGetStandupStatus handles the get_standup_status tool
-func handleGetStandupStatus(args GetStandupStatusArgs) (*mcp.ToolResponse, error) {
-	// Load configuration
-	cfgManager, err := config.NewManager()
+func handleGetStandupStatus(ctx context.Context, args GetStandupStatusArgs) (*mcp.ToolResponse, error) {
+	// Load configuration, routed to the requested team
+	cfg, err := loadMCPConfig(args.Team)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize config manager: %w", err)
-	}
-
-	cfg, err := cfgManager.Load()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load configuration: %w", err)
-	}
-
-	// Check if standup file exists for today
-	standupManager := standup.NewManager(cfg.LocalRepoPath)
-	filePath, err := standupManager.GetStandupFilePath(cfg.Name)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get standup file path: %w", err)
+		return nil, err
 	}
 
-	// Read the file and check for today's entry
-	hasToday, err := checkTodayStandup(filePath)
+	today := cfg.Today()
+	status, err := requestBot(ctx, cfg).Status(today)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check standup status: %w", err)
+		return nil, err
 	}
 
-	status := "incomplete"
-	message := fmt.Sprintf("No standup found for today (%s)", time.Now().Format("2006-01-02"))
-	
-	if hasToday {
-		status = "complete"
-		message = fmt.Sprintf("Standup completed for today (%s)", time.Now().Format("2006-01-02"))
+	state := "incomplete"
+	message := fmt.Sprintf("No standup found for today (%s)", today.Format("2006-01-02"))
+	if status.Submitted {
+		state = "complete"
+		message = fmt.Sprintf("Standup completed for today (%s)", today.Format("2006-01-02"))
+	} else if status.Away != nil {
+		state = "out of office"
+		message = fmt.Sprintf("Out of office today (%s)", status.Away)
 	}
-
-	// Also check for PR
-	gitClient := git.NewClient()
-	branchName := fmt.Sprintf("standup/%s", time.Now().Format("2006-01-02"))
-	prExists, prNumber := gitClient.PRExistsForBranch(cfg.LocalRepoPath, branchName)
-	
-	if prExists {
-		message += fmt.Sprintf(" - PR #%s exists", prNumber)
+	if status.PR != nil {
+		message += fmt.Sprintf(" - PR #%s is %s", status.PR.Number, status.PR.State)
 	}
 
 	return mcp.NewToolResponse(
-		mcp.NewTextContent(fmt.Sprintf("Status: %s\n%s", status, message)),
+		mcp.NewTextContent(fmt.Sprintf("Status: %s\n%s", state, message)),
 	), nil
 }
 
-// submitStandupDirect handles direct commit workflow
-func submitStandupDirect(cfg *config.Config, entry *standup.Entry) error {
-	gitClient := git.NewClient()
+// handleUpdateStandup handles the update_standup tool
+func handleUpdateStandup(ctx context.Context, args UpdateStandupArgs) (*mcp.ToolResponse, error) {
+	if len(args.Yesterday) == 0 && len(args.Today) == 0 && args.Blockers == "" {
+		return nil, fmt.Errorf("nothing to update: provide yesterday, today or blockers")
+	}
 
-	if err := validateEnvironment(gitClient, cfg); err != nil {
-		return err
+	// Load configuration, routed to the requested team
+	cfg, err := loadMCPConfig(args.Team)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkAIAllowed(cfg); err != nil {
+		return nil, err
 	}
 
-	// Sync repository
-	if err := gitClient.SyncRepository(cfg.LocalRepoPath); err != nil {
-		return fmt.Errorf("failed to sync repository: %w", err)
+	update := &standup.Entry{
+		Date:       cfg.Today(),
+		Yesterday:  args.Yesterday,
+		Today:      args.Today,
+		Blockers:   args.Blockers,
+		AIAssisted: true,
 	}
 
-	// Save entry
-	standupManager := standup.NewManager(cfg.LocalRepoPath)
-	if err := standupManager.SaveEntry(entry, cfg.Name); err != nil {
-		return fmt.Errorf("failed to save standup: %w", err)
+	if !args.Confirm {
+		return draftResponse(standupbot.NewManager(cfg), cfg.Name, update, "update_standup"), nil
 	}
 
-	// Commit and push
-	commitMessage := standupManager.FormatCommitMessage(entry, cfg.Name)
-	if err := gitClient.CommitAndPush(cfg.LocalRepoPath, commitMessage); err != nil {
-		return fmt.Errorf("failed to push changes: %w", err)
+	bot, opts := requestBot(ctx, cfg), standupbot.Options{Direct: args.Direct, Force: args.Force}
+	record := bot.Update
+	if args.Append {
+		record = bot.Append
+	}
+	result, err := record(update, opts)
+	metrics.recordSubmission(cfg.Name, err)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	message := fmt.Sprintf("Standup for %s updated via direct commit", update.Date.Format("2006-01-02"))
+	if result.PR != nil {
+		message = fmt.Sprintf("Standup for %s updated in PR #%s", update.Date.Format("2006-01-02"), result.PR.Number)
+	}
+
+	return mcp.NewToolResponse(
+		mcp.NewTextContent(withPending(message, result)),
+	), nil
+}
+
+// withPending notes on a result message when the standup was committed but
+// not pushed
+func withPending(message string, result *standupbot.Result) string {
+	if result.Pending == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (%s)", message, result.Pending)
 }
 
-// submitStandupPR handles PR workflow
-func submitStandupPR(cfg *config.Config, entry *standup.Entry) (*PRInfo, error) {
-	gitClient := git.NewClient()
+// draftResponse shows an AI-drafted entry for the user to review instead of
+// submitting it
+func draftResponse(standupManager *standup.Manager, userName string, entry *standup.Entry, tool string) *mcp.ToolResponse {
+	draft := standupManager.FormatCommitMessage(entry, userName)
+	return mcp.NewToolResponse(
+		mcp.NewTextContent(fmt.Sprintf("Draft only, nothing was submitted:\n\n%s\n\nShow this draft to the user. Once they approve it, call %s again with the same content and confirm=true.", draft, tool)),
+	)
+}
 
-	if err := validateEnvironment(gitClient, cfg); err != nil {
+// handleGetTeamStandups handles the get_team_standups tool
+func handleGetTeamStandups(ctx context.Context, args GetTeamStandupsArgs) (*mcp.ToolResponse, error) {
+	// Load configuration, routed to the requested team
+	cfg, err := loadMCPConfig(args.Team)
+	if err != nil {
 		return nil, err
 	}
 
-	// Sync repository
-	if err := gitClient.SyncRepository(cfg.LocalRepoPath); err != nil {
-		return nil, fmt.Errorf("failed to sync repository: %w", err)
+	result, err := teamStandups(requestBot(ctx, cfg), args.Date, cfg.Today())
+	if err != nil {
+		return nil, err
 	}
 
-	// Ensure main branch exists
-	if err := ensureMainBranch(cfg.LocalRepoPath, gitClient); err != nil {
-		return nil, err
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format team standups: %w", err)
 	}
 
-	// Save and create PR
-	standupManager := standup.NewManager(cfg.LocalRepoPath)
-	return createOrUpdateStandupPR(cfg, gitClient, standupManager, entry, "json")
+	return mcp.NewToolResponse(
+		mcp.NewTextContent(string(data)),
+	), nil
 }
 
-// checkTodayStandup checks if today's standup exists in the file
-func checkTodayStandup(filePath string) (bool, error) {
-	content, err := os.ReadFile(filePath)
+// teamStandups reads every user's entry for date, or for today if date is empty
+func teamStandups(bot *standupbot.Bot, date string, today time.Time) (*TeamStandups, error) {
+	day, err := parseOptionalDate(date)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return false, nil
-		}
-		return false, err
+		return nil, fmt.Errorf("invalid date: %w", err)
+	}
+	if day.IsZero() {
+		day = today
+	}
+
+	entries, err := bot.Team(day)
+	if err != nil {
+		return nil, err
 	}
 
-	todayStr := time.Now().Format("2006-01-02")
-	dateHeader := fmt.Sprintf("## %s", todayStr)
-	
-	// Check if content contains today's date header
-	contentStr := string(content)
-	return containsString(contentStr, dateHeader), nil
+	return &TeamStandups{Date: day.Format("2006-01-02"), Standups: entries}, nil
 }
 
 // containsString is a simple string contains check
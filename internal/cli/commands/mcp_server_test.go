@@ -2,6 +2,11 @@ package commands
 
 import (
 	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
 )
 
 func TestMCPServerTypes(t *testing.T) {
@@ -94,4 +99,59 @@ func TestContainsString(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestTeamStandups(t *testing.T) {
+	bot := standupbot.New(&config.Config{LocalRepoPath: t.TempDir()})
+	manager := bot.Manager()
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	for _, user := range []string{"bob", "alice"} {
+		entry := &standup.Entry{Date: today, Yesterday: []string{"Work"}, Today: []string{"More"}, Blockers: "None"}
+		if err := manager.SaveEntry(entry, user); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+
+	result, err := teamStandups(bot, "", today)
+	if err != nil {
+		t.Fatalf("teamStandups() error = %v", err)
+	}
+	if result.Date != "2024-01-15" || len(result.Standups) != 2 || result.Standups[0].User != "alice" {
+		t.Errorf("teamStandups() = %+v", result)
+	}
+
+	// Another day has no standups, which is an empty list rather than null
+	result, err = teamStandups(bot, "2024-01-14", today)
+	if err != nil {
+		t.Fatalf("teamStandups() error = %v", err)
+	}
+	if result.Standups == nil || len(result.Standups) != 0 {
+		t.Errorf("teamStandups() for an empty day = %+v", result.Standups)
+	}
+
+	if _, err := teamStandups(bot, "yesterday", today); err == nil {
+		t.Error("teamStandups() should reject an invalid date")
+	}
+}
+
+func TestDraftResponse(t *testing.T) {
+	manager := standup.NewManager(t.TempDir())
+	entry := &standup.Entry{
+		Date:       time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Yesterday:  []string{"Shipped auth"},
+		Blockers:   "None",
+		AIAssisted: true,
+	}
+
+	response := draftResponse(manager, "alice", entry, "submit_standup")
+	if len(response.Content) != 1 || response.Content[0].TextContent == nil {
+		t.Fatalf("draftResponse() = %+v, want one text content", response)
+	}
+	text := response.Content[0].TextContent.Text
+	for _, want := range []string{"nothing was submitted", "Shipped auth", "confirm=true", "submit_standup"} {
+		if !containsString(text, want) {
+			t.Errorf("draft should contain %q:\n%s", want, text)
+		}
+	}
+}
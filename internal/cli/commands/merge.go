@@ -5,82 +5,80 @@ import (
 	"time"
 
 	"github.com/standup-bot/standup-bot/pkg/config"
-	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
 )
 
+// MergeOptions are the options of --merge
+type MergeOptions struct {
+	Force bool
+	// Merge overrides the team's merge strategy, branch deletion and
+	// auto-merge settings
+	Merge standupbot.MergeOverrides
+	// OutputFormat is "" for progress messages or a machine-readable format
+	OutputFormat string
+}
+
 // RunMergeDailyStandup handles merging the daily standup PR
-func RunMergeDailyStandup(cfg *config.Config) error {
-	gitClient := git.NewClient()
+func RunMergeDailyStandup(cfg *config.Config, opts MergeOptions) error {
+	bot := newBot(cfg, opts.OutputFormat)
 
-	// Validate environment
-	if err := validateMergeEnvironment(gitClient, cfg); err != nil {
-		return err
+	result, err := bot.Merge(standupbot.Options{
+		Force:       opts.Force,
+		Interactive: !IsMachineOutput(opts.OutputFormat) && canPrompt(),
+		Merge:       opts.Merge,
+	})
+	if err != nil {
+		return handleError(err, opts.OutputFormat)
 	}
 
-	// Get today's branch name
-	today := time.Now()
-	branchName := fmt.Sprintf("standup/%s", today.Format("2006-01-02"))
-	
-	// Find and merge the PR
-	if err := findAndMergePR(gitClient, cfg.LocalRepoPath, branchName); err != nil {
-		return err
+	if IsMachineOutput(opts.OutputFormat) {
+		data := standup.MergeData{
+			Date:        cfg.Today().Format("2006-01-02"),
+			PullRequest: standup.PullRequestData{Number: result.PR.Number, URL: result.PR.URL},
+			Auto:        result.Auto,
+			Warnings:    result.Warnings,
+		}
+		return printEnvelope(standup.NewEnvelope(standup.KindMerge, data), opts.OutputFormat)
 	}
-	
-	fmt.Println("✅ Today's standups have been merged successfully!")
-	
-	// Clean up local repository
-	if err := cleanupAfterMerge(gitClient, cfg.LocalRepoPath); err != nil {
-		// Non-fatal errors, just warn
-		fmt.Printf("Warning during cleanup: %v\n", err)
+
+	if result.Auto {
+		fmt.Printf("✅ Auto-merge is on: pull request #%s will merge once its checks and reviews pass.\n", result.PR.Number)
+	} else {
+		fmt.Println("✅ Today's standups have been merged successfully!")
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
 	}
-	
 	return nil
 }
 
-// validateMergeEnvironment checks prerequisites for merging
-func validateMergeEnvironment(gitClient *git.Client, cfg *config.Config) error {
-	if err := gitClient.CheckGHInstalled(); err != nil {
-		return err
-	}
-
-	if err := gitClient.CheckAuthenticated(); err != nil {
+// RunScheduledMerge waits until a time of day (HH:MM, local time) and then
+// merges today's standup PR. A time that has already passed merges right away.
+func RunScheduledMerge(cfg *config.Config, at string, opts MergeOptions) error {
+	delay, err := mergeDelay(at, time.Now())
+	if err != nil {
 		return err
 	}
 
-	if !gitClient.RepositoryExists(cfg.LocalRepoPath) {
-		return fmt.Errorf("repository not found at %s. Please run 'standup-bot --config' to set up", cfg.LocalRepoPath)
+	if delay > 0 && !IsMachineOutput(opts.OutputFormat) {
+		fmt.Printf("Waiting until %s to merge today's standups (Ctrl+C to cancel)...\n", at)
 	}
+	time.Sleep(delay)
 
-	return nil
+	return RunMergeDailyStandup(cfg, opts)
 }
 
-// findAndMergePR finds and merges the PR for the given branch
-func findAndMergePR(gitClient *git.Client, repoPath, branchName string) error {
-	prExists, prNumber := gitClient.PRExistsForBranch(repoPath, branchName)
-	
-	if !prExists {
-		return fmt.Errorf("no pull request found for today's standups")
+// mergeDelay returns how long to wait from now until the time of day at
+func mergeDelay(at string, now time.Time) (time.Duration, error) {
+	clock, err := time.ParseInLocation("15:04", at, now.Location())
+	if err != nil {
+		return 0, fmt.Errorf("invalid --at time '%s': expected HH:MM", at)
 	}
-	
-	fmt.Printf("Merging pull request #%s...\n", prNumber)
-	if err := gitClient.MergePullRequestByNumber(repoPath, prNumber); err != nil {
-		return fmt.Errorf("failed to merge pull request: %w", err)
-	}
-	
-	return nil
-}
 
-// cleanupAfterMerge switches back to main and syncs the repository
-func cleanupAfterMerge(gitClient *git.Client, repoPath string) error {
-	fmt.Println("Switching back to main branch...")
-	if err := gitClient.SwitchToMainBranch(repoPath); err != nil {
-		return fmt.Errorf("could not switch to main branch: %w", err)
-	}
-	
-	// Pull latest changes
-	if err := gitClient.SyncRepository(repoPath); err != nil {
-		return fmt.Errorf("could not sync repository: %w", err)
+	target := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if target.Before(now) {
+		return 0, nil
 	}
-	
-	return nil
-}
\ No newline at end of file
+	return target.Sub(now), nil
+}
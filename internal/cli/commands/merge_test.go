@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeDelay(t *testing.T) {
+	now := time.Date(2024, 1, 15, 16, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		at      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{at: "17:30", want: 90 * time.Minute},
+		{at: "16:00", want: 0},
+		{at: "09:00", want: 0},
+		{at: "5pm", wantErr: true},
+		{at: "25:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := mergeDelay(tt.at, now)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("mergeDelay(%q) = %v, %v, want %v (error %v)", tt.at, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
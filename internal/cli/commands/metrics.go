@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mergeDurationBounds are the buckets of the merge duration histogram, in seconds
+var mergeDurationBounds = []float64{1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// serverMetrics counts what the long-running processes, the MCP server over
+// HTTP, the issue webhook and the bot's reminders, do, for Prometheus to
+// scrape from /metrics
+type serverMetrics struct {
+	mu sync.Mutex
+	// submissions counts standups recorded, by user and outcome
+	submissions map[[2]string]int
+	// requests counts MCP tool calls and webhook issues, by operation and
+	// outcome, so error rates can be alerted on
+	requests map[[2]string]int
+	// merges holds the merge duration histogram, by outcome
+	merges map[string]*durationHistogram
+	// reminders counts meeting reminders posted, by outcome
+	reminders map[string]int
+}
+
+// durationHistogram is a Prometheus histogram of durations
+type durationHistogram struct {
+	buckets []int
+	count   int
+	sum     float64
+}
+
+// metrics is the process's server metrics
+var metrics = newServerMetrics()
+
+// newServerMetrics creates empty server metrics
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		submissions: make(map[[2]string]int),
+		requests:    make(map[[2]string]int),
+		merges:      make(map[string]*durationHistogram),
+		reminders:   make(map[string]int),
+	}
+}
+
+// outcome is the outcome label for an error
+func outcome(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
+
+// recordSubmission counts a standup recorded, or not, for user
+func (m *serverMetrics) recordSubmission(user string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.submissions[[2]string{user, outcome(err)}]++
+}
+
+// recordRequest counts an operation, such as an MCP tool call
+func (m *serverMetrics) recordRequest(operation string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[[2]string{operation, outcome(err)}]++
+}
+
+// recordReminder counts a meeting reminder posted, or not
+func (m *serverMetrics) recordReminder(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reminders[outcome(err)]++
+}
+
+// recordMerge adds a merge's duration to the histogram
+func (m *serverMetrics) recordMerge(d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := m.merges[outcome(err)]
+	if h == nil {
+		h = &durationHistogram{buckets: make([]int, len(mergeDurationBounds))}
+		m.merges[outcome(err)] = h
+	}
+	seconds := d.Seconds()
+	for i, bound := range mergeDurationBounds {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (m *serverMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(w)
+}
+
+// write writes the metrics in the Prometheus text format
+func (m *serverMetrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP standup_bot_submissions_total Standups recorded, by user and outcome.")
+	fmt.Fprintln(w, "# TYPE standup_bot_submissions_total counter")
+	for _, key := range sortedPairs(m.submissions) {
+		fmt.Fprintf(w, "standup_bot_submissions_total{user=%s,outcome=%s} %d\n", quoteLabel(key[0]), quoteLabel(key[1]), m.submissions[key])
+	}
+
+	fmt.Fprintln(w, "# HELP standup_bot_requests_total MCP tool calls and webhook issues handled, by operation and outcome.")
+	fmt.Fprintln(w, "# TYPE standup_bot_requests_total counter")
+	for _, key := range sortedPairs(m.requests) {
+		fmt.Fprintf(w, "standup_bot_requests_total{operation=%s,outcome=%s} %d\n", quoteLabel(key[0]), quoteLabel(key[1]), m.requests[key])
+	}
+
+	fmt.Fprintln(w, "# HELP standup_bot_reminders_sent_total Meeting reminders posted, by outcome.")
+	fmt.Fprintln(w, "# TYPE standup_bot_reminders_sent_total counter")
+	for _, o := range sortedKeys(m.reminders) {
+		fmt.Fprintf(w, "standup_bot_reminders_sent_total{outcome=%s} %d\n", quoteLabel(o), m.reminders[o])
+	}
+
+	fmt.Fprintln(w, "# HELP standup_bot_merge_duration_seconds Time taken to merge the daily pull request, by outcome.")
+	fmt.Fprintln(w, "# TYPE standup_bot_merge_duration_seconds histogram")
+	outcomes := make([]string, 0, len(m.merges))
+	for o := range m.merges {
+		outcomes = append(outcomes, o)
+	}
+	sort.Strings(outcomes)
+	for _, o := range outcomes {
+		h := m.merges[o]
+		for i, bound := range mergeDurationBounds {
+			fmt.Fprintf(w, "standup_bot_merge_duration_seconds_bucket{outcome=%s,le=\"%g\"} %d\n", quoteLabel(o), bound, h.buckets[i])
+		}
+		fmt.Fprintf(w, "standup_bot_merge_duration_seconds_bucket{outcome=%s,le=\"+Inf\"} %d\n", quoteLabel(o), h.count)
+		fmt.Fprintf(w, "standup_bot_merge_duration_seconds_sum{outcome=%s} %g\n", quoteLabel(o), h.sum)
+		fmt.Fprintf(w, "standup_bot_merge_duration_seconds_count{outcome=%s} %d\n", quoteLabel(o), h.count)
+	}
+}
+
+// sortedKeys returns a counter's labels in order
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedPairs returns a counter's label pairs in order
+func sortedPairs(counts map[[2]string]int) [][2]string {
+	keys := make([][2]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// labelEscaper escapes label values as the Prometheus text format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quoteLabel quotes a label value
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerMetrics(t *testing.T) {
+	m := newServerMetrics()
+	m.recordSubmission("alice", nil)
+	m.recordSubmission("alice", nil)
+	m.recordSubmission(`bob "the builder"`, errors.New("push rejected"))
+	m.recordRequest("submit_standup", nil)
+	m.recordRequest("webhook_issue", errors.New("bad form"))
+	m.recordMerge(3*time.Second, nil)
+	m.recordMerge(90*time.Second, nil)
+	m.recordReminder(nil)
+
+	var b strings.Builder
+	m.write(&b)
+	got := b.String()
+
+	for _, want := range []string{
+		"# TYPE standup_bot_submissions_total counter\n",
+		`standup_bot_submissions_total{user="alice",outcome="success"} 2`,
+		`standup_bot_submissions_total{user="bob \"the builder\"",outcome="failure"} 1`,
+		`standup_bot_requests_total{operation="webhook_issue",outcome="failure"} 1`,
+		`standup_bot_reminders_sent_total{outcome="success"} 1`,
+		"# TYPE standup_bot_merge_duration_seconds histogram\n",
+		`standup_bot_merge_duration_seconds_bucket{outcome="success",le="2.5"} 0`,
+		`standup_bot_merge_duration_seconds_bucket{outcome="success",le="5"} 1`,
+		`standup_bot_merge_duration_seconds_bucket{outcome="success",le="120"} 2`,
+		`standup_bot_merge_duration_seconds_bucket{outcome="success",le="+Inf"} 2`,
+		`standup_bot_merge_duration_seconds_sum{outcome="success"} 93`,
+		`standup_bot_merge_duration_seconds_count{outcome="success"} 2`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("metrics missing %s:\n%s", want, got)
+		}
+	}
+}
+
+func TestMCPHTTPMetrics(t *testing.T) {
+	_, server := newEchoTransport(t)
+
+	resp := mcpRequest(t, http.MethodGet, server.URL+"/metrics", "", "")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("without token: status = %d, want 401", resp.StatusCode)
+	}
+
+	resp = mcpRequest(t, http.MethodGet, server.URL+"/metrics", "secret", "")
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "standup_bot_submissions_total") {
+		t.Errorf("status = %d, body:\n%s", resp.StatusCode, body)
+	}
+}
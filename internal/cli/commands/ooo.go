@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// RunOutOfOffice records that the user is out of office from one day to
+// another (YYYY-MM-DD, both optional and defaulting to today and from), and
+// pushes it to the base branch so the team sees it right away
+func RunOutOfOffice(cfg *config.Config, from, to, reason string, force bool) error {
+	fromDate, err := parseOptionalDate(from)
+	if err != nil {
+		return fmt.Errorf("invalid --from date: %w", err)
+	}
+	if fromDate.IsZero() {
+		fromDate = cfg.Today()
+	}
+	toDate, err := parseOptionalDate(to)
+	if err != nil {
+		return fmt.Errorf("invalid --to date: %w", err)
+	}
+	if toDate.IsZero() {
+		toDate = fromDate
+	}
+	absence, err := standup.NewAbsence(fromDate, toDate, reason)
+	if err != nil {
+		return err
+	}
+
+	gitClient := standupbot.NewGitClient(cfg)
+	if err := standupbot.ValidateDirectEnvironment(gitClient, cfg); err != nil {
+		return err
+	}
+	if err := standupbot.CheckWorkTree(gitClient, cfg.LocalRepoPath, force, canPrompt()); err != nil {
+		return err
+	}
+	if err := checkoutBaseBranch(gitClient, cfg); err != nil {
+		return err
+	}
+
+	manager := standupbot.NewManager(cfg)
+	if err := manager.AddAbsence(cfg.Name, absence); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Out of office: %s %s", cfg.Name, absence)
+	err = gitClient.CommitAndPush(cfg.LocalRepoPath, message, manager.AbsencePath(cfg.Name))
+	if errors.Is(err, git.ErrNoChangesToCommit) {
+		fmt.Printf("✅ Already recorded: out of office %s.\n", absence)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🌴 Recorded: out of office %s.\n", absence)
+	fmt.Println("Those days won't break your streak, and the daily PR lists you as away.")
+	return nil
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/standup-bot/standup-bot/pkg/render"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/ui"
+)
+
+// Output formats for --output. "json" is the versioned envelope; "json=v1"
+// is the original flat shape, kept for existing integrations. "yaml" is the
+// envelope as YAML, and "table" the default human-readable output.
+const (
+	outputTable  = "table"
+	outputJSON   = "json"
+	outputJSONv2 = "json=v2"
+	outputJSONv1 = "json=v1"
+	outputYAML   = "yaml"
+)
+
+// ValidateOutputFormat checks an --output value
+func ValidateOutputFormat(outputFormat string) error {
+	switch outputFormat {
+	case "", outputTable, outputJSON, outputJSONv2, outputJSONv1, outputYAML:
+		return nil
+	}
+	return fmt.Errorf("invalid output format '%s': expected 'table', 'json', 'yaml', 'json=v2' or 'json=v1'", outputFormat)
+}
+
+// IsJSONOutput reports whether the output format is one of the JSON versions
+func IsJSONOutput(outputFormat string) bool {
+	return outputFormat == outputJSON || outputFormat == outputJSONv2 || outputFormat == outputJSONv1
+}
+
+// IsMachineOutput reports whether the output format is machine-readable, in
+// which case progress messages are left out
+func IsMachineOutput(outputFormat string) bool {
+	return IsJSONOutput(outputFormat) || outputFormat == outputYAML
+}
+
+// renderFormat returns the render format for a machine-readable --output
+func renderFormat(outputFormat string) render.Format {
+	if outputFormat == outputYAML {
+		return render.YAML
+	}
+	return render.JSON
+}
+
+// progressSpinner returns a spinner for slow operations, or nil when stdout
+// isn't a terminal, the output is machine-readable or plain output is on
+func progressSpinner(outputFormat string) *ui.Spinner {
+	if plain || IsMachineOutput(outputFormat) || !ui.IsTerminal(os.Stdout) {
+		return nil
+	}
+	return ui.NewSpinner(os.Stdout)
+}
+
+// printJSONOutput prints output in the requested machine-readable format.
+// kind selects the envelope payload for successful results.
+func printJSONOutput(output standup.JSONOutput, kind, outputFormat string) error {
+	if outputFormat == outputJSONv1 {
+		jsonStr, err := standup.FormatJSONOutput(output)
+		if err != nil {
+			return err
+		}
+		fmt.Println(jsonStr)
+		return nil
+	}
+	return printEnvelope(output.Envelope(kind), outputFormat)
+}
+
+// printEnvelope prints a versioned result as JSON or YAML
+func printEnvelope(envelope standup.Envelope, outputFormat string) error {
+	return render.Print(os.Stdout, renderFormat(outputFormat), envelope)
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"io"
+	"os"
+
+	"github.com/standup-bot/standup-bot/pkg/ui"
+)
+
+// plain strips emoji, colors and box-drawing from output (--plain or
+// NO_COLOR) for CI logs and screen readers
+var plain bool
+
+// console is the terminal's stdout and stderr, before plain output
+// redirects them; editors need the real terminal
+var console = struct{ out, err *os.File }{os.Stdout, os.Stderr}
+
+// PlainRequested reports whether plain output was asked for, with --plain
+// or a non-empty NO_COLOR (https://no-color.org)
+func PlainRequested(flag bool) bool {
+	return flag || os.Getenv("NO_COLOR") != ""
+}
+
+// StartPlain passes everything printed to stdout and stderr through
+// ui.Plain, and turns the progress spinner off. The returned function
+// writes what's left and restores stdout and stderr.
+func StartPlain() (func(), error) {
+	restoreOut, err := redirectPlain(&os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	// When both go to the same terminal or log, they share the pipe so
+	// errors stay in order with the output around them
+	restoreErr := func() { os.Stderr = console.err }
+	if !sameFile(console.out, console.err) {
+		if restoreErr, err = redirectPlain(&os.Stderr); err != nil {
+			restoreOut()
+			return nil, err
+		}
+	} else {
+		os.Stderr = os.Stdout
+	}
+	plain = true
+
+	return func() {
+		plain = false
+		restoreErr()
+		restoreOut()
+	}, nil
+}
+
+// sameFile reports whether a and b are the same file, terminal or pipe
+func sameFile(a, b *os.File) bool {
+	aInfo, err := a.Stat()
+	if err != nil {
+		return false
+	}
+	bInfo, err := b.Stat()
+	return err == nil && os.SameFile(aInfo, bInfo)
+}
+
+// redirectPlain replaces *file with a pipe whose contents are written, in
+// plain form, to the original file
+func redirectPlain(file **os.File) (func(), error) {
+	original := *file
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		out := ui.NewPlainWriter(original)
+		io.Copy(out, reader)
+		out.Flush()
+		reader.Close()
+	}()
+	*file = writer
+
+	return func() {
+		*file = original
+		writer.Close()
+		<-done
+	}, nil
+}
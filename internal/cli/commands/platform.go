@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// checkPlatform reports a mismatch between the binary's architecture and the
+// machine's: an amd64 build running under Rosetta on Apple Silicon works, but
+// slowly and with a separate Homebrew prefix for git and gh.
+func checkPlatform(runner git.CommandRunner, goos, goarch string) error {
+	if goos != "darwin" || goarch != "amd64" {
+		return nil
+	}
+
+	// sysctl.proc_translated is 1 for processes translated by Rosetta
+	output, err := runner.Run("sysctl", "-n", "sysctl.proc_translated")
+	if err != nil || strings.TrimSpace(string(output)) != "1" {
+		return nil
+	}
+	return fmt.Errorf("the %s/%s build is running under Rosetta on Apple Silicon", goos, goarch)
+}
+
+// currentPlatform returns the platform the binary was built for
+func currentPlatform() (string, string) {
+	return runtime.GOOS, runtime.GOARCH
+}
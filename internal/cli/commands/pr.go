@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// RunPRRefresh rebuilds the daily pull request's description from the
+// standups on its branch. date is an optional YYYY-MM-DD date; today by
+// default.
+func RunPRRefresh(cfg *config.Config, date string, force bool, outputFormat string) error {
+	day, err := parseOptionalDate(date)
+	if err != nil {
+		return fmt.Errorf("invalid --date: %w", err)
+	}
+	if day.IsZero() {
+		day = cfg.Today()
+	}
+
+	bot := newBot(cfg, outputFormat)
+	pr, err := bot.RefreshPullRequest(day, standupbot.Options{Force: force, Interactive: !IsMachineOutput(outputFormat) && canPrompt()})
+	if err != nil {
+		return handleError(err, outputFormat)
+	}
+
+	if IsMachineOutput(outputFormat) {
+		data := standup.RefreshData{
+			Date:        day.Format("2006-01-02"),
+			PullRequest: standup.PullRequestData{Number: pr.Number, URL: pr.URL},
+		}
+		return printEnvelope(standup.NewEnvelope(standup.KindRefresh, data), outputFormat)
+	}
+
+	fmt.Printf("✅ Refreshed the description of pull request #%s: %s\n", pr.Number, pr.URL)
+	return nil
+}
+
+// RunRequestReview asks the team's approvers to review today's pull request
+func RunRequestReview(cfg *config.Config, outputFormat string) error {
+	bot := newBot(cfg, outputFormat)
+	pr, reviewers, err := bot.RequestReview(cfg.Today())
+	if err != nil {
+		return handleError(err, outputFormat)
+	}
+
+	if IsMachineOutput(outputFormat) {
+		data := standup.ReviewRequestData{
+			Date:        cfg.Today().Format("2006-01-02"),
+			PullRequest: standup.PullRequestData{Number: pr.Number, URL: pr.URL},
+			Reviewers:   reviewers,
+		}
+		return printEnvelope(standup.NewEnvelope(standup.KindReviewRequest, data), outputFormat)
+	}
+
+	fmt.Printf("👀 Asked %s to review pull request #%s: %s\n", strings.Join(reviewers, ", "), pr.Number, pr.URL)
+	return nil
+}
+
+// RunPRDigest emails the digest of the standups merged for date, an
+// optional YYYY-MM-DD date that defaults to today. Merging sends it, so this
+// is for resending it or for pull requests merged outside the bot.
+func RunPRDigest(cfg *config.Config, date string, force bool) error {
+	if cfg.Digest == nil {
+		return fmt.Errorf("no digest settings: add a \"digest\" section with the SMTP server to the config")
+	}
+	day, err := parseOptionalDate(date)
+	if err != nil {
+		return fmt.Errorf("invalid --date: %w", err)
+	}
+	if day.IsZero() {
+		day = cfg.Today()
+	}
+
+	gitClient := standupbot.NewGitClient(cfg)
+	if err := standupbot.CheckWorkTree(gitClient, cfg.LocalRepoPath, force, canPrompt()); err != nil {
+		return err
+	}
+	if err := checkoutBaseBranch(gitClient, cfg); err != nil {
+		return err
+	}
+
+	sent, err := standupbot.New(cfg).SendDigest(day)
+	if err != nil {
+		return err
+	}
+	if sent == 0 {
+		fmt.Printf("No merged standups for %s to email.\n", day.Format("2006-01-02"))
+		return nil
+	}
+	fmt.Printf("✅ Emailed the digest of %d standup(s) for %s\n", sent, day.Format("2006-01-02"))
+	return nil
+}
+
+// RunPRExport publishes the standups merged for date, an optional
+// YYYY-MM-DD date that defaults to today, to the team's Notion database and
+// Confluence space. Merging exports them, so this is for exporting them
+// again or for pull requests merged outside the bot.
+func RunPRExport(cfg *config.Config, date string, force bool) error {
+	day, err := parseOptionalDate(date)
+	if err != nil {
+		return fmt.Errorf("invalid --date: %w", err)
+	}
+	if day.IsZero() {
+		day = cfg.Today()
+	}
+
+	gitClient := standupbot.NewGitClient(cfg)
+	if err := standupbot.CheckWorkTree(gitClient, cfg.LocalRepoPath, force, canPrompt()); err != nil {
+		return err
+	}
+	if err := checkoutBaseBranch(gitClient, cfg); err != nil {
+		return err
+	}
+
+	team, err := config.LoadTeamConfig(cfg.LocalRepoPath)
+	if err != nil {
+		return err
+	}
+	if !team.Export.Enabled() {
+		return fmt.Errorf("nowhere to export to: add an \"export\" section with notion or confluence to %s", config.TeamConfigFile)
+	}
+
+	pages, err := standupbot.New(cfg).ExportStandups(day)
+	for _, page := range pages {
+		fmt.Printf("✅ Exported the standups for %s to %s: %s\n", day.Format("2006-01-02"), page.Target, page.URL)
+	}
+	if err != nil {
+		return err
+	}
+	if len(pages) == 0 {
+		fmt.Printf("No merged standups for %s to export.\n", day.Format("2006-01-02"))
+	}
+	return nil
+}
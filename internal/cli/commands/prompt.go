@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+)
+
+// nonInteractive disables every prompt, so CI jobs and containers fail fast
+// instead of waiting for input that will never come
+var nonInteractive bool
+
+// SetNonInteractive turns prompts off (--non-interactive, or stdin isn't a
+// terminal) or back on
+func SetNonInteractive(disabled bool) {
+	nonInteractive = disabled
+}
+
+// StdinIsTerminal reports whether stdin is attached to a terminal
+func StdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// canPrompt reports whether the user can be asked for input
+func canPrompt() bool {
+	return !nonInteractive
+}
+
+// inputRequired explains that a prompt was needed but prompts are disabled,
+// and how to provide the input instead
+func inputRequired(what, hint string) error {
+	return fmt.Errorf("%s is required but prompts are disabled (--non-interactive, or stdin isn't a terminal): %s", what, hint)
+}
@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// PublishDir is where 'publish' writes the site on the base branch, the
+// folder GitHub Pages can serve from
+const PublishDir = "docs"
+
+// PublishOptions selects where 'publish' puts the site
+type PublishOptions struct {
+	// Out writes the site to a local directory without committing it
+	Out string
+	// Branch commits the site to this branch, such as gh-pages, instead of
+	// the docs folder of the base branch
+	Branch string
+	// Force proceeds despite uncommitted non-standup changes
+	Force bool
+}
+
+// RunPublish renders the standup history as a static site and commits it to
+// the docs folder of the base branch, commits it to a separate branch, or
+// writes it to a local directory
+func RunPublish(cfg *config.Config, opts PublishOptions) error {
+	gitClient := standupbot.NewGitClient(cfg)
+
+	if opts.Out != "" {
+		files, err := renderSite(cfg)
+		if err != nil {
+			return err
+		}
+		if err := writeSite(opts.Out, files); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Wrote the site to %s. Open %s in a browser.\n", opts.Out, filepath.Join(opts.Out, "index.html"))
+		return nil
+	}
+
+	if err := standupbot.ValidateDirectEnvironment(gitClient, cfg); err != nil {
+		return err
+	}
+	if err := standupbot.CheckWorkTree(gitClient, cfg.LocalRepoPath, opts.Force, canPrompt()); err != nil {
+		return err
+	}
+	if err := checkoutBaseBranch(gitClient, cfg); err != nil {
+		return err
+	}
+
+	files, err := renderSite(cfg)
+	if err != nil {
+		return err
+	}
+
+	repoPath, sitePath := cfg.LocalRepoPath, filepath.Join(cfg.LocalRepoPath, PublishDir)
+	target := fmt.Sprintf("%s/ on %s", PublishDir, cfg.GetBaseBranch())
+	if opts.Branch != "" {
+		worktree, err := os.MkdirTemp("", "standup-bot-publish-")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		// git creates the worktree directory itself
+		os.Remove(worktree)
+		if err := gitClient.AddWorktree(cfg.LocalRepoPath, worktree, opts.Branch); err != nil {
+			return err
+		}
+		defer gitClient.RemoveWorktree(cfg.LocalRepoPath, worktree)
+		repoPath, sitePath, target = worktree, worktree, opts.Branch
+	}
+
+	if err := writeSite(sitePath, files); err != nil {
+		return err
+	}
+	err = gitClient.CommitAndPush(repoPath, "Publish standup site", sitePath)
+	if errors.Is(err, git.ErrNoChangesToCommit) {
+		fmt.Printf("✅ The site in %s is already up to date.\n", target)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Published the site to %s.\n", target)
+	fmt.Println("Serve it with GitHub Pages (Settings > Pages) or any static file host.")
+	return nil
+}
+
+// renderSite renders the site from everyone's standups
+func renderSite(cfg *config.Config) (map[string][]byte, error) {
+	manager := standupbot.NewManager(cfg)
+	entries, err := manager.LoadAllEntries()
+	if err != nil {
+		return nil, err
+	}
+	absences, err := manager.TeamAbsences()
+	if err != nil {
+		return nil, err
+	}
+
+	title := "Standups"
+	if cfg.Repository != "" {
+		title = "Standups: " + cfg.Repository
+	}
+	return standup.RenderSite(title, entries, absences, time.Now())
+}
+
+// writeSite replaces the contents of dir with the site, so pages of removed
+// users don't linger. To avoid deleting anything else, dir must be empty or
+// hold a site written before. The .git of a worktree is kept.
+func writeSite(dir string, files map[string][]byte) error {
+	existing, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	var stale []string
+	isSite := false
+	for _, f := range existing {
+		switch f.Name() {
+		case ".git":
+			continue
+		case ".nojekyll":
+			isSite = true
+		}
+		stale = append(stale, f.Name())
+	}
+	if len(stale) > 0 && !isSite {
+		return fmt.Errorf("%s already has files that aren't a standup site; choose an empty directory", dir)
+	}
+
+	for _, name := range stale {
+		if err := os.RemoveAll(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", dir, err)
+		}
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
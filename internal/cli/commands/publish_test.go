@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteSite(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{".nojekyll": {}, "index.html": []byte("v1"), "people/old.html": []byte("old")}
+	if err := writeSite(dir, files); err != nil {
+		t.Fatalf("writeSite() error = %v", err)
+	}
+
+	// A previous site is replaced, including pages that are gone
+	files = map[string][]byte{".nojekyll": {}, "index.html": []byte("v2")}
+	if err := writeSite(dir, files); err != nil {
+		t.Fatalf("writeSite() over a site error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "index.html")); string(data) != "v2" {
+		t.Errorf("index.html = %q, want v2", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "people")); !os.IsNotExist(err) {
+		t.Error("writeSite() should remove pages of the previous site")
+	}
+
+	// Anything else is left alone
+	other := t.TempDir()
+	if err := os.WriteFile(filepath.Join(other, "notes.txt"), []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := writeSite(other, files)
+	if err == nil || !strings.Contains(err.Error(), "aren't a standup site") {
+		t.Errorf("writeSite() into a used directory error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(other, "notes.txt")); err != nil {
+		t.Error("writeSite() must not delete other files")
+	}
+}
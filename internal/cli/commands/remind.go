@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// reminderLookahead is how far ahead RunRemind looks for the next reminder
+// when none is due
+const reminderLookahead = 8 * 24 * time.Hour
+
+// botReminderInterval is how often the bot checks for reminders due
+const botReminderInterval = time.Minute
+
+// RunRemind posts the reminders that fell due in the last window, before
+// each standup meeting in the team's calendar. Run it from cron every
+// window, e.g. every 15 minutes with --window 15m.
+func RunRemind(cfg *config.Config, window time.Duration) error {
+	if window <= 0 {
+		return fmt.Errorf("invalid --window: must be positive")
+	}
+	bot := standupbot.New(cfg)
+	now := time.Now()
+	due, err := bot.Reminders(now.Add(-window), now)
+	if err != nil {
+		return err
+	}
+
+	if len(due) == 0 {
+		next, err := bot.Reminders(now, now.Add(reminderLookahead))
+		if err != nil {
+			return err
+		}
+		if len(next) == 0 {
+			fmt.Println("No reminder due, and no standup meeting in the next week.")
+			return nil
+		}
+		fmt.Printf("No reminder due; the next is at %s.\n", next[0].At.In(cfg.Location()).Format("Mon 2006-01-02 15:04"))
+		return nil
+	}
+
+	return postReminders(cfg, due)
+}
+
+// postReminders prints reminders and posts them to Slack, counting each in
+// the metrics
+func postReminders(cfg *config.Config, reminders []standupbot.Reminder) error {
+	for _, reminder := range reminders {
+		message := reminder.Message(cfg.Location())
+		fmt.Println(message)
+		err := standupbot.PostReminder(message)
+		metrics.recordReminder(err)
+		if err != nil {
+			return fmt.Errorf("failed to post the reminder: %w", err)
+		}
+	}
+	return nil
+}
+
+// remindsFromBot reports whether the bot should post cfg's reminders: the
+// team has a meeting calendar and there is a Slack webhook to post to
+func remindsFromBot(cfg *config.Config) bool {
+	if os.Getenv(config.SlackWebhookEnv) == "" {
+		return false
+	}
+	team, err := config.LoadTeamConfig(cfg.LocalRepoPath)
+	return err == nil && team.Meeting.CalendarURL() != ""
+}
+
+// runReminders posts cfg's reminders as they fall due, checking every
+// interval, for as long as the bot runs
+func runReminders(cfg *config.Config, interval time.Duration) {
+	last := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		ctx, end := startRequest(context.Background(), "reminders")
+		due, err := requestBot(ctx, cfg).Reminders(last, now)
+		if err == nil {
+			err = postReminders(cfg, due)
+		}
+		end(err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not post the reminders for %s: %v\n", cfg.Repository, err)
+		}
+		last = now
+	}
+}
+
+// RunRemindICS writes the reminders for the next days as an iCalendar feed,
+// to out or to stdout when out is empty or "-"
+func RunRemindICS(cfg *config.Config, out string, days int) error {
+	if days < 1 {
+		return fmt.Errorf("invalid --days: must be at least 1")
+	}
+	now := time.Now()
+	reminders, err := standupbot.New(cfg).Reminders(now, now.AddDate(0, 0, days))
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" && out != "-" {
+		f, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := standupbot.ReminderFeed(w, reminders, cfg.Location(), now); err != nil {
+		return err
+	}
+
+	if out != "" && out != "-" {
+		fmt.Printf("✅ Wrote %d reminders to %s\n", len(reminders), out)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+func TestRunRemindRejectsInvalidFlags(t *testing.T) {
+	cfg := &config.Config{LocalRepoPath: t.TempDir()}
+	if err := RunRemind(cfg, 0); err == nil || !strings.Contains(err.Error(), "--window") {
+		t.Errorf("RunRemind() error = %v, want invalid --window", err)
+	}
+	if err := RunRemindICS(cfg, "", 0); err == nil || !strings.Contains(err.Error(), "--days") {
+		t.Errorf("RunRemindICS() error = %v, want invalid --days", err)
+	}
+}
+
+func TestRemindsFromBot(t *testing.T) {
+	cfg := &config.Config{LocalRepoPath: t.TempDir()}
+	t.Setenv(config.CalendarURLEnv, "")
+	t.Setenv(config.SlackWebhookEnv, "https://hooks.slack.com/services/T/B/X")
+	if remindsFromBot(cfg) {
+		t.Error("remindsFromBot() = true without a meeting calendar")
+	}
+
+	team := "meeting:\n  calendar: webcal://calendar.acme.com/team.ics\n"
+	if err := os.WriteFile(filepath.Join(cfg.LocalRepoPath, config.TeamConfigFile), []byte(team), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !remindsFromBot(cfg) {
+		t.Error("remindsFromBot() = false with a calendar and a webhook")
+	}
+
+	t.Setenv(config.SlackWebhookEnv, "")
+	if remindsFromBot(cfg) {
+		t.Error("remindsFromBot() = true with nowhere to post")
+	}
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+// fakeRunner returns canned output for every command
+type fakeRunner struct {
+	output []byte
+	err    error
+}
+
+func (f *fakeRunner) Run(name string, args ...string) ([]byte, error) {
+	return f.output, f.err
+}
+
+func (f *fakeRunner) RunInDir(dir, name string, args ...string) ([]byte, error) {
+	return f.output, f.err
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{"", "table", "json", "json=v2", "json=v1", "yaml"} {
+		if err := ValidateOutputFormat(format); err != nil {
+			t.Errorf("ValidateOutputFormat(%q) error = %v", format, err)
+		}
+	}
+	for _, format := range []string{"xml", "json=v3"} {
+		if err := ValidateOutputFormat(format); err == nil {
+			t.Errorf("ValidateOutputFormat(%q) should fail", format)
+		}
+	}
+	if !IsJSONOutput("json=v1") || IsJSONOutput("") {
+		t.Error("IsJSONOutput() should accept json versions only")
+	}
+	if !IsMachineOutput("yaml") || IsMachineOutput("table") {
+		t.Error("IsMachineOutput() should accept json and yaml only")
+	}
+}
+
+func TestNonInteractive(t *testing.T) {
+	SetNonInteractive(true)
+	defer SetNonInteractive(false)
+
+	cfg := &config.Config{Name: "alice", LocalRepoPath: t.TempDir()}
+	manager := standup.NewManager(cfg.LocalRepoPath)
+	if _, err := collectEntry(cfg, manager, ""); err == nil || !strings.Contains(err.Error(), "--json") {
+		t.Errorf("collectEntry() error = %v, want a hint to use --json", err)
+	}
+	if _, err := collectEntry(cfg, manager, `{"today": ["Ship it"]}`); err != nil {
+		t.Errorf("collectEntry() with JSON input error = %v", err)
+	}
+	if isInteractive("", "") {
+		t.Error("isInteractive() should be false when prompts are disabled")
+	}
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/render"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// Output formats of 'stats'
+const (
+	statsTable    = "table"
+	statsJSON     = "json"
+	statsYAML     = "yaml"
+	statsMarkdown = "markdown"
+)
+
+// RunStats prints per-user and team metrics computed from the standup
+// history as a table, JSON, YAML or a markdown report. since and until are
+// optional YYYY-MM-DD dates.
+func RunStats(cfg *config.Config, since, until, format string) error {
+	switch format {
+	case "", statsTable, statsJSON, statsYAML, statsMarkdown:
+	default:
+		return fmt.Errorf("invalid --format '%s': expected '%s', '%s', '%s' or '%s'", format, statsTable, statsJSON, statsYAML, statsMarkdown)
+	}
+
+	sinceDate, err := parseOptionalDate(since)
+	if err != nil {
+		return fmt.Errorf("invalid --since date: %w", err)
+	}
+	untilDate, err := parseOptionalDate(until)
+	if err != nil {
+		return fmt.Errorf("invalid --until date: %w", err)
+	}
+
+	manager := standupbot.NewManager(cfg)
+	entries, err := manager.LoadAllEntries()
+	if err != nil {
+		return err
+	}
+	absences, err := manager.TeamAbsences()
+	if err != nil {
+		return err
+	}
+
+	stats := standup.ComputeStats(entries, absences, sinceDate, untilDate, cfg.Today())
+
+	switch format {
+	case statsJSON, statsYAML:
+		return render.Print(os.Stdout, render.Format(format), stats)
+	case statsMarkdown:
+		fmt.Print(formatStatsMarkdown(stats))
+	default:
+		writeStatsTable(os.Stdout, stats)
+	}
+	return nil
+}
+
+// writeStatsTable prints one row per user and a team total
+func writeStatsTable(out io.Writer, stats standup.TeamStats) {
+	if len(stats.Users) == 0 {
+		fmt.Fprintln(out, "No standups in this period.")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER\tDAYS\tITEMS/DAY\tBLOCKED\tSTREAK\tLONGEST\tTHEMES")
+	for _, s := range append(stats.Users, stats.Team) {
+		streak, longest := fmt.Sprint(s.CurrentStreak), fmt.Sprint(s.LongestStreak)
+		if s.User == stats.Team.User {
+			streak, longest = "-", "-"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%.0f%%\t%s\t%s\t%s\n",
+			s.User, s.Days, s.ItemsPerDay, s.BlockerRate()*100, streak, longest, standup.FormatThemes(s.Themes))
+	}
+	w.Flush()
+}
+
+// formatStatsMarkdown renders the stats as a report for retrospectives
+func formatStatsMarkdown(stats standup.TeamStats) string {
+	var b strings.Builder
+	b.WriteString("# Standup Stats\n\n")
+	switch {
+	case stats.Since != "" && stats.Until != "":
+		fmt.Fprintf(&b, "%s to %s\n\n", stats.Since, stats.Until)
+	case stats.Since != "":
+		fmt.Fprintf(&b, "Since %s\n\n", stats.Since)
+	case stats.Until != "":
+		fmt.Fprintf(&b, "Until %s\n\n", stats.Until)
+	}
+
+	if len(stats.Users) == 0 {
+		b.WriteString("No standups in this period.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "The team submitted %d standups with %.1f completed items each on average. ", stats.Team.Days, stats.Team.ItemsPerDay)
+	fmt.Fprintf(&b, "%.0f%% reported a blocker.", stats.Team.BlockerRate()*100)
+	if themes := standup.FormatThemes(stats.Team.Themes); themes != "" {
+		fmt.Fprintf(&b, " Most of the work was on %s.", themes)
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString("| User | Standups | Items/day | Blocked | Current streak | Longest streak | Themes |\n")
+	b.WriteString("|------|---------:|----------:|--------:|---------------:|---------------:|--------|\n")
+	for _, s := range stats.Users {
+		fmt.Fprintf(&b, "| %s | %d | %.1f | %.0f%% | %d | %d | %s |\n",
+			s.User, s.Days, s.ItemsPerDay, s.BlockerRate()*100, s.CurrentStreak, s.LongestStreak, standup.FormatThemes(s.Themes))
+	}
+
+	b.WriteString("\n## Blockers\n\n")
+	if len(stats.Blockers) == 0 {
+		b.WriteString("None reported.\n")
+	}
+	for _, blocker := range stats.Blockers {
+		fmt.Fprintf(&b, "- %s **%s**: %s\n", blocker.Date, blocker.User, blocker.Text)
+	}
+	return b.String()
+}
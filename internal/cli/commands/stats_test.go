@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestFormatStatsMarkdown(t *testing.T) {
+	stats := standup.TeamStats{
+		Since: "2024-01-01",
+		Users: []standup.Stats{
+			{User: "alice", Days: 4, BlockedDays: 1, ItemsPerDay: 1.5, CurrentStreak: 2, LongestStreak: 3,
+				Themes: []standup.ThemeCount{{Theme: "Billing", Count: 3}}},
+		},
+		Team:     standup.Stats{User: "Team", Days: 4, BlockedDays: 1, ItemsPerDay: 1.5},
+		Blockers: []standup.Blocker{{Date: "2024-01-03", User: "alice", Text: "Waiting on API keys"}},
+	}
+
+	report := formatStatsMarkdown(stats)
+	for _, want := range []string{
+		"# Standup Stats\n\nSince 2024-01-01\n",
+		"25% reported a blocker.",
+		"| alice | 4 | 1.5 | 25% | 2 | 3 | Billing (3) |",
+		"## Blockers\n\n- 2024-01-03 **alice**: Waiting on API keys\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("formatStatsMarkdown() missing %q:\n%s", want, report)
+		}
+	}
+
+	var table strings.Builder
+	writeStatsTable(&table, stats)
+	if !strings.Contains(table.String(), "ITEMS/DAY") || !strings.Contains(table.String(), "Team") {
+		t.Errorf("writeStatsTable() = %q, want a header and a team row", table.String())
+	}
+}
+
+func TestRunStatsInvalidFormat(t *testing.T) {
+	err := RunStats(&config.Config{}, "", "", "csv")
+	if err == nil || !strings.Contains(err.Error(), "invalid --format") {
+		t.Errorf("RunStats() error = %v, want invalid --format", err)
+	}
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+// RunStatus shows whether you and your teammates have submitted a standup
+// for a day, and the state of the day's pull request and its checks. date is
+// an optional YYYY-MM-DD date; today by default.
+func RunStatus(cfg *config.Config, date, outputFormat string) error {
+	day, err := parseOptionalDate(date)
+	if err != nil {
+		return fmt.Errorf("invalid --date: %w", err)
+	}
+	if day.IsZero() {
+		day = cfg.Today()
+	}
+
+	status, err := standupbot.New(cfg).Status(day)
+	if err != nil {
+		return err
+	}
+
+	if IsMachineOutput(outputFormat) {
+		return printEnvelope(standup.NewEnvelope(standup.KindStatus, statusData(cfg.Name, status)), outputFormat)
+	}
+	writeStatus(os.Stdout, cfg.Name, status)
+	return nil
+}
+
+// statusData converts a status to its JSON payload
+func statusData(user string, status *standupbot.Status) standup.StatusData {
+	data := standup.StatusData{
+		Date:      status.Date.Format("2006-01-02"),
+		User:      user,
+		Submitted: status.Submitted,
+		Team:      []standup.TeammateData{},
+	}
+	if status.Away != nil {
+		data.OutOfOffice = status.Away.String()
+	}
+	if pr := status.PR; pr != nil {
+		data.PullRequest = &standup.PullRequestStatusData{
+			PullRequestData: standup.PullRequestData{Number: pr.Number, URL: pr.URL},
+			State:           pr.State,
+			Merged:          pr.Merged(),
+			Checks:          []standup.CheckData{},
+		}
+		for _, check := range pr.Checks {
+			data.PullRequest.Checks = append(data.PullRequest.Checks, standup.CheckData{Name: check.Name, Status: check.Status})
+		}
+	}
+	for _, teammate := range status.Team {
+		member := standup.TeammateData{User: teammate.Name, Submitted: teammate.Submitted}
+		if teammate.Away != nil {
+			member.OutOfOffice = teammate.Away.String()
+		}
+		data.Team = append(data.Team, member)
+	}
+	return data
+}
+
+// writeStatus prints a status for people
+func writeStatus(out io.Writer, user string, status *standupbot.Status) {
+	fmt.Fprintf(out, "Standups for %s\n\n", status.Date.Format("2006-01-02"))
+
+	fmt.Fprintf(out, "You: %s\n", submissionState(status.Submitted, status.Away))
+
+	if pr := status.PR; pr != nil {
+		fmt.Fprintf(out, "Pull request: #%s %s (%s)\n", pr.Number, pr.State, pr.URL)
+		for _, check := range pr.Checks {
+			fmt.Fprintf(out, "  %s %s\n", checkMarker(check.Status), check.Name)
+		}
+	} else {
+		fmt.Fprintln(out, "Pull request: none")
+	}
+
+	submitted := 0
+	for _, teammate := range status.Team {
+		if teammate.Submitted {
+			submitted++
+		}
+	}
+	fmt.Fprintf(out, "\nTeam (%d of %d submitted):\n", submitted, len(status.Team))
+	for _, teammate := range status.Team {
+		name := teammate.Name
+		if name == user {
+			name += " (you)"
+		}
+		fmt.Fprintf(out, "  %s: %s\n", name, submissionState(teammate.Submitted, teammate.Away))
+	}
+}
+
+// submissionState describes whether a standup is in
+func submissionState(submitted bool, away *standup.Absence) string {
+	switch {
+	case submitted:
+		return "✅ submitted"
+	case away != nil:
+		return fmt.Sprintf("🌴 out of office %s", away)
+	}
+	return "⏳ not submitted"
+}
+
+// checkMarker returns the marker for a check status
+func checkMarker(status string) string {
+	switch status {
+	case forge.CheckPass:
+		return "✅"
+	case forge.CheckFail:
+		return "❌"
+	}
+	return "⏳"
+}
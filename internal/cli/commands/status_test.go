@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+)
+
+func TestWriteStatus(t *testing.T) {
+	status := &standupbot.Status{
+		Date:      time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Submitted: true,
+		PR: &standupbot.PRStatus{
+			PRInfo: standupbot.PRInfo{Number: "12", URL: "https://github.com/org/standups/pull/12"},
+			State:  forge.StateMerged,
+			Checks: []forge.Check{{Name: "lint", Status: forge.CheckPass}, {Name: "test", Status: forge.CheckFail}},
+		},
+		Team: []standupbot.Teammate{
+			{Name: "alice", Submitted: true},
+			{Name: "bob"},
+			{Name: "carol", Away: &standup.Absence{From: "2024-01-15", To: "2024-01-19", Reason: "Vacation"}},
+		},
+	}
+
+	var out bytes.Buffer
+	writeStatus(&out, "alice", status)
+	for _, want := range []string{
+		"You: ✅ submitted",
+		"Pull request: #12 merged (https://github.com/org/standups/pull/12)",
+		"  ❌ test",
+		"Team (1 of 3 submitted):",
+		"  alice (you): ✅ submitted",
+		"  bob: ⏳ not submitted",
+		"  carol: 🌴 out of office 2024-01-15 to 2024-01-19 (Vacation)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("writeStatus() output is missing %q:\n%s", want, out.String())
+		}
+	}
+
+	data := statusData("alice", status)
+	if !data.PullRequest.Merged || len(data.PullRequest.Checks) != 2 || data.Team[2].OutOfOffice == "" {
+		t.Errorf("statusData() = %+v", data)
+	}
+}
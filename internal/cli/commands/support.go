@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+const (
+	// logsDirName is the directory, inside the config directory, holding transcripts
+	logsDirName = "logs"
+
+	// maxTranscripts is the number of transcript files kept on disk
+	maxTranscripts = 20
+
+	// redactedValue replaces personal values in support bundles
+	redactedValue = "[redacted]"
+)
+
+// StartTranscript opens a transcript log for this invocation and makes git
+// clients record every command to it. The returned function records the
+// invocation's final error, if any, and closes the log.
+func StartTranscript(configDir string) (func(error), error) {
+	logsDir := filepath.Join(configDir, logsDirName)
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%d.log", time.Now().UTC().Format("20060102-150405"), os.Getpid())
+	file, err := os.OpenFile(filepath.Join(logsDir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcript: %w", err)
+	}
+
+	fmt.Fprintf(file, "# standup-bot %s\n", strings.Join(os.Args[1:], " "))
+	transcript := git.NewTranscript(file)
+	git.SetTranscript(transcript)
+
+	pruneTranscripts(logsDir)
+
+	return func(err error) {
+		if err != nil {
+			transcript.RecordError(err)
+		}
+		git.SetTranscript(nil)
+		file.Close()
+	}, nil
+}
+
+// recentTranscripts returns transcript paths, newest first, up to limit
+func recentTranscripts(logsDir string, limit int) []string {
+	matches, _ := filepath.Glob(filepath.Join(logsDir, "*.log"))
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// pruneTranscripts removes all but the newest transcripts
+func pruneTranscripts(logsDir string) {
+	matches, _ := filepath.Glob(filepath.Join(logsDir, "*.log"))
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	for i := maxTranscripts; i < len(matches); i++ {
+		os.Remove(matches[i])
+	}
+}
+
+// RunSupportBundle writes a zip with diagnostics to attach to bug reports
+func RunSupportBundle(cfgManager *config.Manager, version, outputPath string) error {
+	if outputPath == "" {
+		outputPath = fmt.Sprintf("standup-bot-support-%s.zip", time.Now().Format("20060102-150405"))
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create support bundle: %w", err)
+	}
+	defer file.Close()
+
+	if err := writeSupportBundle(file, cfgManager, versionReport(git.NewClient(), version)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Support bundle written to %s\n", outputPath)
+	fmt.Println("Please review its contents before attaching it to a bug report.")
+	return nil
+}
+
+// bundleFile is a single file in a support bundle
+type bundleFile struct {
+	name    string
+	content string
+}
+
+// writeSupportBundle writes the redacted config, versions, recent errors and
+// recent transcripts as a zip archive
+func writeSupportBundle(w io.Writer, cfgManager *config.Manager, versions string) error {
+	archive := zip.NewWriter(w)
+
+	logsDir := filepath.Join(cfgManager.ConfigDir(), logsDirName)
+	transcripts := recentTranscripts(logsDir, maxTranscripts)
+
+	files := []bundleFile{
+		{"config.json", redactedConfig(cfgManager)},
+		{"versions.txt", versions},
+		{"errors.txt", recentErrors(transcripts)},
+	}
+	for _, path := range transcripts {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		files = append(files, bundleFile{"logs/" + filepath.Base(path), string(data)})
+	}
+
+	for _, f := range files {
+		entry, err := archive.Create(f.name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to support bundle: %w", f.name, err)
+		}
+		if _, err := io.WriteString(entry, f.content); err != nil {
+			return fmt.Errorf("failed to add %s to support bundle: %w", f.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	return nil
+}
+
+// redactedConfig returns the config file with personal values removed. The
+// file is read without validation so that broken configs can be reported.
+func redactedConfig(cfgManager *config.Manager) string {
+	data, err := os.ReadFile(cfgManager.ConfigFile())
+	if err != nil {
+		return fmt.Sprintf("configuration not readable: %v\n", err)
+	}
+
+	var cfg config.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Sprintf("configuration is not valid JSON: %v\n", err)
+	}
+
+	if cfg.Name != "" {
+		cfg.Name = redactedValue
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil && strings.HasPrefix(cfg.LocalRepoPath, homeDir) {
+		cfg.LocalRepoPath = "~" + cfg.LocalRepoPath[len(homeDir):]
+	}
+
+	out, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("failed to encode configuration: %v\n", err)
+	}
+	return string(out) + "\n"
+}
+
+// versionReport describes the standup-bot build, platform and external tools
+func versionReport(gitClient *git.Client, version string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "standup-bot: %s\n", version)
+	fmt.Fprintf(&b, "go: %s\n", runtime.Version())
+	fmt.Fprintf(&b, "platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	for _, tool := range []string{"git", "gh"} {
+		output, err := gitClient.ToolVersion(tool)
+		if err != nil {
+			output = fmt.Sprintf("not available (%v)", err)
+		}
+		fmt.Fprintf(&b, "%s: %s\n", tool, output)
+	}
+	return b.String()
+}
+
+// recentErrors collects failed commands and errors from the given transcripts
+func recentErrors(transcripts []string) string {
+	var b strings.Builder
+	for _, path := range transcripts {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			// Command output is indented, so only record lines are considered
+			line := scanner.Text()
+			if strings.HasPrefix(line, " ") {
+				continue
+			}
+			if strings.Contains(line, " FAIL ") || strings.Contains(line, " ERROR ") {
+				fmt.Fprintf(&b, "%s: %s\n", filepath.Base(path), line)
+			}
+		}
+		file.Close()
+	}
+	if b.Len() == 0 {
+		return "No recent errors recorded.\n"
+	}
+	return b.String()
+}
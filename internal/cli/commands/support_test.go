@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+func TestSupportBundle(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	cfg := &config.Config{
+		Repository:    "org/standups",
+		Name:          "Alice Example",
+		LocalRepoPath: filepath.Join(home, ".standup-bot", "repo"),
+	}
+	if err := cfgManager.Save(cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Record a run with a failing command
+	finish, err := StartTranscript(cfgManager.ConfigDir())
+	if err != nil {
+		t.Fatalf("StartTranscript() error = %v", err)
+	}
+	client := git.NewClientWithRunner(git.NewRecordingRunner(&fakeRunner{output: []byte("rejected"), err: errors.New("exit status 1")}, transcriptForTest(t, cfgManager)))
+	client.PushBranch(cfg.LocalRepoPath, "main")
+	finish(errors.New("failed to push changes"))
+
+	var buf bytes.Buffer
+	if err := writeSupportBundle(&buf, cfgManager, "standup-bot: test\n"); err != nil {
+		t.Fatalf("writeSupportBundle() error = %v", err)
+	}
+
+	files := readZip(t, buf.Bytes())
+
+	if strings.Contains(files["config.json"], "Alice") || !strings.Contains(files["config.json"], "org/standups") {
+		t.Errorf("config.json should redact the name and keep the repository:\n%s", files["config.json"])
+	}
+	if strings.Contains(files["config.json"], home) {
+		t.Errorf("config.json should not contain the home directory:\n%s", files["config.json"])
+	}
+	if files["versions.txt"] != "standup-bot: test\n" {
+		t.Errorf("versions.txt = %q", files["versions.txt"])
+	}
+	if !strings.Contains(files["errors.txt"], "ERROR failed to push changes") {
+		t.Errorf("errors.txt missing the run's error:\n%s", files["errors.txt"])
+	}
+
+	var logs int
+	for name := range files {
+		if strings.HasPrefix(name, "logs/") {
+			logs++
+		}
+	}
+	if logs == 0 {
+		t.Error("bundle should include recent transcripts")
+	}
+}
+
+func TestPruneTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < maxTranscripts+5; i++ {
+		name := filepath.Join(dir, "20240101-0000"+string(rune('a'+i))+".log")
+		if err := os.WriteFile(name, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pruneTranscripts(dir)
+
+	if got := len(recentTranscripts(dir, 100)); got != maxTranscripts {
+		t.Errorf("kept %d transcripts, want %d", got, maxTranscripts)
+	}
+}
+
+// transcriptForTest opens a transcript in the config's log directory
+func transcriptForTest(t *testing.T, cfgManager *config.Manager) *git.Transcript {
+	t.Helper()
+	file, err := os.Create(filepath.Join(cfgManager.ConfigDir(), logsDirName, "00000000-000000-test.log"))
+	if err != nil {
+		t.Fatalf("failed to create transcript: %v", err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return git.NewTranscript(file)
+}
+
+// readZip returns the contents of each file in a zip archive
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestRunLog(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	logsDir := filepath.Join(cfgManager.ConfigDir(), logsDirName)
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeLog(io.Discard, cfgManager, true); err == nil {
+		t.Error("writeLog() should fail when no runs were recorded")
+	}
+
+	older := "# standup-bot status\n2024-01-15T09:00:00Z RUN git status --porcelain exit=0 duration=5ms\n"
+	newer := "# standup-bot --merge\n2024-01-15T10:00:00Z FAIL gh pr merge --squash exit=1 duration=1.2s\n    not mergeable\n2024-01-15T10:00:01Z ERROR failed to merge pull request\n"
+	os.WriteFile(filepath.Join(logsDir, "20240115-090000-1.log"), []byte(older), 0600)
+	os.WriteFile(filepath.Join(logsDir, "20240115-100000-2.log"), []byte(newer), 0600)
+
+	var buf bytes.Buffer
+	if err := writeLog(&buf, cfgManager, true); err != nil {
+		t.Fatalf("writeLog() error = %v", err)
+	}
+	if output := buf.String(); output != newer {
+		t.Errorf("--last-run printed:\n%s\nwant the newest transcript:\n%s", output, newer)
+	}
+
+	buf.Reset()
+	if err := writeLog(&buf, cfgManager, false); err != nil {
+		t.Fatalf("writeLog() error = %v", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.Contains(lines[0], "failed") || !strings.Contains(lines[0], "standup-bot --merge") {
+		t.Errorf("first run listed = %q, want the failed merge", lines[0])
+	}
+	if !strings.Contains(lines[1], "ok") || !strings.Contains(lines[1], "standup-bot status") {
+		t.Errorf("second run listed = %q, want the status run", lines[1])
+	}
+}
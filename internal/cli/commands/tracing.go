@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+	"github.com/standup-bot/standup-bot/pkg/telemetry"
+)
+
+// serverTelemetry traces the requests of the long-running servers; nil
+// unless SetTelemetry was called
+var serverTelemetry *telemetry.Telemetry
+
+// SetTelemetry makes the MCP server, the issue webhook and the bot trace
+// each request, queued event and reminder check as a trace of its own
+func SetTelemetry(t *telemetry.Telemetry) {
+	serverTelemetry = t
+}
+
+// startRequest starts the trace of a request or of a tick of background
+// work. Without telemetry, ctx is returned as it is.
+func startRequest(ctx context.Context, operation string) (context.Context, func(err error)) {
+	if serverTelemetry == nil {
+		return ctx, func(error) {}
+	}
+	return serverTelemetry.StartRequest(ctx, operation)
+}
+
+// requestBot creates a bot whose steps and commands are traced with ctx
+func requestBot(ctx context.Context, cfg *config.Config) *standupbot.Bot {
+	bot := standupbot.New(cfg)
+	bot.SetContext(ctx)
+	return bot
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/update"
+)
+
+// RunVersion prints build information and, with check, whether a newer
+// release is available. Checks are skipped when disabled in the config.
+func RunVersion(cfgManager *config.Manager, version, buildInfo string, check bool) error {
+	fmt.Printf("standup-bot version %s\n", buildInfo)
+	fmt.Printf("%s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	if !check {
+		return nil
+	}
+
+	// A missing or invalid config shouldn't block the check
+	if cfg, err := cfgManager.Load(); err == nil && cfg.DisableUpdateCheck {
+		fmt.Println("\nUpdate checks are disabled (\"disableUpdateCheck\" in the config).")
+		return nil
+	}
+
+	return checkForUpdate(update.NewChecker(cfgManager.ConfigDir()), version)
+}
+
+// checkForUpdate reports whether the latest release is newer than version
+func checkForUpdate(checker *update.Checker, version string) error {
+	release, err := checker.Latest()
+	if err != nil {
+		return err
+	}
+
+	if version == "" || version == "dev" {
+		fmt.Printf("\nThis is a development build; the latest release is %s.\n", release.Version)
+		return nil
+	}
+
+	if !update.IsNewer(release.Version, version) {
+		fmt.Printf("\nYou are running the latest version (%s).\n", release.Version)
+		return nil
+	}
+
+	fmt.Printf("\nA newer version is available: %s\n", release.Version)
+	if release.URL != "" {
+		fmt.Printf("  %s\n", release.URL)
+	}
+	fmt.Println("  Upgrade with: brew upgrade standup-bot")
+	return nil
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+func TestRunVersionCheckDisabled(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	cfg := &config.Config{
+		Repository:         "owner/repo",
+		Name:               "alice",
+		LocalRepoPath:      filepath.Join(home, "repo"),
+		DisableUpdateCheck: true,
+	}
+	if err := cfgManager.Save(cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// With checks disabled no request is made and nothing is cached
+	if err := RunVersion(cfgManager, "1.0.0", "1.0.0", true); err != nil {
+		t.Fatalf("RunVersion() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfgManager.ConfigDir(), "update-check.json")); !os.IsNotExist(err) {
+		t.Error("update check should not run when disabled")
+	}
+}
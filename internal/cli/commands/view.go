@@ -0,0 +1,629 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+	"github.com/standup-bot/standup-bot/pkg/ui"
+)
+
+// Panes of the viewer, in the order Tab moves through them
+const (
+	paneDays = iota
+	paneUsers
+	paneDetail
+	paneCount
+)
+
+// Widths of the day and user panes
+const (
+	dayPaneWidth  = 13
+	userPaneWidth = 16
+)
+
+// ANSI styles used by the viewer and diff
+const (
+	styleReset   = "\033[0m"
+	styleBold    = "\033[1m"
+	styleDim     = "\033[2m"
+	styleReverse = "\033[7m"
+	styleRed     = "\033[31m"
+	styleGreen   = "\033[32m"
+)
+
+// RunView opens a read-only terminal browser over the team's standups, with
+// a pane of days, a pane of who submitted that day, and the standups. since
+// is an optional YYYY-MM-DD date to start from.
+func RunView(cfg *config.Config, since string) error {
+	if !ui.IsTerminal(os.Stdin) || !ui.IsTerminal(os.Stdout) {
+		return fmt.Errorf("view needs a terminal; use 'standup-bot status' or 'standup-bot export' in scripts")
+	}
+	sinceDate, err := parseOptionalDate(since)
+	if err != nil {
+		return fmt.Errorf("invalid --since date: %w", err)
+	}
+
+	entries, err := standupbot.NewManager(cfg).LoadAllEntries()
+	if err != nil {
+		return err
+	}
+	v := newViewer(entries, sinceDate)
+	if len(v.days) == 0 {
+		fmt.Println("No standups to show.")
+		return nil
+	}
+
+	restore, err := rawTerminal()
+	if err != nil {
+		return err
+	}
+	// Switch to the alternate screen and hide the cursor while browsing
+	fmt.Print("\033[?1049h\033[?25l")
+	defer func() {
+		fmt.Print("\033[?25h\033[?1049l")
+		restore()
+	}()
+
+	buf := make([]byte, 64)
+	for {
+		v.width, v.height = terminalSize()
+		fmt.Print(v.render())
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			if !v.handle(key) {
+				return nil
+			}
+		}
+	}
+}
+
+// viewDay is a day of standups in the viewer
+type viewDay struct {
+	date    time.Time
+	entries []viewEntry
+}
+
+// viewEntry is one member's standup in the viewer
+type viewEntry struct {
+	user  string
+	entry standup.Entry
+	// lines are the entry's text, lowercased for searching
+	lines []string
+}
+
+// blocked reports whether the standup reports a blocker
+func (e viewEntry) blocked() bool {
+	return standup.HasBlockers(e.entry.Blockers)
+}
+
+// viewer is the state of the terminal browser
+type viewer struct {
+	// days are newest first, and their entries ordered by user
+	days []viewDay
+
+	focus int
+	day   int
+	// user is the selected row of the user pane; 0 is the whole team
+	user   int
+	scroll int
+
+	query     string
+	searching bool
+	// blockersOnly hides standups without blockers
+	blockersOnly bool
+
+	width, height int
+}
+
+// newViewer arranges every user's standups by day, leaving out days before
+// since
+func newViewer(entries map[string][]standup.Entry, since time.Time) *viewer {
+	byDate := make(map[string]*viewDay)
+	for user, userEntries := range entries {
+		for _, entry := range userEntries {
+			if !since.IsZero() && entry.Date.Before(since) {
+				continue
+			}
+			key := entry.Date.Format("2006-01-02")
+			day, ok := byDate[key]
+			if !ok {
+				day = &viewDay{date: entry.Date}
+				byDate[key] = day
+			}
+			day.entries = append(day.entries, viewEntry{user: user, entry: entry, lines: searchLines(user, entry)})
+		}
+	}
+
+	v := &viewer{width: 80, height: 24}
+	for _, day := range byDate {
+		sort.Slice(day.entries, func(i, j int) bool {
+			return strings.ToLower(day.entries[i].user) < strings.ToLower(day.entries[j].user)
+		})
+		v.days = append(v.days, *day)
+	}
+	sort.Slice(v.days, func(i, j int) bool { return v.days[i].date.After(v.days[j].date) })
+	return v
+}
+
+// searchLines returns the text of an entry that searches match, lowercased
+func searchLines(user string, entry standup.Entry) []string {
+	lines := []string{user, entry.Blockers}
+	lines = append(lines, entry.Yesterday...)
+	lines = append(lines, entry.Today...)
+	for _, section := range entry.Sections {
+		lines = append(lines, section.Name)
+		lines = append(lines, section.Items...)
+	}
+	for i, line := range lines {
+		lines[i] = strings.ToLower(line)
+	}
+	return lines
+}
+
+// fuzzyMatch reports whether every word of the query appears, in order but
+// not necessarily together, within one of the lines, e.g. "lgn bug" matches
+// "fixed the login bug"
+func fuzzyMatch(query string, lines []string) bool {
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		found := false
+		for _, line := range lines {
+			if isSubsequence(word, line) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isSubsequence reports whether the runes of word appear in s in order
+func isSubsequence(word, s string) bool {
+	for _, r := range word {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+utf8.RuneLen(r):]
+	}
+	return true
+}
+
+// visibleDays returns the days with standups matching the search and the
+// blockers filter, holding only those standups
+func (v *viewer) visibleDays() []viewDay {
+	var days []viewDay
+	for _, day := range v.days {
+		var entries []viewEntry
+		for _, e := range day.entries {
+			if v.blockersOnly && !e.blocked() {
+				continue
+			}
+			if !fuzzyMatch(v.query, e.lines) {
+				continue
+			}
+			entries = append(entries, e)
+		}
+		if len(entries) > 0 {
+			days = append(days, viewDay{date: day.date, entries: entries})
+		}
+	}
+	return days
+}
+
+// selection returns the visible days, the selected day and the standups
+// shown for the selected user, clamping the selection to what is visible
+func (v *viewer) selection() ([]viewDay, *viewDay, []viewEntry) {
+	days := v.visibleDays()
+	if len(days) == 0 {
+		v.day, v.user = 0, 0
+		return days, nil, nil
+	}
+	v.day = clamp(v.day, 0, len(days)-1)
+	day := &days[v.day]
+	v.user = clamp(v.user, 0, len(day.entries))
+	if v.user == 0 {
+		return days, day, day.entries
+	}
+	return days, day, day.entries[v.user-1 : v.user]
+}
+
+// handle applies a key press, returning false to quit
+func (v *viewer) handle(key string) bool {
+	if v.searching {
+		switch key {
+		case "enter":
+			v.searching = false
+		case "esc":
+			v.searching, v.query = false, ""
+		case "backspace":
+			if v.query != "" {
+				_, size := utf8.DecodeLastRuneInString(v.query)
+				v.query = v.query[:len(v.query)-size]
+			}
+		case "ctrl+c":
+			return false
+		default:
+			if utf8.RuneCountInString(key) == 1 {
+				v.query += key
+			}
+		}
+		v.day, v.user, v.scroll = 0, 0, 0
+		return true
+	}
+
+	switch key {
+	case "q", "ctrl+c":
+		return false
+	case "/":
+		v.searching = true
+	case "esc":
+		v.query = ""
+	case "b":
+		v.blockersOnly = !v.blockersOnly
+		v.day, v.user, v.scroll = 0, 0, 0
+	case "tab":
+		v.focus = (v.focus + 1) % paneCount
+	case "right", "l":
+		v.focus = min(v.focus+1, paneCount-1)
+	case "left", "h":
+		v.focus = max(v.focus-1, paneDays)
+	case "up", "k":
+		v.move(-1)
+	case "down", "j":
+		v.move(1)
+	case "pgup":
+		v.scroll = max(v.scroll-v.pageSize(), 0)
+	case "pgdn":
+		v.scroll += v.pageSize()
+	}
+	return true
+}
+
+// move moves the selection of the focused pane, or scrolls the standups
+func (v *viewer) move(delta int) {
+	switch v.focus {
+	case paneDays:
+		v.day = max(v.day+delta, 0)
+		v.user, v.scroll = 0, 0
+	case paneUsers:
+		v.user = max(v.user+delta, 0)
+		v.scroll = 0
+	case paneDetail:
+		v.scroll = max(v.scroll+delta, 0)
+	}
+	// Keep the selection on a visible row
+	v.selection()
+}
+
+// pageSize is how many lines of standups fit on screen
+func (v *viewer) pageSize() int {
+	return max(v.height-2, 1)
+}
+
+// render draws the whole screen
+func (v *viewer) render() string {
+	days, day, shown := v.selection()
+	rows := v.pageSize()
+	detailWidth := max(v.width-dayPaneWidth-userPaneWidth-2, 10)
+
+	var dayCells, userCells []string
+	for i, d := range days {
+		label := d.date.Format("Mon Jan 02")
+		style := ""
+		if anyBlocked(d.entries) {
+			label += " !"
+			style = styleRed
+		}
+		dayCells = append(dayCells, v.cell(label, dayPaneWidth, style, i == v.day, v.focus == paneDays))
+	}
+	if day != nil {
+		userCells = append(userCells, v.cell(fmt.Sprintf("Team (%d)", len(day.entries)), userPaneWidth, styleBold, v.user == 0, v.focus == paneUsers))
+		for i, e := range day.entries {
+			style := ""
+			if e.blocked() {
+				style = styleRed
+			}
+			userCells = append(userCells, v.cell(e.user, userPaneWidth, style, v.user == i+1, v.focus == paneUsers))
+		}
+	}
+
+	detail := detailLines(shown, detailWidth)
+	v.scroll = clamp(v.scroll, 0, max(len(detail)-rows, 0))
+	detail = detail[v.scroll:]
+
+	var b strings.Builder
+	b.WriteString("\033[H")
+	b.WriteString(styleReverse + pad(v.header(day), v.width) + styleReset + "\033[K\r\n")
+	for row := 0; row < rows; row++ {
+		// The day and user panes scroll to keep the selection in view
+		b.WriteString(paneCell(dayCells, row, v.day, rows, dayPaneWidth))
+		b.WriteString(styleDim + "│" + styleReset)
+		b.WriteString(paneCell(userCells, row, v.user, rows, userPaneWidth))
+		b.WriteString(styleDim + "│" + styleReset)
+		if row < len(detail) {
+			b.WriteString(detail[row])
+		}
+		b.WriteString("\033[K\r\n")
+	}
+	b.WriteString(styleDim + truncate(v.footer(), v.width) + styleReset + "\033[K")
+	return b.String()
+}
+
+// header describes the selection and the filters
+func (v *viewer) header(day *viewDay) string {
+	header := " standup-bot view"
+	if day != nil {
+		header += " · " + day.date.Format("Monday, January 2, 2006")
+	}
+	if v.query != "" || v.searching {
+		header += " · search: " + v.query
+		if v.searching {
+			header += "▏"
+		}
+	}
+	if v.blockersOnly {
+		header += " · blockers only"
+	}
+	if day == nil {
+		header += " · no matches"
+	}
+	return header
+}
+
+// footer lists the keys
+func (v *viewer) footer() string {
+	if v.searching {
+		return " type to search · enter keep · esc clear"
+	}
+	return " ↑↓ move · ←→/tab pane · pgup/pgdn scroll · / search · b blockers · q quit"
+}
+
+// cell pads a pane entry, highlighting the selection, more strongly in the
+// focused pane
+func (v *viewer) cell(text string, width int, style string, selected, focused bool) string {
+	text = pad(" "+text, width)
+	switch {
+	case selected && focused:
+		style += styleReverse
+	case selected:
+		style += styleBold + "\033[4m"
+	}
+	if style == "" {
+		return text
+	}
+	return style + text + styleReset
+}
+
+// paneCell returns a pane's cell for a screen row, scrolling the pane so the
+// selected cell is visible
+func paneCell(cells []string, row, selected, rows, width int) string {
+	offset := max(selected-rows+1, 0)
+	if i := row + offset; i < len(cells) {
+		return cells[i]
+	}
+	return strings.Repeat(" ", width)
+}
+
+// detailLines lays out standups, wrapped to width, with blockers in red
+func detailLines(entries []viewEntry, width int) []string {
+	var lines []string
+	add := func(text, style string, indent int) {
+		for _, line := range wrap(text, width-indent-1) {
+			line = " " + strings.Repeat(" ", indent) + line
+			if style != "" {
+				line = style + line + styleReset
+			}
+			lines = append(lines, line)
+		}
+	}
+	section := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		add(title+":", styleDim, 0)
+		for _, item := range items {
+			add("• "+item, "", 2)
+		}
+	}
+
+	for i, e := range entries {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		name := e.user
+		if e.entry.AIAssisted {
+			name += " " + standup.AIAssistedMarker
+		}
+		add(name, styleBold, 0)
+		section("Yesterday", e.entry.Yesterday)
+		section("Today", e.entry.Today)
+		for _, s := range e.entry.Sections {
+			section(s.Name, s.Items)
+		}
+		if e.blocked() {
+			add("Blockers: "+e.entry.Blockers, styleRed+styleBold, 0)
+		} else {
+			add("Blockers: none", styleDim, 0)
+		}
+	}
+	return lines
+}
+
+// anyBlocked reports whether any of the standups reports a blocker
+func anyBlocked(entries []viewEntry) bool {
+	for _, e := range entries {
+		if e.blocked() {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap splits text into lines of at most width characters, at spaces where
+// possible
+func wrap(text string, width int) []string {
+	width = max(width, 1)
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				cut := runeOffset(word, width)
+				lines = append(lines, word[:cut])
+				word = word[cut:]
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pad truncates or pads text with spaces to width characters
+func pad(text string, width int) string {
+	text = truncate(text, width)
+	return text + strings.Repeat(" ", width-utf8.RuneCountInString(text))
+}
+
+// truncate shortens text to width characters, ending with … when cut
+func truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	if width < 1 {
+		return ""
+	}
+	return text[:runeOffset(text, width-1)] + "…"
+}
+
+// runeOffset returns the byte offset of the n-th character of s
+func runeOffset(s string, n int) int {
+	offset := 0
+	for i := 0; i < n && offset < len(s); i++ {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// clamp limits n to [low, high]
+func clamp(n, low, high int) int {
+	return max(low, min(n, high))
+}
+
+// parseKeys splits terminal input into key names: arrows and the like by
+// name, such as "up" or "pgdn", and other keys as the character typed
+func parseKeys(input []byte) []string {
+	sequences := map[string]string{
+		"\033[A": "up", "\033[B": "down", "\033[C": "right", "\033[D": "left",
+		"\033OA": "up", "\033OB": "down", "\033OC": "right", "\033OD": "left",
+		"\033[5~": "pgup", "\033[6~": "pgdn",
+	}
+
+	var keys []string
+	s := string(input)
+	for s != "" {
+		if s[0] == '\033' {
+			matched := false
+			for seq, name := range sequences {
+				if strings.HasPrefix(s, seq) {
+					keys = append(keys, name)
+					s = s[len(seq):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				keys = append(keys, "esc")
+				s = s[1:]
+			}
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		switch r {
+		case 3:
+			keys = append(keys, "ctrl+c")
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case '\t':
+			keys = append(keys, "tab")
+		case 127, 8:
+			keys = append(keys, "backspace")
+		default:
+			if r >= ' ' {
+				keys = append(keys, string(r))
+			}
+		}
+	}
+	return keys
+}
+
+// rawTerminal puts the terminal in raw mode with stty, so keys are read as
+// they are pressed, and returns a function restoring it
+func rawTerminal() (func(), error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the terminal settings: %w", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	return func() { stty(state) }, nil
+}
+
+// terminalSize returns the terminal's width and height, or 80x24 if stty
+// can't tell
+func terminalSize() (int, int) {
+	size, err := stty("size")
+	if err != nil {
+		return 80, 24
+	}
+	fields := strings.Fields(size)
+	if len(fields) != 2 {
+		return 80, 24
+	}
+	height, err1 := strconv.Atoi(fields[0])
+	width, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || width == 0 || height == 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// stty runs stty on the terminal attached to stdin
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
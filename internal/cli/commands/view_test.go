@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+// viewerFixture is two days of standups, with bob blocked on the second
+func viewerFixture() *viewer {
+	monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	return newViewer(map[string][]standup.Entry{
+		"alice": {
+			{Date: monday, Yesterday: []string{"Fixed the login bug"}, Blockers: "None"},
+			{Date: monday.AddDate(0, 0, 1), Today: []string{"Write the release notes"}, Blockers: "None"},
+		},
+		"bob": {
+			{Date: monday.AddDate(0, 0, 1), Today: []string{"Deploy"}, Blockers: "Waiting on the staging database"},
+		},
+	}, time.Time{})
+}
+
+func TestNewViewer(t *testing.T) {
+	v := viewerFixture()
+	if len(v.days) != 2 || v.days[0].date.Day() != 16 {
+		t.Fatalf("days should be newest first: %+v", v.days)
+	}
+	if users := []string{v.days[0].entries[0].user, v.days[0].entries[1].user}; !reflect.DeepEqual(users, []string{"alice", "bob"}) {
+		t.Errorf("entries should be ordered by user: %v", users)
+	}
+
+	entries := map[string][]standup.Entry{"alice": {v.days[0].entries[0].entry, v.days[1].entries[0].entry}}
+	if since := newViewer(entries, v.days[0].date); len(since.days) != 1 {
+		t.Errorf("days before since should be left out: %+v", since.days)
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	lines := []string{"alice", "fixed the login bug"}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"login", true},
+		{"lgn bug", true},
+		{"ALICE", true},
+		{"bug lgn", true},
+		{"logout", false},
+		{"alice deploy", false},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.query, lines); got != tt.want {
+			t.Errorf("fuzzyMatch(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestViewerFilters(t *testing.T) {
+	v := viewerFixture()
+
+	for _, key := range parseKeys([]byte("/lgn\r")) {
+		v.handle(key)
+	}
+	days := v.visibleDays()
+	if v.searching || len(days) != 1 || days[0].date.Day() != 15 {
+		t.Errorf("searching for lgn should leave Monday: %+v", days)
+	}
+
+	v.handle("esc")
+	v.handle("b")
+	days = v.visibleDays()
+	if len(days) != 1 || len(days[0].entries) != 1 || days[0].entries[0].user != "bob" {
+		t.Errorf("blockers only should leave bob's standup: %+v", days)
+	}
+}
+
+func TestViewerNavigation(t *testing.T) {
+	v := viewerFixture()
+
+	v.handle("down")
+	if _, day, _ := v.selection(); day.date.Day() != 15 {
+		t.Errorf("down should select the older day, got %v", day.date)
+	}
+	v.handle("down")
+	if v.day != 1 {
+		t.Errorf("the selection should stop at the last day, got %d", v.day)
+	}
+
+	v.handle("up")
+	v.handle("tab")
+	v.handle("down")
+	v.handle("down")
+	_, _, shown := v.selection()
+	if v.focus != paneUsers || len(shown) != 1 || shown[0].user != "bob" {
+		t.Errorf("selecting the second user should show bob's standup: %+v", shown)
+	}
+
+	if v.handle("q") {
+		t.Error("q should quit")
+	}
+}
+
+func TestViewerRender(t *testing.T) {
+	v := viewerFixture()
+	v.width, v.height = 100, 12
+	screen := v.render()
+
+	for _, want := range []string{"Tuesday, January 16, 2024", "Tue Jan 16 !", "Team (2)", "Write the release notes", styleRed + styleBold + " Blockers: Waiting on the staging database"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen should contain %q:\n%s", want, screen)
+		}
+	}
+	if lines := strings.Count(screen, "\r\n"); lines != v.height-1 {
+		t.Errorf("screen has %d lines, want %d", lines+1, v.height)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("j\033[A\033[6~\033/\x7f\t\x03é"))
+	want := []string{"j", "up", "pgdn", "esc", "/", "backspace", "tab", "ctrl+c", "é"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	got := wrap("Fixed the flaky integration test in CI", 12)
+	want := []string{"Fixed the", "flaky", "integration", "test in CI"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrap() = %q, want %q", got, want)
+	}
+	if got := wrap("abcdefghij", 4); !reflect.DeepEqual(got, []string{"abcd", "efgh", "ij"}) {
+		t.Errorf("wrap() of a long word = %q", got)
+	}
+}
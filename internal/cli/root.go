@@ -1,20 +1,37 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/standup-bot/standup-bot/internal/cli/commands"
 	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standupbot"
+	"github.com/standup-bot/standup-bot/pkg/telemetry"
 )
 
 var (
-	configFlag bool
-	directFlag bool
-	mergeFlag  bool
-	nameFlag   string
-	jsonFlag   string
-	outputFlag string
+	configFlag         bool
+	directFlag         bool
+	mergeFlag          bool
+	mergeAtFlag        string
+	mergeStrategyFlag  string
+	deleteBranchFlag   bool
+	autoMergeFlag      bool
+	requestReviewFlag  bool
+	appendFlag         bool
+	nameFlag           string
+	jsonFlag           string
+	outputFlag         string
+	forceFlag          bool
+	nonInteractiveFlag bool
+	noCacheFlag        bool
+	plainFlag          bool
 	
 	// Version information
 	version string
@@ -47,33 +64,897 @@ Examples:
   standup-bot --json standup.json --output json
 
   # Direct commit mode with JSON
-  standup-bot --direct --json '{"yesterday": ["Task A"], "today": ["Task B"]}' --output json`,
+  standup-bot --direct --json '{"yesterday": ["Task A"], "today": ["Task B"]}' --output json
+
+  # Add an update to today's standup later in the day
+  standup-bot --append --json '{"today": ["Shipped the fix"], "blockers": "None"}'`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			startTranscript(cmd)
+			startCache(cmd)
+			startPlain(cmd)
+			startTelemetry(cmd)
+			commands.SetNonInteractive(nonInteractiveFlag || !commands.StdinIsTerminal())
+			return commands.ValidateOutputFormat(outputFlag)
+		},
 		RunE: runStandup,
 	}
 	
+	mcpTransportFlag string
+	mcpAddrFlag      string
+
 	mcpServerCmd = &cobra.Command{
 		Use:   "mcp-server",
 		Short: "Run the MCP (Model Context Protocol) server",
 		Long: `Starts the standup-bot MCP server using stdio transport.
 This allows AI assistants to interact with standup-bot functionality.
 
+With --transport http, the server listens on --addr so remote agents can
+connect: POST JSON-RPC messages to /mcp, or open an SSE stream at /sse.
+Clients must send "Authorization: Bearer <token>" with the token set in
+STANDUP_BOT_MCP_TOKEN.
+
 The server exposes these tools:
 - submit_standup: Submit daily standup with yesterday/today/blockers
 - create_standup_pr: Create or manage standup pull requests
-- get_standup_status: Check if today's standup is complete`,
+- get_standup_status: Check if today's standup is complete
+- update_standup: Add items to today's standup without overwriting it
+- get_team_standups: Get every team member's standup for a day`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.RunMCPServer(commands.MCPServerOptions{
+				Transport: mcpTransportFlag,
+				Addr:      mcpAddrFlag,
+			})
+		},
+	}
+
+	supportBundleCmd = &cobra.Command{
+		Use:   "support-bundle [output.zip]",
+		Short: "Collect diagnostics for a bug report",
+		Long: `Writes a zip file to attach to bug reports. It contains the configuration
+with personal values redacted, version information, recent errors and the
+transcripts of recent runs (every git and gh command, its exit status,
+duration and truncated output).
+
+Transcripts are kept in ~/.standup-bot/logs.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgManager, err := config.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to initialize config manager: %w", err)
+			}
+
+			var outputPath string
+			if len(args) > 0 {
+				outputPath = args[0]
+			}
+			return commands.RunSupportBundle(cfgManager, buildVersion(), outputPath)
+		},
+	}
+
+	logLastRunFlag bool
+
+	logCmd = &cobra.Command{
+		Use:   "log",
+		Short: "Show the git and gh commands of recent runs",
+		Long: `Lists recent runs, newest first, with the command each ran and whether it
+failed. With --last-run, prints the newest run's transcript: every git and gh
+command with its exit status, duration and truncated output, and the error
+that ended the run, if any. Paste it into bug reports.
+
+Transcripts are kept in ~/.standup-bot/logs; runs of 'log' itself aren't
+recorded.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgManager, err := config.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to initialize config manager: %w", err)
+			}
+			return commands.RunLog(cfgManager, logLastRunFlag)
+		},
+	}
+
+	doctorJSONFlag bool
+
+	doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the environment and suggest fixes",
+		Long: `Checks that standup-bot can run: the binary's architecture, git
+installation and version (2.23 or newer), forge CLI installation and
+authentication, configuration validity, the repository clone, branch
+divergence, write permissions and network access to the remote.
+
+Prints a pass/fail checklist with suggested fixes, or a report with --json
+or --output json|yaml.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgManager, err := config.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to initialize config manager: %w", err)
+			}
+			outputFormat := outputFlag
+			if doctorJSONFlag {
+				outputFormat = "json"
+			}
+			return commands.RunDoctor(cfgManager, outputFormat)
+		},
+	}
+
+	bragSinceFlag     string
+	bragUntilFlag     string
+	bragUserFlag      string
+	bragSummarizeFlag bool
+
+	bragCmd = &cobra.Command{
+		Use:   "brag",
+		Short: "Compile your accomplishments into a brag document",
+		Long: `Compiles completed work (the "Yesterday" items) from a user's standup
+history into a markdown document grouped by month and project, ready for
+performance reviews. Tag items with a leading "[Project]" or a "#project"
+hashtag to group them; untagged items are listed under "Other".
+
+With --summarize, the AI provider configured under "ai" in the config adds a
+short summary at the top. AI features are off unless a provider is set.
+
+Examples:
+  standup-bot brag --since 2024-01-01
+  standup-bot brag --since 2024-01-01 --until 2024-06-30 --user alice > brag.md`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunBrag(cfg, bragUserFlag, bragSinceFlag, bragUntilFlag, bragSummarizeFlag)
+		},
+	}
+
+	statsSinceFlag  string
+	statsUntilFlag  string
+	statsFormatFlag string
+
+	statsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Show blocker and velocity trends for the team",
+		Long: `Computes metrics from the whole standup history, per user and for the
+team: standups submitted, average completed items per day, how often blockers
+were reported, current and longest streaks of weekday standups, and the most
+mentioned project tags ("[Project]" or "#project").
+
+The markdown report also lists every blocker in the period, for retros.
+
+Examples:
+  standup-bot stats
+  standup-bot stats --since 2024-06-01 --format markdown > retro.md
+  standup-bot stats --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunStats(cfg, statsSinceFlag, statsUntilFlag, formatFlag(cmd, statsFormatFlag))
+		},
+	}
+
+	leaderboardFormatFlag string
+
+	leaderboardCmd = &cobra.Command{
+		Use:   "leaderboard",
+		Short: "Rank the team by standup streak",
+		Long: `Ranks everyone by their current streak of consecutive working days with a
+standup, then by their longest streak. Weekends and out-of-office days don't
+break a streak.
+
+Streaks are computed from the standup repository, not stored locally, so
+they're the same on every machine.
+
+Examples:
+  standup-bot leaderboard
+  standup-bot leaderboard --format markdown`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunLeaderboard(cfg, formatFlag(cmd, leaderboardFormatFlag))
+		},
+	}
+
+	statusDateFlag string
+	statusJSONFlag bool
+
+	statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show who has submitted today and the state of the daily PR",
+		Long: `Shows whether you have submitted today's standup, the daily pull request
+with its state (open or merged) and CI checks, and which teammates have
+submitted or are out of office.
+
+Standups are read from the local clone, so in the pull request workflow
+teammates' unmerged standups show up once you're on the day's branch.
+
+Examples:
+  standup-bot status
+  standup-bot status --date 2024-01-15
+  standup-bot status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			outputFormat := outputFlag
+			if statusJSONFlag {
+				outputFormat = "json"
+			}
+			return commands.RunStatus(cfg, statusDateFlag, outputFormat)
+		},
+	}
+
+	viewSinceFlag string
+
+	viewCmd = &cobra.Command{
+		Use:   "view",
+		Short: "Browse the team's standups in the terminal",
+		Long: `Opens a read-only browser over the standups in the local clone: a pane of
+days, newest first, a pane of who submitted that day, and their standups.
+Days and members reporting blockers are shown in red.
+
+Keys: ↑/↓ or j/k move, ←/→, h/l or Tab switch panes, PgUp/PgDn scroll the
+standups, / searches (fuzzy: "lgn bug" finds "fixed the login bug"), b shows
+only standups with blockers, q quits.
+
+Examples:
+  standup-bot view
+  standup-bot view --since 2024-01-08`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunView(cfg, viewSinceFlag)
+		},
+	}
+
+	diffDateFlag string
+	diffUserFlag string
+
+	diffCmd = &cobra.Command{
+		Use:   "diff",
+		Short: "Show how a day's standup changed from commit to commit",
+		Long: `Walks the git history of your standup file and prints each version of the
+day's standup with what changed from the one before: added lines with "+",
+removed lines with "-". Commits that touched other days are left out. Useful
+when a standup was edited after the pull request was opened.
+
+The history comes from the day's standup branch when the clone has it,
+otherwise from the checked out branch, including uncommitted edits.
+
+Examples:
+  standup-bot diff
+  standup-bot diff --date 2024-01-15 --user alice`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunDiff(cfg, diffUserFlag, diffDateFlag)
+		},
+	}
+
+	oooFromFlag   string
+	oooToFlag     string
+	oooReasonFlag string
+	oooForceFlag  bool
+
+	oooCmd = &cobra.Command{
+		Use:   "ooo",
+		Short: "Record that you are out of office",
+		Long: `Records an out-of-office period in the standup repository and pushes it to
+the base branch. Standups aren't expected on those days: they don't break
+streaks in 'stats', and the daily PR lists you as away.
+
+--from defaults to today and --to to the same day as --from. Recording the
+same days again updates the reason.
+
+Examples:
+  standup-bot ooo --reason "Sick"
+  standup-bot ooo --from 2024-07-01 --to 2024-07-12 --reason "Vacation"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunOutOfOffice(cfg, oooFromFlag, oooToFlag, oooReasonFlag, oooForceFlag)
+		},
+	}
+
+	exportFormatFlag string
+	exportSinceFlag  string
+	exportUntilFlag  string
+	exportOutFlag    string
+
+	exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export standups for spreadsheets",
+		Long: `Writes everyone's standups as CSV with one row per user, date, section and
+item, ready to open in Excel or Google Sheets and pivot on. Placeholder items
+and "None" blockers are left out.
+
+Examples:
+  standup-bot export --since 2024-01-01 > standups.csv
+  standup-bot export --format csv --since 2024-01-01 --until 2024-03-31 --out q1.csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunExport(cfg, exportFormatFlag, exportSinceFlag, exportUntilFlag, exportOutFlag)
+		},
+	}
+
+	publishOutFlag    string
+	publishBranchFlag string
+	publishForceFlag  bool
+
+	publishCmd = &cobra.Command{
+		Use:   "publish",
+		Short: "Publish the standup history as a static website",
+		Long: `Renders everyone's standups as a static site: an index by date with a
+search box, a page per person and a blockers dashboard.
+
+By default the site is committed to the docs/ folder of the base branch, which
+GitHub Pages can serve. --branch commits it to its own branch instead, such as
+gh-pages, and --out only writes it to a local directory.
+
+Examples:
+  standup-bot publish
+  standup-bot publish --branch gh-pages
+  standup-bot publish --out /tmp/standup-site`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunPublish(cfg, commands.PublishOptions{
+				Out:    publishOutFlag,
+				Branch: publishBranchFlag,
+				Force:  publishForceFlag,
+			})
+		},
+	}
+
+	demoJSONFlag string
+	demoKeepFlag bool
+
+	demoCmd = &cobra.Command{
+		Use:   "demo",
+		Short: "Try standup-bot against a sandbox team",
+		Long: `Creates a throwaway local repository with a few generated teammates and two
+weeks of standups, then walks through submitting a standup, checking who has
+submitted, reading the day's standups and compiling a brag document.
+
+Nothing leaves your machine and your own config and repository are not
+touched. The sandbox is deleted afterwards unless --keep is given. With
+--json the standup is taken from JSON and the demo runs without pausing,
+for scripted runs and documentation.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.RunDemo(commands.DemoOptions{JSON: demoJSONFlag, Keep: demoKeepFlag})
+		},
+	}
+
+	examplesCmd = &cobra.Command{
+		Use:   "examples [topic]",
+		Short: "Show examples filled in with your settings",
+		Long: `Prints copy-pastable examples for a topic, filled in with your configured
+repository, name, base branch and team template. Without a topic, lists the
+topics:
+
+  json   Submit a standup from JSON, a file or a script
+  cron   Submit and merge on a schedule with cron
+  ci     Merge the daily PR from a scheduled CI job`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgManager, err := config.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to initialize config manager: %w", err)
+			}
+
+			var topic string
+			if len(args) > 0 {
+				topic = args[0]
+			}
+			return commands.RunExamples(cfgManager, topic, os.Stdout)
+		},
+	}
+
+	issuesForceFlag bool
+
+	issuesCmd = &cobra.Command{
+		Use:   "issues",
+		Short: "Submit standups through GitHub issues",
+		Long: `Lets teammates submit standups by opening a GitHub issue from a form, for
+example from the GitHub mobile app when they are away from a terminal.
+
+'issues template' adds the form to the standup repository. 'issues import'
+records each open standup issue as its author's standup, then closes it with
+a comment. Run it on a schedule or on issue events in CI. 'issues webhook'
+records them as soon as they are opened, from a GitHub webhook.
+
+'issues daily' opens an issue for the day that teammates comment their
+standups on instead. 'issues webhook', or 'gh-action' on issue_comment
+events, records each comment through the day's pull request.`,
+	}
+
+	issuesTemplateCmd = &cobra.Command{
+		Use:   "template",
+		Short: "Add the standup issue form to the standup repository",
+		Long: `Generates .github/ISSUE_TEMPLATE/standup.yml from the standup questions,
+including your team's template sections, then commits and pushes it to the
+base branch. Run it again after changing the template.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunIssueTemplate(cfg, issuesForceFlag)
+		},
+	}
+
+	issuesImportCmd = &cobra.Command{
+		Use:   "import",
+		Short: "Record standups submitted as issues and close the issues",
+		Long: `Reads the open issues labeled "standup", commits each as a standup for
+the day the issue was opened, and closes it with a confirmation. Issues that
+can't be read are closed with the reason. Requires GitHub and gh.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunIssueImport(cfg, issuesForceFlag)
+		},
+	}
+
+	issuesDailyCmd = &cobra.Command{
+		Use:   "daily",
+		Short: "Open today's issue for teammates to comment their standups on",
+		Long: `Opens an issue titled "Standups for YYYY-MM-DD", labeled "standup-daily",
+with a form to copy into a comment. Daily issues of earlier days are closed.
+Does nothing if today's issue is already open, so it can run on a schedule in
+CI. Requires GitHub and gh.
+
+Comments are recorded for the issue's day by 'issues webhook', with a reply
+saying whether it worked, or by 'gh-action' in a workflow on issue_comment
+events. Comments that aren't standups are left alone.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunIssueDaily(cfg)
+		},
+	}
+
+	issuesAddrFlag string
+
+	issuesWebhookCmd = &cobra.Command{
+		Use:   "webhook",
+		Short: "Record standup issues as they are opened, from a GitHub webhook",
+		Long: `Serves a webhook endpoint at /webhook for the standup repository's "Issues",
+"Issue comments", "Pushes" and "Pull requests" events. Each issue opened with
+the standup form is validated, committed as a standup and closed with a
+confirmation within seconds, the same way 'issues import' records it. Open
+issues are imported once on startup. Standups commented on a daily issue (see
+'issues daily') are added to the day's pull request, with a reply. With teams
+configured, each event is handled in the repository it came from.
+
+A push to a daily standup branch rebuilds its pull request's description, and
+a merged daily pull request is announced on Slack when
+STANDUP_BOT_SLACK_WEBHOOK is set, its digest emailed when the config has
+"digest" settings, and its standups exported to the team's Notion or
+Confluence. Deliveries are handled once; a replayed delivery is rejected.
+
+Deliveries must be signed with the webhook secret in STANDUP_BOT_WEBHOOK_SECRET.
+Requires GitHub and gh.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgs, err := loadAllConfigs()
+			if err != nil {
+				return err
+			}
+			return commands.RunIssueWebhook(cfgs, issuesAddrFlag, issuesForceFlag)
+		},
+	}
+
+	botMCPAddrFlag     string
+	botWebhookAddrFlag string
+	botForceFlag       bool
+
+	botCmd = &cobra.Command{
+		Use:   "bot",
+		Short: "Run a shared bot instance for the team, e.g. in a container",
+		Long: `Runs the MCP server over HTTP and the issue webhook in one process, so a
+team can share one bot instance. It never prompts and needs no config file:
+
+  STANDUP_BOT_REPOSITORY      Standup repository (org/repo or a git URL)
+  STANDUP_BOT_NAME            Name the bot records standups under by default
+  STANDUP_BOT_REPO_PATH       Writable path to clone the repository to
+                              (default $STANDUP_BOT_CONFIG_DIR/repo)
+  STANDUP_BOT_CONFIG_DIR      Writable directory for state (default ~/.standup-bot)
+  STANDUP_BOT_MCP_TOKEN       Serves MCP on --mcp-addr when set
+  STANDUP_BOT_WEBHOOK_SECRET  Serves the issue webhook on --webhook-addr when set
+  GH_TOKEN                    Token gh and git push authenticate with, instead
+                              of 'gh auth login'
+
+STANDUP_BOT_FORGE, STANDUP_BOT_BASE_BRANCH and STANDUP_BOT_TIMEZONE override
+the other settings. STANDUP_BOT_TEAMS (team=org/repo,...) serves more teams,
+each from its own repository; MCP requests pick one with their "team"
+argument and webhook issues are recorded in the repository they came from.
+The repositories are cloned on first start. Both servers answer /healthz,
+/readyz and /metrics. With STANDUP_BOT_SLACK_WEBHOOK set, the reminders of
+teams with a meeting calendar are posted as they fall due, as 'remind' would.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgs, err := loadAllConfigs()
+			if err != nil {
+				return err
+			}
+			return commands.RunBot(cfgs, commands.BotOptions{
+				MCPAddr:     botMCPAddrFlag,
+				WebhookAddr: botWebhookAddrFlag,
+				Force:       botForceFlag,
+			})
+		},
+	}
+
+	ghActionDirectFlag bool
+	ghActionForceFlag  bool
+
+	ghActionCmd = &cobra.Command{
+		Use:   "gh-action",
+		Short: "Record a standup from a GitHub Actions workflow",
+		Long: `Records the standup in the event that started a GitHub Actions workflow:
+
+- issue_comment: the comment holds JSON input, or "### Yesterday", "### Today"
+  and "### Blockers" sections like the issue form, and may start with /standup.
+  It is recorded for the comment's author unless a "### Name" section is given.
+- workflow_dispatch: a "json" input, or "yesterday" and "today" inputs with one
+  item per line and "blockers". It is recorded for "name" or the user who ran
+  the workflow.
+
+The pr-number and file-path outputs are written to $GITHUB_OUTPUT, and a
+failure is reported as an error annotation. Without a configuration, the
+workflow's repository and checkout are used, so nothing needs installing
+beyond the binary. Requires GH_TOKEN for the pull request workflow.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgManager, err := config.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to initialize config manager: %w", err)
+			}
+			return commands.RunGHAction(cfgManager, commands.GHActionOptions{
+				Direct: ghActionDirectFlag,
+				Force:  ghActionForceFlag,
+			})
+		},
+	}
+
+	prCmd = &cobra.Command{
+		Use:   "pr",
+		Short: "Manage the daily standup pull request",
+	}
+
+	prDateFlag  string
+	prForceFlag bool
+
+	prRefreshCmd = &cobra.Command{
+		Use:   "refresh",
+		Short: "Rebuild the daily PR description from the standups on its branch",
+		Long: `Reads every standup on the day's branch and replaces the pull request
+description with them. Run it when the description has gone stale, for
+example after someone force-pushed or edited their standup directly.
+
+Examples:
+  standup-bot pr refresh
+  standup-bot pr refresh --date 2024-01-15`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunPRRefresh(cfg, prDateFlag, prForceFlag, outputFlag)
+		},
+	}
+
+	prDigestCmd = &cobra.Command{
+		Use:   "digest",
+		Short: "Email the digest of the day's merged standups",
+		Long: `Emails the standups for the day, as merged into the base branch, to the
+digest's recipients: an HTML version with a plain text fallback. Members
+listed under digest.optOut in the team config are left out.
+
+Merging the daily PR with 'standup-bot --merge', or through 'issues webhook',
+sends the digest already; run this to resend it. Needs a "digest" section in
+the config with the SMTP server, and the password in STANDUP_BOT_SMTP_PASSWORD.
+
+Examples:
+  standup-bot pr digest
+  standup-bot pr digest --date 2024-01-15`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunPRDigest(cfg, prDateFlag, prForceFlag)
+		},
+	}
+
+	prExportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Publish the day's merged standups to Notion or Confluence",
+		Long: `Publishes the standups for the day, as merged into the base branch, to the
+Notion database and Confluence space in the "export" section of the team
+config. With export.period set to weekly, the page holds the week so far.
+Publishing again replaces the page.
+
+Merging the daily PR with 'standup-bot --merge', or through 'issues webhook',
+exports the standups already; run this to export them again. The API tokens
+are read from STANDUP_BOT_NOTION_TOKEN and STANDUP_BOT_CONFLUENCE_TOKEN.
+
+Examples:
+  standup-bot pr export
+  standup-bot pr export --date 2024-01-15`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return commands.RunMCPServer()
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunPRExport(cfg, prDateFlag, prForceFlag)
 		},
 	}
+
+	remindCmd = &cobra.Command{
+		Use:   "remind",
+		Short: "Remind the team to submit standups before the meeting",
+		Long: `Posts a reminder before each standup meeting in the team's calendar, to
+the Slack webhook in STANDUP_BOT_SLACK_WEBHOOK, and prints it. Reminders
+follow the actual meeting time, so moved meetings move their reminder and
+cancelled meetings have none.
+
+The calendar is the iCal address in meeting.calendar of the team config, or
+STANDUP_BOT_CALENDAR_URL. Reminders fire meeting.remindBefore (15m by
+default) before meetings whose title contains meeting.summary.
+
+Run it from cron as often as --window; each run posts the reminders that
+fell due within the last window.
+
+Examples:
+  */15 * * * * standup-bot remind --window 15m
+  standup-bot remind ics --out reminders.ics`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunRemind(cfg, remindWindowFlag)
+		},
+	}
+
+	remindWindowFlag time.Duration
+
+	remindICSCmd = &cobra.Command{
+		Use:   "ics",
+		Short: "Write the reminder schedule as an iCalendar file",
+		Long: `Writes the reminders for the coming days as an .ics file, each with a
+notification, for members to import or subscribe to. Regenerate it, for
+example from cron into a published directory, to pick up moved meetings.
+
+Examples:
+  standup-bot remind ics > reminders.ics
+  standup-bot remind ics --out site/reminders.ics --days 60`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunRemindICS(cfg, remindOutFlag, remindDaysFlag)
+		},
+	}
+
+	remindOutFlag  string
+	remindDaysFlag int
+
+	importFormatFlag  string
+	importUserFlag    string
+	importAuthorsFlag map[string]string
+	importDryRunFlag  bool
+	importForceFlag   bool
+
+	importCmd = &cobra.Command{
+		Use:   "import <path>",
+		Short: "Import standup history from Slack, Geekbot, Standuply or markdown",
+		Long: `Records standups kept in another tool in the standup repository, dated and
+attributed as they were posted, in one commit to the base branch. Days a
+member already has a standup for are left as they are.
+
+  slack     A channel's directory from a Slack export, or one of its daily
+            JSON files. Members are named by their Slack username from the
+            export's users.json; messages without yesterday or today
+            sections are skipped.
+  csv       A Geekbot or Standuply export: a Member or User column, a Date or
+            Timestamp column, and a column per question or Question and
+            Answer columns. Questions are matched to sections by wording.
+  markdown  Standups under date headings (YYYY-MM-DD), with sections such as
+            "Yesterday:" or "### Today". Files written by standup-bot name
+            their author; pass --user for others.
+
+The format is detected from the path unless --format is given. Use --author
+to map names in the archive to standup names.
+
+Examples:
+  standup-bot import export/standup --dry-run
+  standup-bot import geekbot.csv --author jdoe=Jane
+  standup-bot import notes.md --user alice`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunImport(cfg, args[0], commands.ImportOptions{
+				Format:  importFormatFlag,
+				User:    importUserFlag,
+				Authors: importAuthorsFlag,
+				DryRun:  importDryRunFlag,
+				Force:   importForceFlag,
+			})
+		},
+	}
+
+	indexCmd = &cobra.Command{
+		Use:   "index",
+		Short: "Manage the local index of the standup history",
+		Long: `History, stats, search and team status read a local index of every
+standup rather than parsing each file on every command. The index is kept in
+the clone's .git directory and brought up to date as it is read: a user's
+standups are parsed again when one of their files has changed.`,
+	}
+
+	indexRebuildCmd = &cobra.Command{
+		Use:   "rebuild",
+		Short: "Rebuild the index from the standup files",
+		Long: `Discards the local index and builds it again from every standup file in the
+repository. It is never needed for correctness, but rebuilds an index that
+was copied from another machine or that you suspect is out of date.
+
+Examples:
+  standup-bot index rebuild`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return commands.RunIndexRebuild(cfg)
+		},
+	}
+
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Set, show or edit the configuration",
+		Long: `Manages ~/.standup-bot/config.json without the interactive setup.
+
+'config set' changes settings from flags, validates them and clones the
+repository, or checks it is reachable, before saving. 'config show' prints
+the settings and 'config edit' opens them in $VISUAL or $EDITOR.`,
+	}
+
+	configSettings commands.ConfigSettings
+
+	configSetCmd = &cobra.Command{
+		Use:   "set",
+		Short: "Change settings from flags",
+		Long: `Changes the given settings, keeping the others, then validates the whole
+configuration and clones the repository if it isn't cloned yet.
+
+  standup-bot config set --repository org/standups --name "Jane Doe" --path ~/standups`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgManager, err := config.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to initialize config manager: %w", err)
+			}
+			return commands.RunConfigSet(cfgManager, configSettings)
+		},
+	}
+
+	configShowCmd = &cobra.Command{
+		Use:   "show",
+		Short: "Print the configuration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgManager, err := config.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to initialize config manager: %w", err)
+			}
+			return commands.RunConfigShow(cfgManager, outputFlag)
+		},
+	}
+
+	configEditCmd = &cobra.Command{
+		Use:   "edit",
+		Short: "Open the configuration in your editor and validate it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgManager, err := config.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to initialize config manager: %w", err)
+			}
+			return commands.RunConfigEdit(cfgManager)
+		},
+	}
+
+	versionCheckFlag bool
+
+	versionCmd = &cobra.Command{
+		Use:   "version",
+		Short: "Print version information and check for updates",
+		Long: `Prints the version, commit and build date of this binary.
+
+With --check, queries GitHub Releases for a newer version. The result is
+cached for a day in ~/.standup-bot/update-check.json. Set
+"disableUpdateCheck": true in the config to turn checks off.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgManager, err := config.NewManager()
+			if err != nil {
+				return fmt.Errorf("failed to initialize config manager: %w", err)
+			}
+			return commands.RunVersion(cfgManager, version, buildVersion(), versionCheckFlag)
+		},
+	}
+
+	// finishTranscript records the final error and closes this run's transcript
+	finishTranscript = func(error) {}
+
+	// finishPlain writes the rest of this run's plain output
+	finishPlain = func() {}
+
+	// finishTelemetry records the run's result and exports its telemetry
+	finishTelemetry = func(error) {}
 )
 
 func init() {
 	rootCmd.Flags().BoolVar(&configFlag, "config", false, "Run configuration setup")
 	rootCmd.Flags().BoolVar(&directFlag, "direct", false, "Use direct commit workflow (multi-line commit message)")
 	rootCmd.Flags().BoolVar(&mergeFlag, "merge", false, "Merge today's standup pull request")
+	rootCmd.Flags().StringVar(&mergeAtFlag, "at", "", "With --merge, wait until this time of day (HH:MM) before merging")
+	rootCmd.Flags().StringVar(&mergeStrategyFlag, "strategy", "", "With --merge, merge with 'squash', 'merge' or 'rebase' instead of the team's strategy (default squash)")
+	rootCmd.Flags().BoolVar(&deleteBranchFlag, "delete-branch", true, "With --merge, delete the daily branch once merged")
+	rootCmd.Flags().BoolVar(&autoMergeFlag, "auto", false, "Turn on auto-merge for the daily PR, so it merges once checks and reviews pass (when recording or with --merge)")
+	rootCmd.Flags().BoolVar(&requestReviewFlag, "request-review", false, "Ask the team's approvers (pullRequest.approvers) to review today's PR")
+	rootCmd.Flags().BoolVar(&appendFlag, "append", false, "Add the standup as a timestamped update under today's standup instead of replacing it")
 	rootCmd.Flags().StringVar(&nameFlag, "name", "", "Override configured name (useful for testing)")
 	rootCmd.Flags().StringVar(&jsonFlag, "json", "", "Accept standup data as JSON (direct string, file path, or '-' for stdin)")
-	rootCmd.Flags().StringVar(&outputFlag, "output", "", "Output format: 'json' for machine-readable output")
+	rootCmd.PersistentFlags().StringVar(&outputFlag, "output", "", "Output format: 'table', 'json' or 'yaml' ('json=v1' for the legacy flat shape)")
+	rootCmd.Flags().BoolVar(&forceFlag, "force", false, "Proceed even if the standup repository has uncommitted non-standup changes, or with --merge, checks are failing or reviews missing")
+	rootCmd.PersistentFlags().BoolVar(&nonInteractiveFlag, "non-interactive", false, "Never prompt; fail when input is needed (the default when stdin isn't a terminal)")
+	rootCmd.PersistentFlags().BoolVar(&noCacheFlag, "no-cache", false, "Don't reuse gh lookups (auth status, open pull requests, default branch) from the last couple of minutes")
+	rootCmd.PersistentFlags().BoolVar(&plainFlag, "plain", false, "Print without emoji, colors or box-drawing, for CI logs and screen readers (also when NO_COLOR is set)")
 	
 	// Set version template
 	rootCmd.Version = buildVersion()
@@ -82,6 +963,113 @@ func init() {
 	
 	// Add subcommands
 	rootCmd.AddCommand(mcpServerCmd)
+	mcpServerCmd.Flags().StringVar(&mcpTransportFlag, "transport", "stdio", "Transport: 'stdio', or 'http' for remote clients (HTTP and SSE)")
+	mcpServerCmd.Flags().StringVar(&mcpAddrFlag, "addr", ":8972", "Listen address for the http transport")
+	rootCmd.AddCommand(supportBundleCmd)
+	rootCmd.AddCommand(logCmd)
+	logCmd.Flags().BoolVar(&logLastRunFlag, "last-run", false, "Print the transcript of the last run")
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&doctorJSONFlag, "json", false, "Print the report as JSON")
+
+	rootCmd.AddCommand(bragCmd)
+	bragCmd.Flags().StringVar(&bragSinceFlag, "since", "", "Only include standups on or after this date (YYYY-MM-DD)")
+	bragCmd.Flags().StringVar(&bragUntilFlag, "until", "", "Only include standups on or before this date (YYYY-MM-DD)")
+	bragCmd.Flags().StringVar(&bragUserFlag, "user", "me", "User whose standups to compile ('me' for the configured name)")
+	bragCmd.Flags().BoolVar(&bragSummarizeFlag, "summarize", false, "Add a summary written by the configured AI provider")
+
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().StringVar(&statsSinceFlag, "since", "", "Only include standups on or after this date (YYYY-MM-DD)")
+	statsCmd.Flags().StringVar(&statsUntilFlag, "until", "", "Only include standups on or before this date (YYYY-MM-DD)")
+	statsCmd.Flags().StringVar(&statsFormatFlag, "format", "table", "Output format: 'table', 'json' or 'markdown'")
+
+	rootCmd.AddCommand(leaderboardCmd)
+	leaderboardCmd.Flags().StringVar(&leaderboardFormatFlag, "format", "table", "Output format: 'table', 'json' or 'markdown'")
+
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().StringVar(&statusDateFlag, "date", "", "Day to report on (YYYY-MM-DD, default today)")
+	statusCmd.Flags().BoolVar(&statusJSONFlag, "json", false, "Print the status as JSON, e.g. for dashboards")
+
+	rootCmd.AddCommand(viewCmd)
+	viewCmd.Flags().StringVar(&viewSinceFlag, "since", "", "Only show standups on or after this date (YYYY-MM-DD)")
+
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVar(&diffDateFlag, "date", "", "Day whose standup to show (YYYY-MM-DD, default today)")
+	diffCmd.Flags().StringVar(&diffUserFlag, "user", "me", "User whose standup to show ('me' for the configured name)")
+
+	rootCmd.AddCommand(oooCmd)
+	oooCmd.Flags().StringVar(&oooFromFlag, "from", "", "First day out of office (YYYY-MM-DD, default today)")
+	oooCmd.Flags().StringVar(&oooToFlag, "to", "", "Last day out of office (YYYY-MM-DD, default --from)")
+	oooCmd.Flags().StringVar(&oooReasonFlag, "reason", "", "Why you are out, e.g. \"Vacation\"")
+	oooCmd.Flags().BoolVar(&oooForceFlag, "force", false, "Proceed even if the standup repository has uncommitted non-standup changes")
+
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFormatFlag, "format", "csv", "Output format: 'csv'")
+	exportCmd.Flags().StringVar(&exportSinceFlag, "since", "", "Only include standups on or after this date (YYYY-MM-DD)")
+	exportCmd.Flags().StringVar(&exportUntilFlag, "until", "", "Only include standups on or before this date (YYYY-MM-DD)")
+	exportCmd.Flags().StringVar(&exportOutFlag, "out", "", "Write to this file instead of stdout")
+
+	rootCmd.AddCommand(publishCmd)
+	publishCmd.Flags().StringVar(&publishOutFlag, "out", "", "Write the site to this directory instead of committing it")
+	publishCmd.Flags().StringVar(&publishBranchFlag, "branch", "", "Commit the site to this branch (such as gh-pages) instead of docs/ on the base branch")
+	publishCmd.Flags().BoolVar(&publishForceFlag, "force", false, "Proceed even if the standup repository has uncommitted non-standup changes")
+	publishCmd.MarkFlagsMutuallyExclusive("out", "branch")
+
+	rootCmd.AddCommand(demoCmd)
+	demoCmd.Flags().StringVar(&demoJSONFlag, "json", "", "Submit this standup JSON instead of asking (direct string, file path, or '-' for stdin)")
+	demoCmd.Flags().BoolVar(&demoKeepFlag, "keep", false, "Keep the sandbox repository when the demo ends")
+
+	rootCmd.AddCommand(examplesCmd)
+
+	rootCmd.AddCommand(issuesCmd)
+	issuesCmd.AddCommand(issuesTemplateCmd, issuesImportCmd, issuesDailyCmd, issuesWebhookCmd)
+	issuesWebhookCmd.Flags().StringVar(&issuesAddrFlag, "addr", ":8973", "Listen address for the webhook endpoint")
+	issuesCmd.PersistentFlags().BoolVar(&issuesForceFlag, "force", false, "Proceed even if the standup repository has uncommitted non-standup changes")
+
+	rootCmd.AddCommand(botCmd)
+	botCmd.Flags().StringVar(&botMCPAddrFlag, "mcp-addr", ":8972", "Listen address for the MCP server")
+	botCmd.Flags().StringVar(&botWebhookAddrFlag, "webhook-addr", ":8973", "Listen address for the issue webhook")
+	botCmd.Flags().BoolVar(&botForceFlag, "force", false, "Record issues even if the standup repository has uncommitted non-standup changes")
+
+	rootCmd.AddCommand(ghActionCmd)
+	ghActionCmd.Flags().BoolVar(&ghActionDirectFlag, "direct", false, "Commit to the base branch instead of the daily pull request")
+	ghActionCmd.Flags().BoolVar(&ghActionForceFlag, "force", false, "Proceed even if the standup repository has uncommitted non-standup changes")
+
+	rootCmd.AddCommand(prCmd)
+	prCmd.AddCommand(prRefreshCmd, prDigestCmd, prExportCmd)
+	prRefreshCmd.Flags().StringVar(&prDateFlag, "date", "", "Day of the pull request (YYYY-MM-DD, default today)")
+	prRefreshCmd.Flags().BoolVar(&prForceFlag, "force", false, "Proceed even if the standup repository has uncommitted non-standup changes")
+	prDigestCmd.Flags().StringVar(&prDateFlag, "date", "", "Day of the merged standups (YYYY-MM-DD, default today)")
+	prDigestCmd.Flags().BoolVar(&prForceFlag, "force", false, "Proceed even if the standup repository has uncommitted non-standup changes")
+	prExportCmd.Flags().StringVar(&prDateFlag, "date", "", "Day of the merged standups (YYYY-MM-DD, default today)")
+	prExportCmd.Flags().BoolVar(&prForceFlag, "force", false, "Proceed even if the standup repository has uncommitted non-standup changes")
+
+	rootCmd.AddCommand(remindCmd)
+	remindCmd.AddCommand(remindICSCmd)
+	remindCmd.Flags().DurationVar(&remindWindowFlag, "window", 15*time.Minute, "Post reminders due within this long before now; match the cron interval")
+	remindICSCmd.Flags().StringVar(&remindOutFlag, "out", "", "Write to this file instead of stdout")
+	remindICSCmd.Flags().IntVar(&remindDaysFlag, "days", 30, "Number of days of reminders to include")
+
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&importFormatFlag, "format", "", "Archive format: slack, csv or markdown (detected from the path when empty)")
+	importCmd.Flags().StringVar(&importUserFlag, "user", "", "Record every standup under this name")
+	importCmd.Flags().StringToStringVar(&importAuthorsFlag, "author", nil, "Rename an author of the archive, as archive-name=standup-name (repeatable)")
+	importCmd.Flags().BoolVar(&importDryRunFlag, "dry-run", false, "List the standups found without recording them")
+	importCmd.Flags().BoolVar(&importForceFlag, "force", false, "Proceed even if the standup repository has uncommitted non-standup changes")
+
+	rootCmd.AddCommand(indexCmd)
+	indexCmd.AddCommand(indexRebuildCmd)
+
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configSetCmd, configShowCmd, configEditCmd)
+	configSetCmd.Flags().StringVar(&configSettings.Repository, "repository", "", "Repository (org/repo or a git URL)")
+	configSetCmd.Flags().StringVar(&configSettings.Name, "name", "", "Your name, as it appears in standups")
+	configSetCmd.Flags().StringVar(&configSettings.Path, "path", "", "Where to clone the standup repository")
+	configSetCmd.Flags().StringVar(&configSettings.Forge, "forge", "", "Forge: github, gitlab or bitbucket (detected when empty)")
+	configSetCmd.Flags().StringVar(&configSettings.BaseBranch, "base-branch", "", "Branch standups are merged into")
+
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&versionCheckFlag, "check", false, "Check GitHub Releases for a newer version")
 }
 
 // SetVersionInfo sets the version information for the CLI
@@ -112,7 +1100,143 @@ func buildVersion() string {
 
 // Execute runs the root command
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	finishTelemetry(err)
+	finishPlain()
+	finishTranscript(err)
+	return err
+}
+
+// formatFlag returns a command's --format value, or the global --output
+// format when --format wasn't given
+func formatFlag(cmd *cobra.Command, format string) string {
+	if cmd.Flags().Changed("format") || outputFlag == "" {
+		return format
+	}
+	if commands.IsJSONOutput(outputFlag) {
+		return "json"
+	}
+	return outputFlag
+}
+
+// startTranscript records this run's git and gh commands for support bundles
+// and 'log'. Transcripts are best effort and never stop a run. 'log' isn't
+// recorded, so the last run it shows is the one before it.
+func startTranscript(cmd *cobra.Command) {
+	if cmd == logCmd {
+		return
+	}
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		return
+	}
+	if finish, err := commands.StartTranscript(cfgManager.ConfigDir()); err == nil {
+		finishTranscript = finish
+	}
+}
+
+// startCache caches gh lookups such as the open pull request of a branch for
+// a couple of minutes, unless --no-cache is given. doctor always asks afresh.
+func startCache(cmd *cobra.Command) {
+	if noCacheFlag || cmd == doctorCmd {
+		return
+	}
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		return
+	}
+	git.SetCache(git.NewResponseCache(filepath.Join(cfgManager.ConfigDir(), "cache.json"), git.CacheTTL))
+}
+
+// startPlain strips emoji, colors and box-drawing from output when --plain
+// or NO_COLOR asks for it. Machine-readable output and the MCP server's
+// protocol are left as they are.
+func startPlain(cmd *cobra.Command) {
+	if !commands.PlainRequested(plainFlag) || cmd == mcpServerCmd || commands.IsMachineOutput(outputFlag) {
+		return
+	}
+	if format := cmd.Flags().Lookup("format"); format != nil && (format.Value.String() == "json" || format.Value.String() == "yaml") {
+		return
+	}
+	if finish, err := commands.StartPlain(); err == nil {
+		finishPlain = finish
+	}
+}
+
+// startTelemetry exports traces and metrics of this run, its workflow steps
+// and its git and gh commands when an OTLP endpoint is configured with
+// OTEL_EXPORTER_OTLP_ENDPOINT. The MCP server, the issue webhook and the
+// bot trace each request as a trace of its own instead, and export
+// periodically. Telemetry never stops a run.
+func startTelemetry(cmd *cobra.Command) {
+	settings, ok, err := telemetry.SettingsFromEnv(os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Telemetry is off: %v\n", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	if settings.Warning != "" {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", settings.Warning)
+	}
+	settings.ServiceVersion = version
+
+	t := telemetry.New(settings)
+	standupbot.SetSubmissionCounter(t.CountSubmission)
+	if cmd == mcpServerCmd || cmd == issuesWebhookCmd || cmd == botCmd {
+		// Work outside a request, such as cloning on startup, is traced
+		// on its own
+		git.SetTracer(t, context.Background())
+		standupbot.SetStepTracer(t.StartStep, context.Background())
+		commands.SetTelemetry(t)
+		t.Start()
+		finishTelemetry = func(error) {
+			if err := t.Shutdown(); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			}
+		}
+		return
+	}
+
+	ctx, endRun := t.StartRun(context.Background(), cmd.CommandPath())
+	git.SetTracer(t, ctx)
+	standupbot.SetStepTracer(t.StartStep, ctx)
+	finishTelemetry = func(err error) {
+		endRun(err)
+		if err := t.Shutdown(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+	}
+}
+
+// loadConfig loads the configuration for subcommands
+func loadConfig() (*config.Config, error) {
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfg, err := cfgManager.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadAllConfigs loads the configuration of the main repository followed by
+// that of each configured team, for the servers that route between them
+func loadAllConfigs() ([]*config.Config, error) {
+	cfgManager, err := config.NewManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize config manager: %w", err)
+	}
+
+	cfgs, err := cfgManager.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return cfgs, nil
 }
 
 // runStandup is the main entry point for the standup command
@@ -123,8 +1247,26 @@ func runStandup(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize config manager: %w", err)
 	}
 
+	if mergeAtFlag != "" && !mergeFlag {
+		return fmt.Errorf("--at can only be used with --merge")
+	}
+	for _, name := range []string{"strategy", "delete-branch"} {
+		if cmd.Flags().Changed(name) && !mergeFlag {
+			return fmt.Errorf("--%s can only be used with --merge", name)
+		}
+	}
+	if autoMergeFlag && directFlag {
+		return fmt.Errorf("--auto needs the pull request workflow; it can't be used with --direct")
+	}
+	if requestReviewFlag && (mergeFlag || directFlag) {
+		return fmt.Errorf("--request-review can't be combined with --merge or --direct")
+	}
+	if appendFlag && (mergeFlag || requestReviewFlag) {
+		return fmt.Errorf("--append can't be combined with --merge or --request-review")
+	}
+
 	// Check if we need to run configuration
-	if configFlag || !cfgManager.Exists() {
+	if configFlag || !cfgManager.Configured() {
 		return commands.RunConfiguration(cfgManager)
 	}
 
@@ -140,15 +1282,43 @@ func runStandup(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Using name override: %s\n", nameFlag)
 	}
 
+	// Operate on the current checkout if we're running inside a standup repository
+	if cwd, err := os.Getwd(); err == nil {
+		if repoPath, ok := commands.DetectLocalRepository(git.NewClient(), cfg, cwd); ok {
+			cfg.LocalRepoPath = repoPath
+			if !commands.IsMachineOutput(outputFlag) {
+				fmt.Printf("Using standup repository at %s\n", repoPath)
+			}
+		}
+	}
+
+	if requestReviewFlag {
+		return commands.RunRequestReview(cfg, outputFlag)
+	}
+
 	// Handle merge command
 	if mergeFlag {
-		return commands.RunMergeDailyStandup(cfg)
+		opts := commands.MergeOptions{
+			Force:        forceFlag,
+			Merge:        standupbot.MergeOverrides{Strategy: mergeStrategyFlag, Auto: autoMergeFlag},
+			OutputFormat: outputFlag,
+		}
+		if cmd.Flags().Changed("delete-branch") {
+			opts.Merge.DeleteBranch = &deleteBranchFlag
+		}
+		if mergeAtFlag != "" {
+			return commands.RunScheduledMerge(cfg, mergeAtFlag, opts)
+		}
+		return commands.RunMergeDailyStandup(cfg, opts)
 	}
 
-
 	// Run the standup workflow
-	if directFlag {
-		return commands.RunStandupDirect(cfg, jsonFlag, outputFlag)
-	}
-	return commands.RunStandupPR(cfg, jsonFlag, outputFlag)
+	return commands.RunStandup(cfg, commands.StandupOptions{
+		Direct:       directFlag,
+		JSONInput:    jsonFlag,
+		OutputFormat: outputFlag,
+		Force:        forceFlag,
+		AutoMerge:    autoMergeFlag,
+		Append:       appendFlag,
+	})
 }
\ No newline at end of file
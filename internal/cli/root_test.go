@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func TestExecute(t *testing.T) {
@@ -127,4 +128,19 @@ func TestConfigFlag(t *testing.T) {
 	if configFlag {
 		t.Error("Config flag should be false when not specified")
 	}
-}
\ No newline at end of file
+}
+func TestSubcommandsKeepPersistentFlags(t *testing.T) {
+	// A local flag named like a persistent one, such as --output, hides it
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+			if cmd != rootCmd && rootCmd.PersistentFlags().Lookup(f.Name) != nil {
+				t.Errorf("%s --%s shadows the global --%s flag", cmd.CommandPath(), f.Name, f.Name)
+			}
+		})
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
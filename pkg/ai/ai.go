@@ -0,0 +1,124 @@
+// Package ai provides a common interface to the LLM providers used by AI
+// features. AI is off by default: without a configured provider New returns
+// ErrDisabled and no request is ever made.
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// ErrDisabled is returned by New when no AI provider is configured
+var ErrDisabled = errors.New("AI features are disabled; set \"ai\" in the config to enable them")
+
+// summaryPrompt asks for a summary of the text that follows it
+const summaryPrompt = `Summarize the following for a performance review or team update.
+Write 3 to 5 short bullet points in markdown, focusing on outcomes. Do not
+invent facts that are not in the text.
+
+`
+
+// Provider generates text with an LLM
+type Provider interface {
+	// Complete returns the model's response to a prompt
+	Complete(ctx context.Context, prompt string) (string, error)
+	// Summarize returns a short markdown summary of text
+	Summarize(ctx context.Context, text string) (string, error)
+}
+
+// Options configures a provider
+type Options struct {
+	Kind        types.AIProviderKind
+	Model       string
+	Endpoint    string
+	Temperature float64
+}
+
+// New creates the provider selected by opts. API keys are read from
+// OPENAI_API_KEY and ANTHROPIC_API_KEY.
+func New(opts Options) (Provider, error) {
+	httpClient := &http.Client{Timeout: 2 * time.Minute}
+
+	switch opts.Kind {
+	case types.AIOpenAI:
+		key := os.Getenv("OPENAI_API_KEY")
+		if key == "" && opts.Endpoint == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is not set")
+		}
+		return &OpenAI{opts: withEndpoint(opts, openAIEndpoint), apiKey: key, httpClient: httpClient}, nil
+	case types.AIAnthropic:
+		key := os.Getenv("ANTHROPIC_API_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("ANTHROPIC_API_KEY is not set")
+		}
+		return &Anthropic{opts: withEndpoint(opts, anthropicEndpoint), apiKey: key, httpClient: httpClient}, nil
+	case types.AIOllama:
+		return &Ollama{opts: withEndpoint(opts, ollamaEndpoint), httpClient: httpClient}, nil
+	case types.AINone, "":
+		return nil, ErrDisabled
+	}
+	return nil, fmt.Errorf("unsupported AI provider: %s", opts.Kind)
+}
+
+// withEndpoint fills in the default endpoint
+func withEndpoint(opts Options, endpoint string) Options {
+	if opts.Endpoint == "" {
+		opts.Endpoint = endpoint
+	}
+	opts.Endpoint = strings.TrimSuffix(opts.Endpoint, "/")
+	return opts
+}
+
+// summarize asks a provider for a summary of text
+func summarize(ctx context.Context, p Provider, text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("nothing to summarize")
+	}
+	return p.Complete(ctx, summaryPrompt+text)
+}
+
+// postJSON sends a JSON request and decodes the JSON response into result
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("AI request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read AI response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("AI provider returned %s: %s", resp.Status, strings.TrimSpace(string(respData)))
+	}
+
+	if err := json.Unmarshal(respData, result); err != nil {
+		return fmt.Errorf("failed to decode AI response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+func TestNewDisabledByDefault(t *testing.T) {
+	for _, kind := range []types.AIProviderKind{"", types.AINone} {
+		if _, err := New(Options{Kind: kind}); !errors.Is(err, ErrDisabled) {
+			t.Errorf("New(%q) error = %v, want ErrDisabled", kind, err)
+		}
+	}
+}
+
+func TestNewRequiresAPIKey(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	if _, err := New(Options{Kind: types.AIOpenAI, Model: "m"}); err == nil {
+		t.Error("New(openai) should require OPENAI_API_KEY")
+	}
+	if _, err := New(Options{Kind: types.AIAnthropic, Model: "m"}); err == nil {
+		t.Error("New(anthropic) should require ANTHROPIC_API_KEY")
+	}
+	// A custom OpenAI-compatible endpoint may not need a key
+	if _, err := New(Options{Kind: types.AIOpenAI, Model: "m", Endpoint: "http://localhost:8080/v1"}); err != nil {
+		t.Errorf("New(openai with endpoint) error = %v", err)
+	}
+}
+
+func TestProviders(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	t.Setenv("ANTHROPIC_API_KEY", "ant-test")
+
+	tests := []struct {
+		kind     types.AIProviderKind
+		path     string
+		auth     func(*http.Request) bool
+		response string
+	}{
+		{
+			kind:     types.AIOpenAI,
+			path:     "/chat/completions",
+			auth:     func(r *http.Request) bool { return r.Header.Get("Authorization") == "Bearer sk-test" },
+			response: `{"choices": [{"message": {"role": "assistant", "content": " - Shipped auth\n"}}]}`,
+		},
+		{
+			kind: types.AIAnthropic,
+			path: "/v1/messages",
+			auth: func(r *http.Request) bool {
+				return r.Header.Get("x-api-key") == "ant-test" && r.Header.Get("anthropic-version") != ""
+			},
+			response: `{"content": [{"type": "text", "text": "- Shipped auth"}]}`,
+		},
+		{
+			kind:     types.AIOllama,
+			path:     "/api/generate",
+			auth:     func(r *http.Request) bool { return true },
+			response: `{"response": "- Shipped auth", "done": true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			var request map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path || !tt.auth(r) {
+					http.Error(w, "unexpected request", http.StatusBadRequest)
+					return
+				}
+				json.NewDecoder(r.Body).Decode(&request)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			provider, err := New(Options{Kind: tt.kind, Model: "test-model", Endpoint: server.URL, Temperature: 0.2})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := provider.Summarize(context.Background(), "Yesterday: shipped auth")
+			if err != nil {
+				t.Fatalf("Summarize() error = %v", err)
+			}
+			if got != "- Shipped auth" {
+				t.Errorf("Summarize() = %q", got)
+			}
+			if request["model"] != "test-model" {
+				t.Errorf("request model = %v, want test-model", request["model"])
+			}
+			if !strings.Contains(string(mustJSON(t, request)), "shipped auth") {
+				t.Errorf("request should include the text to summarize: %v", request)
+			}
+		})
+	}
+}
+
+func TestProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "model not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	provider, err := New(Options{Kind: types.AIOllama, Model: "missing", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := provider.Complete(context.Background(), "hi"); err == nil || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("Complete() error = %v, want the provider's message", err)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
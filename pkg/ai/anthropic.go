@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// anthropicEndpoint is the default Anthropic API base URL
+	anthropicEndpoint = "https://api.anthropic.com"
+
+	// anthropicVersion is the Messages API version sent with each request
+	anthropicVersion = "2023-06-01"
+
+	// anthropicMaxTokens caps the length of responses
+	anthropicMaxTokens = 1024
+)
+
+// Anthropic uses the Anthropic Messages API
+type Anthropic struct {
+	opts       Options
+	apiKey     string
+	httpClient *http.Client
+}
+
+// Complete returns the model's response to a prompt
+func (a *Anthropic) Complete(ctx context.Context, prompt string) (string, error) {
+	request := map[string]interface{}{
+		"model":       a.opts.Model,
+		"max_tokens":  anthropicMaxTokens,
+		"temperature": a.opts.Temperature,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	headers := map[string]string{
+		"x-api-key":         a.apiKey,
+		"anthropic-version": anthropicVersion,
+	}
+
+	var response struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := postJSON(ctx, a.httpClient, a.opts.Endpoint+"/v1/messages", headers, request, &response); err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("AI provider returned no text")
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+// Summarize returns a short markdown summary of text
+func (a *Anthropic) Summarize(ctx context.Context, text string) (string, error) {
+	return summarize(ctx, a, text)
+}
@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ollamaEndpoint is the default address of a local Ollama server
+const ollamaEndpoint = "http://localhost:11434"
+
+// Ollama uses a local Ollama server, so prompts never leave the machine
+type Ollama struct {
+	opts       Options
+	httpClient *http.Client
+}
+
+// Complete returns the model's response to a prompt
+func (o *Ollama) Complete(ctx context.Context, prompt string) (string, error) {
+	request := map[string]interface{}{
+		"model":   o.opts.Model,
+		"prompt":  prompt,
+		"stream":  false,
+		"options": map[string]float64{"temperature": o.opts.Temperature},
+	}
+
+	var response struct {
+		Response string `json:"response"`
+	}
+	if err := postJSON(ctx, o.httpClient, o.opts.Endpoint+"/api/generate", nil, request, &response); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(response.Response), nil
+}
+
+// Summarize returns a short markdown summary of text
+func (o *Ollama) Summarize(ctx context.Context, text string) (string, error) {
+	return summarize(ctx, o, text)
+}
@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// openAIEndpoint is the default OpenAI API base URL
+const openAIEndpoint = "https://api.openai.com/v1"
+
+// OpenAI uses an OpenAI-compatible chat completions API. With a custom
+// endpoint the API key is optional, for gateways that don't need one.
+type OpenAI struct {
+	opts       Options
+	apiKey     string
+	httpClient *http.Client
+}
+
+// openAIMessage is a chat message in a completions request or response
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Complete returns the model's response to a prompt
+func (o *OpenAI) Complete(ctx context.Context, prompt string) (string, error) {
+	request := map[string]interface{}{
+		"model":       o.opts.Model,
+		"temperature": o.opts.Temperature,
+		"messages":    []openAIMessage{{Role: "user", Content: prompt}},
+	}
+
+	headers := map[string]string{}
+	if o.apiKey != "" {
+		headers["Authorization"] = "Bearer " + o.apiKey
+	}
+
+	var response struct {
+		Choices []struct {
+			Message openAIMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := postJSON(ctx, o.httpClient, o.opts.Endpoint+"/chat/completions", headers, request, &response); err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("AI provider returned no choices")
+	}
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
+
+// Summarize returns a short markdown summary of text
+func (o *OpenAI) Summarize(ctx context.Context, text string) (string, error) {
+	return summarize(ctx, o, text)
+}
@@ -0,0 +1,359 @@
+// Package calendar reads meetings from iCalendar (.ics) feeds, such as a
+// Google Calendar's iCal address, and writes feeds of reminders to subscribe
+// to.
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fetchTimeout bounds downloading a feed
+const fetchTimeout = 30 * time.Second
+
+// maxFeedSize bounds the size of a feed, since busy calendars can be large
+const maxFeedSize = 16 << 20
+
+// overrideLookback is how far before the requested range recurring events
+// are expanded, so an occurrence moved into the range from earlier is found
+const overrideLookback = 31 * 24 * time.Hour
+
+// Occurrence is one meeting: a single event, or one instance of a recurring
+// event
+type Occurrence struct {
+	UID     string
+	Summary string
+	Start   time.Time
+}
+
+// Calendar holds the events of a feed
+type Calendar struct {
+	events []event
+}
+
+// event is a VEVENT
+type event struct {
+	uid     string
+	summary string
+	start   time.Time
+	allDay  bool
+	rule    *rule
+	exdates []time.Time
+	// recurrenceID is set on an event that moves or cancels one occurrence
+	// of a recurring event, and is that occurrence's original start
+	recurrenceID time.Time
+	cancelled    bool
+}
+
+// rule is the supported part of an RRULE: daily or weekly recurrence
+type rule struct {
+	freq     string
+	interval int
+	byDay    []time.Weekday
+	until    time.Time
+	count    int
+}
+
+// Fetch downloads and parses a feed. webcal:// addresses are fetched over
+// https. Times without a zone are taken to be in loc.
+func Fetch(url string, loc *time.Location) (*Calendar, error) {
+	if rest, ok := strings.CutPrefix(url, "webcal://"); ok {
+		url = "https://" + rest
+	}
+
+	client := &http.Client{Timeout: fetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the calendar: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the calendar: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the calendar: %w", err)
+	}
+	return Parse(data, loc)
+}
+
+// Parse reads the events of a feed. Times without a zone, and times in a
+// zone Go doesn't know, are taken to be in loc.
+func Parse(data []byte, loc *time.Location) (*Calendar, error) {
+	lines := unfold(string(data))
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("not an iCalendar feed")
+	}
+
+	cal := &Calendar{}
+	var components []string
+	var current *event
+	for _, line := range lines {
+		name, params, value, ok := parseProperty(line)
+		if !ok {
+			continue
+		}
+		switch name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(value))
+			if strings.EqualFold(value, "VEVENT") {
+				current = &event{}
+			}
+			continue
+		case "END":
+			if len(components) > 0 {
+				components = components[:len(components)-1]
+			}
+			if strings.EqualFold(value, "VEVENT") && current != nil {
+				cal.events = append(cal.events, *current)
+				current = nil
+			}
+			continue
+		}
+		// Properties of alarms inside an event aren't the event's
+		if current == nil || components[len(components)-1] != "VEVENT" {
+			continue
+		}
+
+		var err error
+		switch name {
+		case "UID":
+			current.uid = value
+		case "SUMMARY":
+			current.summary = unescapeText(value)
+		case "STATUS":
+			current.cancelled = strings.EqualFold(value, "CANCELLED")
+		case "DTSTART":
+			current.start, current.allDay, err = parseTime(value, params, loc)
+		case "RECURRENCE-ID":
+			current.recurrenceID, _, err = parseTime(value, params, loc)
+		case "RRULE":
+			current.rule, err = parseRule(value, loc)
+		case "EXDATE":
+			for _, date := range strings.Split(value, ",") {
+				var exdate time.Time
+				if exdate, _, err = parseTime(date, params, loc); err != nil {
+					break
+				}
+				current.exdates = append(current.exdates, exdate)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s of event %q: %w", name, current.summary, err)
+		}
+	}
+	return cal, nil
+}
+
+// Occurrences returns the meetings starting from from until to, in order.
+// Moved and cancelled instances of recurring events are applied, and
+// all-day events, which aren't meetings, are left out.
+func (c *Calendar) Occurrences(from, to time.Time) []Occurrence {
+	// Instances moved or cancelled, by event and original start
+	overridden := make(map[string]bool)
+	for _, e := range c.events {
+		if !e.recurrenceID.IsZero() {
+			overridden[overrideKey(e.uid, e.recurrenceID)] = true
+		}
+	}
+
+	var occurrences []Occurrence
+	for _, e := range c.events {
+		if e.allDay || e.start.IsZero() || e.cancelled {
+			continue
+		}
+		for _, start := range e.expand(from.Add(-overrideLookback), to) {
+			if e.recurrenceID.IsZero() && overridden[overrideKey(e.uid, start)] {
+				continue
+			}
+			if slices.ContainsFunc(e.exdates, start.Equal) {
+				continue
+			}
+			if start.Before(from) || !start.Before(to) {
+				continue
+			}
+			occurrences = append(occurrences, Occurrence{UID: e.uid, Summary: e.summary, Start: start})
+		}
+	}
+	slices.SortFunc(occurrences, func(a, b Occurrence) int { return a.Start.Compare(b.Start) })
+	return occurrences
+}
+
+// overrideKey identifies an instance of a recurring event
+func overrideKey(uid string, start time.Time) string {
+	return uid + "@" + start.UTC().Format(time.RFC3339)
+}
+
+// expand returns the starts of the event's instances from from until to.
+// Instances are counted from the first, so COUNT holds however early from is.
+func (e event) expand(from, to time.Time) []time.Time {
+	if e.rule == nil {
+		return []time.Time{e.start}
+	}
+
+	var starts []time.Time
+	hour, minute, second := e.start.Clock()
+	year, month, day := e.start.Date()
+	// Days are counted in UTC, so daylight saving changes don't skew them
+	first := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	// Weeks start on Monday
+	weekOffset := (int(e.start.Weekday()) + 6) % 7
+	count := 0
+	for i := 0; ; i++ {
+		date := first.AddDate(0, 0, i)
+		start := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, 0, e.start.Location())
+		if !start.Before(to) || (!e.rule.until.IsZero() && start.After(e.rule.until)) {
+			break
+		}
+		if !e.rule.matches(i, weekOffset, date.Weekday(), e.start.Weekday()) {
+			continue
+		}
+		count++
+		if e.rule.count > 0 && count > e.rule.count {
+			break
+		}
+		if !start.Before(from) {
+			starts = append(starts, start)
+		}
+	}
+	return starts
+}
+
+// matches reports whether the rule has an instance the given number of days
+// after the first
+func (r *rule) matches(days, weekOffset int, weekday, firstWeekday time.Weekday) bool {
+	byDay := r.byDay
+	if len(byDay) == 0 && r.freq == "WEEKLY" {
+		byDay = []time.Weekday{firstWeekday}
+	}
+	if len(byDay) > 0 && !slices.Contains(byDay, weekday) {
+		return false
+	}
+	if r.freq == "WEEKLY" {
+		return ((days+weekOffset)/7)%r.interval == 0
+	}
+	return days%r.interval == 0
+}
+
+// weekdays maps RRULE day names to weekdays
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRule reads an RRULE. Standups repeat daily or weekly; other
+// frequencies aren't supported.
+func parseRule(value string, loc *time.Location) (*rule, error) {
+	r := &rule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.freq = strings.ToUpper(val)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(val)
+			if err == nil && r.interval < 1 {
+				err = fmt.Errorf("interval must be positive")
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(val)
+		case "UNTIL":
+			r.until, _, err = parseTime(val, nil, loc)
+		case "BYDAY":
+			for _, name := range strings.Split(val, ",") {
+				weekday, ok := weekdays[strings.ToUpper(name)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported BYDAY %q", name)
+				}
+				r.byDay = append(r.byDay, weekday)
+			}
+		case "WKST":
+			// Weeks are taken to start on Monday, the default
+		default:
+			return nil, fmt.Errorf("unsupported recurrence %q", part)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	if r.freq != "DAILY" && r.freq != "WEEKLY" {
+		return nil, fmt.Errorf("unsupported recurrence FREQ=%s (only DAILY and WEEKLY)", r.freq)
+	}
+	return r, nil
+}
+
+// parseTime reads a DATE or DATE-TIME value, returning whether it is a date
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		date, err := time.ParseInLocation("20060102", value, loc)
+		return date, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	if tzid := params["TZID"]; tzid != "" {
+		// Outlook uses Windows zone names, which Go doesn't know
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			loc = zone
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseProperty splits a content line into its name, parameters and value
+func parseProperty(line string) (string, map[string]string, string, bool) {
+	// The value starts at the first colon outside a quoted parameter
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+// unfold joins folded content lines, which continue on lines starting with
+// a space or tab
+func unfold(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// textUnescaper undoes the escaping of TEXT values
+var textUnescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+// unescapeText reads a TEXT value
+func unescapeText(value string) string {
+	return textUnescaper.Replace(value)
+}
@@ -0,0 +1,134 @@
+package calendar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// feed is a standup every weekday at 9:30 in Berlin, with Wednesday the
+// 17th cancelled, Thursday the 18th moved to 10:00 and Friday the 19th
+// excluded, plus a one-off retro and an all-day event
+const feed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup-1\r\n" +
+	"SUMMARY:Daily Standup\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20240115T093000\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;WKST=MO\r\n" +
+	"EXDATE;TZID=Europe/Berlin:20240119T093000\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"SUMMARY:Not the event's\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup-1\r\n" +
+	"SUMMARY:Daily Standup\r\n" +
+	"RECURRENCE-ID;TZID=Europe/Berlin:20240117T093000\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20240117T093000\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup-1\r\n" +
+	"SUMMARY:Daily Standup\r\n" +
+	"RECURRENCE-ID:20240118T083000Z\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20240118T100000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:retro-1\r\n" +
+	"SUMMARY:Retro\\, sprint\r\n" +
+	"  12\r\n" +
+	"DTSTART:20240116T140000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:offsite\r\n" +
+	"SUMMARY:Offsite\r\n" +
+	"DTSTART;VALUE=DATE:20240116\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestOccurrences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	cal, err := Parse([]byte(feed), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, berlin)
+	var got []string
+	for _, o := range cal.Occurrences(from, from.AddDate(0, 0, 8)) {
+		got = append(got, o.Start.In(berlin).Format("Mon 02 15:04")+" "+o.Summary)
+	}
+	want := []string{
+		"Mon 15 09:30 Daily Standup",
+		"Tue 16 09:30 Daily Standup",
+		"Tue 16 15:00 Retro, sprint 12",
+		"Thu 18 10:00 Daily Standup",
+		"Mon 22 09:30 Daily Standup",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Occurrences() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestOccurrencesRules(t *testing.T) {
+	event := func(start, rule string) string {
+		return "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\nDTSTART:" + start + "\nRRULE:" + rule + "\nEND:VEVENT\nEND:VCALENDAR\n"
+	}
+	tests := []struct {
+		name string
+		rule string
+		want []string
+	}{
+		{name: "every other day", rule: "FREQ=DAILY;INTERVAL=2", want: []string{"Mon 01", "Wed 03", "Fri 05", "Sun 07"}},
+		{name: "count", rule: "FREQ=DAILY;COUNT=2", want: []string{"Mon 01", "Tue 02"}},
+		{name: "until", rule: "FREQ=DAILY;UNTIL=20240103T235959Z", want: []string{"Mon 01", "Tue 02", "Wed 03"}},
+		{name: "fortnightly", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", want: []string{"Mon 01", "Fri 05"}},
+		{name: "weekly on the first day", rule: "FREQ=WEEKLY", want: []string{"Mon 01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal, err := Parse([]byte(event("20240101T090000Z", tt.rule)), time.UTC)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			var got []string
+			for _, o := range cal.Occurrences(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)) {
+				got = append(got, o.Start.Format("Mon 02"))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Occurrences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Parse([]byte(event("20240101T090000Z", "FREQ=MONTHLY;BYDAY=1MO")), time.UTC); err == nil {
+		t.Error("Parse() should reject unsupported recurrences")
+	}
+	if _, err := Parse([]byte("<html>"), time.UTC); err == nil {
+		t.Error("Parse() should reject what isn't a feed")
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/basic.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(feed))
+	}))
+	defer server.Close()
+
+	if _, err := Fetch(server.URL+"/basic.ics", time.UTC); err != nil {
+		t.Errorf("Fetch() error = %v", err)
+	}
+	if _, err := Fetch(server.URL+"/missing.ics", time.UTC); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Fetch() error = %v, want the status", err)
+	}
+}
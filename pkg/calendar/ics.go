@@ -0,0 +1,96 @@
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineLength is the longest content line, in bytes, before it is folded
+const maxLineLength = 75
+
+// Event is an event of a feed written by Write
+type Event struct {
+	// UID must stay the same for the same event across feeds, so
+	// subscribers update it instead of adding a copy
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	Duration    time.Duration
+	// Alarm adds a notification when the event starts
+	Alarm bool
+}
+
+// textEscaper escapes TEXT values
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// Write writes events as an iCalendar feed named name. stamp is when the
+// feed was generated.
+func Write(w io.Writer, name string, events []Event, stamp time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//standup-bot//reminders//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + textEscaper.Replace(name),
+	}
+	for _, e := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+textEscaper.Replace(e.UID),
+			"DTSTAMP:"+formatUTC(stamp),
+			"DTSTART:"+formatUTC(e.Start),
+			"DTEND:"+formatUTC(e.Start.Add(e.Duration)),
+			"SUMMARY:"+textEscaper.Replace(e.Summary),
+		)
+		if e.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+textEscaper.Replace(e.Description))
+		}
+		if e.Alarm {
+			lines = append(lines,
+				"BEGIN:VALARM",
+				"ACTION:DISPLAY",
+				"TRIGGER:PT0S",
+				"DESCRIPTION:"+textEscaper.Replace(e.Summary),
+				"END:VALARM",
+			)
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, fold(line)+"\r\n"); err != nil {
+			return fmt.Errorf("failed to write the calendar: %w", err)
+		}
+	}
+	return nil
+}
+
+// formatUTC formats a DATE-TIME in UTC
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// fold splits a content line longer than maxLineLength bytes into lines
+// starting with a space, without splitting a character
+func fold(line string) string {
+	var b strings.Builder
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space
+		limit = maxLineLength - 1
+	}
+	b.WriteString(line)
+	return b.String()
+}
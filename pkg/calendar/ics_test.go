@@ -0,0 +1,44 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	start := time.Date(2024, 1, 15, 9, 15, 0, 0, time.UTC)
+	events := []Event{{
+		UID:         "standup-1-20240115T093000Z@standup-bot",
+		Summary:     "Submit your standup",
+		Description: "Daily Standup starts at 09:30; run standup-bot, or comment on the daily issue. " + strings.Repeat("ü", 40),
+		Start:       start,
+		Duration:    5 * time.Minute,
+		Alarm:       true,
+	}}
+
+	var b strings.Builder
+	if err := Write(&b, "Standup reminders", events, start); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	text := b.String()
+	for _, want := range []string{"BEGIN:VCALENDAR\r\n", "DTSTART:20240115T091500Z\r\n", "DTEND:20240115T092000Z\r\n", `09:30\; run`, "TRIGGER:PT0S", "END:VCALENDAR\r\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Write() should contain %q:\n%s", want, text)
+		}
+	}
+	for _, line := range strings.Split(text, "\r\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("line longer than %d bytes: %q", maxLineLength, line)
+		}
+	}
+
+	// What is written reads back
+	cal, err := Parse([]byte(text), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := cal.Occurrences(start, start.Add(time.Hour)); len(got) != 1 || got[0].Summary != "Submit your standup" || !got[0].Start.Equal(start) {
+		t.Errorf("Occurrences() of the written feed = %+v", got)
+	}
+}
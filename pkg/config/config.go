@@ -2,34 +2,409 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/mail"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 	
 	"github.com/standup-bot/standup-bot/pkg/types"
 )
 
 // Config holds the application configuration
 type Config struct {
+	// Repository is "owner/name" or a git URL (git@host:owner/name.git)
 	Repository    string `json:"repository"`
 	Name          string `json:"name"`
 	LocalRepoPath string `json:"localRepoPath"`
+	StorageFormat string `json:"storageFormat,omitempty"`
+
+	// Forge selects github, gitlab or bitbucket; detected from the remote URL when empty
+	Forge string `json:"forge,omitempty"`
+
+	// BaseBranch is the branch standups are merged into; the remote's
+	// default branch when empty
+	BaseBranch string `json:"baseBranch,omitempty"`
+
+	// DayCutoffHour is the hour (0-23) before which submissions count as the previous day
+	DayCutoffHour int `json:"dayCutoffHour,omitempty"`
+
+	// Timezone is the IANA time zone standups are dated in, e.g.
+	// Europe/Berlin; the machine's when empty
+	Timezone string `json:"timezone,omitempty"`
+
+	// DisableUpdateCheck turns off 'version --check' queries to GitHub Releases
+	DisableUpdateCheck bool `json:"disableUpdateCheck,omitempty"`
+
+	// Clone trims what the first clone of the standup repository downloads
+	Clone *CloneConfig `json:"clone,omitempty"`
+
+	// Storage keeps standups in a plain directory or an S3 bucket instead of
+	// a git repository
+	Storage *StorageConfig `json:"storage,omitempty"`
+
+	// Commit sets the author identity and signing of standup commits, e.g.
+	// to satisfy branch protection that requires signed commits
+	Commit *CommitConfig `json:"commit,omitempty"`
+
+	// Template lists extra sections collected after the standard ones
+	Template []types.SectionSpec `json:"template,omitempty"`
+
+	// AI configures the LLM used by AI features. Without it they are off and
+	// nothing leaves the machine.
+	AI *AIConfig `json:"ai,omitempty"`
+
+	// Jira links Jira issue keys in standup items to the issues
+	Jira *JiraConfig `json:"jira,omitempty"`
+
+	// Pipeline turns off steps of recording a standup and runs commands
+	// after them
+	Pipeline *PipelineConfig `json:"pipeline,omitempty"`
+
+	// Hooks run commands before a standup is recorded and after it is
+	// recorded or merged
+	Hooks *HooksConfig `json:"hooks,omitempty"`
+
+	// Digest emails the day's standups once the daily pull request is merged
+	Digest *DigestConfig `json:"digest,omitempty"`
+
+	// Teams routes requests that name a team to that team's standup
+	// repository, so one shared instance can serve several teams
+	Teams map[string]*TeamRoute `json:"teams,omitempty"`
+}
+
+// TeamRoute is the standup repository of a team served by a shared
+// instance. The team's roster, escalation and other shared settings come
+// from the team config in that repository.
+type TeamRoute struct {
+	Repository string `json:"repository"`
+	// LocalRepoPath defaults to teams/<team> in the config directory
+	LocalRepoPath string `json:"localRepoPath,omitempty"`
+	Forge         string `json:"forge,omitempty"`
+	BaseBranch    string `json:"baseBranch,omitempty"`
+}
+
+// TeamIDs returns the identifiers of the configured teams, in order
+func (c *Config) TeamIDs() []string {
+	ids := make([]string, 0, len(c.Teams))
+	for id := range c.Teams {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// HooksConfig lists shell commands run in the standup repository. Each gets
+// the standup as JSON on stdin and its details in STANDUP_BOT_* variables.
+type HooksConfig struct {
+	// PreRecord runs before the standup is saved; a failing command stops it
+	PreRecord []string `json:"pre_record,omitempty"`
+	// PostRecord runs once the standup is pushed
+	PostRecord []string `json:"post_record,omitempty"`
+	// PostMerge runs once the daily pull request is merged
+	PostMerge []string `json:"post_merge,omitempty"`
+}
+
+// PipelineSteps are the steps that record a standup, in the order they run
+var PipelineSteps = []string{"validate", "sync", "collect", "save", "commit", "push", "pr", "notify"}
+
+// PipelineConfig customizes the steps that record a standup
+type PipelineConfig struct {
+	// Skip lists steps not to run, e.g. notify to never escalate blockers.
+	// The collect and save steps can't be skipped.
+	Skip []string `json:"skip,omitempty"`
+	// After maps a step to shell commands run in the standup repository
+	// once it succeeds
+	After map[string][]string `json:"after,omitempty"`
+}
+
+// validate checks that the pipeline settings name known steps
+func (p *PipelineConfig) validate() error {
+	for _, name := range p.Skip {
+		if !slices.Contains(PipelineSteps, name) {
+			return fmt.Errorf("unknown step '%s' in skip", name)
+		}
+		if name == "collect" || name == "save" {
+			return fmt.Errorf("the %s step can't be skipped", name)
+		}
+	}
+	for name := range p.After {
+		if !slices.Contains(PipelineSteps, name) {
+			return fmt.Errorf("unknown step '%s' in after", name)
+		}
+	}
+	return nil
+}
+
+// JiraConfig points issue keys such as ABC-123 at a Jira site
+type JiraConfig struct {
+	// BaseURL is the site, e.g. https://acme.atlassian.net
+	BaseURL string `json:"baseURL"`
+	// Projects limits linking to these project keys, so that things like
+	// UTF-8 aren't taken for issues
+	Projects []string `json:"projects,omitempty"`
+	// Email is the Jira Cloud account the API token belongs to; leave it
+	// empty for a Data Center personal access token
+	Email string `json:"email,omitempty"`
+	// Enrich looks up each issue's title and status (using JIRA_API_TOKEN)
+	// and writes them next to the link
+	Enrich bool `json:"enrich,omitempty"`
+}
+
+// SMTPPasswordEnv holds the password for the digest's SMTP server. It is a
+// secret, so it is read from the environment rather than the config file.
+const SMTPPasswordEnv = "STANDUP_BOT_SMTP_PASSWORD"
+
+// DigestConfig sets the mail server and sender of the email digest of merged
+// standups
+type DigestConfig struct {
+	// To lists the recipients, e.g. the team's distribution list, unless the
+	// team config lists its own
+	To []string `json:"to,omitempty"`
+	// From is the sender, e.g. "Standup Bot <standups@acme.com>"
+	From string     `json:"from"`
+	SMTP SMTPConfig `json:"smtp"`
+}
+
+// SMTPConfig is the mail server the digest is sent through. Connections are
+// upgraded with STARTTLS when the server offers it.
+type SMTPConfig struct {
+	Host string `json:"host"`
+	// Port defaults to DefaultSMTPPort
+	Port int `json:"port,omitempty"`
+	// Username logs in with the password in STANDUP_BOT_SMTP_PASSWORD; no
+	// login when empty
+	Username string `json:"username,omitempty"`
+}
+
+// DefaultSMTPPort is the mail submission port
+const DefaultSMTPPort = 587
+
+// Addr returns the server's host:port
+func (s SMTPConfig) Addr() string {
+	port := s.Port
+	if port == 0 {
+		port = DefaultSMTPPort
+	}
+	return net.JoinHostPort(s.Host, strconv.Itoa(port))
+}
+
+// validate checks the digest's server and addresses
+func (d *DigestConfig) validate() error {
+	if d.SMTP.Host == "" {
+		return fmt.Errorf("smtp.host cannot be empty")
+	}
+	if d.SMTP.Port < 0 || d.SMTP.Port > 65535 {
+		return fmt.Errorf("invalid smtp.port: %d", d.SMTP.Port)
+	}
+	if _, err := mail.ParseAddress(d.From); err != nil {
+		return fmt.Errorf("invalid from address %q: %w", d.From, err)
+	}
+	for _, to := range d.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("invalid to address %q: %w", to, err)
+		}
+	}
+	return nil
+}
+
+// AIConfig selects the LLM provider and model for AI features
+type AIConfig struct {
+	// Provider is none, openai, anthropic or ollama
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+
+	// Endpoint overrides the provider's default API URL, e.g. for an
+	// OpenAI-compatible gateway or a remote Ollama server
+	Endpoint    string  `json:"endpoint,omitempty"`
+	Temperature float64 `json:"temperature,omitempty"`
+}
+
+// CloneConfig makes cloning a standup repository with years of history
+// fast. Only the first clone is affected.
+type CloneConfig struct {
+	// Depth keeps only this many commits of history; 0 keeps all of it
+	Depth int `json:"depth,omitempty"`
+	// Blobless downloads file contents only when they're checked out
+	Blobless bool `json:"blobless,omitempty"`
+}
+
+// Storage backends
+const (
+	StorageGit = "git"
+	StorageDir = "dir"
+	StorageS3  = "s3"
+)
+
+// StorageConfig picks where standups are kept. The git backend records them
+// in the standup repository; the others only save them, for solo users and
+// servers without a repository, so there is nothing to merge.
+type StorageConfig struct {
+	// Backend is git (the default), dir for the local repository path as a
+	// plain directory, or s3
+	Backend string `json:"backend,omitempty"`
+	// S3 is the bucket of the s3 backend
+	S3 *S3Config `json:"s3,omitempty"`
+}
+
+// S3Config is an S3 bucket, or a bucket of an S3-compatible service such as
+// MinIO. The credentials are read from AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN.
+type S3Config struct {
+	Bucket string `json:"bucket"`
+	// Region is us-east-1 when empty
+	Region string `json:"region,omitempty"`
+	// Endpoint is the address of an S3-compatible service, e.g.
+	// http://localhost:9000; AWS when empty
+	Endpoint string `json:"endpoint,omitempty"`
+	// Prefix is prepended to the key of every file, e.g. team-a/
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// GetStorageBackend returns the storage backend
+func (c *Config) GetStorageBackend() string {
+	if c.Storage == nil || c.Storage.Backend == "" {
+		return StorageGit
+	}
+	return c.Storage.Backend
+}
+
+// Standalone reports whether standups are kept outside a git repository
+func (c *Config) Standalone() bool {
+	return c.GetStorageBackend() != StorageGit
+}
+
+// CommitConfig overrides git config for standup commits only. Empty fields
+// use the user's git config.
+type CommitConfig struct {
+	AuthorName  string `json:"authorName,omitempty"`
+	AuthorEmail string `json:"authorEmail,omitempty"`
+
+	// Sign signs commits with the user's GPG or SSH key
+	Sign bool `json:"sign,omitempty"`
+	// SigningKey is the GPG key ID or SSH public key path to sign with
+	SigningKey string `json:"signingKey,omitempty"`
+	// SigningFormat is openpgp, ssh or x509
+	SigningFormat string `json:"signingFormat,omitempty"`
+
+	// Template is a Go template for the commit message, e.g.
+	// "docs(standup): {{.User}} - {{.Date}}"; the [Standup] message when empty
+	Template string `json:"template,omitempty"`
+}
+
+// CommitTemplate returns the commit message template, or "" for the default
+// message
+func (c *Config) CommitTemplate() string {
+	if c.Commit == nil {
+		return ""
+	}
+	return c.Commit.Template
 }
 
 // GetRepository returns the repository as a typed value
 func (c *Config) GetRepository() (types.Repository, error) {
+	if c.HasRemoteURL() {
+		return types.ParseRepositoryURL(c.Repository)
+	}
 	return types.NewRepository(c.Repository)
 }
 
+// HasRemoteURL reports whether the repository is configured as a raw git URL,
+// which is cloned and pushed with git directly instead of a forge CLI
+func (c *Config) HasRemoteURL() bool {
+	return types.IsRemoteURL(c.Repository)
+}
+
 // GetUserName returns the user name as a typed value
 func (c *Config) GetUserName() (types.UserName, error) {
 	return types.NewUserName(c.Name)
 }
 
+// GetStorageFormat returns the storage format as a typed value
+func (c *Config) GetStorageFormat() (types.StorageFormat, error) {
+	return types.NewStorageFormat(c.StorageFormat)
+}
+
+// GetForge returns the configured forge as a typed value
+func (c *Config) GetForge() (types.ForgeKind, error) {
+	return types.NewForgeKind(c.Forge)
+}
+
+// DefaultBaseBranch is the base branch when none is configured or detected
+const DefaultBaseBranch = "main"
+
+// GetBaseBranch returns the branch standups are merged into
+func (c *Config) GetBaseBranch() string {
+	if c.BaseBranch == "" {
+		return DefaultBaseBranch
+	}
+	return c.BaseBranch
+}
+
+// GetAIProvider returns the configured AI provider as a typed value
+func (c *Config) GetAIProvider() (types.AIProviderKind, error) {
+	if c.AI == nil {
+		return types.AINone, nil
+	}
+	return types.NewAIProviderKind(c.AI.Provider)
+}
+
+// Location returns the configured time zone, or the local one when none
+// (or an unknown one) is configured
+func (c *Config) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.Local
+	}
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// Today returns the current time in the configured time zone, shifted to
+// the previous day before the configured cutoff hour
+func (c *Config) Today() time.Time {
+	return types.StandupDay(time.Now().In(c.Location()), c.DayCutoffHour)
+}
+
+// ApplyTeam layers the team config from the standup repository under this
+// config: each setting left empty here takes the team's value
+func (c *Config) ApplyTeam(team *TeamConfig) {
+	if c.StorageFormat == "" {
+		c.StorageFormat = team.StorageFormat
+	}
+	if c.Forge == "" {
+		c.Forge = team.Forge
+	}
+	if c.BaseBranch == "" {
+		c.BaseBranch = team.BaseBranch
+	}
+	if c.DayCutoffHour == 0 {
+		c.DayCutoffHour = team.DayCutoffHour
+	}
+	if c.Timezone == "" {
+		c.Timezone = team.Timezone
+	}
+	if len(c.Template) == 0 {
+		c.Template = team.Template
+	}
+	if c.CommitTemplate() == "" && team.CommitTemplate != "" {
+		if c.Commit == nil {
+			c.Commit = &CommitConfig{}
+		}
+		c.Commit.Template = team.CommitTemplate
+	}
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
-	if c.Repository == "" {
+	if c.Repository == "" && !c.Standalone() {
 		return fmt.Errorf("repository cannot be empty")
 	}
 	
@@ -42,18 +417,141 @@ func (c *Config) Validate() error {
 	}
 	
 	// Validate repository format
-	if _, err := c.GetRepository(); err != nil {
-		return fmt.Errorf("invalid repository: %w", err)
+	if c.Repository != "" {
+		if _, err := c.GetRepository(); err != nil {
+			return fmt.Errorf("invalid repository: %w", err)
+		}
+	}
+
+	// Validate storage
+	switch c.GetStorageBackend() {
+	case StorageGit, StorageDir:
+	case StorageS3:
+		if c.Storage.S3 == nil || c.Storage.S3.Bucket == "" {
+			return fmt.Errorf("the s3 storage backend requires a bucket")
+		}
+		if e := c.Storage.S3.Endpoint; e != "" && !strings.HasPrefix(e, "https://") && !strings.HasPrefix(e, "http://") {
+			return fmt.Errorf("invalid s3 endpoint %q: must be an http(s) URL", e)
+		}
+	default:
+		return fmt.Errorf("invalid storage backend: %q (must be git, dir or s3)", c.Storage.Backend)
 	}
 	
 	// Validate user name
 	if _, err := c.GetUserName(); err != nil {
 		return fmt.Errorf("invalid user name: %w", err)
 	}
+
+	// Validate storage format
+	if _, err := c.GetStorageFormat(); err != nil {
+		return fmt.Errorf("invalid storage format: %w", err)
+	}
+
+	// Validate forge
+	if _, err := c.GetForge(); err != nil {
+		return fmt.Errorf("invalid forge: %w", err)
+	}
+
+	// Validate base branch
+	if strings.HasPrefix(c.BaseBranch, "-") || strings.ContainsAny(c.BaseBranch, " \t\n") {
+		return fmt.Errorf("invalid base branch: %q", c.BaseBranch)
+	}
+
+	// Validate commit signing
+	if c.Commit != nil {
+		switch c.Commit.SigningFormat {
+		case "", "openpgp", "ssh", "x509":
+		default:
+			return fmt.Errorf("invalid signing format: %q (must be openpgp, ssh or x509)", c.Commit.SigningFormat)
+		}
+		if _, err := types.ParseCommitTemplate(c.Commit.Template); err != nil {
+			return fmt.Errorf("invalid commit template: %w", err)
+		}
+	}
+
+	// Validate clone options
+	if c.Clone != nil && c.Clone.Depth < 0 {
+		return fmt.Errorf("invalid clone depth: %d (must be 0 or more)", c.Clone.Depth)
+	}
+
+	// Validate day cutoff
+	if c.DayCutoffHour < 0 || c.DayCutoffHour > 23 {
+		return fmt.Errorf("invalid day cutoff hour: %d (must be between 0 and 23)", c.DayCutoffHour)
+	}
+
+	// Validate time zone
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %q", c.Timezone)
+		}
+	}
+
+	// Validate template sections
+	if err := types.ValidateTemplate(c.Template); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	// Validate AI settings
+	provider, err := c.GetAIProvider()
+	if err != nil {
+		return fmt.Errorf("invalid AI settings: %w", err)
+	}
+	if provider != types.AINone {
+		if c.AI.Model == "" {
+			return fmt.Errorf("invalid AI settings: model is required for provider %s", provider)
+		}
+		if c.AI.Temperature < 0 || c.AI.Temperature > 2 {
+			return fmt.Errorf("invalid AI settings: temperature %.2f must be between 0 and 2", c.AI.Temperature)
+		}
+	}
+
+	// Validate Jira settings
+	if c.Jira != nil && !strings.HasPrefix(c.Jira.BaseURL, "https://") && !strings.HasPrefix(c.Jira.BaseURL, "http://") {
+		return fmt.Errorf("invalid Jira settings: baseURL '%s' must be an http(s) URL", c.Jira.BaseURL)
+	}
+
+	// Validate digest settings
+	if c.Digest != nil {
+		if err := c.Digest.validate(); err != nil {
+			return fmt.Errorf("invalid digest settings: %w", err)
+		}
+	}
+
+	// Validate team routes
+	for _, id := range c.TeamIDs() {
+		if err := validateTeamRoute(id, c.Teams[id]); err != nil {
+			return fmt.Errorf("invalid team '%s': %w", id, err)
+		}
+	}
+
+	// Validate pipeline settings
+	if c.Pipeline != nil {
+		if err := c.Pipeline.validate(); err != nil {
+			return fmt.Errorf("invalid pipeline settings: %w", err)
+		}
+	}
 	
 	return nil
 }
 
+// validateTeamRoute checks a team's identifier and repository
+func validateTeamRoute(id string, route *TeamRoute) error {
+	if id == "" || strings.Trim(id, "abcdefghijklmnopqrstuvwxyz0123456789-_") != "" {
+		return fmt.Errorf("identifiers may only contain lowercase letters, digits, '-' and '_'")
+	}
+	if route == nil || route.Repository == "" {
+		return fmt.Errorf("repository cannot be empty")
+	}
+	repo := &Config{Repository: route.Repository, Forge: route.Forge}
+	if _, err := repo.GetRepository(); err != nil {
+		return fmt.Errorf("invalid repository: %w", err)
+	}
+	if _, err := repo.GetForge(); err != nil {
+		return fmt.Errorf("invalid forge: %w", err)
+	}
+	return nil
+}
+
 // Manager handles configuration operations
 type Manager struct {
 	configDir  string
@@ -68,6 +566,9 @@ func NewManager() (*Manager, error) {
 	}
 
 	configDir := filepath.Join(homeDir, ".standup-bot")
+	if dir := os.Getenv(EnvConfigDir); dir != "" {
+		configDir = dir
+	}
 	configFile := filepath.Join(configDir, "config.json")
 
 	return &Manager{
@@ -81,8 +582,90 @@ func (m *Manager) ConfigDir() string {
 	return m.configDir
 }
 
-// Load reads the configuration from disk
+// ConfigFile returns the configuration file path
+func (m *Manager) ConfigFile() string {
+	return m.configFile
+}
+
+// Load reads the configuration from disk, overrides it from STANDUP_BOT_*
+// environment variables, layers the team config of the standup repository's
+// clone under it and validates the result. Without a configuration file the
+// environment alone is used when it names the repository.
 func (m *Manager) Load() (*Config, error) {
+	return m.LoadTeam("")
+}
+
+// LoadTeam loads the configuration like Load, routed to the standup
+// repository of a configured team. The empty team is the main repository.
+func (m *Manager) LoadTeam(team string) (*Config, error) {
+	cfg, err := m.Read()
+	if errors.Is(err, ErrConfigNotFound) && os.Getenv(EnvRepository) != "" {
+		cfg, err = &Config{LocalRepoPath: filepath.Join(m.configDir, "repo")}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := m.applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	if team != "" {
+		route, ok := cfg.Teams[team]
+		if !ok || route == nil {
+			if len(cfg.Teams) == 0 {
+				return nil, fmt.Errorf("unknown team '%s': no teams are configured", team)
+			}
+			return nil, fmt.Errorf("unknown team '%s': expected one of %s", team, strings.Join(cfg.TeamIDs(), ", "))
+		}
+		cfg.Repository = route.Repository
+		cfg.LocalRepoPath = route.LocalRepoPath
+		if cfg.LocalRepoPath == "" {
+			cfg.LocalRepoPath = filepath.Join(m.configDir, "teams", team)
+		}
+		cfg.Forge = route.Forge
+		cfg.BaseBranch = route.BaseBranch
+		if err := m.expandPath(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.LocalRepoPath != "" {
+		team, err := LoadTeamConfig(cfg.LocalRepoPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ApplyTeam(team)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// LoadAll loads the configuration of the main repository followed by that
+// of every configured team, in order
+func (m *Manager) LoadAll() ([]*Config, error) {
+	cfg, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+	cfgs := []*Config{cfg}
+	for _, id := range cfg.TeamIDs() {
+		team, err := m.LoadTeam(id)
+		if err != nil {
+			return nil, fmt.Errorf("team %s: %w", id, err)
+		}
+		cfgs = append(cfgs, team)
+	}
+	return cfgs, nil
+}
+
+// Read reads the configuration from disk without validating it, so that an
+// invalid configuration can be shown and fixed
+func (m *Manager) Read() (*Config, error) {
 	if !m.Exists() {
 		return nil, ErrConfigNotFound
 	}
@@ -102,12 +685,58 @@ func (m *Manager) Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to expand path: %w", err)
 	}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+	return &cfg, nil
+}
+
+// Environment variables that override the configuration file, so that a
+// container can be configured without one. EnvConfigDir replaces
+// ~/.standup-bot, e.g. with a mounted volume, EnvRepoPath must be writable
+// and EnvTeams adds teams as a comma-separated list of team=repository.
+const (
+	EnvConfigDir  = "STANDUP_BOT_CONFIG_DIR"
+	EnvRepository = "STANDUP_BOT_REPOSITORY"
+	EnvName       = "STANDUP_BOT_NAME"
+	EnvRepoPath   = "STANDUP_BOT_REPO_PATH"
+	EnvTeams      = "STANDUP_BOT_TEAMS"
+	EnvForge      = "STANDUP_BOT_FORGE"
+	EnvBaseBranch = "STANDUP_BOT_BASE_BRANCH"
+	EnvTimezone   = "STANDUP_BOT_TIMEZONE"
+)
+
+// Configured reports whether there is a configuration to load, either a
+// file or the environment
+func (m *Manager) Configured() bool {
+	return m.Exists() || os.Getenv(EnvRepository) != ""
+}
+
+// applyEnv overrides settings with the environment variables that are set
+func (m *Manager) applyEnv(cfg *Config) error {
+	for name, setting := range map[string]*string{
+		EnvRepository: &cfg.Repository,
+		EnvName:       &cfg.Name,
+		EnvRepoPath:   &cfg.LocalRepoPath,
+		EnvForge:      &cfg.Forge,
+		EnvBaseBranch: &cfg.BaseBranch,
+		EnvTimezone:   &cfg.Timezone,
+	} {
+		if value := os.Getenv(name); value != "" {
+			*setting = value
+		}
 	}
 
-	return &cfg, nil
+	if teams := os.Getenv(EnvTeams); teams != "" {
+		for _, pair := range strings.Split(teams, ",") {
+			id, repository, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return fmt.Errorf("invalid %s entry '%s': expected team=repository", EnvTeams, pair)
+			}
+			if cfg.Teams == nil {
+				cfg.Teams = make(map[string]*TeamRoute)
+			}
+			cfg.Teams[strings.TrimSpace(id)] = &TeamRoute{Repository: strings.TrimSpace(repository)}
+		}
+	}
+	return m.expandPath(cfg)
 }
 
 // ErrConfigNotFound indicates the configuration file doesn't exist
@@ -0,0 +1,513 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// TeamConfigFile is the shared settings file at the root of the standup
+// repository. It also marks a repository as a standup repository.
+const TeamConfigFile = ".standup-bot.yaml"
+
+// HasRepoSettings checks if the repository at repoPath contains the team
+// settings file
+func HasRepoSettings(repoPath string) bool {
+	_, err := os.Stat(filepath.Join(repoPath, TeamConfigFile))
+	return err == nil
+}
+
+// TeamConfig holds settings shared by everyone using a standup repository
+type TeamConfig struct {
+	// DisableAI turns off AI features, including AI-assisted submissions
+	// through the MCP server, for the whole team
+	DisableAI bool `yaml:"disableAI"`
+
+	// Defaults for everyone's local config. A setting in someone's local
+	// config wins over the team's.
+	StorageFormat string              `yaml:"storageFormat"`
+	Forge         string              `yaml:"forge"`
+	BaseBranch    string              `yaml:"baseBranch"`
+	DayCutoffHour int                 `yaml:"dayCutoffHour"`
+	Timezone      string              `yaml:"timezone"`
+	Template      []types.SectionSpec `yaml:"template"`
+
+	// CommitTemplate is the default Go template for standup commit messages
+	CommitTemplate string `yaml:"commitTemplate"`
+
+	// Roster lists the team's members in their preferred order
+	Roster []string `yaml:"roster"`
+
+	// SubTeams splits the roster into named groups
+	SubTeams []SubTeam `yaml:"subTeams"`
+
+	// PRBody controls how standups are laid out in the daily PR body
+	PRBody PRBodySettings `yaml:"prBody"`
+
+	// PullRequest sets who and what is attached to new daily PRs
+	PullRequest PullRequestSettings `yaml:"pullRequest"`
+
+	// Escalation raises blockers outside the standup repository
+	Escalation EscalationSettings `yaml:"escalation"`
+
+	// Digest sets who gets the email digest of merged standups
+	Digest DigestSettings `yaml:"digest"`
+
+	// Meeting is the team's standup meeting, which reminders are timed by
+	Meeting MeetingSettings `yaml:"meeting"`
+
+	// Export publishes the merged standups to Notion or Confluence
+	Export ExportSettings `yaml:"export"`
+
+	// Redaction masks sensitive text in standups before they are published
+	Redaction RedactionSettings `yaml:"redaction"`
+
+	// Maintenance makes the repository read-only for everyone, e.g. during a
+	// migration, and MaintenanceMessage tells them why
+	Maintenance        bool   `yaml:"maintenance"`
+	MaintenanceMessage string `yaml:"maintenanceMessage"`
+}
+
+// PullRequestSettings are applied when the daily PR is created. Bitbucket
+// only supports reviewers, given as account IDs.
+type PullRequestSettings struct {
+	// Title is a Go template for the title of new daily PRs, executed like
+	// prBody.template; [Standup] and the date when empty
+	Title string `yaml:"title"`
+
+	// Draft opens the daily PR as a draft; it is marked ready when merged
+	Draft bool `yaml:"draft"`
+
+	Labels    []string `yaml:"labels"`
+	Assignees []string `yaml:"assignees"`
+	Reviewers []string `yaml:"reviewers"`
+
+	// Approvers are asked to review the daily PR by 'standup-bot
+	// --request-review', for members who can't merge to the base branch
+	Approvers []string `yaml:"approvers"`
+
+	// Merge sets how the daily PR is merged
+	Merge MergeSettings `yaml:"merge"`
+}
+
+// Merge strategies
+const (
+	MergeSquash = "squash"
+	MergeCommit = "merge"
+	MergeRebase = "rebase"
+)
+
+// MergeSettings control how the daily PR is merged, for organisations whose
+// policy forbids squash merges. The defaults squash and delete the branch.
+type MergeSettings struct {
+	// Strategy is squash, merge or rebase
+	Strategy string `yaml:"strategy"`
+	// DeleteBranch removes the daily branch once merged; true when unset
+	DeleteBranch *bool `yaml:"deleteBranch"`
+	// Auto turns on the forge's auto-merge when the daily PR is opened and
+	// instead of merging right away, so the PR merges once its checks and
+	// reviews pass
+	Auto bool `yaml:"auto"`
+}
+
+// GetStrategy returns the merge strategy
+func (m MergeSettings) GetStrategy() string {
+	if m.Strategy == "" {
+		return MergeSquash
+	}
+	return m.Strategy
+}
+
+// ShouldDeleteBranch reports whether the daily branch is deleted once merged
+func (m MergeSettings) ShouldDeleteBranch() bool {
+	return m.DeleteBranch == nil || *m.DeleteBranch
+}
+
+// ValidateMergeStrategy checks a merge strategy name
+func ValidateMergeStrategy(strategy string) error {
+	switch strategy {
+	case "", MergeSquash, MergeCommit, MergeRebase:
+		return nil
+	}
+	return fmt.Errorf("unknown merge strategy %q (valid: %s, %s, %s)", strategy, MergeSquash, MergeCommit, MergeRebase)
+}
+
+// DefaultBlockerLabel marks blocker issues in the tracker repository
+const DefaultBlockerLabel = "standup-blocker"
+
+// SlackWebhookEnv holds the Slack incoming webhook blockers are posted to.
+// It is a secret, so it is read from the environment rather than the
+// shared team config.
+const SlackWebhookEnv = "STANDUP_BOT_SLACK_WEBHOOK"
+
+// EscalationSettings open a GitHub issue, or post to Slack, when someone
+// reports a blocker, and close it or announce the fix once their standup
+// reports none
+type EscalationSettings struct {
+	// Repository is the owner/name tracker where each blocked member gets
+	// an issue
+	Repository string `yaml:"repository"`
+	// Label marks the blocker issues; DefaultBlockerLabel when empty
+	Label string `yaml:"label"`
+	// Mentions maps member names to the GitHub login mentioned in their
+	// issue, e.g. alice: "@alice-gh"
+	Mentions map[string]string `yaml:"mentions"`
+
+	// Slack posts blockers to the webhook in STANDUP_BOT_SLACK_WEBHOOK
+	Slack bool `yaml:"slack"`
+	// SlackMentions maps member names to their Slack member ID, e.g.
+	// alice: U024BE7LH
+	SlackMentions map[string]string `yaml:"slackMentions"`
+}
+
+// Enabled reports whether blockers are escalated anywhere
+func (e EscalationSettings) Enabled() bool {
+	return e.Repository != "" || e.Slack
+}
+
+// BlockerLabel returns the label of blocker issues
+func (e EscalationSettings) BlockerLabel() string {
+	if e.Label == "" {
+		return DefaultBlockerLabel
+	}
+	return e.Label
+}
+
+// DigestSettings are the team's choices for the email digest sent once the
+// daily PR is merged. The mail server is in the sender's local config.
+type DigestSettings struct {
+	// To lists the recipients, e.g. the team's distribution list, instead of
+	// those in the sender's config
+	To []string `yaml:"to"`
+	// OptOut lists members whose standups are left out of the digest, for
+	// those who don't want them emailed outside the repository
+	OptOut []string `yaml:"optOut"`
+}
+
+// CalendarURLEnv holds the iCal address of the calendar with the standup
+// meeting when the team config has none, since a private calendar's address
+// is a secret
+const CalendarURLEnv = "STANDUP_BOT_CALENDAR_URL"
+
+// DefaultRemindBefore is how long before the meeting reminders fire
+const DefaultRemindBefore = 15 * time.Minute
+
+// MeetingSettings find the team's standup meeting in a calendar, so
+// reminders follow it when it moves or is cancelled
+type MeetingSettings struct {
+	// Calendar is the iCal (.ics) address of the calendar, e.g. a Google
+	// Calendar's public address; CalendarURLEnv when empty
+	Calendar string `yaml:"calendar"`
+	// Summary picks the meeting's events by a word in their title, e.g.
+	// Standup; every timed event when empty
+	Summary string `yaml:"summary"`
+	// RemindBefore is how long before the meeting reminders fire, e.g. 30m;
+	// DefaultRemindBefore when empty
+	RemindBefore string `yaml:"remindBefore"`
+}
+
+// CalendarURL returns the address of the calendar with the meeting
+func (m MeetingSettings) CalendarURL() string {
+	if m.Calendar != "" {
+		return m.Calendar
+	}
+	return os.Getenv(CalendarURLEnv)
+}
+
+// Lead returns how long before the meeting reminders fire
+func (m MeetingSettings) Lead() time.Duration {
+	lead, err := time.ParseDuration(m.RemindBefore)
+	if err != nil {
+		return DefaultRemindBefore
+	}
+	return lead
+}
+
+// Export periods
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+// ExportSettings publish the merged standups as a page per day or week in
+// Notion or Confluence, for stakeholders who don't follow the repository.
+// The API tokens are secrets, read from the environment.
+type ExportSettings struct {
+	// Period is daily, for a page per day, or weekly, for a page per week
+	// that grows as each day is merged; daily when empty
+	Period string `yaml:"period"`
+
+	Notion     *NotionSettings     `yaml:"notion"`
+	Confluence *ConfluenceSettings `yaml:"confluence"`
+}
+
+// GetPeriod returns the export period
+func (e ExportSettings) GetPeriod() string {
+	if e.Period == "" {
+		return PeriodDaily
+	}
+	return e.Period
+}
+
+// Enabled reports whether standups are exported anywhere
+func (e ExportSettings) Enabled() bool {
+	return e.Notion != nil || e.Confluence != nil
+}
+
+// NotionSettings add the standups as pages of a Notion database shared with
+// the integration whose token is in STANDUP_BOT_NOTION_TOKEN
+type NotionSettings struct {
+	// Database is the ID of the database, from its URL
+	Database string `yaml:"database"`
+	// TitleProperty is the database's title column; Name when empty
+	TitleProperty string `yaml:"titleProperty"`
+}
+
+// ConfluenceSettings write the standups as pages of a Confluence space,
+// using the API token in STANDUP_BOT_CONFLUENCE_TOKEN
+type ConfluenceSettings struct {
+	// BaseURL is the Confluence site, e.g. https://acme.atlassian.net/wiki
+	BaseURL string `yaml:"baseURL"`
+	// Space is the key of the space, e.g. ENG
+	Space string `yaml:"space"`
+	// Parent is the ID of the page new pages are created under
+	Parent string `yaml:"parent"`
+	// Email is the Confluence Cloud account the API token belongs to; leave
+	// it empty for a Data Center personal access token
+	Email string `yaml:"email"`
+}
+
+// RedactedText replaces the text masked by redaction
+const RedactedText = "[redacted]"
+
+// secretPatterns match common credentials: GitHub, GitLab, Slack and AWS
+// tokens, JWTs, private keys, and values assigned to secret-sounding names
+var secretPatterns = []string{
+	`\b(?:ghp|gho|ghu|ghs|ghr|github_pat)_[A-Za-z0-9_]{20,}`,
+	`\bglpat-[A-Za-z0-9_-]{20,}`,
+	`\bxox[abposr]-[A-Za-z0-9-]{10,}`,
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`,
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----`,
+	`(?i)\b(?:api[_-]?key|access[_-]?key|secret|token|password|passwd)\s*[:=]\s*\S+`,
+}
+
+// emailPattern matches email addresses
+const emailPattern = `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`
+
+// RedactionSettings mask text in standups before they go into commit
+// messages, the daily PR body and Slack or tracker posts. The standup files
+// themselves are kept as written.
+type RedactionSettings struct {
+	// Secrets masks token-like credentials, e.g. GitHub or AWS keys
+	Secrets bool `yaml:"secrets"`
+	// Emails masks email addresses, e.g. those of customers
+	Emails bool `yaml:"emails"`
+	// Patterns are regular expressions whose matches are masked
+	Patterns []string `yaml:"patterns"`
+	// Keywords are words masked wherever they appear, regardless of case,
+	// e.g. a customer's name
+	Keywords []string `yaml:"keywords"`
+}
+
+// Compile returns the expressions matching the text to mask, or none when
+// redaction is off
+func (r RedactionSettings) Compile() ([]*regexp.Regexp, error) {
+	var sources []string
+	if r.Secrets {
+		sources = append(sources, secretPatterns...)
+	}
+	if r.Emails {
+		sources = append(sources, emailPattern)
+	}
+	sources = append(sources, r.Patterns...)
+	for _, keyword := range r.Keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			sources = append(sources, `(?i)\b`+regexp.QuoteMeta(keyword)+`\b`)
+		}
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(sources))
+	for _, source := range sources {
+		pattern, err := regexp.Compile(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", source, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// SubTeam is a named group of team members
+type SubTeam struct {
+	Name    string   `yaml:"name"`
+	Members []string `yaml:"members"`
+}
+
+// Orderings of standups in the daily PR body
+const (
+	OrderAlphabetical = "alphabetical"
+	OrderRoster       = "roster"
+	OrderSubmission   = "submission"
+)
+
+// Groupings of standups in the daily PR body
+const (
+	GroupNone    = "none"
+	GroupTeam    = "team"
+	GroupProject = "project"
+)
+
+// PRBodySettings controls the ordering, grouping and layout of the daily PR
+// body. Empty values mean roster order, or alphabetical without a roster,
+// and no grouping.
+type PRBodySettings struct {
+	Order   string `yaml:"order"`
+	GroupBy string `yaml:"groupBy"`
+
+	// Template is a Go template for the whole body, given the date, roster
+	// and standups, e.g. to add the team's own sections
+	Template string `yaml:"template"`
+}
+
+// PROrder returns the order of standups in the daily PR body: the roster's
+// by default, or alphabetical without a roster
+func (t *TeamConfig) PROrder() string {
+	if t.PRBody.Order != "" {
+		return t.PRBody.Order
+	}
+	if len(t.Roster) > 0 {
+		return OrderRoster
+	}
+	return OrderAlphabetical
+}
+
+// Validate checks the team config for unknown settings
+func (t *TeamConfig) Validate() error {
+	switch t.PRBody.Order {
+	case "", OrderAlphabetical, OrderRoster, OrderSubmission:
+	default:
+		return fmt.Errorf("invalid prBody.order %q (valid: %s, %s, %s)", t.PRBody.Order, OrderAlphabetical, OrderRoster, OrderSubmission)
+	}
+
+	switch t.PRBody.GroupBy {
+	case "", GroupNone, GroupTeam, GroupProject:
+	default:
+		return fmt.Errorf("invalid prBody.groupBy %q (valid: %s, %s, %s)", t.PRBody.GroupBy, GroupNone, GroupTeam, GroupProject)
+	}
+	if t.PRBody.GroupBy == GroupTeam && len(t.SubTeams) == 0 {
+		return fmt.Errorf("prBody.groupBy %q requires subTeams", GroupTeam)
+	}
+	if _, err := types.ParsePRTemplate("body", t.PRBody.Template); err != nil {
+		return fmt.Errorf("invalid prBody.template: %w", err)
+	}
+	if _, err := types.ParsePRTemplate("title", t.PullRequest.Title); err != nil {
+		return fmt.Errorf("invalid pullRequest.title: %w", err)
+	}
+
+	if _, err := types.NewStorageFormat(t.StorageFormat); err != nil {
+		return fmt.Errorf("invalid storageFormat: %w", err)
+	}
+	if _, err := types.NewForgeKind(t.Forge); err != nil {
+		return fmt.Errorf("invalid forge: %w", err)
+	}
+	if t.DayCutoffHour < 0 || t.DayCutoffHour > 23 {
+		return fmt.Errorf("invalid dayCutoffHour: %d (must be between 0 and 23)", t.DayCutoffHour)
+	}
+	if t.Timezone != "" {
+		if _, err := time.LoadLocation(t.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %q", t.Timezone)
+		}
+	}
+	if err := types.ValidateTemplate(t.Template); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	if _, err := types.ParseCommitTemplate(t.CommitTemplate); err != nil {
+		return fmt.Errorf("invalid commitTemplate: %w", err)
+	}
+
+	if err := ValidateMergeStrategy(t.PullRequest.Merge.Strategy); err != nil {
+		return fmt.Errorf("invalid pullRequest.merge: %w", err)
+	}
+
+	for _, team := range t.SubTeams {
+		if team.Name == "" {
+			return fmt.Errorf("subTeams entries must have a name")
+		}
+	}
+
+	for _, to := range t.Digest.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("invalid digest.to address %q: %w", to, err)
+		}
+	}
+
+	if url := t.Meeting.Calendar; url != "" && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "webcal://") {
+		return fmt.Errorf("invalid meeting.calendar %q: must be an http(s) or webcal URL", url)
+	}
+	if before := t.Meeting.RemindBefore; before != "" {
+		if lead, err := time.ParseDuration(before); err != nil || lead < 0 {
+			return fmt.Errorf("invalid meeting.remindBefore %q: must be a duration such as 30m", before)
+		}
+	}
+
+	switch t.Export.Period {
+	case "", PeriodDaily, PeriodWeekly:
+	default:
+		return fmt.Errorf("invalid export.period %q (valid: %s, %s)", t.Export.Period, PeriodDaily, PeriodWeekly)
+	}
+	if n := t.Export.Notion; n != nil && n.Database == "" {
+		return fmt.Errorf("export.notion requires a database")
+	}
+	if c := t.Export.Confluence; c != nil {
+		if !strings.HasPrefix(c.BaseURL, "https://") && !strings.HasPrefix(c.BaseURL, "http://") {
+			return fmt.Errorf("invalid export.confluence.baseURL %q: must be an http(s) URL", c.BaseURL)
+		}
+		if c.Space == "" {
+			return fmt.Errorf("export.confluence requires a space")
+		}
+	}
+
+	if _, err := t.Redaction.Compile(); err != nil {
+		return fmt.Errorf("invalid redaction: %w", err)
+	}
+
+	if repo := t.Escalation.Repository; repo != "" {
+		if _, err := types.NewRepository(repo); err != nil {
+			return fmt.Errorf("invalid escalation.repository: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadTeamConfig reads the team config from a clone of the standup
+// repository. A repository without one has the default settings.
+func LoadTeamConfig(repoPath string) (*TeamConfig, error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, TeamConfigFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &TeamConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", TeamConfigFile, err)
+	}
+	return ParseTeamConfig(data)
+}
+
+// ParseTeamConfig parses and validates the contents of a team config file
+func ParseTeamConfig(data []byte) (*TeamConfig, error) {
+	var team TeamConfig
+	if err := yaml.Unmarshal(data, &team); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", TeamConfigFile, err)
+	}
+	if err := team.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", TeamConfigFile, err)
+	}
+	return &team, nil
+}
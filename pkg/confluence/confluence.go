@@ -0,0 +1,169 @@
+// Package confluence publishes markdown documents as Confluence pages, for
+// stakeholders who read updates in Confluence.
+package confluence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// TokenEnv holds the Confluence API token. With an email configured it is a
+// Confluence Cloud API token, otherwise a Data Center personal access token.
+const TokenEnv = "STANDUP_BOT_CONFLUENCE_TOKEN"
+
+// Client writes pages through the Confluence REST API
+type Client struct {
+	baseURL    string
+	email      string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the Confluence site at baseURL, e.g.
+// https://acme.atlassian.net/wiki, authenticating with the token in
+// STANDUP_BOT_CONFLUENCE_TOKEN
+func NewClient(baseURL, email string) (*Client, error) {
+	token := os.Getenv(TokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("%s is not set", TokenEnv)
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		email:      email,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// page is the part of a Confluence page the client reads
+type page struct {
+	ID      string `json:"id"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Links struct {
+		Base  string `json:"base"`
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+// Publish creates or updates the page titled title in the space, with
+// markdown as its content, and returns its URL. New pages go under the
+// parent page when parent, a page ID, is given.
+func (c *Client) Publish(ctx context.Context, space, parent, title, markdown string) (string, error) {
+	body, err := storageFormat(markdown)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{"spaceKey": {space}, "title": {title}, "expand": {"version"}}
+	var found struct {
+		Results []page `json:"results"`
+		Links   struct {
+			Base string `json:"base"`
+		} `json:"_links"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/rest/api/content?"+query.Encode(), nil, &found); err != nil {
+		return "", err
+	}
+
+	content := map[string]any{
+		"type":  "page",
+		"title": title,
+		"space": map[string]any{"key": space},
+		"body": map[string]any{
+			"storage": map[string]any{"value": body, "representation": "storage"},
+		},
+	}
+	var saved page
+	if len(found.Results) > 0 {
+		existing := found.Results[0]
+		content["version"] = map[string]any{"number": existing.Version.Number + 1}
+		err = c.do(ctx, http.MethodPut, "/rest/api/content/"+existing.ID, content, &saved)
+	} else {
+		if parent != "" {
+			content["ancestors"] = []any{map[string]any{"id": parent}}
+		}
+		err = c.do(ctx, http.MethodPost, "/rest/api/content", content, &saved)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	base := saved.Links.Base
+	if base == "" {
+		base = c.baseURL
+	}
+	return base + saved.Links.WebUI, nil
+}
+
+// do sends a request to the API, decoding the response into result
+func (c *Client) do(ctx context.Context, method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode Confluence request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.email != "" {
+		req.SetBasicAuth(c.email, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Confluence request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read Confluence response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("Confluence returned %s: %s", resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("Confluence returned %s", resp.Status)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode Confluence response: %w", err)
+	}
+	return nil
+}
+
+// storageFormat renders markdown as Confluence storage format, which is
+// XHTML. Raw HTML in the markdown is left out.
+func storageFormat(markdown string) (string, error) {
+	var b bytes.Buffer
+	md := goldmark.New(goldmark.WithRendererOptions(html.WithXHTML()))
+	if err := md.Convert([]byte(markdown), &b); err != nil {
+		return "", fmt.Errorf("failed to render the page: %w", err)
+	}
+	return b.String(), nil
+}
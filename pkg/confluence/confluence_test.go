@@ -0,0 +1,87 @@
+package confluence
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+		body     string
+	}{
+		{"new page", `{"results":[]}`, "POST /rest/api/content", `"ancestors":[{"id":"42"}]`},
+		{"existing page", `{"results":[{"id":"7","version":{"number":3}}]}`, "PUT /rest/api/content/7", `"version":{"number":4}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if user, token, ok := r.BasicAuth(); !ok || user != "bot@acme.com" || token != "secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				if r.Method == http.MethodGet {
+					if r.URL.Query().Get("spaceKey") != "ENG" || r.URL.Query().Get("title") != "Daily Standups - 2024-01-15" {
+						t.Errorf("lookup query = %s", r.URL.RawQuery)
+					}
+					w.Write([]byte(tt.existing))
+					return
+				}
+				data, _ := io.ReadAll(r.Body)
+				saved, body = r.Method+" "+r.URL.Path, string(data)
+				w.Write([]byte(`{"id":"7","_links":{"base":"https://acme.atlassian.net/wiki","webui":"/spaces/ENG/pages/7"}}`))
+			}))
+			defer server.Close()
+
+			t.Setenv(TokenEnv, "secret")
+			client, err := NewClient(server.URL+"/", "bot@acme.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			url, err := client.Publish(context.Background(), "ENG", "42", "Daily Standups - 2024-01-15", "**alice**\n\n- Shipped <b>it</b><br>\n")
+			if err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+
+			if url != "https://acme.atlassian.net/wiki/spaces/ENG/pages/7" {
+				t.Errorf("Publish() = %q", url)
+			}
+			if saved != tt.want {
+				t.Errorf("saved with %s, want %s", saved, tt.want)
+			}
+			for _, want := range []string{tt.body, `"representation":"storage"`, `\u003cstrong\u003ealice\u003c/strong\u003e`} {
+				if !strings.Contains(body, want) {
+					t.Errorf("request should contain %s:\n%s", want, body)
+				}
+			}
+			if strings.Contains(body, `\u003cb\u003e`) {
+				t.Errorf("raw HTML should be left out:\n%s", body)
+			}
+		})
+	}
+}
+
+func TestPublishReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"No space with key ENG"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv(TokenEnv, "secret")
+	client, err := NewClient(server.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Publish(context.Background(), "ENG", "", "Standups", "text")
+	if err == nil || !strings.Contains(err.Error(), "No space with key ENG") {
+		t.Errorf("Publish() error = %v, want the API's message", err)
+	}
+}
@@ -0,0 +1,317 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// bitbucketAPI is the Bitbucket Cloud REST API endpoint
+const bitbucketAPI = "https://api.bitbucket.org/2.0"
+
+// Bitbucket manages pull requests with the Bitbucket Cloud REST API. It
+// authenticates with BITBUCKET_TOKEN, or BITBUCKET_USERNAME and
+// BITBUCKET_APP_PASSWORD.
+type Bitbucket struct {
+	repository string
+	runner     git.CommandRunner
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewBitbucket creates a Bitbucket provider
+func NewBitbucket(repository string, runner git.CommandRunner) *Bitbucket {
+	return &Bitbucket{
+		repository: repository,
+		runner:     runner,
+		apiURL:     bitbucketAPI,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns the display name of the forge
+func (b *Bitbucket) Name() string {
+	return "Bitbucket"
+}
+
+// CheckAvailable checks that credentials are set and accepted
+func (b *Bitbucket) CheckAvailable() error {
+	if os.Getenv("BITBUCKET_TOKEN") == "" && (os.Getenv("BITBUCKET_USERNAME") == "" || os.Getenv("BITBUCKET_APP_PASSWORD") == "") {
+		return fmt.Errorf("Bitbucket credentials not found. Please set BITBUCKET_TOKEN, or BITBUCKET_USERNAME and BITBUCKET_APP_PASSWORD")
+	}
+	if err := b.do(http.MethodGet, "/repositories/"+b.repository, nil, nil); err != nil {
+		return fmt.Errorf("not authenticated with Bitbucket: %w", err)
+	}
+	return nil
+}
+
+// Clone clones the repository over HTTPS with git
+func (b *Bitbucket) Clone(targetPath string, opts git.CloneOptions) error {
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	cloneURL := fmt.Sprintf("https://bitbucket.org/%s.git", b.repository)
+	args := append(append([]string{"clone"}, opts.Flags()...), cloneURL, targetPath)
+	output, err := b.runner.Run("git", args...)
+	if err != nil {
+		return fmt.Errorf("failed to clone repository: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// bitbucketPR is the subset of a Bitbucket pull request used here
+type bitbucketPR struct {
+	ID int `json:"id"`
+}
+
+// FindPullRequest returns the open pull request for a branch
+func (b *Bitbucket) FindPullRequest(repoPath, branch string) (bool, string) {
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf(`source.branch.name="%s" AND state="OPEN"`, branch))
+
+	var page struct {
+		Values []bitbucketPR `json:"values"`
+	}
+	if err := b.do(http.MethodGet, b.pullRequestsPath()+"?"+query.Encode(), nil, &page); err != nil || len(page.Values) == 0 {
+		return false, ""
+	}
+	return true, strconv.Itoa(page.Values[0].ID)
+}
+
+// CreatePullRequest opens a pull request. Without a head branch, the
+// repository's current branch is used.
+func (b *Bitbucket) CreatePullRequest(repoPath string, opts PullRequestOptions) error {
+	head := opts.Head
+	if head == "" {
+		output, err := b.runner.RunInDir(repoPath, "git", "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return fmt.Errorf("failed to determine current branch: %w", err)
+		}
+		head = strings.TrimSpace(string(output))
+	}
+	base := opts.Base
+	if base == "" {
+		base = "main"
+	}
+
+	request := map[string]interface{}{
+		"title":               opts.Title,
+		"description":         opts.Body,
+		"source":              map[string]interface{}{"branch": map[string]string{"name": head}},
+		"destination":         map[string]interface{}{"branch": map[string]string{"name": base}},
+		"close_source_branch": true,
+		"draft":               opts.Draft,
+	}
+	// Bitbucket has no labels or assignees; reviewers are account IDs
+	if len(opts.Reviewers) > 0 {
+		reviewers := make([]map[string]string, 0, len(opts.Reviewers))
+		for _, reviewer := range opts.Reviewers {
+			reviewers = append(reviewers, map[string]string{"account_id": reviewer})
+		}
+		request["reviewers"] = reviewers
+	}
+	if err := b.do(http.MethodPost, b.pullRequestsPath(), request, nil); err != nil {
+		return fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return nil
+}
+
+// UpdatePullRequest replaces the description of a pull request
+func (b *Bitbucket) UpdatePullRequest(repoPath, number, body string) error {
+	request := map[string]string{"description": body}
+	if err := b.do(http.MethodPut, b.pullRequestsPath()+"/"+number, request, nil); err != nil {
+		return fmt.Errorf("failed to update pull request: %w", err)
+	}
+	return nil
+}
+
+// MarkReady takes a pull request out of draft
+func (b *Bitbucket) MarkReady(repoPath, number string) error {
+	request := map[string]bool{"draft": false}
+	if err := b.do(http.MethodPut, b.pullRequestsPath()+"/"+number, request, nil); err != nil {
+		return fmt.Errorf("failed to mark pull request ready: %w", err)
+	}
+	return nil
+}
+
+// bitbucketStrategies maps merge strategies to Bitbucket's names
+var bitbucketStrategies = map[string]string{
+	"":          "squash",
+	MergeSquash: "squash",
+	MergeCommit: "merge_commit",
+	MergeRebase: "rebase_fast_forward",
+}
+
+// MergePullRequest merges a pull request with the given strategy
+func (b *Bitbucket) MergePullRequest(repoPath, number string, opts MergeOptions) error {
+	if opts.Auto {
+		return fmt.Errorf("Bitbucket doesn't support auto-merge")
+	}
+	request := map[string]interface{}{
+		"merge_strategy":      bitbucketStrategies[opts.Strategy],
+		"close_source_branch": opts.DeleteBranch,
+	}
+	if err := b.do(http.MethodPost, b.pullRequestsPath()+"/"+number+"/merge", request, nil); err != nil {
+		return fmt.Errorf("failed to merge pull request: %w", err)
+	}
+	return nil
+}
+
+// PullRequestURL returns the web URL of a pull request
+func (b *Bitbucket) PullRequestURL(number string) string {
+	return fmt.Sprintf("https://bitbucket.org/%s/pull-requests/%s", b.repository, number)
+}
+
+// RequestReview adds reviewers, given as account IDs, to a pull request.
+// Bitbucket replaces the whole list, so the current reviewers are read first.
+func (b *Bitbucket) RequestReview(repoPath, number string, reviewers []string) error {
+	var pr struct {
+		Reviewers []struct {
+			AccountID string `json:"account_id"`
+		} `json:"reviewers"`
+	}
+	if err := b.do(http.MethodGet, b.pullRequestsPath()+"/"+number, nil, &pr); err != nil {
+		return fmt.Errorf("failed to read pull request: %w", err)
+	}
+
+	var accounts []map[string]string
+	seen := make(map[string]bool)
+	for _, reviewer := range pr.Reviewers {
+		seen[reviewer.AccountID] = true
+		accounts = append(accounts, map[string]string{"account_id": reviewer.AccountID})
+	}
+	for _, reviewer := range reviewers {
+		if !seen[reviewer] {
+			seen[reviewer] = true
+			accounts = append(accounts, map[string]string{"account_id": reviewer})
+		}
+	}
+
+	request := map[string]interface{}{"reviewers": accounts}
+	if err := b.do(http.MethodPut, b.pullRequestsPath()+"/"+number, request, nil); err != nil {
+		return fmt.Errorf("failed to request review: %w", err)
+	}
+	return nil
+}
+
+// PullRequestState returns the latest pull request for a branch with its
+// build statuses
+func (b *Bitbucket) PullRequestState(repoPath, branch string) (*PullRequestState, error) {
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf(`source.branch.name="%s"`, branch))
+	query.Set("sort", "-created_on")
+	query.Add("state", "OPEN")
+	query.Add("state", "MERGED")
+	query.Add("state", "DECLINED")
+	query.Add("state", "SUPERSEDED")
+
+	var page struct {
+		Values []struct {
+			ID    int    `json:"id"`
+			State string `json:"state"`
+		} `json:"values"`
+	}
+	if err := b.do(http.MethodGet, b.pullRequestsPath()+"?"+query.Encode(), nil, &page); err != nil {
+		return nil, fmt.Errorf("failed to look up pull request: %w", err)
+	}
+	if len(page.Values) == 0 {
+		return nil, nil
+	}
+
+	pr := page.Values[0]
+	state := &PullRequestState{Number: strconv.Itoa(pr.ID), State: StateClosed}
+	switch pr.State {
+	case "OPEN":
+		state.State = StateOpen
+	case "MERGED":
+		state.State = StateMerged
+	}
+
+	var statuses struct {
+		Values []struct {
+			Key   string `json:"key"`
+			Name  string `json:"name"`
+			State string `json:"state"`
+		} `json:"values"`
+	}
+	if err := b.do(http.MethodGet, fmt.Sprintf("%s/%d/statuses", b.pullRequestsPath(), pr.ID), nil, &statuses); err != nil {
+		return nil, fmt.Errorf("failed to look up build statuses: %w", err)
+	}
+	for _, status := range statuses.Values {
+		check := Check{Name: status.Name, Status: CheckPending}
+		if check.Name == "" {
+			check.Name = status.Key
+		}
+		switch status.State {
+		case "SUCCESSFUL":
+			check.Status = CheckPass
+		case "FAILED", "STOPPED":
+			check.Status = CheckFail
+		}
+		state.Checks = append(state.Checks, check)
+	}
+	return state, nil
+}
+
+// pullRequestsPath returns the API path of the repository's pull requests
+func (b *Bitbucket) pullRequestsPath() string {
+	return "/repositories/" + b.repository + "/pullrequests"
+}
+
+// do sends an authenticated API request, encoding body and decoding the
+// response into result when they are non-nil
+func (b *Bitbucket) do(method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, b.apiURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token := os.Getenv("BITBUCKET_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else {
+		req.SetBasicAuth(os.Getenv("BITBUCKET_USERNAME"), os.Getenv("BITBUCKET_APP_PASSWORD"))
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to Bitbucket failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read Bitbucket response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Bitbucket returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("failed to decode Bitbucket response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBitbucket(t *testing.T) {
+	t.Setenv("BITBUCKET_TOKEN", "secret")
+
+	var created map[string]interface{}
+	var merged bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repositories/team/standups/pullrequests":
+			if r.URL.Query().Get("q") != `source.branch.name="standup/2024-01-31" AND state="OPEN"` {
+				t.Errorf("unexpected query %q", r.URL.Query().Get("q"))
+			}
+			w.Write([]byte(`{"values": [{"id": 7}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repositories/team/standups/pullrequests":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPost && r.URL.Path == "/repositories/team/standups/pullrequests/7/merge":
+			merged = true
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	runner := &fakeRunner{outputs: map[string]string{"git rev-parse": "standup/2024-01-31\n"}}
+	bitbucket := NewBitbucket("team/standups", runner)
+	bitbucket.apiURL = server.URL
+
+	exists, number := bitbucket.FindPullRequest("/repo", "standup/2024-01-31")
+	if !exists || number != "7" {
+		t.Errorf("FindPullRequest() = %v, %s, want true, 7", exists, number)
+	}
+
+	if err := bitbucket.CreatePullRequest("/repo", PullRequestOptions{Title: "[Standup] 2024-01-31", Body: "body", Draft: true, Reviewers: []string{"557058:abc"}}); err != nil {
+		t.Fatalf("CreatePullRequest() error = %v", err)
+	}
+	source := created["source"].(map[string]interface{})["branch"].(map[string]interface{})["name"]
+	if source != "standup/2024-01-31" {
+		t.Errorf("source branch = %v, want the current branch", source)
+	}
+	destination := created["destination"].(map[string]interface{})["branch"].(map[string]interface{})["name"]
+	if destination != "main" {
+		t.Errorf("destination branch = %v, want main", destination)
+	}
+	if created["draft"] != true {
+		t.Errorf("draft = %v, want true", created["draft"])
+	}
+	reviewers, _ := created["reviewers"].([]interface{})
+	if len(reviewers) != 1 || reviewers[0].(map[string]interface{})["account_id"] != "557058:abc" {
+		t.Errorf("reviewers = %v, want the account ID", created["reviewers"])
+	}
+
+	if err := bitbucket.MergePullRequest("/repo", "7", MergeOptions{Strategy: MergeSquash, DeleteBranch: true}); err != nil || !merged {
+		t.Errorf("MergePullRequest() error = %v, merged = %v", err, merged)
+	}
+
+	if err := bitbucket.UpdatePullRequest("/repo", "8", "body"); err == nil {
+		t.Error("UpdatePullRequest() should surface API errors")
+	}
+}
+
+func TestBitbucketRequiresCredentials(t *testing.T) {
+	t.Setenv("BITBUCKET_TOKEN", "")
+	t.Setenv("BITBUCKET_USERNAME", "")
+	t.Setenv("BITBUCKET_APP_PASSWORD", "")
+
+	if err := NewBitbucket("team/standups", &fakeRunner{}).CheckAvailable(); err == nil {
+		t.Error("CheckAvailable() without credentials should return an error")
+	}
+}
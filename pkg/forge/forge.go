@@ -0,0 +1,148 @@
+// Package forge abstracts the hosting service of the standup repository, so
+// the pull request workflow works on GitHub, GitLab and Bitbucket.
+package forge
+
+import (
+	"fmt"
+
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// PullRequestOptions contains options for creating a pull request
+type PullRequestOptions struct {
+	Title string
+	Body  string
+	Base  string
+	Head  string
+	Draft bool
+
+	// Labels, Assignees and Reviewers are applied where the forge supports them
+	Labels    []string
+	Assignees []string
+	Reviewers []string
+}
+
+// Merge strategies
+const (
+	MergeSquash = "squash"
+	MergeCommit = "merge"
+	MergeRebase = "rebase"
+)
+
+// MergeOptions controls how a pull request is merged
+type MergeOptions struct {
+	// Strategy is MergeSquash, MergeCommit or MergeRebase
+	Strategy string
+	// DeleteBranch removes the source branch once merged
+	DeleteBranch bool
+	// Auto merges once checks and reviews pass instead of right away. Only
+	// GitHub and GitLab support it.
+	Auto bool
+}
+
+// Provider performs the operations that depend on the hosting service:
+// cloning the repository and managing pull (or merge) requests
+type Provider interface {
+	// Name returns the display name of the forge, e.g. "GitLab"
+	Name() string
+
+	// CheckAvailable verifies that the tools and credentials the provider
+	// needs are present
+	CheckAvailable() error
+
+	// Clone clones the repository to targetPath
+	Clone(targetPath string, opts git.CloneOptions) error
+
+	// FindPullRequest returns whether an open pull request exists for the
+	// branch, and its number
+	FindPullRequest(repoPath, branch string) (bool, string)
+
+	// CreatePullRequest opens a pull request
+	CreatePullRequest(repoPath string, opts PullRequestOptions) error
+
+	// UpdatePullRequest replaces the body of an existing pull request
+	UpdatePullRequest(repoPath, number, body string) error
+
+	// MarkReady takes a pull request out of draft
+	MarkReady(repoPath, number string) error
+
+	// MergePullRequest merges a pull request, or with opts.Auto arranges for
+	// it to merge once its checks and reviews pass
+	MergePullRequest(repoPath, number string, opts MergeOptions) error
+
+	// PullRequestURL returns the web URL of a pull request
+	PullRequestURL(number string) string
+
+	// RequestReview asks reviewers to review a pull request, keeping the
+	// reviewers it already has
+	RequestReview(repoPath, number string, reviewers []string) error
+
+	// PullRequestState returns the most recent pull request for the branch,
+	// open or not, with its checks; nil when the branch has none
+	PullRequestState(repoPath, branch string) (*PullRequestState, error)
+}
+
+// Pull request states
+const (
+	StateOpen   = "open"
+	StateMerged = "merged"
+	StateClosed = "closed"
+)
+
+// Check statuses
+const (
+	CheckPass    = "pass"
+	CheckFail    = "fail"
+	CheckPending = "pending"
+)
+
+// Review decisions
+const (
+	ReviewApproved         = "approved"
+	ReviewRequired         = "review_required"
+	ReviewChangesRequested = "changes_requested"
+)
+
+// PullRequestState describes a pull request and the checks run on it
+type PullRequestState struct {
+	Number string
+	// State is StateOpen, StateMerged or StateClosed
+	State  string
+	Checks []Check
+	// Review is ReviewApproved, ReviewRequired or ReviewChangesRequested;
+	// empty when no review is required or the forge doesn't report it
+	Review string
+}
+
+// FailingChecks returns the names of the checks that failed
+func (p *PullRequestState) FailingChecks() []string {
+	var names []string
+	for _, check := range p.Checks {
+		if check.Status == CheckFail {
+			names = append(names, check.Name)
+		}
+	}
+	return names
+}
+
+// Check is a CI job or commit status reported on a pull request
+type Check struct {
+	Name string
+	// Status is CheckPass, CheckFail or CheckPending
+	Status string
+}
+
+// New creates the provider for a forge. The repository is in "owner/name"
+// form; commands are run with runner.
+func New(kind types.ForgeKind, repository string, runner git.CommandRunner) (Provider, error) {
+	switch kind {
+	case types.ForgeGitHub, types.ForgeAuto:
+		return NewGitHub(repository, runner), nil
+	case types.ForgeGitLab:
+		return NewGitLab(repository, runner), nil
+	case types.ForgeBitbucket:
+		return NewBitbucket(repository, runner), nil
+	}
+	return nil, fmt.Errorf("unsupported forge: %s", kind)
+}
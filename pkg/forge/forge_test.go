@@ -0,0 +1,194 @@
+package forge
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// fakeRunner records commands and returns canned output keyed by the command line
+type fakeRunner struct {
+	outputs  map[string]string
+	errors   map[string]error
+	commands []string
+}
+
+func (f *fakeRunner) Run(name string, args ...string) ([]byte, error) {
+	return f.RunInDir("", name, args...)
+}
+
+func (f *fakeRunner) RunInDir(dir, name string, args ...string) ([]byte, error) {
+	line := strings.Join(append([]string{name}, args...), " ")
+	f.commands = append(f.commands, line)
+	for prefix, err := range f.errors {
+		if strings.HasPrefix(line, prefix) {
+			return nil, err
+		}
+	}
+	for prefix, output := range f.outputs {
+		if strings.HasPrefix(line, prefix) {
+			return []byte(output), nil
+		}
+	}
+	return nil, nil
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		kind types.ForgeKind
+		want string
+	}{
+		{types.ForgeAuto, "GitHub"},
+		{types.ForgeGitHub, "GitHub"},
+		{types.ForgeGitLab, "GitLab"},
+		{types.ForgeBitbucket, "Bitbucket"},
+	}
+
+	for _, tt := range tests {
+		provider, err := New(tt.kind, "org/standups", &fakeRunner{})
+		if err != nil {
+			t.Fatalf("New(%q) error = %v", tt.kind, err)
+		}
+		if provider.Name() != tt.want {
+			t.Errorf("New(%q).Name() = %s, want %s", tt.kind, provider.Name(), tt.want)
+		}
+	}
+
+	if _, err := New("gitea", "org/standups", &fakeRunner{}); err == nil {
+		t.Error("New() with an unknown forge should return an error")
+	}
+}
+
+func TestDetectForgeKind(t *testing.T) {
+	tests := []struct {
+		url  string
+		want types.ForgeKind
+	}{
+		{"https://github.com/org/standups.git", types.ForgeGitHub},
+		{"git@gitlab.com:org/standups.git", types.ForgeGitLab},
+		{"https://gitlab.example.com/group/standups", types.ForgeGitLab},
+		{"ssh://git@bitbucket.org/team/standups.git", types.ForgeBitbucket},
+		{"https://alice@bitbucket.org/team/standups.git", types.ForgeBitbucket},
+		{"https://github.com/org/gitlab-mirror.git", types.ForgeGitHub},
+		{"org/standups", types.ForgeGitHub},
+	}
+
+	for _, tt := range tests {
+		if got := types.DetectForgeKind(tt.url); got != tt.want {
+			t.Errorf("DetectForgeKind(%q) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestGitLab(t *testing.T) {
+	runner := &fakeRunner{
+		outputs: map[string]string{
+			"glab mr list": `[{"iid": 42, "title": "[Standup] 2024-01-31"}]`,
+		},
+	}
+	gitlab := NewGitLab("org/standups", runner)
+
+	exists, number := gitlab.FindPullRequest("/repo", "standup/2024-01-31")
+	if !exists || number != "42" {
+		t.Errorf("FindPullRequest() = %v, %s, want true, 42", exists, number)
+	}
+
+	opts := PullRequestOptions{Title: "T", Body: "B", Base: "main", Head: "standup/2024-01-31", Draft: true, Labels: []string{"standup"}, Reviewers: []string{"lead"}}
+	if err := gitlab.CreatePullRequest("/repo", opts); err != nil {
+		t.Fatalf("CreatePullRequest() error = %v", err)
+	}
+	if err := gitlab.MarkReady("/repo", "42"); err != nil {
+		t.Fatalf("MarkReady() error = %v", err)
+	}
+	if err := gitlab.MergePullRequest("/repo", "42", MergeOptions{Strategy: MergeSquash, DeleteBranch: true}); err != nil {
+		t.Fatalf("MergePullRequest() error = %v", err)
+	}
+
+	want := []string{
+		"glab mr list --source-branch standup/2024-01-31 --output json",
+		"glab mr create --title T --description B --yes --target-branch main --source-branch standup/2024-01-31 --draft --label standup --reviewer lead",
+		"glab mr update 42 --ready",
+		"glab mr merge 42 --squash --remove-source-branch --yes",
+	}
+	if fmt.Sprint(runner.commands) != fmt.Sprint(want) {
+		t.Errorf("commands = %q, want %q", runner.commands, want)
+	}
+
+	if got := gitlab.PullRequestURL("42"); got != "https://gitlab.com/org/standups/-/merge_requests/42" {
+		t.Errorf("PullRequestURL() = %s", got)
+	}
+}
+
+func TestGitLabNoMergeRequest(t *testing.T) {
+	gitlab := NewGitLab("org/standups", &fakeRunner{outputs: map[string]string{"glab mr list": "[]"}})
+	if exists, _ := gitlab.FindPullRequest("/repo", "standup/2024-01-31"); exists {
+		t.Error("FindPullRequest() should report no merge request for an empty list")
+	}
+
+	failing := NewGitLab("org/standups", &fakeRunner{errors: map[string]error{"glab auth": fmt.Errorf("exit status 1")}})
+	if err := failing.CheckAvailable(); err == nil || !strings.Contains(err.Error(), "glab auth login") {
+		t.Errorf("CheckAvailable() error = %v, want a login hint", err)
+	}
+}
+
+func TestPullRequestState(t *testing.T) {
+	github := NewGitHub("org/standups", &fakeRunner{outputs: map[string]string{
+		"gh pr list": `[{"number": 12, "state": "OPEN", "statusCheckRollup": [
+			{"name": "lint", "status": "COMPLETED", "conclusion": "SUCCESS"},
+			{"name": "test", "status": "IN_PROGRESS", "conclusion": ""},
+			{"context": "ci/deploy", "state": "FAILURE"}
+		], "reviewDecision": "REVIEW_REQUIRED"}]`,
+	}})
+	state, err := github.PullRequestState("/repo", "standup/2024-01-31")
+	if err != nil {
+		t.Fatalf("PullRequestState() error = %v", err)
+	}
+	want := []Check{{"lint", CheckPass}, {"test", CheckPending}, {"ci/deploy", CheckFail}}
+	if state.Number != "12" || state.State != StateOpen || state.Review != ReviewRequired || fmt.Sprint(state.Checks) != fmt.Sprint(want) {
+		t.Errorf("GitHub PullRequestState() = %+v", state)
+	}
+	if failing := state.FailingChecks(); fmt.Sprint(failing) != "[ci/deploy]" {
+		t.Errorf("FailingChecks() = %q", failing)
+	}
+
+	gitlab := NewGitLab("org/standups", &fakeRunner{outputs: map[string]string{
+		"glab mr list": `[{"iid": 42, "state": "merged", "head_pipeline": {"status": "success"}}]`,
+	}})
+	state, err = gitlab.PullRequestState("/repo", "standup/2024-01-31")
+	if err != nil {
+		t.Fatalf("PullRequestState() error = %v", err)
+	}
+	if state.Number != "42" || state.State != StateMerged || fmt.Sprint(state.Checks) != fmt.Sprint([]Check{{"pipeline", CheckPass}}) {
+		t.Errorf("GitLab PullRequestState() = %+v", state)
+	}
+
+	none := NewGitHub("org/standups", &fakeRunner{outputs: map[string]string{"gh pr list": "[]"}})
+	if state, err := none.PullRequestState("/repo", "standup/2024-01-31"); state != nil || err != nil {
+		t.Errorf("PullRequestState() = %+v, %v, want nil for a branch without pull requests", state, err)
+	}
+}
+
+func TestRequestReview(t *testing.T) {
+	tests := []struct {
+		kind types.ForgeKind
+		want string
+	}{
+		{types.ForgeGitHub, "gh pr edit 12 --add-reviewer alice,bob"},
+		{types.ForgeGitLab, "glab mr update 12 --reviewer +alice,+bob"},
+	}
+	for _, tt := range tests {
+		runner := &fakeRunner{}
+		provider, err := New(tt.kind, "org/standups", runner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := provider.RequestReview("/repo", "12", []string{"alice", "bob"}); err != nil {
+			t.Fatalf("%s RequestReview() error = %v", tt.kind, err)
+		}
+		if len(runner.commands) != 1 || runner.commands[0] != tt.want {
+			t.Errorf("%s RequestReview() ran %q, want %q", tt.kind, runner.commands, tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// GitHub manages pull requests with the GitHub CLI
+type GitHub struct {
+	repository string
+	runner     git.CommandRunner
+	client     *git.Client
+}
+
+// NewGitHub creates a GitHub provider
+func NewGitHub(repository string, runner git.CommandRunner) *GitHub {
+	return &GitHub{
+		repository: repository,
+		runner:     runner,
+		client:     git.NewClientWithRunner(runner),
+	}
+}
+
+// Name returns the display name of the forge
+func (g *GitHub) Name() string {
+	return "GitHub"
+}
+
+// CheckAvailable checks that gh is installed and authenticated
+func (g *GitHub) CheckAvailable() error {
+	if err := g.client.CheckGHInstalled(); err != nil {
+		return err
+	}
+	return g.client.CheckAuthenticated()
+}
+
+// Clone clones the repository with gh
+func (g *GitHub) Clone(targetPath string, opts git.CloneOptions) error {
+	return g.client.CloneRepository(g.repository, targetPath, opts)
+}
+
+// FindPullRequest returns the open pull request for a branch
+func (g *GitHub) FindPullRequest(repoPath, branch string) (bool, string) {
+	return g.client.PRExistsForBranch(repoPath, branch)
+}
+
+// CreatePullRequest opens a pull request
+func (g *GitHub) CreatePullRequest(repoPath string, opts PullRequestOptions) error {
+	return g.client.CreatePullRequestWithOptions(repoPath, git.PullRequestOptions{
+		Title: opts.Title,
+		Body:  opts.Body,
+		Base:  opts.Base,
+		Head:  opts.Head,
+		Draft: opts.Draft,
+
+		Labels:    opts.Labels,
+		Assignees: opts.Assignees,
+		Reviewers: opts.Reviewers,
+	})
+}
+
+// UpdatePullRequest replaces the body of a pull request
+func (g *GitHub) UpdatePullRequest(repoPath, number, body string) error {
+	return g.client.UpdatePullRequest(repoPath, number, body)
+}
+
+// MarkReady marks a draft pull request as ready for review
+func (g *GitHub) MarkReady(repoPath, number string) error {
+	return g.client.MarkPullRequestReady(repoPath, number)
+}
+
+// MergePullRequest merges a pull request with the given strategy
+func (g *GitHub) MergePullRequest(repoPath, number string, opts MergeOptions) error {
+	return g.client.MergePullRequestByNumber(repoPath, number, git.MergeOptions{
+		Auto:         opts.Auto,
+		Strategy:     opts.Strategy,
+		DeleteBranch: opts.DeleteBranch,
+	})
+}
+
+// PullRequestURL returns the web URL of a pull request
+func (g *GitHub) PullRequestURL(number string) string {
+	return fmt.Sprintf("https://github.com/%s/pull/%s", g.repository, number)
+}
+
+// RequestReview adds reviewers to a pull request
+func (g *GitHub) RequestReview(repoPath, number string, reviewers []string) error {
+	output, err := g.runner.RunInDir(repoPath, "gh", "pr", "edit", number, "--add-reviewer", strings.Join(reviewers, ","))
+	if err != nil {
+		return fmt.Errorf("failed to request review: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// githubCheck is an entry of a pull request's statusCheckRollup: a check
+// run (name, status, conclusion) or a commit status (context, state)
+type githubCheck struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	Context    string `json:"context"`
+	State      string `json:"state"`
+}
+
+// PullRequestState returns the latest pull request for a branch and its checks
+func (g *GitHub) PullRequestState(repoPath, branch string) (*PullRequestState, error) {
+	output, err := g.runner.RunInDir(repoPath, "gh", "pr", "list",
+		"--head", branch,
+		"--state", "all",
+		"--limit", "1",
+		"--json", "number,state,statusCheckRollup,reviewDecision")
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up pull request: %w\nOutput: %s%s", err, string(output), git.GHUpgradeHint(output))
+	}
+
+	var prs []struct {
+		Number int           `json:"number"`
+		State  string        `json:"state"`
+		Checks []githubCheck `json:"statusCheckRollup"`
+		Review string        `json:"reviewDecision"`
+	}
+	if err := json.Unmarshal(output, &prs); err != nil {
+		return nil, fmt.Errorf("failed to parse pull request: %w", err)
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+
+	pr := prs[0]
+	state := &PullRequestState{
+		Number: fmt.Sprint(pr.Number),
+		State:  strings.ToLower(pr.State),
+		Review: strings.ToLower(pr.Review),
+	}
+	for _, check := range pr.Checks {
+		if check.Context != "" {
+			state.Checks = append(state.Checks, Check{Name: check.Context, Status: githubStatus(check.State)})
+			continue
+		}
+		status := CheckPending
+		if check.Status == "COMPLETED" {
+			status = githubStatus(check.Conclusion)
+		}
+		state.Checks = append(state.Checks, Check{Name: check.Name, Status: status})
+	}
+	return state, nil
+}
+
+// githubStatus maps a check conclusion or commit status state to a Check status
+func githubStatus(state string) string {
+	switch state {
+	case "SUCCESS", "NEUTRAL", "SKIPPED":
+		return CheckPass
+	case "PENDING", "EXPECTED", "":
+		return CheckPending
+	}
+	return CheckFail
+}
@@ -0,0 +1,220 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// GitLab manages merge requests with the GitLab CLI (glab)
+type GitLab struct {
+	repository string
+	runner     git.CommandRunner
+}
+
+// NewGitLab creates a GitLab provider. Self-hosted instances are selected
+// with the GITLAB_HOST environment variable, as for glab itself.
+func NewGitLab(repository string, runner git.CommandRunner) *GitLab {
+	return &GitLab{
+		repository: repository,
+		runner:     runner,
+	}
+}
+
+// Name returns the display name of the forge
+func (g *GitLab) Name() string {
+	return "GitLab"
+}
+
+// CheckAvailable checks that glab is installed and authenticated
+func (g *GitLab) CheckAvailable() error {
+	if _, err := g.runner.Run("glab", "--version"); err != nil {
+		return fmt.Errorf("GitLab CLI not found: %w. Please install it from https://gitlab.com/gitlab-org/cli", err)
+	}
+	if _, err := g.runner.Run("glab", "auth", "status"); err != nil {
+		return fmt.Errorf("not authenticated with GitLab: %w. Please run 'glab auth login'", err)
+	}
+	return nil
+}
+
+// Clone clones the repository with glab
+func (g *GitLab) Clone(targetPath string, opts git.CloneOptions) error {
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	args := []string{"repo", "clone", g.repository, targetPath}
+	if flags := opts.Flags(); len(flags) > 0 {
+		args = append(append(args, "--"), flags...)
+	}
+	output, err := g.runner.Run("glab", args...)
+	if err != nil {
+		return fmt.Errorf("failed to clone repository: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// FindPullRequest returns the open merge request for a branch
+func (g *GitLab) FindPullRequest(repoPath, branch string) (bool, string) {
+	output, err := g.runner.RunInDir(repoPath, "glab", "mr", "list", "--source-branch", branch, "--output", "json")
+	if err != nil {
+		return false, ""
+	}
+
+	var mrs []struct {
+		IID int `json:"iid"`
+	}
+	if err := json.Unmarshal(output, &mrs); err != nil || len(mrs) == 0 {
+		return false, ""
+	}
+	return true, strconv.Itoa(mrs[0].IID)
+}
+
+// CreatePullRequest opens a merge request
+func (g *GitLab) CreatePullRequest(repoPath string, opts PullRequestOptions) error {
+	args := []string{"mr", "create", "--title", opts.Title, "--description", opts.Body, "--yes"}
+	if opts.Base != "" {
+		args = append(args, "--target-branch", opts.Base)
+	}
+	if opts.Head != "" {
+		args = append(args, "--source-branch", opts.Head)
+	}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	for _, label := range opts.Labels {
+		args = append(args, "--label", label)
+	}
+	for _, assignee := range opts.Assignees {
+		args = append(args, "--assignee", assignee)
+	}
+	for _, reviewer := range opts.Reviewers {
+		args = append(args, "--reviewer", reviewer)
+	}
+
+	output, err := g.runner.RunInDir(repoPath, "glab", args...)
+	if err != nil {
+		return fmt.Errorf("failed to create merge request: %w (output: %s)", err, string(output))
+	}
+	return nil
+}
+
+// UpdatePullRequest replaces the description of a merge request
+func (g *GitLab) UpdatePullRequest(repoPath, number, body string) error {
+	output, err := g.runner.RunInDir(repoPath, "glab", "mr", "update", number, "--description", body)
+	if err != nil {
+		return fmt.Errorf("failed to update merge request: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// MarkReady takes a merge request out of draft
+func (g *GitLab) MarkReady(repoPath, number string) error {
+	output, err := g.runner.RunInDir(repoPath, "glab", "mr", "update", number, "--ready")
+	if err != nil {
+		return fmt.Errorf("failed to mark merge request ready: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// MergePullRequest merges a merge request with the given strategy
+func (g *GitLab) MergePullRequest(repoPath, number string, opts MergeOptions) error {
+	args := []string{"mr", "merge", number}
+	switch opts.Strategy {
+	case MergeSquash, "":
+		args = append(args, "--squash")
+	case MergeRebase:
+		args = append(args, "--rebase")
+	}
+	if opts.DeleteBranch {
+		args = append(args, "--remove-source-branch")
+	}
+	if opts.Auto {
+		args = append(args, "--auto-merge")
+	}
+	args = append(args, "--yes")
+
+	output, err := g.runner.RunInDir(repoPath, "glab", args...)
+	if err != nil {
+		return fmt.Errorf("failed to merge merge request: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// PullRequestURL returns the web URL of a merge request
+func (g *GitLab) PullRequestURL(number string) string {
+	return fmt.Sprintf("https://%s/%s/-/merge_requests/%s", gitlabHost(), g.repository, number)
+}
+
+// RequestReview adds reviewers to a merge request; glab adds rather than
+// replaces usernames prefixed with "+"
+func (g *GitLab) RequestReview(repoPath, number string, reviewers []string) error {
+	added := make([]string, len(reviewers))
+	for i, reviewer := range reviewers {
+		added[i] = "+" + reviewer
+	}
+	output, err := g.runner.RunInDir(repoPath, "glab", "mr", "update", number, "--reviewer", strings.Join(added, ","))
+	if err != nil {
+		return fmt.Errorf("failed to request review: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// PullRequestState returns the latest merge request for a branch, with its
+// head pipeline as the only check
+func (g *GitLab) PullRequestState(repoPath, branch string) (*PullRequestState, error) {
+	output, err := g.runner.RunInDir(repoPath, "glab", "mr", "list", "--source-branch", branch, "--all", "--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up merge request: %w\nOutput: %s", err, string(output))
+	}
+
+	var mrs []struct {
+		IID      int    `json:"iid"`
+		State    string `json:"state"`
+		Pipeline *struct {
+			Status string `json:"status"`
+		} `json:"head_pipeline"`
+	}
+	if err := json.Unmarshal(output, &mrs); err != nil {
+		return nil, fmt.Errorf("failed to parse merge request: %w", err)
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+
+	mr := mrs[0]
+	state := &PullRequestState{Number: strconv.Itoa(mr.IID), State: StateClosed}
+	switch mr.State {
+	case "opened":
+		state.State = StateOpen
+	case "merged":
+		state.State = StateMerged
+	}
+	if mr.Pipeline != nil {
+		status := CheckPending
+		switch mr.Pipeline.Status {
+		case "success", "skipped", "manual":
+			status = CheckPass
+		case "failed", "canceled":
+			status = CheckFail
+		}
+		state.Checks = []Check{{Name: "pipeline", Status: status}}
+	}
+	return state, nil
+}
+
+// gitlabHost returns the GitLab host, honouring GITLAB_HOST
+func gitlabHost() string {
+	host := strings.TrimSpace(os.Getenv("GITLAB_HOST"))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	if host == "" {
+		return "gitlab.com"
+	}
+	return host
+}
@@ -0,0 +1,208 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheTTL is how long a cached lookup is reused. It's short: the cache is
+// there so one run doesn't ask the same thing twice, not to work offline.
+const CacheTTL = 2 * time.Minute
+
+// cacheEntry is a command's cached output
+type cacheEntry struct {
+	Output   string    `json:"output"`
+	StoredAt time.Time `json:"storedAt"`
+}
+
+// ResponseCache keeps the output of slow, read-only gh and git lookups in a
+// file, so that the commands of one run (and of runs in quick succession)
+// share them
+type ResponseCache struct {
+	mu   sync.Mutex
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewResponseCache creates a cache stored at path whose entries expire after ttl
+func NewResponseCache(path string, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		path: path,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// get returns a fresh cached output for key
+func (c *ResponseCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.load()[key]
+	if !ok || c.now().Sub(entry.StoredAt) >= c.ttl {
+		return nil, false
+	}
+	return []byte(entry.Output), true
+}
+
+// set stores the output for key, dropping expired entries
+func (c *ResponseCache) set(key string, output []byte) {
+	c.update(func(entries map[string]cacheEntry) {
+		entries[key] = cacheEntry{Output: string(output), StoredAt: c.now()}
+	})
+}
+
+// forget drops the entries of commands starting with group, e.g. "gh pr"
+func (c *ResponseCache) forget(group string) {
+	c.update(func(entries map[string]cacheEntry) {
+		for key := range entries {
+			if _, line, _ := strings.Cut(key, "\x00"); strings.HasPrefix(line, group+" ") {
+				delete(entries, key)
+			}
+		}
+	})
+}
+
+// update changes the entries and saves them. Caching is best effort; a
+// failed write only means asking again next time.
+func (c *ResponseCache) update(change func(map[string]cacheEntry)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := c.load()
+	for key, entry := range entries {
+		if c.now().Sub(entry.StoredAt) >= c.ttl {
+			delete(entries, key)
+		}
+	}
+	change(entries)
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(c.path), 0755) == nil {
+		os.WriteFile(c.path, data, 0600)
+	}
+}
+
+// load reads the entries; a missing or corrupt file is an empty cache
+func (c *ResponseCache) load() map[string]cacheEntry {
+	entries := map[string]cacheEntry{}
+	if data, err := os.ReadFile(c.path); err == nil {
+		json.Unmarshal(data, &entries)
+	}
+	return entries
+}
+
+// cacheable reports whether a command is a read-only lookup worth caching:
+// gh's auth status, the open pull request of a branch and the remote's
+// default branch. Everything else, such as a pull request's checks, always
+// runs.
+func cacheable(line string) bool {
+	switch {
+	case line == "gh auth status",
+		line == "git ls-remote --symref origin HEAD":
+		return true
+	case strings.HasPrefix(line, "gh pr list --head ") && strings.HasSuffix(line, " --json number"):
+		return true
+	}
+	return false
+}
+
+// invalidatedGroup returns the group of cached lookups a command may make
+// stale, e.g. "gh pr" for "gh pr create", or "" for reads
+func invalidatedGroup(name string, args []string) string {
+	if name != "gh" || len(args) < 2 || (args[0] != "pr" && args[0] != "auth") {
+		return ""
+	}
+	switch args[1] {
+	case "list", "view", "status", "checks", "diff":
+		return ""
+	}
+	return name + " " + args[0]
+}
+
+// CachingRunner is a CommandRunner that answers repeated lookups from a
+// ResponseCache. Commands that change pull requests or authentication, e.g.
+// "gh pr create", forget the cached lookups of their group, so a pull
+// request that was just opened or merged is seen.
+type CachingRunner struct {
+	runner CommandRunner
+	cache  *ResponseCache
+}
+
+// NewCachingRunner wraps runner so its lookups are cached in cache
+func NewCachingRunner(runner CommandRunner, cache *ResponseCache) *CachingRunner {
+	return &CachingRunner{
+		runner: runner,
+		cache:  cache,
+	}
+}
+
+// Run executes a command, or returns its cached output
+func (r *CachingRunner) Run(name string, args ...string) ([]byte, error) {
+	return r.run("", name, args, func() ([]byte, error) { return r.runner.Run(name, args...) })
+}
+
+// RunInDir executes a command in a specific directory, or returns its cached output
+func (r *CachingRunner) RunInDir(dir, name string, args ...string) ([]byte, error) {
+	return r.run(dir, name, args, func() ([]byte, error) { return r.runner.RunInDir(dir, name, args...) })
+}
+
+// run answers a cacheable command from the cache, or runs it. Only
+// successful lookups are cached.
+func (r *CachingRunner) run(dir, name string, args []string, run func() ([]byte, error)) ([]byte, error) {
+	line := strings.Join(append([]string{name}, args...), " ")
+	if !cacheable(line) {
+		if group := invalidatedGroup(name, args); group != "" {
+			r.cache.forget(group)
+		}
+		return run()
+	}
+
+	// Lookups such as the open pull request depend on the repository
+	key := dir + "\x00" + line
+	if output, ok := r.cache.get(key); ok {
+		return output, nil
+	}
+	output, err := run()
+	if err == nil {
+		r.cache.set(key, output)
+	}
+	return output, err
+}
+
+// withContext reports the commands of the runner it wraps with ctx
+func (r *CachingRunner) withContext(ctx context.Context) CommandRunner {
+	inner, ok := r.runner.(contextRunner)
+	if !ok {
+		return r
+	}
+	return NewCachingRunner(inner.withContext(ctx), r.cache)
+}
+
+// activeCache answers the lookups of clients created by NewClient
+var activeCache *ResponseCache
+
+// SetCache makes clients created by NewClient cache their lookups in c.
+// Passing nil turns caching off.
+func SetCache(c *ResponseCache) {
+	activeCache = c
+}
+
+// InvalidateCache drops every cached lookup, e.g. when a webhook reports
+// that a pull request changed elsewhere
+func InvalidateCache() {
+	if activeCache != nil {
+		activeCache.update(func(entries map[string]cacheEntry) {
+			clear(entries)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCachingRunner(t *testing.T) {
+	lookup := []string{"pr", "list", "--head", "standup/2024-01-15", "--json", "number"}
+	mock := &MockCommandRunner{
+		Commands: []MockCommand{
+			{Name: "gh", Args: []string{"auth", "status"}},
+			{Name: "gh", Args: lookup, Dir: "/repo"},
+			{Name: "gh", Args: []string{"pr", "create"}, Dir: "/repo"},
+			{Name: "gh", Args: lookup, Dir: "/repo", Output: []byte(`[{"number":12}]`)},
+			{Name: "gh", Args: []string{"auth", "status"}},
+		},
+	}
+	cache := NewResponseCache(filepath.Join(t.TempDir(), "cache.json"), time.Minute)
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	client := NewClientWithRunner(NewCachingRunner(mock, cache))
+
+	for i := 0; i < 2; i++ {
+		if err := client.CheckAuthenticated(); err != nil {
+			t.Fatalf("CheckAuthenticated() error = %v", err)
+		}
+		if exists, _ := client.PRExistsForBranch("/repo", "standup/2024-01-15"); exists {
+			t.Fatal("PRExistsForBranch() = true before the PR was created")
+		}
+	}
+
+	// Creating the PR forgets the cached lookup
+	if _, err := client.runner.RunInDir("/repo", "gh", "pr", "create"); err != nil {
+		t.Fatal(err)
+	}
+	if exists, number := client.PRExistsForBranch("/repo", "standup/2024-01-15"); !exists || number != "12" {
+		t.Errorf("PRExistsForBranch() = %v, %q, want the new PR", exists, number)
+	}
+
+	// Expired lookups run again
+	now = now.Add(time.Minute)
+	if err := client.CheckAuthenticated(); err != nil {
+		t.Fatalf("CheckAuthenticated() error = %v", err)
+	}
+	if mock.Index != len(mock.Commands) {
+		t.Errorf("ran %d commands, want %d", mock.Index, len(mock.Commands))
+	}
+}
+
+func TestInvalidateCache(t *testing.T) {
+	mock := &MockCommandRunner{
+		Commands: []MockCommand{
+			{Name: "gh", Args: []string{"auth", "status"}},
+			{Name: "gh", Args: []string{"auth", "status"}},
+		},
+	}
+	SetCache(NewResponseCache(filepath.Join(t.TempDir(), "cache.json"), time.Minute))
+	defer SetCache(nil)
+	client := NewClientWithRunner(NewCachingRunner(mock, activeCache))
+
+	for i := 0; i < 2; i++ {
+		if err := client.CheckAuthenticated(); err != nil {
+			t.Fatalf("CheckAuthenticated() error = %v", err)
+		}
+		InvalidateCache()
+	}
+	if mock.Index != len(mock.Commands) {
+		t.Errorf("ran %d commands, want %d", mock.Index, len(mock.Commands))
+	}
+}
@@ -0,0 +1,50 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// MinGHVersion is the oldest supported gh release. Older releases lack some
+// of the --json fields standup-bot reads.
+var MinGHVersion = [2]int{2, 0}
+
+// ghLabelCreateVersion is the first gh release with 'gh label create'
+var ghLabelCreateVersion = [2]int{2, 14}
+
+// ghVersion is the gh release found by CheckGHInstalled, shared by every
+// client. Until it's known, gh is assumed to be recent.
+var ghVersion atomic.Pointer[[2]int]
+
+// ghSupports reports whether the installed gh is at least version
+func ghSupports(version [2]int) bool {
+	installed := ghVersion.Load()
+	return installed == nil || !versionBefore(*installed, version)
+}
+
+// versionBefore reports whether major.minor version a is older than b
+func versionBefore(a, b [2]int) bool {
+	return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+}
+
+// ghTooOld explains that the installed gh can't do something, and how to fix it
+func ghTooOld(what string, needs [2]int) error {
+	installed := ghVersion.Load()
+	return fmt.Errorf("gh %d.%d can't %s: that needs gh %d.%d or newer. Please upgrade from https://cli.github.com/",
+		installed[0], installed[1], what, needs[0], needs[1])
+}
+
+// GHUpgradeHint returns a hint to upgrade gh when its output shows it
+// rejected a command, flag or JSON field, which usually means it's older
+// than standup-bot expects; otherwise it returns ""
+func GHUpgradeHint(output []byte) string {
+	text := string(output)
+	for _, rejected := range []string{"unknown command", "unknown flag", "unknown shorthand flag", "Unknown JSON field"} {
+		if strings.Contains(text, rejected) {
+			return fmt.Sprintf("\nYour gh may be too old: check 'gh --version' (standup-bot needs %d.%d or newer) and upgrade from https://cli.github.com/",
+				MinGHVersion[0], MinGHVersion[1])
+		}
+	}
+	return ""
+}
@@ -2,11 +2,16 @@ package git
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // CommandRunner interface for executing commands (allows mocking in tests)
@@ -34,12 +39,54 @@ func (r *RealCommandRunner) RunInDir(dir, name string, args ...string) ([]byte,
 // Client handles Git operations via GitHub CLI
 type Client struct {
 	runner CommandRunner
+	commit CommitOptions
+}
+
+// CommitOptions sets the author identity and signing of commits. Empty
+// fields leave the user's git config in charge.
+type CommitOptions struct {
+	AuthorName  string
+	AuthorEmail string
+	// Sign signs commits, as commit.gpgsign does
+	Sign bool
+	// SigningKey is the GPG key ID or SSH key path (user.signingkey)
+	SigningKey string
+	// SigningFormat is openpgp, ssh or x509 (gpg.format)
+	SigningFormat string
+}
+
+// configFlags returns the options as "-c key=value" flags for git
+func (o CommitOptions) configFlags() []string {
+	var flags []string
+	set := func(key, value string) {
+		if value != "" {
+			flags = append(flags, "-c", key+"="+value)
+		}
+	}
+	set("user.name", o.AuthorName)
+	set("user.email", o.AuthorEmail)
+	if o.Sign {
+		set("commit.gpgsign", "true")
+	}
+	set("user.signingkey", o.SigningKey)
+	set("gpg.format", o.SigningFormat)
+	return flags
+}
+
+// SetCommitOptions sets the identity and signing of the client's commits
+func (c *Client) SetCommitOptions(opts CommitOptions) {
+	c.commit = opts
+}
+
+// commitArgs returns the arguments to git for a commit with the message
+func (c *Client) commitArgs(message string) []string {
+	return append(c.commit.configFlags(), "commit", "-m", message)
 }
 
 // NewClient creates a new Git client
 func NewClient() *Client {
 	return &Client{
-		runner: &RealCommandRunner{},
+		runner: NewRunner(),
 	}
 }
 
@@ -50,7 +97,9 @@ func NewClientWithRunner(runner CommandRunner) *Client {
 	}
 }
 
-// CheckGHInstalled checks if GitHub CLI is installed
+// CheckGHInstalled checks that the GitHub CLI is installed and recent
+// enough, and remembers its version so commands can adapt to it. A version
+// that can't be parsed is accepted.
 func (c *Client) CheckGHInstalled() error {
 	output, err := c.runner.Run("gh", "--version")
 	if err != nil {
@@ -62,9 +111,28 @@ func (c *Client) CheckGHInstalled() error {
 		return fmt.Errorf("gh command found but appears to be incorrect: output=%s", string(output))
 	}
 
+	major, minor, ok := parseToolVersion("gh", string(output))
+	if !ok {
+		return nil
+	}
+	if versionBefore([2]int{major, minor}, MinGHVersion) {
+		return fmt.Errorf("gh %d.%d is too old: standup-bot needs gh %d.%d or newer. Please upgrade from https://cli.github.com/",
+			major, minor, MinGHVersion[0], MinGHVersion[1])
+	}
+	ghVersion.Store(&[2]int{major, minor})
 	return nil
 }
 
+// ToolVersion returns the first line of "<tool> --version", e.g. "git version 2.43.0"
+func (c *Client) ToolVersion(tool string) (string, error) {
+	output, err := c.runner.Run(tool, "--version")
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s version: %w", tool, err)
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return line, nil
+}
+
 // CheckAuthenticated checks if user is authenticated with GitHub
 func (c *Client) CheckAuthenticated() error {
 	_, err := c.runner.Run("gh", "auth", "status")
@@ -74,8 +142,41 @@ func (c *Client) CheckAuthenticated() error {
 	return nil
 }
 
+// SetupGitCredentials makes git use gh's credentials for HTTPS, so a token
+// in GH_TOKEN serves pushes as well as gh's own API calls
+func (c *Client) SetupGitCredentials() error {
+	output, err := c.runner.Run("gh", "auth", "setup-git")
+	if err != nil {
+		return fmt.Errorf("failed to set up git credentials: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// CloneOptions trim what a clone downloads, for standup repositories with
+// years of history. The zero value clones everything.
+type CloneOptions struct {
+	// Depth keeps only the latest commits of the default branch; other
+	// branches are fetched when they're needed
+	Depth int
+	// Blobless downloads file contents only when they're checked out
+	// (--filter=blob:none)
+	Blobless bool
+}
+
+// Flags returns the options as flags for git clone
+func (o CloneOptions) Flags() []string {
+	var flags []string
+	if o.Depth > 0 {
+		flags = append(flags, "--depth", strconv.Itoa(o.Depth))
+	}
+	if o.Blobless {
+		flags = append(flags, "--filter=blob:none")
+	}
+	return flags
+}
+
 // CloneRepository clones a repository to the specified path
-func (c *Client) CloneRepository(repo, targetPath string) error {
+func (c *Client) CloneRepository(repo, targetPath string, opts CloneOptions) error {
 	// Ensure parent directory exists
 	parentDir := filepath.Dir(targetPath)
 	if err := os.MkdirAll(parentDir, 0755); err != nil {
@@ -83,7 +184,27 @@ func (c *Client) CloneRepository(repo, targetPath string) error {
 	}
 
 	// Clone the repository
-	output, err := c.runner.Run("gh", "repo", "clone", repo, targetPath)
+	args := []string{"repo", "clone", repo, targetPath}
+	if flags := opts.Flags(); len(flags) > 0 {
+		args = append(append(args, "--"), flags...)
+	}
+	output, err := c.runner.Run("gh", args...)
+	if err != nil {
+		return fmt.Errorf("failed to clone repository: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// CloneURL clones a repository from a raw git URL over SSH or HTTPS, using
+// git's own credentials instead of the GitHub CLI
+func (c *Client) CloneURL(url, targetPath string, opts CloneOptions) error {
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	args := append(append([]string{"clone"}, opts.Flags()...), url, targetPath)
+	output, err := c.runner.Run("git", args...)
 	if err != nil {
 		return fmt.Errorf("failed to clone repository: %w\nOutput: %s", err, string(output))
 	}
@@ -91,8 +212,80 @@ func (c *Client) CloneRepository(repo, targetPath string) error {
 	return nil
 }
 
-// SyncRepository syncs the repository with the remote
-func (c *Client) SyncRepository(repoPath string) error {
+// InitBareRepository creates an empty bare repository at path
+func (c *Client) InitBareRepository(path string) error {
+	output, err := c.runner.Run("git", "init", "--bare", path)
+	if err != nil {
+		return fmt.Errorf("failed to create repository: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// SetLocalConfig sets a git config value for a single repository
+func (c *Client) SetLocalConfig(repoPath, key, value string) error {
+	output, err := c.runner.RunInDir(repoPath, "git", "config", "--local", key, value)
+	if err != nil {
+		return fmt.Errorf("failed to set %s: %w\nOutput: %s", key, err, string(output))
+	}
+	return nil
+}
+
+// MinGitVersion is the oldest supported git release (the first with
+// 'git switch' and 'git restore')
+var MinGitVersion = [2]int{2, 23}
+
+// CheckGitInstalled checks that git is on the PATH and recent enough. A
+// version that can't be parsed is accepted.
+func (c *Client) CheckGitInstalled() error {
+	output, err := c.runner.Run("git", "--version")
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("git not found on PATH. Please install it from https://git-scm.com/")
+		}
+		return fmt.Errorf("git not found: %w. Please install it from https://git-scm.com/", err)
+	}
+
+	major, minor, ok := parseGitVersion(string(output))
+	if ok && (major < MinGitVersion[0] || (major == MinGitVersion[0] && minor < MinGitVersion[1])) {
+		return fmt.Errorf("git %d.%d is too old: standup-bot needs git %d.%d or newer. Please upgrade from https://git-scm.com/",
+			major, minor, MinGitVersion[0], MinGitVersion[1])
+	}
+	return nil
+}
+
+// parseGitVersion extracts the major and minor version from "git --version"
+// output such as "git version 2.39.3 (Apple Git-146)"
+func parseGitVersion(output string) (int, int, bool) {
+	return parseToolVersion("git", output)
+}
+
+// parseToolVersion extracts the major and minor version from a tool's
+// "<tool> version X.Y.Z ..." output, e.g. "gh version 2.40.0 (2024-01-10)"
+func parseToolVersion(tool, output string) (int, int, bool) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != tool || fields[1] != "version" {
+		return 0, 0, false
+	}
+
+	parts := strings.SplitN(fields[2], ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// SyncRepository syncs the current branch with the remote. Only the base
+// branch and the current branch are fetched, rather than every daily branch
+// the repository has accumulated.
+func (c *Client) SyncRepository(repoPath, baseBranch string) error {
 	// Check if this is an empty repository
 	if isEmpty, err := c.isEmptyRepository(repoPath); err != nil {
 		return fmt.Errorf("failed to check repository state: %w", err)
@@ -100,17 +293,22 @@ func (c *Client) SyncRepository(repoPath string) error {
 		return nil // Nothing to sync in empty repo
 	}
 
-	// Fetch all changes
-	if err := c.fetchAll(repoPath); err != nil {
-		return fmt.Errorf("failed to fetch remote changes: %w", err)
-	}
-
 	// Get current branch
 	branch, err := c.getCurrentBranch(repoPath)
 	if err != nil {
 		return fmt.Errorf("failed to determine current branch: %w", err)
 	}
 
+	branches := []string{baseBranch}
+	if branch != "" && branch != baseBranch {
+		branches = append(branches, branch)
+	}
+	for _, name := range branches {
+		if err := c.fetchBranch(repoPath, name); err != nil {
+			return fmt.Errorf("failed to fetch remote changes: %w", err)
+		}
+	}
+
 	if branch == "" {
 		return nil // Detached HEAD state, nothing to sync
 	}
@@ -122,6 +320,19 @@ func (c *Client) SyncRepository(repoPath string) error {
 		return nil // Remote branch doesn't exist yet
 	}
 
+	// Keep commits that haven't been pushed yet, e.g. made offline, on top
+	// of the remote instead of resetting them away
+	ahead, err := c.commitsAhead(repoPath, branch)
+	if err != nil {
+		return fmt.Errorf("failed to check for unpushed commits: %w", err)
+	}
+	if ahead > 0 {
+		if err := c.rebaseOntoRemote(repoPath, branch); err != nil {
+			return fmt.Errorf("failed to sync %d unpushed commit(s) with remote: %w", ahead, err)
+		}
+		return nil
+	}
+
 	// Reset to remote branch
 	if err := c.resetToRemote(repoPath, branch); err != nil {
 		return fmt.Errorf("failed to sync with remote: %w", err)
@@ -130,6 +341,40 @@ func (c *Client) SyncRepository(repoPath string) error {
 	return nil
 }
 
+// UnpushedCommits returns how many commits on the current branch haven't
+// been pushed to origin
+func (c *Client) UnpushedCommits(repoPath string) (int, error) {
+	branch, err := c.getCurrentBranch(repoPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to determine current branch: %w", err)
+	}
+	return c.commitsAhead(repoPath, branch)
+}
+
+// commitsAhead counts the commits on HEAD that origin's copy of branch lacks
+func (c *Client) commitsAhead(repoPath, branch string) (int, error) {
+	output, err := c.runner.RunInDir(repoPath, "git", "rev-list", "--count", fmt.Sprintf("origin/%s..HEAD", branch))
+	if err != nil {
+		return 0, fmt.Errorf("%w (output: %s)", err, string(output))
+	}
+	var count int
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(output)), "%d", &count); err != nil {
+		return 0, fmt.Errorf("unexpected rev-list output %q: %w", string(output), err)
+	}
+	return count, nil
+}
+
+// rebaseOntoRemote replays local commits on top of origin's branch, leaving
+// the branch as it was if they conflict
+func (c *Client) rebaseOntoRemote(repoPath, branch string) error {
+	output, err := c.runner.RunInDir(repoPath, "git", "rebase", fmt.Sprintf("origin/%s", branch))
+	if err != nil {
+		c.runner.RunInDir(repoPath, "git", "rebase", "--abort")
+		return fmt.Errorf("%w (output: %s)", err, string(output))
+	}
+	return nil
+}
+
 // isEmptyRepository checks if the repository has any branches
 func (c *Client) isEmptyRepository(repoPath string) (bool, error) {
 	output, err := c.runner.RunInDir(repoPath, "git", "branch", "-a")
@@ -139,15 +384,27 @@ func (c *Client) isEmptyRepository(repoPath string) (bool, error) {
 	return strings.TrimSpace(string(output)) == "", nil
 }
 
-// fetchAll fetches all remote changes
-func (c *Client) fetchAll(repoPath string) error {
-	output, err := c.runner.RunInDir(repoPath, "git", "fetch", "--all")
+// fetchBranch updates origin/<branch> from the remote. The refspec is
+// explicit so that it works in single-branch (shallow) clones too. A branch
+// that isn't on the remote yet is not an error.
+func (c *Client) fetchBranch(repoPath, branch string) error {
+	refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", branch, branch)
+	output, err := c.runner.RunInDir(repoPath, "git", "fetch", "origin", refspec)
 	if err != nil {
+		if strings.Contains(string(output), "couldn't find remote ref") {
+			return nil
+		}
 		return fmt.Errorf("%w (output: %s)", err, string(output))
 	}
 	return nil
 }
 
+// CurrentBranch returns the branch checked out in the repository, or "" on
+// a detached HEAD
+func (c *Client) CurrentBranch(repoPath string) (string, error) {
+	return c.getCurrentBranch(repoPath)
+}
+
 // getCurrentBranch returns the current branch name
 func (c *Client) getCurrentBranch(repoPath string) (string, error) {
 	output, err := c.runner.RunInDir(repoPath, "git", "branch", "--show-current")
@@ -172,25 +429,35 @@ func (c *Client) resetToRemote(repoPath, branch string) error {
 	return nil
 }
 
-// AddAll adds all changes to staging
-func (c *Client) AddAll(repoPath string) ([]byte, error) {
-	return c.runner.RunInDir(repoPath, "git", "add", ".")
+// Add stages changes to the given paths, including deletions
+func (c *Client) Add(repoPath string, paths ...string) ([]byte, error) {
+	return c.runner.RunInDir(repoPath, "git", append([]string{"add", "--"}, paths...)...)
 }
 
 // Commit creates a commit with the given message
 func (c *Client) Commit(repoPath, message string) ([]byte, error) {
-	return c.runner.RunInDir(repoPath, "git", "commit", "-m", message)
+	return c.runner.RunInDir(repoPath, "git", c.commitArgs(message)...)
 }
 
-// CommitAndPush commits changes and pushes to remote
-func (c *Client) CommitAndPush(repoPath, message string) error {
-	// Stage all changes
-	if err := c.stageAllChanges(repoPath); err != nil {
+// CommitAndPush commits changes to the given paths and pushes to remote.
+// Other changes in the working tree are left alone.
+func (c *Client) CommitAndPush(repoPath, message string, paths ...string) error {
+	if err := c.CommitPaths(repoPath, message, paths...); err != nil {
+		return err
+	}
+	return c.Push(repoPath)
+}
+
+// CommitPaths commits changes to the given paths, leaving other changes in
+// the working tree alone. It returns ErrNoChangesToCommit if there are none.
+func (c *Client) CommitPaths(repoPath, message string, paths ...string) error {
+	// Stage the paths
+	if err := c.stageChanges(repoPath, paths); err != nil {
 		return fmt.Errorf("failed to stage changes: %w", err)
 	}
 
 	// Check if there are changes to commit
-	hasChanges, err := c.hasUncommittedChanges(repoPath)
+	hasChanges, err := c.hasStagedChanges(repoPath)
 	if err != nil {
 		return fmt.Errorf("failed to check for changes: %w", err)
 	}
@@ -199,49 +466,80 @@ func (c *Client) CommitAndPush(repoPath, message string) error {
 		return ErrNoChangesToCommit
 	}
 
-	// Commit changes
 	if err := c.createCommit(repoPath, message); err != nil {
 		return fmt.Errorf("failed to create commit: %w", err)
 	}
+	return nil
+}
 
+// Push pushes the current branch, setting its upstream. When the push fails
+// it returns ErrNotPushed and the commits stay on the local branch.
+func (c *Client) Push(repoPath string) error {
 	// Get or create branch
 	branch, err := c.ensureBranch(repoPath)
 	if err != nil {
 		return fmt.Errorf("failed to determine branch: %w", err)
 	}
 
-	// Push changes
 	if err := c.pushWithUpstream(repoPath, branch); err != nil {
-		return fmt.Errorf("failed to push to remote: %w", err)
+		return fmt.Errorf("%w: %w", ErrNotPushed, err)
 	}
-
 	return nil
 }
 
 // ErrNoChangesToCommit indicates there are no changes to commit
 var ErrNoChangesToCommit = fmt.Errorf("no changes to commit")
 
-// stageAllChanges adds all changes to the staging area
-func (c *Client) stageAllChanges(repoPath string) error {
-	output, err := c.runner.RunInDir(repoPath, "git", "add", ".")
+// ErrNotPushed indicates a commit was made but couldn't be pushed, e.g.
+// while offline. It stays on the local branch and goes out with the next push.
+var ErrNotPushed = fmt.Errorf("committed locally but failed to push to remote")
+
+// stageChanges adds changes to the paths to the staging area
+func (c *Client) stageChanges(repoPath string, paths []string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no paths to stage")
+	}
+	output, err := c.Add(repoPath, paths...)
 	if err != nil {
 		return fmt.Errorf("%w (output: %s)", err, string(output))
 	}
 	return nil
 }
 
-// hasUncommittedChanges checks if there are uncommitted changes
-func (c *Client) hasUncommittedChanges(repoPath string) (bool, error) {
-	output, err := c.runner.RunInDir(repoPath, "git", "status", "--porcelain")
+// hasStagedChanges checks if there are staged changes to commit
+func (c *Client) hasStagedChanges(repoPath string) (bool, error) {
+	output, err := c.runner.RunInDir(repoPath, "git", "diff", "--cached", "--name-only")
 	if err != nil {
 		return false, err
 	}
 	return len(bytes.TrimSpace(output)) > 0, nil
 }
 
+// UncommittedChanges returns the paths with uncommitted changes, including untracked files
+func (c *Client) UncommittedChanges(repoPath string) ([]string, error) {
+	output, err := c.runner.RunInDir(repoPath, "git", "status", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository status: %w\nOutput: %s", err, string(output))
+	}
+
+	var paths []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		path := line[3:]
+		// Renames are reported as "old -> new"
+		if idx := strings.Index(path, " -> "); idx >= 0 {
+			path = path[idx+4:]
+		}
+		paths = append(paths, strings.Trim(path, "\""))
+	}
+	return paths, nil
+}
+
 // createCommit creates a commit with the given message
 func (c *Client) createCommit(repoPath, message string) error {
-	output, err := c.runner.RunInDir(repoPath, "git", "commit", "-m", message)
+	output, err := c.runner.RunInDir(repoPath, "git", c.commitArgs(message)...)
 	if err != nil {
 		return fmt.Errorf("%w (output: %s)", err, string(output))
 	}
@@ -256,11 +554,14 @@ func (c *Client) ensureBranch(repoPath string) (string, error) {
 	}
 
 	if branch == "" {
-		// Detached HEAD state, create main branch
-		branch = "main"
+		// Detached HEAD state, create the remote's default branch
+		branch, err = c.DefaultBranch(repoPath)
+		if err != nil {
+			branch = "main"
+		}
 		output, err := c.runner.RunInDir(repoPath, "git", "checkout", "-b", branch)
 		if err != nil {
-			return "", fmt.Errorf("failed to create main branch: %w (output: %s)", err, string(output))
+			return "", fmt.Errorf("failed to create %s branch: %w (output: %s)", branch, err, string(output))
 		}
 	}
 
@@ -282,7 +583,7 @@ func (c *Client) pushWithUpstream(repoPath, branch string) error {
 	}
 	
 	// Non-fast-forward error detected, fetch and retry
-	if err := c.fetchAll(repoPath); err != nil {
+	if err := c.fetchBranch(repoPath, branch); err != nil {
 		return fmt.Errorf("failed to fetch during push retry: %w", err)
 	}
 	
@@ -350,7 +651,7 @@ func (c *Client) PushBranchWithRetry(repoPath, branchName string) error {
 	}
 	
 	// Fetch the latest changes from remote
-	if err := c.fetchAll(repoPath); err != nil {
+	if err := c.fetchBranch(repoPath, branchName); err != nil {
 		return fmt.Errorf("failed to fetch remote changes during retry: %w", err)
 	}
 	
@@ -377,19 +678,109 @@ func (c *Client) PushBranchWithRetry(repoPath, branchName string) error {
 	return nil
 }
 
+// maxSaveAttempts bounds how many times SaveBranch pushes before giving up
+const maxSaveAttempts = 3
+
+// SaveReport describes how SaveBranch got a commit onto a shared branch
+type SaveReport struct {
+	// Attempts is the number of pushes made
+	Attempts int
+	// Reapplied is true when others had pushed first, so the commit was
+	// replayed on top of their changes
+	Reapplied bool
+	// UpToDate is true when the remote already had the same changes
+	UpToDate bool
+}
+
+// SaveBranch pushes the last commit on a branch that others push to too.
+// When the push is rejected because someone else pushed first, the branch is
+// moved to the remote's tip and only the commit's changes to paths are
+// re-applied and committed again, so a teammate's concurrent changes are
+// neither clobbered nor conflicted with. Gives up after a few attempts.
+func (c *Client) SaveBranch(repoPath, branchName string, paths ...string) (*SaveReport, error) {
+	report := &SaveReport{}
+	for {
+		report.Attempts++
+		output, err := c.runner.RunInDir(repoPath, "git", "push", "-u", "origin", branchName)
+		if err == nil {
+			return report, nil
+		}
+		if !strings.Contains(string(output), "non-fast-forward") && !strings.Contains(string(output), "rejected") {
+			return report, fmt.Errorf("failed to push branch: %w\nOutput: %s", err, string(output))
+		}
+		if report.Attempts == maxSaveAttempts {
+			return report, fmt.Errorf("failed to push branch: the remote kept changing after %d attempts", report.Attempts)
+		}
+
+		upToDate, err := c.reapplyOnRemote(repoPath, branchName, paths)
+		if err != nil {
+			return report, err
+		}
+		report.Reapplied = true
+		if upToDate {
+			report.UpToDate = true
+			return report, nil
+		}
+	}
+}
+
+// reapplyOnRemote replays the last commit's changes to paths on top of the
+// remote branch. It reports whether the remote already had them.
+func (c *Client) reapplyOnRemote(repoPath, branchName string, paths []string) (bool, error) {
+	output, err := c.runner.RunInDir(repoPath, "git", "rev-parse", "HEAD")
+	if err != nil {
+		return false, fmt.Errorf("failed to find the commit to re-apply: %w\nOutput: %s", err, string(output))
+	}
+	commit := strings.TrimSpace(string(output))
+
+	remote := fmt.Sprintf("origin/%s", branchName)
+	if err := c.fetchBranch(repoPath, branchName); err != nil {
+		return false, fmt.Errorf("failed to fetch %s: %w", branchName, err)
+	}
+	// --keep refuses to discard uncommitted changes, unlike --hard
+	if output, err := c.runner.RunInDir(repoPath, "git", "reset", "--keep", remote); err != nil {
+		return false, fmt.Errorf("failed to move to %s: %w\nOutput: %s", remote, err, string(output))
+	}
+
+	args := append([]string{"checkout", commit, "--"}, paths...)
+	if output, err := c.runner.RunInDir(repoPath, "git", args...); err != nil {
+		return false, fmt.Errorf("failed to re-apply changes: %w\nOutput: %s", err, string(output))
+	}
+
+	changed, err := c.hasStagedChanges(repoPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if !changed {
+		return true, nil
+	}
+
+	// -C keeps the original message and author
+	args = append(c.commit.configFlags(), "commit", "-C", commit)
+	if output, err := c.runner.RunInDir(repoPath, "git", args...); err != nil {
+		return false, fmt.Errorf("failed to commit re-applied changes: %w\nOutput: %s", err, string(output))
+	}
+	return false, nil
+}
+
 // PullRequestOptions contains options for creating a pull request
 type PullRequestOptions struct {
-	Title string
-	Body  string
-	Base  string
+	Title     string
+	Body      string
+	Base      string
+	Head      string
+	Draft     bool
+	Labels    []string
+	Assignees []string
+	Reviewers []string
 }
 
 // CreatePullRequest creates a pull request using GitHub CLI
 func (c *Client) CreatePullRequest(repoPath, title, body string) error {
+	// Without a base, gh targets the repository's default branch
 	opts := PullRequestOptions{
 		Title: title,
 		Body:  body,
-		Base:  "main",
 	}
 	return c.CreatePullRequestWithOptions(repoPath, opts)
 }
@@ -400,26 +791,58 @@ func (c *Client) CreatePullRequestWithOptions(repoPath string, opts PullRequestO
 	if opts.Base != "" {
 		args = append(args, "--base", opts.Base)
 	}
+	if opts.Head != "" {
+		args = append(args, "--head", opts.Head)
+	}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	for _, label := range opts.Labels {
+		args = append(args, "--label", label)
+	}
+	for _, assignee := range opts.Assignees {
+		args = append(args, "--assignee", assignee)
+	}
+	for _, reviewer := range opts.Reviewers {
+		args = append(args, "--reviewer", reviewer)
+	}
 
 	output, err := c.runner.RunInDir(repoPath, "gh", args...)
 	if err != nil {
-		return fmt.Errorf("failed to create pull request: %w (output: %s)", err, string(output))
+		return fmt.Errorf("failed to create pull request: %w (output: %s)%s", err, string(output), GHUpgradeHint(output))
 	}
 	return nil
 }
 
 // MergeOptions contains options for merging a pull request
 type MergeOptions struct {
-	Auto         bool
-	Squash       bool
+	Auto bool
+	// Strategy is squash, merge or rebase; squash when empty
+	Strategy     string
 	DeleteBranch bool
 }
 
+// args returns the gh pr merge flags for the options
+func (o MergeOptions) args() []string {
+	var args []string
+	if o.Auto {
+		args = append(args, "--auto")
+	}
+	strategy := o.Strategy
+	if strategy == "" {
+		strategy = "squash"
+	}
+	args = append(args, "--"+strategy)
+	if o.DeleteBranch {
+		args = append(args, "--delete-branch")
+	}
+	return args
+}
+
 // MergePullRequest merges a pull request using GitHub CLI with default options
 func (c *Client) MergePullRequest(repoPath string) error {
 	opts := MergeOptions{
 		Auto:         true,
-		Squash:       true,
 		DeleteBranch: true,
 	}
 	return c.MergePullRequestWithOptions(repoPath, opts)
@@ -427,37 +850,96 @@ func (c *Client) MergePullRequest(repoPath string) error {
 
 // MergePullRequestWithOptions merges a pull request with custom options
 func (c *Client) MergePullRequestWithOptions(repoPath string, opts MergeOptions) error {
-	args := []string{"pr", "merge"}
-	if opts.Auto {
-		args = append(args, "--auto")
+	args := append([]string{"pr", "merge"}, opts.args()...)
+
+	output, err := c.runner.RunInDir(repoPath, "gh", args...)
+	if err != nil {
+		return fmt.Errorf("failed to merge pull request: %w (output: %s)%s", err, string(output), GHUpgradeHint(output))
+	}
+	return nil
+}
+
+// BranchExists checks if a branch exists locally
+func (c *Client) BranchExists(repoPath, branchName string) bool {
+	output, err := c.runner.RunInDir(repoPath, "git", "branch", "--list", branchName)
+	return err == nil && strings.TrimSpace(string(output)) != ""
+}
+
+// DefaultBranch returns the remote's default branch, from origin/HEAD when
+// the clone recorded it, or by asking the remote otherwise
+func (c *Client) DefaultBranch(repoPath string) (string, error) {
+	output, err := c.runner.RunInDir(repoPath, "git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err == nil {
+		if branch := strings.TrimPrefix(strings.TrimSpace(string(output)), "origin/"); branch != "" {
+			return branch, nil
+		}
 	}
-	if opts.Squash {
-		args = append(args, "--squash")
+
+	output, err = c.runner.RunInDir(repoPath, "git", "ls-remote", "--symref", "origin", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to query the default branch: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
 	}
-	if opts.DeleteBranch {
-		args = append(args, "--delete-branch")
+	// The first line is "ref: refs/heads/<branch>\tHEAD"
+	for _, line := range strings.Split(string(output), "\n") {
+		if ref, ok := strings.CutPrefix(line, "ref: refs/heads/"); ok {
+			if branch, _, _ := strings.Cut(ref, "\t"); branch != "" {
+				return branch, nil
+			}
+		}
 	}
+	return "", fmt.Errorf("the remote has no default branch")
+}
 
-	output, err := c.runner.RunInDir(repoPath, "gh", args...)
+// RemoteFile fetches a branch from origin and returns a file's contents on
+// it. The error wraps os.ErrNotExist when the file isn't on the branch.
+func (c *Client) RemoteFile(repoPath, branch, path string) ([]byte, error) {
+	if err := c.fetchBranch(repoPath, branch); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", branch, err)
+	}
+
+	output, err := c.runner.RunInDir(repoPath, "git", "show", "origin/"+branch+":"+path)
 	if err != nil {
-		return fmt.Errorf("failed to merge pull request: %w (output: %s)", err, string(output))
+		return nil, fmt.Errorf("%s is not on %s: %w", path, branch, os.ErrNotExist)
 	}
-	return nil
+	return output, nil
 }
 
-// SwitchToMainBranch switches back to the main branch
-func (c *Client) SwitchToMainBranch(repoPath string) error {
-	output, err := c.runner.RunInDir(repoPath, "git", "checkout", "main")
+// AddWorktree checks out a branch from origin in a new worktree at path. A
+// branch that isn't on origin yet is started as an orphan with no files.
+func (c *Client) AddWorktree(repoPath, path, branch string) error {
+	if c.RemoteBranchExists(repoPath, branch) {
+		if err := c.fetchBranch(repoPath, branch); err != nil {
+			return fmt.Errorf("failed to fetch %s: %w", branch, err)
+		}
+		output, err := c.runner.RunInDir(repoPath, "git", "worktree", "add", "-B", branch, path, "origin/"+branch)
+		if err != nil {
+			return fmt.Errorf("failed to check out %s: %w\nOutput: %s", branch, err, string(output))
+		}
+		return nil
+	}
+
+	output, err := c.runner.RunInDir(repoPath, "git", "worktree", "add", "--detach", path)
+	if err != nil {
+		return fmt.Errorf("failed to create worktree: %w\nOutput: %s", err, string(output))
+	}
+	output, err = c.runner.RunInDir(path, "git", "checkout", "--orphan", branch)
+	if err != nil {
+		return fmt.Errorf("failed to create branch %s: %w\nOutput: %s", branch, err, string(output))
+	}
+	output, err = c.runner.RunInDir(path, "git", "rm", "-r", "-q", "-f", "--ignore-unmatch", ".")
 	if err != nil {
-		return fmt.Errorf("failed to switch to main branch: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("failed to empty branch %s: %w\nOutput: %s", branch, err, string(output))
 	}
 	return nil
 }
 
-// BranchExists checks if a branch exists locally
-func (c *Client) BranchExists(repoPath, branchName string) bool {
-	output, err := c.runner.RunInDir(repoPath, "git", "branch", "--list", branchName)
-	return err == nil && strings.TrimSpace(string(output)) != ""
+// RemoveWorktree deletes a worktree created with AddWorktree
+func (c *Client) RemoveWorktree(repoPath, path string) error {
+	output, err := c.runner.RunInDir(repoPath, "git", "worktree", "remove", "--force", path)
+	if err != nil {
+		return fmt.Errorf("failed to remove worktree: %w\nOutput: %s", err, string(output))
+	}
+	return nil
 }
 
 // RemoteBranchExists checks if a branch exists on remote
@@ -477,9 +959,9 @@ func (c *Client) SwitchToBranch(repoPath, branchName string) error {
 
 // CreateOrCheckoutBranch creates a new branch or checks out an existing one (local or remote)
 func (c *Client) CreateOrCheckoutBranch(repoPath, branchName string) error {
-	// First, fetch all remote branches to ensure we have the latest
-	if err := c.fetchAll(repoPath); err != nil {
-		return fmt.Errorf("failed to fetch remote branches: %w", err)
+	// First, fetch the branch to ensure we have the latest
+	if err := c.fetchBranch(repoPath, branchName); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", branchName, err)
 	}
 	
 	// Check if branch exists locally
@@ -545,20 +1027,26 @@ func (c *Client) PRExistsForBranch(repoPath, branchName string) (bool, string) {
 	return info.Exists, info.Number
 }
 
-// GetPRInfoForBranch retrieves PR information for a specific branch
+// GetPRInfoForBranch retrieves PR information for a specific branch. The
+// JSON is read here rather than with --jq, which older gh releases lack.
 func (c *Client) GetPRInfoForBranch(repoPath, branchName string) PRInfo {
-	output, err := c.runner.RunInDir(repoPath, "gh", "pr", "list", 
-		"--head", branchName, 
-		"--json", "number", 
-		"--jq", ".[0].number")
-	
-	if err != nil || strings.TrimSpace(string(output)) == "" {
+	output, err := c.runner.RunInDir(repoPath, "gh", "pr", "list",
+		"--head", branchName,
+		"--json", "number")
+	if err != nil {
 		return PRInfo{Exists: false, Number: ""}
 	}
-	
+
+	var prs []struct {
+		Number int `json:"number"`
+	}
+	if err := json.Unmarshal(output, &prs); err != nil || len(prs) == 0 {
+		return PRInfo{Exists: false, Number: ""}
+	}
+
 	return PRInfo{
 		Exists: true,
-		Number: strings.TrimSpace(string(output)),
+		Number: strconv.Itoa(prs[0].Number),
 	}
 }
 
@@ -571,11 +1059,264 @@ func (c *Client) UpdatePullRequest(repoPath, prNumber, body string) error {
 	return nil
 }
 
+// MarkPullRequestReady marks a draft PR as ready for review
+func (c *Client) MarkPullRequestReady(repoPath, prNumber string) error {
+	output, err := c.runner.RunInDir(repoPath, "gh", "pr", "ready", prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to mark pull request ready: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// Issue is an open GitHub issue
+type Issue struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title,omitempty"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	Author    struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// ListIssues returns the open issues with a label, oldest first
+func (c *Client) ListIssues(repoPath, label string) ([]Issue, error) {
+	output, err := c.runner.RunInDir(repoPath, "gh", "issue", "list",
+		"--label", label,
+		"--state", "open",
+		"--json", "number,title,body,createdAt,author")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w\nOutput: %s%s", err, string(output), GHUpgradeHint(output))
+	}
+
+	var issues []Issue
+	if err := json.Unmarshal(output, &issues); err != nil {
+		return nil, fmt.Errorf("failed to parse issues: %w", err)
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Number < issues[j].Number })
+	return issues, nil
+}
+
+// CloseIssue closes an issue with a comment
+func (c *Client) CloseIssue(repoPath string, number int, comment string) error {
+	output, err := c.runner.RunInDir(repoPath, "gh", "issue", "close", strconv.Itoa(number), "--comment", comment)
+	if err != nil {
+		return fmt.Errorf("failed to close issue #%d: %w\nOutput: %s", number, err, string(output))
+	}
+	return nil
+}
+
+// CreateIssue opens an issue with a label, creating the label if the
+// repository doesn't have it yet
+func (c *Client) CreateIssue(repoPath, title, body, label string) error {
+	if ghSupports(ghLabelCreateVersion) {
+		output, err := c.runner.RunInDir(repoPath, "gh", "label", "create", label, "--force")
+		if err != nil {
+			return fmt.Errorf("failed to create label %q: %w\nOutput: %s%s", label, err, string(output), GHUpgradeHint(output))
+		}
+	}
+
+	output, err := c.runner.RunInDir(repoPath, "gh", "issue", "create", "--title", title, "--body", body, "--label", label)
+	if err != nil {
+		return fmt.Errorf("failed to create issue: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// CommentOnIssue adds a comment to an issue
+func (c *Client) CommentOnIssue(repoPath string, number int, comment string) error {
+	output, err := c.runner.RunInDir(repoPath, "gh", "issue", "comment", strconv.Itoa(number), "--body", comment)
+	if err != nil {
+		return fmt.Errorf("failed to comment on issue #%d: %w\nOutput: %s", number, err, string(output))
+	}
+	return nil
+}
+
+// ListRepoIssues returns the open issues with a label in another repository,
+// such as a tracker, oldest first
+func (c *Client) ListRepoIssues(repoPath, repo, label string) ([]Issue, error) {
+	output, err := c.runner.RunInDir(repoPath, "gh", "issue", "list",
+		"--repo", repo,
+		"--label", label,
+		"--state", "open",
+		"--json", "number,title,body,createdAt,author")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues in %s: %w\nOutput: %s%s", repo, err, string(output), GHUpgradeHint(output))
+	}
+
+	var issues []Issue
+	if err := json.Unmarshal(output, &issues); err != nil {
+		return nil, fmt.Errorf("failed to parse issues: %w", err)
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Number < issues[j].Number })
+	return issues, nil
+}
+
+// CreateRepoIssue opens an issue with a label in another repository,
+// creating the label if the repository doesn't have it yet. gh releases
+// without 'gh label create' rely on the label already existing.
+func (c *Client) CreateRepoIssue(repoPath, repo, title, body, label string) error {
+	canCreateLabel := ghSupports(ghLabelCreateVersion)
+	if canCreateLabel {
+		output, err := c.runner.RunInDir(repoPath, "gh", "label", "create", label, "--repo", repo, "--force")
+		if err != nil {
+			return fmt.Errorf("failed to create label %q in %s: %w\nOutput: %s%s", label, repo, err, string(output), GHUpgradeHint(output))
+		}
+	}
+
+	output, err := c.runner.RunInDir(repoPath, "gh", "issue", "create",
+		"--repo", repo,
+		"--title", title,
+		"--body", body,
+		"--label", label)
+	if err != nil {
+		if !canCreateLabel {
+			return fmt.Errorf("failed to create issue in %s: %w\nOutput: %s\nIf the label %q is missing, create it by hand: %v", repo, err, string(output), label, ghTooOld("create labels", ghLabelCreateVersion))
+		}
+		return fmt.Errorf("failed to create issue in %s: %w\nOutput: %s", repo, err, string(output))
+	}
+	return nil
+}
+
+// UpdateRepoIssue replaces the body of an issue in another repository and
+// comments on it, so watchers are notified
+func (c *Client) UpdateRepoIssue(repoPath, repo string, number int, body, comment string) error {
+	output, err := c.runner.RunInDir(repoPath, "gh", "issue", "edit", strconv.Itoa(number), "--repo", repo, "--body", body)
+	if err != nil {
+		return fmt.Errorf("failed to update issue #%d in %s: %w\nOutput: %s", number, repo, err, string(output))
+	}
+	output, err = c.runner.RunInDir(repoPath, "gh", "issue", "comment", strconv.Itoa(number), "--repo", repo, "--body", comment)
+	if err != nil {
+		return fmt.Errorf("failed to comment on issue #%d in %s: %w\nOutput: %s", number, repo, err, string(output))
+	}
+	return nil
+}
+
+// CloseRepoIssue closes an issue in another repository with a comment
+func (c *Client) CloseRepoIssue(repoPath, repo string, number int, comment string) error {
+	output, err := c.runner.RunInDir(repoPath, "gh", "issue", "close", strconv.Itoa(number), "--repo", repo, "--comment", comment)
+	if err != nil {
+		return fmt.Errorf("failed to close issue #%d in %s: %w\nOutput: %s", number, repo, err, string(output))
+	}
+	return nil
+}
+
 // MergePullRequestByNumber merges a PR by its number
-func (c *Client) MergePullRequestByNumber(repoPath, prNumber string) error {
-	output, err := c.runner.RunInDir(repoPath, "gh", "pr", "merge", prNumber, "--squash", "--delete-branch")
+func (c *Client) MergePullRequestByNumber(repoPath, prNumber string, opts MergeOptions) error {
+	args := append([]string{"pr", "merge", prNumber}, opts.args()...)
+	output, err := c.runner.RunInDir(repoPath, "gh", args...)
+	if err != nil {
+		return fmt.Errorf("failed to merge pull request: %w\nOutput: %s%s", err, string(output), GHUpgradeHint(output))
+	}
+	return nil
+}
+
+// RepositoryRoot returns the top-level directory of the repository containing dir
+func (c *Client) RepositoryRoot(dir string) (string, error) {
+	output, err := c.runner.RunInDir(dir, "git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("not inside a git repository: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RemoteURL returns the URL of the origin remote
+func (c *Client) RemoteURL(repoPath string) (string, error) {
+	output, err := c.runner.RunInDir(repoPath, "git", "remote", "get-url", "origin")
+	if err != nil {
+		return "", fmt.Errorf("failed to get origin URL: %w\nOutput: %s", err, string(output))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RemoteReachable checks that the origin remote can be contacted with the
+// current credentials
+func (c *Client) RemoteReachable(repoPath string) error {
+	output, err := c.runner.RunInDir(repoPath, "git", "ls-remote", "--heads", "origin")
 	if err != nil {
-		return fmt.Errorf("failed to merge pull request: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("cannot reach origin: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// Divergence returns how many commits the current branch is ahead of and
+// behind its upstream, as of the last fetch
+func (c *Client) Divergence(repoPath string) (ahead, behind int, err error) {
+	output, err := c.runner.RunInDir(repoPath, "git", "rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to compare with upstream: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(output)), "%d %d", &ahead, &behind); err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q: %w", string(output), err)
+	}
+	return ahead, behind, nil
+}
+
+// LastCommitTime returns when path was last committed to, or the zero time
+// if it has never been committed
+func (c *Client) LastCommitTime(repoPath, path string) (time.Time, error) {
+	output, err := c.runner.RunInDir(repoPath, "git", "log", "-1", "--format=%ct", "--", path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read history of %s: %w\nOutput: %s", path, err, strings.TrimSpace(string(output)))
+	}
+
+	value := strings.TrimSpace(string(output))
+	if value == "" {
+		return time.Time{}, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected git log output %q: %w", value, err)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// Revision is a commit that changed a file
+type Revision struct {
+	Hash    string
+	Time    time.Time
+	Author  string
+	Subject string
+}
+
+// ShortHash returns the commit's abbreviated hash, for display
+func (r Revision) ShortHash() string {
+	if len(r.Hash) > 7 {
+		return r.Hash[:7]
+	}
+	return r.Hash
+}
+
+// FileHistory returns the commits reachable from ref that changed path,
+// oldest first. path is relative to the repository root.
+func (c *Client) FileHistory(repoPath, ref, path string) ([]Revision, error) {
+	output, err := c.runner.RunInDir(repoPath, "git", "log", "--reverse", "--format=%H%x1f%ct%x1f%an%x1f%s", ref, "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of %s: %w\nOutput: %s", path, err, strings.TrimSpace(string(output)))
+	}
+
+	var revisions []Revision
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\x1f", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		seconds, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected git log output %q: %w", line, err)
+		}
+		revisions = append(revisions, Revision{Hash: fields[0], Time: time.Unix(seconds, 0), Author: fields[2], Subject: fields[3]})
+	}
+	return revisions, nil
+}
+
+// FileAt returns a file's contents at a revision. The error wraps
+// os.ErrNotExist when the file isn't in that revision.
+func (c *Client) FileAt(repoPath, revision, path string) ([]byte, error) {
+	output, err := c.runner.RunInDir(repoPath, "git", "show", revision+":"+path)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not in %s: %w", path, revision, os.ErrNotExist)
+	}
+	return output, nil
+}
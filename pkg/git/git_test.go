@@ -1,11 +1,14 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // MockCommandRunner implements CommandRunner for testing
@@ -117,7 +120,17 @@ func TestCheckGHInstalled(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "too old",
+			mock: MockCommand{
+				Name:   "gh",
+				Args:   []string{"--version"},
+				Output: []byte("gh version 1.14.0 (2021-08-04)\n"),
+			},
+			wantErr: true,
+		},
 	}
+	t.Cleanup(func() { ghVersion.Store(nil) })
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -189,6 +202,7 @@ func TestCloneRepository(t *testing.T) {
 	tests := []struct {
 		name    string
 		repo    string
+		opts    CloneOptions
 		mock    MockCommand
 		wantErr bool
 	}{
@@ -203,6 +217,16 @@ func TestCloneRepository(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "shallow blobless clone",
+			repo: "test/repo",
+			opts: CloneOptions{Depth: 50, Blobless: true},
+			mock: MockCommand{
+				Name: "gh",
+				Args: []string{"repo", "clone", "test/repo", targetPath, "--", "--depth", "50", "--filter=blob:none"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "clone fails",
 			repo: "test/repo",
@@ -223,7 +247,7 @@ func TestCloneRepository(t *testing.T) {
 			}
 			client := NewClientWithRunner(runner)
 
-			err := client.CloneRepository(tt.repo, targetPath)
+			err := client.CloneRepository(tt.repo, targetPath, tt.opts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CloneRepository() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -231,6 +255,100 @@ func TestCloneRepository(t *testing.T) {
 	}
 }
 
+func TestCloneURL(t *testing.T) {
+	targetPath := filepath.Join(t.TempDir(), "repo")
+	url := "git@github.com:test/repo.git"
+
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{Name: "git", Args: []string{"clone", url, targetPath}, Output: []byte("Cloning into 'repo'...")},
+		},
+	}
+	client := NewClientWithRunner(runner)
+
+	if err := client.CloneURL(url, targetPath, CloneOptions{}); err != nil {
+		t.Errorf("CloneURL() error = %v", err)
+	}
+
+	failing := NewClientWithRunner(&MockCommandRunner{
+		Commands: []MockCommand{
+			{Name: "git", Output: []byte("Permission denied (publickey)"), Error: fmt.Errorf("exit status 128")},
+		},
+	})
+	if err := failing.CloneURL(url, targetPath, CloneOptions{}); err == nil || !strings.Contains(err.Error(), "Permission denied") {
+		t.Errorf("CloneURL() error = %v, want git's output", err)
+	}
+}
+
+func TestCheckGitInstalled(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		wantErr string
+	}{
+		{name: "supported", output: "git version 2.43.0\n"},
+		{name: "apple build", output: "git version 2.39.3 (Apple Git-146)\n"},
+		{name: "minimum", output: "git version 2.23.0\n"},
+		{name: "unparseable", output: "git version unknown\n"},
+		{name: "too old", output: "git version 2.17.1\n", wantErr: "git 2.17 is too old"},
+		{name: "missing", err: exec.ErrNotFound, wantErr: "git not found on PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithRunner(&MockCommandRunner{
+				Commands: []MockCommand{
+					{Name: "git", Args: []string{"--version"}, Output: []byte(tt.output), Error: tt.err},
+				},
+			})
+
+			err := client.CheckGitInstalled()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckGitInstalled() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckGitInstalled() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGHVersionAdaptsCommands(t *testing.T) {
+	t.Cleanup(func() { ghVersion.Store(nil) })
+
+	// gh 2.10 has no 'gh label create', so the label must already exist
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{Name: "gh", Args: []string{"--version"}, Output: []byte("gh version 2.10.1 (2022-05-10)\n")},
+			{Name: "gh", Args: []string{"issue", "create", "--repo", "acme/blockers", "--title", "Blocker", "--body", "> Stuck", "--label", "standup-blocker"}, Dir: "/repo", Error: fmt.Errorf("exit status 1"), Output: []byte("could not add label: 'standup-blocker' not found")},
+		},
+	}
+	client := NewClientWithRunner(runner)
+	if err := client.CheckGHInstalled(); err != nil {
+		t.Fatalf("CheckGHInstalled() error = %v", err)
+	}
+	err := client.CreateRepoIssue("/repo", "acme/blockers", "Blocker", "> Stuck", "standup-blocker")
+	if err == nil || !strings.Contains(err.Error(), "create it by hand") || !strings.Contains(err.Error(), "needs gh 2.14 or newer") {
+		t.Errorf("CreateRepoIssue() error = %v, want a hint to create the label or upgrade", err)
+	}
+	if runner.Index != len(runner.Commands) {
+		t.Errorf("ran %d of %d commands", runner.Index, len(runner.Commands))
+	}
+}
+
+func TestGHUpgradeHint(t *testing.T) {
+	if hint := GHUpgradeHint([]byte("unknown flag: --auto")); !strings.Contains(hint, "upgrade from https://cli.github.com/") {
+		t.Errorf("GHUpgradeHint() = %q, want an upgrade hint", hint)
+	}
+	if hint := GHUpgradeHint([]byte("GraphQL: Pull request is not mergeable")); hint != "" {
+		t.Errorf("GHUpgradeHint() = %q, want none", hint)
+	}
+}
+
 func TestSyncRepository(t *testing.T) {
 	repoPath := "/test/repo"
 
@@ -252,16 +370,16 @@ func TestSyncRepository(t *testing.T) {
 				},
 				{
 					Name:   "git",
-					Args:   []string{"fetch", "--all"},
+					Args:   []string{"branch", "--show-current"},
 					Dir:    repoPath,
-					Output: []byte("Fetching origin"),
+					Output: []byte("main\n"),
 					Error:  nil,
 				},
 				{
 					Name:   "git",
-					Args:   []string{"branch", "--show-current"},
+					Args:   []string{"fetch", "origin", "+refs/heads/main:refs/remotes/origin/main"},
 					Dir:    repoPath,
-					Output: []byte("main\n"),
+					Output: []byte("From github.com:org/standups"),
 					Error:  nil,
 				},
 				{
@@ -271,6 +389,13 @@ func TestSyncRepository(t *testing.T) {
 					Output: []byte("abc123"),
 					Error:  nil,
 				},
+				{
+					Name:   "git",
+					Args:   []string{"rev-list", "--count", "origin/main..HEAD"},
+					Dir:    repoPath,
+					Output: []byte("0\n"),
+					Error:  nil,
+				},
 				{
 					Name:   "git",
 					Args:   []string{"reset", "--hard", "origin/main"},
@@ -281,6 +406,44 @@ func TestSyncRepository(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "unpushed commits are rebased",
+			mocks: []MockCommand{
+				{Name: "git", Args: []string{"branch", "-a"}, Dir: repoPath, Output: []byte("* main\n  remotes/origin/main\n")},
+				{Name: "git", Args: []string{"branch", "--show-current"}, Dir: repoPath, Output: []byte("main\n")},
+				{Name: "git", Args: []string{"fetch", "origin", "+refs/heads/main:refs/remotes/origin/main"}, Dir: repoPath},
+				{Name: "git", Args: []string{"rev-parse", "origin/main"}, Dir: repoPath, Output: []byte("abc123")},
+				{Name: "git", Args: []string{"rev-list", "--count", "origin/main..HEAD"}, Dir: repoPath, Output: []byte("2\n")},
+				{Name: "git", Args: []string{"rebase", "origin/main"}, Dir: repoPath},
+			},
+			wantErr: false,
+		},
+		{
+			name: "conflicting unpushed commits are kept",
+			mocks: []MockCommand{
+				{Name: "git", Args: []string{"branch", "-a"}, Dir: repoPath, Output: []byte("* main\n  remotes/origin/main\n")},
+				{Name: "git", Args: []string{"branch", "--show-current"}, Dir: repoPath, Output: []byte("main\n")},
+				{Name: "git", Args: []string{"fetch", "origin", "+refs/heads/main:refs/remotes/origin/main"}, Dir: repoPath},
+				{Name: "git", Args: []string{"rev-parse", "origin/main"}, Dir: repoPath, Output: []byte("abc123")},
+				{Name: "git", Args: []string{"rev-list", "--count", "origin/main..HEAD"}, Dir: repoPath, Output: []byte("1\n")},
+				{Name: "git", Args: []string{"rebase", "origin/main"}, Dir: repoPath, Output: []byte("CONFLICT"), Error: fmt.Errorf("exit status 1")},
+				{Name: "git", Args: []string{"rebase", "--abort"}, Dir: repoPath},
+			},
+			wantErr:  true,
+			errMatch: "1 unpushed commit(s)",
+		},
+		{
+			name: "new daily branch",
+			mocks: []MockCommand{
+				{Name: "git", Args: []string{"branch", "-a"}, Dir: repoPath, Output: []byte("* standup/2024-01-15\n  main\n  remotes/origin/main\n")},
+				{Name: "git", Args: []string{"branch", "--show-current"}, Dir: repoPath, Output: []byte("standup/2024-01-15\n")},
+				{Name: "git", Args: []string{"fetch", "origin", "+refs/heads/main:refs/remotes/origin/main"}, Dir: repoPath},
+				{Name: "git", Args: []string{"fetch", "origin", "+refs/heads/standup/2024-01-15:refs/remotes/origin/standup/2024-01-15"}, Dir: repoPath,
+					Output: []byte("fatal: couldn't find remote ref refs/heads/standup/2024-01-15"), Error: fmt.Errorf("exit status 128")},
+				{Name: "git", Args: []string{"rev-parse", "origin/standup/2024-01-15"}, Dir: repoPath, Error: fmt.Errorf("exit status 128")},
+			},
+			wantErr: false,
+		},
 		{
 			name: "fetch fails",
 			mocks: []MockCommand{
@@ -291,9 +454,10 @@ func TestSyncRepository(t *testing.T) {
 					Output: []byte("* main\n"),
 					Error:  nil,
 				},
+				{Name: "git", Args: []string{"branch", "--show-current"}, Dir: repoPath, Output: []byte("main\n")},
 				{
 					Name:   "git",
-					Args:   []string{"fetch", "--all"},
+					Args:   []string{"fetch", "origin", "+refs/heads/main:refs/remotes/origin/main"},
 					Dir:    repoPath,
 					Output: []byte("error: failed to fetch"),
 					Error:  fmt.Errorf("exit status 1"),
@@ -311,7 +475,7 @@ func TestSyncRepository(t *testing.T) {
 			}
 			client := NewClientWithRunner(runner)
 
-			err := client.SyncRepository(repoPath)
+			err := client.SyncRepository(repoPath, "main")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SyncRepository() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -337,16 +501,16 @@ func TestCommitAndPush(t *testing.T) {
 			mocks: []MockCommand{
 				{
 					Name:   "git",
-					Args:   []string{"add", "."},
+					Args:   []string{"add", "--", "stand-ups/alice.md"},
 					Dir:    repoPath,
 					Output: []byte(""),
 					Error:  nil,
 				},
 				{
 					Name:   "git",
-					Args:   []string{"status", "--porcelain"},
+					Args:   []string{"diff", "--cached", "--name-only"},
 					Dir:    repoPath,
-					Output: []byte("M file.txt\n"),
+					Output: []byte("stand-ups/alice.md\n"),
 					Error:  nil,
 				},
 				{
@@ -378,14 +542,14 @@ func TestCommitAndPush(t *testing.T) {
 			mocks: []MockCommand{
 				{
 					Name:   "git",
-					Args:   []string{"add", "."},
+					Args:   []string{"add", "--", "stand-ups/alice.md"},
 					Dir:    repoPath,
 					Output: []byte(""),
 					Error:  nil,
 				},
 				{
 					Name:   "git",
-					Args:   []string{"status", "--porcelain"},
+					Args:   []string{"diff", "--cached", "--name-only"},
 					Dir:    repoPath,
 					Output: []byte(""),
 					Error:  nil,
@@ -403,7 +567,7 @@ func TestCommitAndPush(t *testing.T) {
 			}
 			client := NewClientWithRunner(runner)
 
-			err := client.CommitAndPush(repoPath, message)
+			err := client.CommitAndPush(repoPath, message, "stand-ups/alice.md")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CommitAndPush() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -476,7 +640,7 @@ func TestPushBranchWithRetry(t *testing.T) {
 				},
 				{
 					Name:   "git",
-					Args:   []string{"fetch", "--all"},
+					Args:   []string{"fetch", "origin", "+refs/heads/feature-branch:refs/remotes/origin/feature-branch"},
 					Dir:    repoPath,
 					Output: []byte("Fetching origin"),
 					Error:  nil,
@@ -510,7 +674,7 @@ func TestPushBranchWithRetry(t *testing.T) {
 				},
 				{
 					Name:   "git",
-					Args:   []string{"fetch", "--all"},
+					Args:   []string{"fetch", "origin", "+refs/heads/feature-branch:refs/remotes/origin/feature-branch"},
 					Dir:    repoPath,
 					Output: []byte("Fetching origin"),
 					Error:  nil,
@@ -595,7 +759,7 @@ func TestCreateOrCheckoutBranch(t *testing.T) {
 			mocks: []MockCommand{
 				{
 					Name:   "git",
-					Args:   []string{"fetch", "--all"},
+					Args:   []string{"fetch", "origin", "+refs/heads/standup/2025-01-20:refs/remotes/origin/standup/2025-01-20"},
 					Dir:    repoPath,
 					Output: []byte("Fetching origin"),
 					Error:  nil,
@@ -636,7 +800,7 @@ func TestCreateOrCheckoutBranch(t *testing.T) {
 			mocks: []MockCommand{
 				{
 					Name:   "git",
-					Args:   []string{"fetch", "--all"},
+					Args:   []string{"fetch", "origin", "+refs/heads/standup/2025-01-20:refs/remotes/origin/standup/2025-01-20"},
 					Dir:    repoPath,
 					Output: []byte("Fetching origin"),
 					Error:  nil,
@@ -670,7 +834,7 @@ func TestCreateOrCheckoutBranch(t *testing.T) {
 			mocks: []MockCommand{
 				{
 					Name:   "git",
-					Args:   []string{"fetch", "--all"},
+					Args:   []string{"fetch", "origin", "+refs/heads/standup/2025-01-20:refs/remotes/origin/standup/2025-01-20"},
 					Dir:    repoPath,
 					Output: []byte("Fetching origin"),
 					Error:  nil,
@@ -717,4 +881,487 @@ func TestCreateOrCheckoutBranch(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestRepositoryRoot(t *testing.T) {
+	tests := []struct {
+		name    string
+		mock    MockCommand
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "inside repository",
+			mock: MockCommand{
+				Name:   "git",
+				Args:   []string{"rev-parse", "--show-toplevel"},
+				Dir:    "/work/standups/stand-ups",
+				Output: []byte("/work/standups\n"),
+			},
+			want: "/work/standups",
+		},
+		{
+			name: "outside repository",
+			mock: MockCommand{
+				Name:   "git",
+				Args:   []string{"rev-parse", "--show-toplevel"},
+				Dir:    "/work/standups/stand-ups",
+				Output: []byte("fatal: not a git repository"),
+				Error:  fmt.Errorf("exit status 128"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &MockCommandRunner{
+				Commands: []MockCommand{tt.mock},
+			}
+			client := NewClientWithRunner(runner)
+
+			got, err := client.RepositoryRoot("/work/standups/stand-ups")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RepositoryRoot() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RepositoryRoot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteURL(t *testing.T) {
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{
+				Name:   "git",
+				Args:   []string{"remote", "get-url", "origin"},
+				Dir:    "/test/repo",
+				Output: []byte("git@github.com:org/standups.git\n"),
+			},
+		},
+	}
+	client := NewClientWithRunner(runner)
+
+	got, err := client.RemoteURL("/test/repo")
+	if err != nil {
+		t.Fatalf("RemoteURL() error = %v", err)
+	}
+	if got != "git@github.com:org/standups.git" {
+		t.Errorf("RemoteURL() = %v, want git@github.com:org/standups.git", got)
+	}
+}
+
+func TestUncommittedChanges(t *testing.T) {
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{
+				Name:   "git",
+				Args:   []string{"status", "--porcelain"},
+				Dir:    "/test/repo",
+				Output: []byte(" M stand-ups/alice.md\n?? notes.txt\nR  old.go -> new.go\n?? \"with space.txt\"\n"),
+			},
+		},
+	}
+	client := NewClientWithRunner(runner)
+
+	got, err := client.UncommittedChanges("/test/repo")
+	if err != nil {
+		t.Fatalf("UncommittedChanges() error = %v", err)
+	}
+
+	want := []string{"stand-ups/alice.md", "notes.txt", "new.go", "with space.txt"}
+	if !argsMatch(want, got) {
+		t.Errorf("UncommittedChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestDivergence(t *testing.T) {
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{Name: "git", Args: []string{"rev-list", "--left-right", "--count", "HEAD...@{upstream}"}, Dir: "/repo", Output: []byte("2\t5\n")},
+			{Name: "git", Dir: "/repo", Output: []byte("fatal: no upstream configured"), Error: fmt.Errorf("exit status 128")},
+		},
+	}
+	client := NewClientWithRunner(runner)
+
+	ahead, behind, err := client.Divergence("/repo")
+	if err != nil || ahead != 2 || behind != 5 {
+		t.Errorf("Divergence() = %d, %d, %v, want 2, 5, nil", ahead, behind, err)
+	}
+
+	if _, _, err := client.Divergence("/repo"); err == nil {
+		t.Error("Divergence() without an upstream should return an error")
+	}
+}
+
+func TestRemoteReachable(t *testing.T) {
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{Name: "git", Args: []string{"ls-remote", "--heads", "origin"}, Dir: "/repo", Output: []byte("abc123\trefs/heads/main\n")},
+			{Name: "git", Dir: "/repo", Output: []byte("Could not resolve host: github.com"), Error: fmt.Errorf("exit status 128")},
+		},
+	}
+	client := NewClientWithRunner(runner)
+
+	if err := client.RemoteReachable("/repo"); err != nil {
+		t.Errorf("RemoteReachable() error = %v", err)
+	}
+	if err := client.RemoteReachable("/repo"); err == nil || !strings.Contains(err.Error(), "Could not resolve host") {
+		t.Errorf("RemoteReachable() error = %v, want git's output", err)
+	}
+}
+
+func TestLastCommitTime(t *testing.T) {
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{Name: "git", Args: []string{"log", "-1", "--format=%ct", "--", "stand-ups/alice.md"}, Dir: "/repo", Output: []byte("1705312800\n")},
+			{Name: "git", Dir: "/repo", Output: []byte("")},
+		},
+	}
+	client := NewClientWithRunner(runner)
+
+	got, err := client.LastCommitTime("/repo", "stand-ups/alice.md")
+	if err != nil || !got.Equal(time.Unix(1705312800, 0)) {
+		t.Errorf("LastCommitTime() = %v, %v", got, err)
+	}
+
+	got, err = client.LastCommitTime("/repo", "stand-ups/new.md")
+	if err != nil || !got.IsZero() {
+		t.Errorf("LastCommitTime() for an uncommitted file = %v, %v, want the zero time", got, err)
+	}
+}
+
+func TestFileHistory(t *testing.T) {
+	log := "aaa111\x1f1705312800\x1fAlice\x1f[Standup] alice - 2024-01-15\nbbb222\x1f1705320000\x1fAlice\x1fFix a typo\n"
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{Name: "git", Args: []string{"log", "--reverse", "--format=%H%x1f%ct%x1f%an%x1f%s", "HEAD", "--", "stand-ups/alice.md"}, Dir: "/repo", Output: []byte(log)},
+			{Name: "git", Args: []string{"show", "bbb222:stand-ups/alice.md"}, Dir: "/repo", Output: []byte("# alice\n")},
+			{Name: "git", Args: []string{"show", "aaa111:stand-ups/bob.md"}, Dir: "/repo", Error: fmt.Errorf("exit status 128")},
+		},
+	}
+	client := NewClientWithRunner(runner)
+
+	revisions, err := client.FileHistory("/repo", "HEAD", "stand-ups/alice.md")
+	if err != nil || len(revisions) != 2 {
+		t.Fatalf("FileHistory() = %+v, %v", revisions, err)
+	}
+	if r := revisions[1]; r.Hash != "bbb222" || !r.Time.Equal(time.Unix(1705320000, 0)) || r.Author != "Alice" || r.Subject != "Fix a typo" {
+		t.Errorf("FileHistory()[1] = %+v", r)
+	}
+
+	if content, err := client.FileAt("/repo", "bbb222", "stand-ups/alice.md"); err != nil || string(content) != "# alice\n" {
+		t.Errorf("FileAt() = %q, %v", content, err)
+	}
+	if _, err := client.FileAt("/repo", "aaa111", "stand-ups/bob.md"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("FileAt() for a missing file error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestSaveBranch(t *testing.T) {
+	branch := "standup/2024-01-15"
+	rejected := MockCommand{Name: "git", Args: []string{"push", "-u", "origin", branch}, Output: []byte("! [rejected] (fetch first)"), Error: fmt.Errorf("exit status 1")}
+	pushed := MockCommand{Name: "git", Args: []string{"push", "-u", "origin", branch}}
+	reapply := []MockCommand{
+		{Name: "git", Args: []string{"rev-parse", "HEAD"}, Output: []byte("abc123\n")},
+		{Name: "git", Args: []string{"fetch", "origin", "+refs/heads/" + branch + ":refs/remotes/origin/" + branch}},
+		{Name: "git", Args: []string{"reset", "--keep", "origin/" + branch}},
+		{Name: "git", Args: []string{"checkout", "abc123", "--", "stand-ups/alice.md"}},
+	}
+	staged := MockCommand{Name: "git", Args: []string{"diff", "--cached", "--name-only"}, Output: []byte("stand-ups/alice.md\n")}
+	unchanged := MockCommand{Name: "git", Args: []string{"diff", "--cached", "--name-only"}}
+	recommit := MockCommand{Name: "git", Args: []string{"commit", "-C", "abc123"}}
+
+	var keepsChanging []MockCommand
+	for i := 1; i < maxSaveAttempts; i++ {
+		keepsChanging = append(keepsChanging, rejected)
+		keepsChanging = append(keepsChanging, reapply...)
+		keepsChanging = append(keepsChanging, staged, recommit)
+	}
+	keepsChanging = append(keepsChanging, rejected)
+
+	tests := []struct {
+		name    string
+		mocks   []MockCommand
+		want    SaveReport
+		wantErr bool
+	}{
+		{name: "first push", mocks: []MockCommand{pushed}, want: SaveReport{Attempts: 1}},
+		{
+			name:  "teammate pushed first",
+			mocks: append(append([]MockCommand{rejected}, reapply...), staged, recommit, pushed),
+			want:  SaveReport{Attempts: 2, Reapplied: true},
+		},
+		{
+			name:  "remote already has the change",
+			mocks: append(append([]MockCommand{rejected}, reapply...), unchanged),
+			want:  SaveReport{Attempts: 1, Reapplied: true, UpToDate: true},
+		},
+		{
+			name:    "remote keeps changing",
+			mocks:   keepsChanging,
+			want:    SaveReport{Attempts: 3, Reapplied: true},
+			wantErr: true,
+		},
+		{
+			name:    "other push failure",
+			mocks:   []MockCommand{{Name: "git", Output: []byte("Permission denied"), Error: fmt.Errorf("exit status 128")}},
+			want:    SaveReport{Attempts: 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithRunner(&MockCommandRunner{Commands: tt.mocks})
+			report, err := client.SaveBranch("/repo", branch, "stand-ups/alice.md")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SaveBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if *report != tt.want {
+				t.Errorf("SaveBranch() report = %+v, want %+v", *report, tt.want)
+			}
+		})
+	}
+}
+
+func TestListIssues(t *testing.T) {
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{
+				Name:   "gh",
+				Args:   []string{"issue", "list", "--label", "standup", "--state", "open", "--json", "number,title,body,createdAt,author"},
+				Dir:    "/repo",
+				Output: []byte(`[{"number":9,"body":"b","createdAt":"2024-01-15T09:00:00Z","author":{"login":"bob"}},{"number":7,"body":"a","createdAt":"2024-01-15T08:00:00Z","author":{"login":"alice"}}]`),
+			},
+			{Name: "gh", Args: []string{"issue", "close", "7", "--comment", "Recorded"}, Dir: "/repo"},
+			{Name: "gh", Args: []string{"label", "create", "standup-daily", "--force"}, Dir: "/repo"},
+			{Name: "gh", Args: []string{"issue", "create", "--title", "Standups for 2024-01-15", "--body", "Comment", "--label", "standup-daily"}, Dir: "/repo"},
+			{Name: "gh", Args: []string{"issue", "comment", "8", "--body", "Recorded"}, Dir: "/repo"},
+		},
+	}
+	client := NewClientWithRunner(runner)
+
+	issues, err := client.ListIssues("/repo", "standup")
+	if err != nil {
+		t.Fatalf("ListIssues() error = %v", err)
+	}
+	if len(issues) != 2 || issues[0].Number != 7 || issues[0].Author.Login != "alice" || issues[1].CreatedAt.Hour() != 9 {
+		t.Errorf("ListIssues() = %+v, want oldest first", issues)
+	}
+
+	if err := client.CloseIssue("/repo", 7, "Recorded"); err != nil {
+		t.Errorf("CloseIssue() error = %v", err)
+	}
+	if err := client.CreateIssue("/repo", "Standups for 2024-01-15", "Comment", "standup-daily"); err != nil {
+		t.Errorf("CreateIssue() error = %v", err)
+	}
+	if err := client.CommentOnIssue("/repo", 8, "Recorded"); err != nil {
+		t.Errorf("CommentOnIssue() error = %v", err)
+	}
+}
+
+func TestRepoIssues(t *testing.T) {
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{
+				Name:   "gh",
+				Args:   []string{"issue", "list", "--repo", "acme/blockers", "--label", "standup-blocker", "--state", "open", "--json", "number,title,body,createdAt,author"},
+				Dir:    "/repo",
+				Output: []byte(`[{"number":4,"title":"Blocker: alice (2024-01-15)","body":"> Waiting","createdAt":"2024-01-15T09:00:00Z","author":{"login":"bot"}}]`),
+			},
+			{Name: "gh", Args: []string{"label", "create", "standup-blocker", "--repo", "acme/blockers", "--force"}, Dir: "/repo"},
+			{Name: "gh", Args: []string{"issue", "create", "--repo", "acme/blockers", "--title", "Blocker: bob (2024-01-16)", "--body", "> Stuck", "--label", "standup-blocker"}, Dir: "/repo"},
+			{Name: "gh", Args: []string{"issue", "edit", "4", "--repo", "acme/blockers", "--body", "> Still waiting"}, Dir: "/repo"},
+			{Name: "gh", Args: []string{"issue", "comment", "4", "--repo", "acme/blockers", "--body", "Update"}, Dir: "/repo"},
+			{Name: "gh", Args: []string{"issue", "close", "4", "--repo", "acme/blockers", "--comment", "Resolved"}, Dir: "/repo"},
+		},
+	}
+	client := NewClientWithRunner(runner)
+
+	issues, err := client.ListRepoIssues("/repo", "acme/blockers", "standup-blocker")
+	if err != nil {
+		t.Fatalf("ListRepoIssues() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].Title != "Blocker: alice (2024-01-15)" {
+		t.Errorf("ListRepoIssues() = %+v", issues)
+	}
+
+	if err := client.CreateRepoIssue("/repo", "acme/blockers", "Blocker: bob (2024-01-16)", "> Stuck", "standup-blocker"); err != nil {
+		t.Errorf("CreateRepoIssue() error = %v", err)
+	}
+	if err := client.UpdateRepoIssue("/repo", "acme/blockers", 4, "> Still waiting", "Update"); err != nil {
+		t.Errorf("UpdateRepoIssue() error = %v", err)
+	}
+	if err := client.CloseRepoIssue("/repo", "acme/blockers", 4, "Resolved"); err != nil {
+		t.Errorf("CloseRepoIssue() error = %v", err)
+	}
+}
+
+func TestCommitOptions(t *testing.T) {
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{
+				Name: "git",
+				Args: []string{"-c", "user.name=Standup Bot", "-c", "user.email=bot@example.com",
+					"-c", "commit.gpgsign=true", "-c", "gpg.format=ssh", "commit", "-m", "Add standup"},
+				Dir: "/repo",
+			},
+		},
+	}
+	client := NewClientWithRunner(runner)
+	client.SetCommitOptions(CommitOptions{
+		AuthorName:    "Standup Bot",
+		AuthorEmail:   "bot@example.com",
+		Sign:          true,
+		SigningFormat: "ssh",
+	})
+
+	if _, err := client.Commit("/repo", "Add standup"); err != nil {
+		t.Errorf("Commit() error = %v", err)
+	}
+}
+
+func TestDefaultBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		mocks   []MockCommand
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "origin HEAD recorded by clone",
+			mocks: []MockCommand{
+				{Name: "git", Args: []string{"symbolic-ref", "--short", "refs/remotes/origin/HEAD"}, Dir: "/repo", Output: []byte("origin/master\n")},
+			},
+			want: "master",
+		},
+		{
+			name: "asks the remote",
+			mocks: []MockCommand{
+				{Name: "git", Dir: "/repo", Error: fmt.Errorf("exit status 128")},
+				{Name: "git", Args: []string{"ls-remote", "--symref", "origin", "HEAD"}, Dir: "/repo", Output: []byte("ref: refs/heads/develop\tHEAD\nabc123\tHEAD\n")},
+			},
+			want: "develop",
+		},
+		{
+			name: "empty remote",
+			mocks: []MockCommand{
+				{Name: "git", Dir: "/repo", Error: fmt.Errorf("exit status 128")},
+				{Name: "git", Dir: "/repo", Output: []byte("")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithRunner(&MockCommandRunner{Commands: tt.mocks})
+			got, err := client.DefaultBranch("/repo")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("DefaultBranch() = %q, %v, want %q (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreatePullRequestWithOptions(t *testing.T) {
+	runner := &MockCommandRunner{
+		Commands: []MockCommand{
+			{
+				Name: "gh",
+				Args: []string{"pr", "create", "--title", "T", "--body", "B", "--base", "main", "--draft",
+					"--label", "standup", "--label", "daily", "--assignee", "alice", "--reviewer", "org/leads"},
+				Dir: "/repo",
+			},
+		},
+	}
+	client := NewClientWithRunner(runner)
+
+	err := client.CreatePullRequestWithOptions("/repo", PullRequestOptions{
+		Title:     "T",
+		Body:      "B",
+		Base:      "main",
+		Draft:     true,
+		Labels:    []string{"standup", "daily"},
+		Assignees: []string{"alice"},
+		Reviewers: []string{"org/leads"},
+	})
+	if err != nil {
+		t.Errorf("CreatePullRequestWithOptions() error = %v", err)
+	}
+}
+
+func TestAddWorktree(t *testing.T) {
+	tests := []struct {
+		name  string
+		mocks []MockCommand
+	}{
+		{
+			name: "branch on origin",
+			mocks: []MockCommand{
+				{Name: "git", Args: []string{"ls-remote", "--heads", "origin", "gh-pages"}, Dir: "/repo", Output: []byte("abc123\trefs/heads/gh-pages\n")},
+				{Name: "git", Args: []string{"fetch", "origin", "+refs/heads/gh-pages:refs/remotes/origin/gh-pages"}, Dir: "/repo"},
+				{Name: "git", Args: []string{"worktree", "add", "-B", "gh-pages", "/tmp/site", "origin/gh-pages"}, Dir: "/repo"},
+			},
+		},
+		{
+			name: "new orphan branch",
+			mocks: []MockCommand{
+				{Name: "git", Args: []string{"ls-remote", "--heads", "origin", "gh-pages"}, Dir: "/repo", Output: []byte("")},
+				{Name: "git", Args: []string{"worktree", "add", "--detach", "/tmp/site"}, Dir: "/repo"},
+				{Name: "git", Args: []string{"checkout", "--orphan", "gh-pages"}, Dir: "/tmp/site"},
+				{Name: "git", Args: []string{"rm", "-r", "-q", "-f", "--ignore-unmatch", "."}, Dir: "/tmp/site"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &MockCommandRunner{Commands: tt.mocks}
+			client := NewClientWithRunner(runner)
+			if err := client.AddWorktree("/repo", "/tmp/site", "gh-pages"); err != nil {
+				t.Fatalf("AddWorktree() error = %v", err)
+			}
+			if runner.Index != len(tt.mocks) {
+				t.Errorf("ran %d of %d commands", runner.Index, len(tt.mocks))
+			}
+		})
+	}
+}
+
+func TestGetPRInfoForBranch(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		want   PRInfo
+	}{
+		{name: "open pull request", output: `[{"number":42}]`, want: PRInfo{Exists: true, Number: "42"}},
+		{name: "no pull request", output: `[]`, want: PRInfo{}},
+		{name: "gh fails", err: errors.New("exit status 1"), want: PRInfo{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &MockCommandRunner{
+				Commands: []MockCommand{
+					{
+						Name:   "gh",
+						Args:   []string{"pr", "list", "--head", "standup/2024-01-15", "--json", "number"},
+						Dir:    "/repo",
+						Output: []byte(tt.output),
+						Error:  tt.err,
+					},
+				},
+			}
+			client := NewClientWithRunner(runner)
+
+			if got := client.GetPRInfoForBranch("/repo", "standup/2024-01-15"); got != tt.want {
+				t.Errorf("GetPRInfoForBranch() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package git
+
+import "context"
+
+// CommandTracer observes the commands clients run, e.g. to export them as
+// trace spans. StartCommand is called as a command starts, with the context
+// of the client that runs it, and the function it returns when the command
+// ends.
+type CommandTracer interface {
+	StartCommand(ctx context.Context, dir, name string, args []string) func(output []byte, err error)
+}
+
+// TracingRunner is a CommandRunner that reports every command to a tracer
+type TracingRunner struct {
+	runner CommandRunner
+	tracer CommandTracer
+	ctx    context.Context
+}
+
+// NewTracingRunner wraps runner so its commands are reported to tracer
+func NewTracingRunner(runner CommandRunner, tracer CommandTracer) *TracingRunner {
+	return &TracingRunner{
+		runner: runner,
+		tracer: tracer,
+		ctx:    context.Background(),
+	}
+}
+
+// Run executes a command and reports it
+func (r *TracingRunner) Run(name string, args ...string) ([]byte, error) {
+	end := r.tracer.StartCommand(r.ctx, "", name, args)
+	output, err := r.runner.Run(name, args...)
+	end(output, err)
+	return output, err
+}
+
+// RunInDir executes a command in a specific directory and reports it
+func (r *TracingRunner) RunInDir(dir, name string, args ...string) ([]byte, error) {
+	end := r.tracer.StartCommand(r.ctx, dir, name, args)
+	output, err := r.runner.RunInDir(dir, name, args...)
+	end(output, err)
+	return output, err
+}
+
+// withContext reports the commands with ctx instead
+func (r *TracingRunner) withContext(ctx context.Context) CommandRunner {
+	return &TracingRunner{
+		runner: r.runner,
+		tracer: r.tracer,
+		ctx:    ctx,
+	}
+}
+
+// contextRunner is a runner, or a wrapper around one, that reports its
+// commands with a context
+type contextRunner interface {
+	withContext(ctx context.Context) CommandRunner
+}
+
+// WithContext returns a copy of the client whose commands are reported to
+// the tracer with ctx, e.g. so they belong to a server request's trace
+func (c *Client) WithContext(ctx context.Context) *Client {
+	client := *c
+	if r, ok := c.runner.(contextRunner); ok {
+		client.runner = r.withContext(ctx)
+	}
+	return &client
+}
+
+// activeTracer observes the commands of clients created by NewClient
+var activeTracer CommandTracer
+
+// activeTracerContext is the context commands are reported with, unless
+// their client was given another with WithContext
+var activeTracerContext = context.Background()
+
+// SetTracer makes clients created by NewClient report their commands to t,
+// with ctx unless they are given another with WithContext. Passing nil stops
+// tracing.
+func SetTracer(t CommandTracer, ctx context.Context) {
+	activeTracer = t
+	activeTracerContext = ctx
+}
@@ -0,0 +1,40 @@
+package git
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+// contextKey marks the contexts commands are reported with
+type contextKey struct{}
+
+// contextTracer records the context each command is reported with
+type contextTracer struct {
+	requests []any
+}
+
+func (t *contextTracer) StartCommand(ctx context.Context, dir, name string, args []string) func(output []byte, err error) {
+	t.requests = append(t.requests, ctx.Value(contextKey{}))
+	return func([]byte, error) {}
+}
+
+func TestClientWithContext(t *testing.T) {
+	tracer := &contextTracer{}
+	mock := &MockCommandRunner{Commands: []MockCommand{{Name: "git"}, {Name: "git"}}}
+	// The tracing runner is found under the runners wrapping it
+	runner := NewCachingRunner(NewRecordingRunner(NewTracingRunner(mock, tracer), NewTranscript(io.Discard)), NewResponseCache("", time.Minute))
+	client := NewClientWithRunner(runner)
+
+	request := context.WithValue(context.Background(), contextKey{}, "request")
+	if _, err := client.WithContext(request).CurrentBranch("/repo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CurrentBranch("/repo"); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracer.requests) != 2 || tracer.requests[0] != "request" || tracer.requests[1] != nil {
+		t.Errorf("commands reported with %v, want the request's context and then none", tracer.requests)
+	}
+}
@@ -0,0 +1,178 @@
+// Package jira links Jira issue keys mentioned in standup items, and can look
+// up each issue's title and status to show next to the link.
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TokenEnv holds the Jira API token. With an email configured it is a Jira
+// Cloud API token, otherwise a Data Center personal access token.
+const TokenEnv = "JIRA_API_TOKEN"
+
+// keyPattern matches issue keys such as ABC-123
+var keyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`)
+
+// Issue is the part of a Jira issue shown in standups
+type Issue struct {
+	Key     string
+	Summary string
+	Status  string
+}
+
+// Client reads issues from the Jira REST API
+type Client struct {
+	baseURL    string
+	email      string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the Jira site at baseURL, authenticating
+// with the token in JIRA_API_TOKEN
+func NewClient(baseURL, email string) (*Client, error) {
+	token := os.Getenv(TokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("%s is not set", TokenEnv)
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		email:      email,
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Issue fetches an issue's summary and status
+func (c *Client) Issue(ctx context.Context, key string) (Issue, error) {
+	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,status", c.baseURL, url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Issue{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.email != "" {
+		req.SetBasicAuth(c.email, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Issue{}, fmt.Errorf("Jira request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Issue{}, fmt.Errorf("failed to read Jira response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Issue{}, fmt.Errorf("Jira returned %s for %s", resp.Status, key)
+	}
+
+	var result struct {
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return Issue{}, fmt.Errorf("failed to decode Jira response: %w", err)
+	}
+	return Issue{Key: key, Summary: result.Fields.Summary, Status: result.Fields.Status.Name}, nil
+}
+
+// Linker links the issue keys of a Jira site
+type Linker struct {
+	// BaseURL is the Jira site, e.g. https://acme.atlassian.net
+	BaseURL string
+	// Projects limits linking to these project keys. Without it anything
+	// shaped like a key is linked, including the likes of UTF-8.
+	Projects []string
+}
+
+// Keys returns the unlinked issue keys in an item, in order and without
+// duplicates
+func (l Linker) Keys(item string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, loc := range l.unlinkedKeys(item) {
+		key := item[loc[0]:loc[1]]
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Link turns the issue keys in an item into markdown links to the issues.
+// Keys found in issues are followed by the issue's title and status. Keys
+// that are already links, or part of a URL, are left alone, so linking an
+// item twice changes nothing.
+func (l Linker) Link(item string, issues map[string]Issue) string {
+	baseURL := strings.TrimSuffix(l.BaseURL, "/")
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range l.unlinkedKeys(item) {
+		key := item[loc[0]:loc[1]]
+		b.WriteString(item[last:loc[0]])
+		fmt.Fprintf(&b, "[%s](%s/browse/%s)", key, baseURL, key)
+		if issue, ok := issues[key]; ok && issue.Summary != "" {
+			if issue.Status != "" {
+				fmt.Fprintf(&b, " (%s, %s)", issue.Summary, issue.Status)
+			} else {
+				fmt.Fprintf(&b, " (%s)", issue.Summary)
+			}
+		}
+		last = loc[1]
+	}
+	b.WriteString(item[last:])
+	return b.String()
+}
+
+// unlinkedKeys returns the positions of keys of the linker's projects that
+// aren't link text or part of a URL
+func (l Linker) unlinkedKeys(item string) [][]int {
+	var locs [][]int
+	for _, loc := range keyPattern.FindAllStringIndex(item, -1) {
+		if loc[0] > 0 && strings.ContainsRune("[/=#", rune(item[loc[0]-1])) {
+			continue
+		}
+		if strings.HasPrefix(item[loc[1]:], "](") {
+			continue
+		}
+		if !l.inProjects(item[loc[0]:loc[1]]) {
+			continue
+		}
+		locs = append(locs, loc)
+	}
+	return locs
+}
+
+// inProjects reports whether a key belongs to one of the linker's projects
+func (l Linker) inProjects(key string) bool {
+	if len(l.Projects) == 0 {
+		return true
+	}
+	project, _, _ := strings.Cut(key, "-")
+	for _, p := range l.Projects {
+		if strings.EqualFold(p, project) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLink(t *testing.T) {
+	issues := map[string]Issue{"ABC-1": {Key: "ABC-1", Summary: "Login broken", Status: "In Progress"}}
+
+	tests := []struct {
+		name string
+		item string
+		want string
+	}{
+		{"plain key", "Fixed ABC-2", "Fixed [ABC-2](https://acme.atlassian.net/browse/ABC-2)"},
+		{"enriched key", "ABC-1: tests", "[ABC-1](https://acme.atlassian.net/browse/ABC-1) (Login broken, In Progress): tests"},
+		{"already linked", "[ABC-2](https://acme.atlassian.net/browse/ABC-2) done", "[ABC-2](https://acme.atlassian.net/browse/ABC-2) done"},
+		{"part of a URL", "See https://x.io/browse/ABC-2", "See https://x.io/browse/ABC-2"},
+		{"other project", "Upgraded to UTF-8 and abc-1", "Upgraded to UTF-8 and abc-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linker := Linker{BaseURL: "https://acme.atlassian.net/", Projects: []string{"ABC"}}
+			got := linker.Link(tt.item, issues)
+			if got != tt.want {
+				t.Errorf("Link() = %q, want %q", got, tt.want)
+			}
+			if again := linker.Link(got, issues); again != got {
+				t.Errorf("Link() twice = %q, want %q", again, got)
+			}
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	item := "ABC-1 and XY2-30, again ABC-1, linked [ABC-9](u)"
+	if got := (Linker{}).Keys(item); strings.Join(got, ",") != "ABC-1,XY2-30" {
+		t.Errorf("Keys() = %v, want [ABC-1 XY2-30]", got)
+	}
+	if got := (Linker{Projects: []string{"xy2"}}).Keys(item); strings.Join(got, ",") != "XY2-30" {
+		t.Errorf("Keys() for XY2 = %v, want [XY2-30]", got)
+	}
+}
+
+func TestClientIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@acme.com" || pass != "tok" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/rest/api/2/issue/ABC-1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"key":"ABC-1","fields":{"summary":"Login broken","status":{"name":"Done"}}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv(TokenEnv, "")
+	if _, err := NewClient(server.URL, "me@acme.com"); err == nil {
+		t.Fatal("NewClient() should require JIRA_API_TOKEN")
+	}
+
+	t.Setenv(TokenEnv, "tok")
+	client, err := NewClient(server.URL+"/", "me@acme.com")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	issue, err := client.Issue(context.Background(), "ABC-1")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if issue.Summary != "Login broken" || issue.Status != "Done" {
+		t.Errorf("Issue() = %+v", issue)
+	}
+
+	if _, err := client.Issue(context.Background(), "ABC-404"); err == nil {
+		t.Error("Issue() should fail for a missing issue")
+	}
+}
@@ -0,0 +1,343 @@
+// Package notion publishes markdown documents as pages of a Notion
+// database, for stakeholders who read updates in Notion.
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// TokenEnv holds the token of the Notion integration, which the database
+// must be shared with
+const TokenEnv = "STANDUP_BOT_NOTION_TOKEN"
+
+// apiVersion is the Notion API version requests are made against
+const apiVersion = "2022-06-28"
+
+// maxChildren is how many blocks Notion takes in one request
+const maxChildren = 100
+
+// maxTextLength is the longest text Notion takes in one rich text object
+const maxTextLength = 2000
+
+// DefaultTitleProperty is the title property of a new database
+const DefaultTitleProperty = "Name"
+
+// Client writes pages through the Notion API
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client authenticating with the token in
+// STANDUP_BOT_NOTION_TOKEN
+func NewClient() (*Client, error) {
+	token := os.Getenv(TokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("%s is not set", TokenEnv)
+	}
+	return &Client{
+		baseURL:    "https://api.notion.com/v1",
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// block is a Notion block object
+type block map[string]any
+
+// Publish adds a page titled title, with markdown as its content, to the
+// database, and returns its URL. Pages of the database with the same title
+// are archived, so publishing a document again replaces it.
+// titleProperty is the database's title column; DefaultTitleProperty when
+// empty.
+func (c *Client) Publish(ctx context.Context, database, titleProperty, title, markdown string) (string, error) {
+	if titleProperty == "" {
+		titleProperty = DefaultTitleProperty
+	}
+
+	var existing struct {
+		Results []struct {
+			ID string `json:"id"`
+		} `json:"results"`
+	}
+	query := map[string]any{
+		"filter": map[string]any{"property": titleProperty, "title": map[string]any{"equals": title}},
+	}
+	if err := c.do(ctx, http.MethodPost, "/databases/"+database+"/query", query, &existing); err != nil {
+		return "", err
+	}
+
+	blocks := toBlocks(markdown)
+	first := blocks[:min(len(blocks), maxChildren)]
+	page := map[string]any{
+		"parent": map[string]any{"database_id": database},
+		"properties": map[string]any{
+			titleProperty: map[string]any{"title": richText(title, nil)},
+		},
+		"children": first,
+	}
+	var created struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/pages", page, &created); err != nil {
+		return "", err
+	}
+	for rest := blocks[len(first):]; len(rest) > 0; {
+		n := min(len(rest), maxChildren)
+		if err := c.do(ctx, http.MethodPatch, "/blocks/"+created.ID+"/children", map[string]any{"children": rest[:n]}, nil); err != nil {
+			return "", err
+		}
+		rest = rest[n:]
+	}
+
+	// Archived once the new page is complete, so readers always find one
+	for _, old := range existing.Results {
+		if err := c.do(ctx, http.MethodPatch, "/pages/"+old.ID, map[string]any{"archived": true}, nil); err != nil {
+			return "", err
+		}
+	}
+	return created.URL, nil
+}
+
+// do sends a request to the API, decoding the response into result if given
+func (c *Client) do(ctx context.Context, method, path string, body, result any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode Notion request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Notion-Version", apiVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Notion request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read Notion response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("Notion returned %s: %s", resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("Notion returned %s", resp.Status)
+	}
+	if result != nil {
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("failed to decode Notion response: %w", err)
+		}
+	}
+	return nil
+}
+
+// toBlocks converts markdown into Notion blocks: headings, paragraphs,
+// lists, quotes, code and dividers, with bold, italic, code and links in text
+func toBlocks(markdown string) []block {
+	source := []byte(markdown)
+	root := goldmark.New().Parser().Parse(text.NewReader(source))
+	c := converter{source: source}
+	var blocks []block
+	for n := root.FirstChild(); n != nil; n = n.NextSibling() {
+		blocks = append(blocks, c.blocks(n)...)
+	}
+	return blocks
+}
+
+// converter turns goldmark nodes into Notion blocks
+type converter struct {
+	source []byte
+}
+
+// blocks converts a block node
+func (c converter) blocks(n ast.Node) []block {
+	switch n := n.(type) {
+	case *ast.Heading:
+		// Notion has three levels of heading
+		kind := fmt.Sprintf("heading_%d", min(n.Level, 3))
+		return []block{newBlock(kind, map[string]any{"rich_text": c.inline(n)})}
+	case *ast.Paragraph, *ast.TextBlock:
+		return []block{newBlock("paragraph", map[string]any{"rich_text": c.inline(n)})}
+	case *ast.List:
+		kind := "bulleted_list_item"
+		if n.IsOrdered() {
+			kind = "numbered_list_item"
+		}
+		var items []block
+		for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+			items = append(items, c.listItem(kind, item))
+		}
+		return items
+	case *ast.Blockquote:
+		var lines []string
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			lines = append(lines, c.plain(child))
+		}
+		return []block{newBlock("quote", map[string]any{"rich_text": richText(strings.Join(lines, "\n"), nil)})}
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		code := strings.TrimSuffix(string(n.Lines().Value(c.source)), "\n")
+		return []block{newBlock("code", map[string]any{"rich_text": richText(code, nil), "language": "plain text"})}
+	case *ast.ThematicBreak:
+		return []block{newBlock("divider", map[string]any{})}
+	}
+	if text := c.plain(n); text != "" {
+		return []block{newBlock("paragraph", map[string]any{"rich_text": richText(text, nil)})}
+	}
+	return nil
+}
+
+// listItem converts a list item, whose first paragraph is its text and whose
+// other blocks, such as a nested list, are its children
+func (c converter) listItem(kind string, item ast.Node) block {
+	content := map[string]any{"rich_text": []any{}}
+	var children []block
+	for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+		_, isText := child.(*ast.TextBlock)
+		_, isParagraph := child.(*ast.Paragraph)
+		if (isText || isParagraph) && child == item.FirstChild() {
+			content["rich_text"] = c.inline(child)
+			continue
+		}
+		children = append(children, c.blocks(child)...)
+	}
+	if len(children) > 0 {
+		content["children"] = children
+	}
+	return newBlock(kind, content)
+}
+
+// annotations are the styles of a run of text
+type annotations struct {
+	bold, italic, code bool
+	link               string
+}
+
+// inline converts the inline content of a block into rich text
+func (c converter) inline(n ast.Node) []any {
+	var runs []any
+	var walk func(n ast.Node, style annotations)
+	walk = func(n ast.Node, style annotations) {
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			switch child := child.(type) {
+			case *ast.Text:
+				value := string(child.Value(c.source))
+				if child.HardLineBreak() {
+					value += "\n"
+				} else if child.SoftLineBreak() {
+					value += " "
+				}
+				runs = append(runs, richText(value, &style)...)
+			case *ast.String:
+				runs = append(runs, richText(string(child.Value), &style)...)
+			case *ast.CodeSpan:
+				code := style
+				code.code = true
+				runs = append(runs, richText(c.plain(child), &code)...)
+			case *ast.Emphasis:
+				emphasis := style
+				if child.Level >= 2 {
+					emphasis.bold = true
+				} else {
+					emphasis.italic = true
+				}
+				walk(child, emphasis)
+			case *ast.Link:
+				link := style
+				link.link = string(child.Destination)
+				walk(child, link)
+			case *ast.AutoLink:
+				link := style
+				link.link = string(child.URL(c.source))
+				runs = append(runs, richText(string(child.Label(c.source)), &link)...)
+			default:
+				walk(child, style)
+			}
+		}
+	}
+	walk(n, annotations{})
+	return runs
+}
+
+// plain returns the text of a node without its styling
+func (c converter) plain(n ast.Node) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := node.(type) {
+		case *ast.Text:
+			b.Write(node.Value(c.source))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				b.WriteString("\n")
+			}
+		case *ast.String:
+			b.Write(node.Value)
+		case *ast.AutoLink:
+			b.Write(node.Label(c.source))
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(b.String())
+}
+
+// newBlock creates a block of a type with its content
+func newBlock(kind string, content map[string]any) block {
+	return block{"object": "block", "type": kind, kind: content}
+}
+
+// richText creates rich text objects for a run of text, split into pieces
+// Notion accepts
+func richText(value string, style *annotations) []any {
+	var runs []any
+	for value != "" {
+		piece := value
+		if len(piece) > maxTextLength {
+			// Notion counts UTF-16 code units, so a byte limit is safe; back
+			// up to a character boundary
+			cut := maxTextLength
+			for cut > 0 && !utf8.RuneStart(piece[cut]) {
+				cut--
+			}
+			piece = piece[:cut]
+		}
+		value = value[len(piece):]
+
+		content := map[string]any{"content": piece}
+		run := map[string]any{"type": "text", "text": content}
+		if style != nil {
+			if style.link != "" {
+				content["link"] = map[string]any{"url": style.link}
+			}
+			if style.bold || style.italic || style.code {
+				run["annotations"] = map[string]any{"bold": style.bold, "italic": style.italic, "code": style.code}
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
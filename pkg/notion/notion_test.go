@@ -0,0 +1,104 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestToBlocks(t *testing.T) {
+	blocks := toBlocks("## Monday\n\n**alice**\n\n*Yesterday:*\n- Fixed [ABC-1](https://acme.atlassian.net/browse/ABC-1)\n- `make test`\n\n---\n")
+
+	var kinds []string
+	for _, b := range blocks {
+		kinds = append(kinds, b["type"].(string))
+	}
+	want := "heading_2,paragraph,paragraph,bulleted_list_item,bulleted_list_item,divider"
+	if strings.Join(kinds, ",") != want {
+		t.Fatalf("block types = %v, want %s", kinds, want)
+	}
+
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`{"annotations":{"bold":true,"code":false,"italic":false},"text":{"content":"alice"}`,
+		`"link":{"url":"https://acme.atlassian.net/browse/ABC-1"}`,
+		`"annotations":{"bold":false,"code":true,"italic":false}`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("blocks should contain %s:\n%s", want, data)
+		}
+	}
+}
+
+func TestRichTextSplitsLongText(t *testing.T) {
+	runs := richText(strings.Repeat("é", maxTextLength), nil)
+	if len(runs) != 2 {
+		t.Fatalf("richText() made %d runs, want 2", len(runs))
+	}
+	first := runs[0].(map[string]any)["text"].(map[string]any)["content"].(string)
+	if len(first) != maxTextLength || !strings.HasSuffix(first, "é") {
+		t.Errorf("first run has %d bytes, want a whole character at the limit", len(first))
+	}
+}
+
+func TestPublish(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Notion-Version") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/databases/db1/query":
+			if !strings.Contains(string(body), `"equals":"Daily Standups - 2024-01-15"`) {
+				t.Errorf("query = %s", body)
+			}
+			w.Write([]byte(`{"results":[{"id":"old"}]}`))
+		case "/pages":
+			if !strings.Contains(string(body), `"database_id":"db1"`) || !strings.Contains(string(body), `"Title":{"title"`) {
+				t.Errorf("page = %s", body)
+			}
+			w.Write([]byte(`{"id":"new","url":"https://www.notion.so/new"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv(TokenEnv, "secret")
+	client, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.baseURL = server.URL
+
+	// More blocks than fit in one request
+	markdown := strings.Repeat("- item\n", maxChildren+5)
+	url, err := client.Publish(context.Background(), "db1", "Title", "Daily Standups - 2024-01-15", markdown)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if url != "https://www.notion.so/new" {
+		t.Errorf("Publish() = %q", url)
+	}
+	want := "POST /databases/db1/query,POST /pages,PATCH /blocks/new/children,PATCH /pages/old"
+	if strings.Join(requests, ",") != want {
+		t.Errorf("requests = %v, want %s", requests, want)
+	}
+}
+
+func TestNewClientNeedsToken(t *testing.T) {
+	t.Setenv(TokenEnv, "")
+	if _, err := NewClient(); err == nil {
+		t.Error("NewClient() should fail without a token")
+	}
+}
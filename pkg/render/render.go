@@ -0,0 +1,95 @@
+// Package render writes command results in the format chosen with --output.
+// YAML is converted from the JSON encoding, so both formats use the same
+// field names and a result's schema is defined once, by its json tags.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format
+type Format string
+
+// Output formats. Table is the human-readable output each command prints by
+// default.
+const (
+	Table Format = "table"
+	JSON  Format = "json"
+	YAML  Format = "yaml"
+)
+
+// ParseFormat checks an output format name; an empty name is Table
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case "", Table:
+		return Table, nil
+	case JSON, YAML:
+		return Format(name), nil
+	}
+	return "", fmt.Errorf("invalid output format '%s': expected 'table', 'json' or 'yaml'", name)
+}
+
+// Marshal encodes v as indented JSON or as YAML
+func Marshal(format Format, v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	switch format {
+	case JSON:
+		return data, nil
+	case YAML:
+		return jsonToYAML(data)
+	}
+	return nil, fmt.Errorf("%s output has no encoding", format)
+}
+
+// Print writes v to w as JSON or YAML, ending with a newline
+func Print(w io.Writer, format Format, v interface{}) error {
+	data, err := Marshal(format, v)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// jsonToYAML re-encodes JSON as block-style YAML, keeping the key order
+func jsonToYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("failed to convert output to YAML: %w", err)
+	}
+	blockStyle(&node)
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, fmt.Errorf("failed to convert output to YAML: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// blockStyle clears the flow style JSON is parsed with, and the quoting of
+// strings that don't need it
+func blockStyle(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		if node.Style == yaml.DoubleQuotedStyle {
+			node.Style = 0
+		}
+	} else {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
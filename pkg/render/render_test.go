@@ -0,0 +1,51 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+)
+
+type result struct {
+	Name    string   `json:"name"`
+	Count   int      `json:"count"`
+	Version string   `json:"version"`
+	Items   []string `json:"items,omitempty"`
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"": Table, "table": Table, "json": JSON, "yaml": YAML} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %s, %v, want %s", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat() should reject unknown formats")
+	}
+}
+
+func TestPrint(t *testing.T) {
+	v := result{Name: "alice", Count: 3, Version: "1.0", Items: []string{"a: b", "c"}}
+
+	var out bytes.Buffer
+	if err := Print(&out, YAML, v); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	// Keys keep the JSON names and order; strings that YAML would read as
+	// another type stay quoted
+	want := "name: alice\ncount: 3\nversion: \"1.0\"\nitems:\n  - 'a: b'\n  - c\n"
+	if out.String() != want {
+		t.Errorf("Print(YAML) =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := Print(&out, JSON, v); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	if out.String() != "{\n  \"name\": \"alice\",\n  \"count\": 3,\n  \"version\": \"1.0\",\n  \"items\": [\n    \"a: b\",\n    \"c\"\n  ]\n}\n" {
+		t.Errorf("Print(JSON) = %s", out.String())
+	}
+
+	if err := Print(&out, Table, v); err == nil {
+		t.Error("Print(Table) should fail")
+	}
+}
@@ -0,0 +1,143 @@
+package standup
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UntaggedProject is the group for items without a project tag
+const UntaggedProject = "Other"
+
+var (
+	// bracketTagRegex matches a leading "[Project]" tag
+	bracketTagRegex = regexp.MustCompile(`^\[([^\]]+)\]\s*`)
+	// hashTagRegex matches "#project" tags anywhere in an item
+	hashTagRegex = regexp.MustCompile(`(^|\s)#([A-Za-z0-9][A-Za-z0-9_-]*)`)
+)
+
+// placeholderItems are written for empty answers and aren't accomplishments
+var placeholderItems = map[string]bool{
+	"nothing to report": true,
+	"nothing planned":   true,
+	"none":              true,
+}
+
+// Accomplishment is a completed item from a standup
+type Accomplishment struct {
+	Date    time.Time
+	Project string
+	Text    string
+}
+
+// Accomplishments extracts completed work ("Yesterday" items) from entries
+// dated within [since, until]. Zero bounds are open. Items are tagged with a
+// leading "[Project]" or a "#project" hashtag; duplicates are dropped.
+func Accomplishments(entries []Entry, since, until time.Time) []Accomplishment {
+	// Walk entries oldest first so a repeated item keeps its first date
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	var result []Accomplishment
+	seen := make(map[string]bool)
+
+	for _, entry := range sorted {
+		day := entry.Date.Format("2006-01-02")
+		if !since.IsZero() && day < since.Format("2006-01-02") {
+			continue
+		}
+		if !until.IsZero() && day > until.Format("2006-01-02") {
+			continue
+		}
+
+		for _, item := range entry.Yesterday {
+			item = strings.TrimSpace(item)
+			if item == "" || placeholderItems[strings.ToLower(item)] {
+				continue
+			}
+
+			project, text := ProjectTag(item)
+			key := strings.ToLower(project + "\x00" + text)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			result = append(result, Accomplishment{Date: entry.Date, Project: project, Text: text})
+		}
+	}
+
+	return result
+}
+
+// ProjectTag returns an item's project tag ("[Project] item" or "#project")
+// and the item without it. Untagged items belong to UntaggedProject.
+func ProjectTag(item string) (string, string) {
+	if match := bracketTagRegex.FindStringSubmatch(item); match != nil {
+		return strings.TrimSpace(match[1]), strings.TrimSpace(item[len(match[0]):])
+	}
+	if match := hashTagRegex.FindStringSubmatch(item); match != nil {
+		text := hashTagRegex.ReplaceAllString(item, "$1")
+		return match[2], strings.Join(strings.Fields(text), " ")
+	}
+	return UntaggedProject, item
+}
+
+// FormatBrag renders accomplishments as a markdown brag document, grouped by
+// month and then by project
+func FormatBrag(userName string, accomplishments []Accomplishment, since time.Time) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Brag Document: %s\n\n", userName)
+	if len(accomplishments) == 0 {
+		b.WriteString("No accomplishments recorded for this period.\n")
+		return b.String()
+	}
+
+	from := since
+	if from.IsZero() {
+		from = accomplishments[0].Date
+	}
+	fmt.Fprintf(&b, "%d accomplishments since %s.\n", len(accomplishments), from.Format("January 2, 2006"))
+
+	// Accomplishments are sorted by date, so months appear in order
+	var months []string
+	byMonth := make(map[string]map[string][]Accomplishment)
+	for _, a := range accomplishments {
+		month := a.Date.Format("January 2006")
+		if byMonth[month] == nil {
+			byMonth[month] = make(map[string][]Accomplishment)
+			months = append(months, month)
+		}
+		byMonth[month][a.Project] = append(byMonth[month][a.Project], a)
+	}
+
+	for _, month := range months {
+		fmt.Fprintf(&b, "\n## %s\n", month)
+
+		projects := make([]string, 0, len(byMonth[month]))
+		for project := range byMonth[month] {
+			projects = append(projects, project)
+		}
+		sort.Slice(projects, func(i, j int) bool {
+			// Untagged work goes last
+			if (projects[i] == UntaggedProject) != (projects[j] == UntaggedProject) {
+				return projects[j] == UntaggedProject
+			}
+			return strings.ToLower(projects[i]) < strings.ToLower(projects[j])
+		})
+
+		for _, project := range projects {
+			fmt.Fprintf(&b, "\n### %s\n\n", project)
+			for _, a := range byMonth[month][project] {
+				fmt.Fprintf(&b, "- %s (%s)\n", a.Text, a.Date.Format("Jan 2"))
+			}
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package standup
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccomplishments(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	entries := []Entry{
+		{Date: day(3), Yesterday: []string{"[Payments] Shipped refunds", "Nothing to report"}},
+		{Date: day(2), Yesterday: []string{"Fixed flaky test #ci", "[payments] shipped refunds"}},
+		{Date: day(1), Yesterday: []string{"Onboarding"}},
+	}
+
+	got := Accomplishments(entries, day(2), time.Time{})
+	if len(got) != 2 {
+		t.Fatalf("Accomplishments() = %+v, want 2 items", got)
+	}
+
+	if got[0].Project != "ci" || got[0].Text != "Fixed flaky test" {
+		t.Errorf("first = %+v, want ci/Fixed flaky test", got[0])
+	}
+	if got[1].Project != "payments" || got[1].Text != "shipped refunds" {
+		t.Errorf("second = %+v, want the earliest payments item (duplicates dropped)", got[1])
+	}
+}
+
+func TestFormatBrag(t *testing.T) {
+	accomplishments := []Accomplishment{
+		{Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Project: UntaggedProject, Text: "Mentored intern"},
+		{Date: time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC), Project: "Payments", Text: "Shipped refunds"},
+		{Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Project: "CI", Text: "Halved build time"},
+	}
+
+	doc := FormatBrag("Alice", accomplishments, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	checks := []string{
+		"# Brag Document: Alice",
+		"3 accomplishments since January 1, 2024.",
+		"## January 2024\n\n### Payments\n\n- Shipped refunds (Jan 9)\n\n### Other\n\n- Mentored intern (Jan 5)\n",
+		"## February 2024\n\n### CI\n\n- Halved build time (Feb 1)\n",
+	}
+	for _, want := range checks {
+		if !strings.Contains(doc, want) {
+			t.Errorf("FormatBrag() missing %q:\n%s", want, doc)
+		}
+	}
+	if strings.Index(doc, "January 2024") > strings.Index(doc, "February 2024") {
+		t.Error("months should be in chronological order")
+	}
+
+	if empty := FormatBrag("Alice", nil, time.Time{}); !strings.Contains(empty, "No accomplishments") {
+		t.Errorf("FormatBrag() with no items = %q", empty)
+	}
+}
@@ -0,0 +1,165 @@
+package standup
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// APIVersion is the version of the JSON output envelope. The flat JSONOutput
+// shape is version v1.
+const APIVersion = "v2"
+
+// Envelope kinds, one per result type
+const (
+	KindStandup            = "Standup"
+	KindStandupPullRequest = "StandupPullRequest"
+	KindMerge              = "StandupMerge"
+	KindStatus             = "StandupStatus"
+	KindRefresh            = "StandupPullRequestRefresh"
+	KindReviewRequest      = "StandupReviewRequest"
+	KindError              = "Error"
+)
+
+// Envelope is the versioned JSON output: a kind naming the payload type and
+// the payload itself
+type Envelope struct {
+	APIVersion string      `json:"apiVersion"`
+	Kind       string      `json:"kind"`
+	Data       interface{} `json:"data"`
+}
+
+// StandupData is the payload of a recorded standup
+type StandupData struct {
+	Message   string              `json:"message"`
+	Date      string              `json:"date"`
+	User      string              `json:"user"`
+	Yesterday []string            `json:"yesterday"`
+	Today     []string            `json:"today"`
+	Blockers  string              `json:"blockers"`
+	Sections  map[string][]string `json:"sections,omitempty"`
+	FilePath  string              `json:"filePath"`
+	CommitSHA string              `json:"commitSha,omitempty"`
+	// AlreadySubmitted is set when the same standup was already recorded,
+	// so nothing changed
+	AlreadySubmitted bool `json:"alreadySubmitted,omitempty"`
+}
+
+// PullRequestData describes a standup pull request
+type PullRequestData struct {
+	Number string `json:"number"`
+	URL    string `json:"url"`
+	// AutoMerge is set when auto-merge was turned on as the standup was recorded
+	AutoMerge bool `json:"autoMerge,omitempty"`
+}
+
+// StandupPullRequestData is the payload of a standup recorded through a pull request
+type StandupPullRequestData struct {
+	StandupData
+	PullRequest PullRequestData `json:"pullRequest"`
+}
+
+// ErrorData is the payload of a failed command
+type ErrorData struct {
+	Message string `json:"message"`
+	Date    string `json:"date"`
+}
+
+// MergeData is the payload of a merged daily pull request
+type MergeData struct {
+	Date        string          `json:"date"`
+	PullRequest PullRequestData `json:"pullRequest"`
+	// Auto is set when auto-merge was turned on instead of merging
+	Auto bool `json:"auto,omitempty"`
+	// Warnings are problems that didn't stop the merge
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// RefreshData is the payload of a refreshed daily pull request
+type RefreshData struct {
+	Date        string          `json:"date"`
+	PullRequest PullRequestData `json:"pullRequest"`
+}
+
+// ReviewRequestData is the payload of a review requested from the approvers
+type ReviewRequestData struct {
+	Date        string          `json:"date"`
+	PullRequest PullRequestData `json:"pullRequest"`
+	Reviewers   []string        `json:"reviewers"`
+}
+
+// StatusData is the payload of 'status'
+type StatusData struct {
+	Date      string `json:"date"`
+	User      string `json:"user"`
+	Submitted bool   `json:"submitted"`
+	// OutOfOffice describes the absence covering the day, if any
+	OutOfOffice string                 `json:"outOfOffice,omitempty"`
+	PullRequest *PullRequestStatusData `json:"pullRequest,omitempty"`
+	Team        []TeammateData         `json:"team"`
+}
+
+// PullRequestStatusData describes the day's pull request and its checks
+type PullRequestStatusData struct {
+	PullRequestData
+	State  string      `json:"state"`
+	Merged bool        `json:"merged"`
+	Checks []CheckData `json:"checks"`
+}
+
+// CheckData is a CI check on a pull request
+type CheckData struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// TeammateData says whether a teammate has submitted
+type TeammateData struct {
+	User        string `json:"user"`
+	Submitted   bool   `json:"submitted"`
+	OutOfOffice string `json:"outOfOffice,omitempty"`
+}
+
+// NewEnvelope wraps a payload of the given kind in the current version
+func NewEnvelope(kind string, data interface{}) Envelope {
+	return Envelope{APIVersion: APIVersion, Kind: kind, Data: data}
+}
+
+// Envelope converts the output to the versioned shape. Failed outputs are
+// always of kind Error; otherwise kind selects the payload type.
+func (o JSONOutput) Envelope(kind string) Envelope {
+	if !o.Success {
+		return Envelope{APIVersion: APIVersion, Kind: KindError, Data: ErrorData{Message: o.Error, Date: o.Date}}
+	}
+
+	standup := StandupData{
+		Message:   o.Message,
+		Date:      o.Date,
+		User:      o.User,
+		Yesterday: o.Yesterday,
+		Today:     o.Today,
+		Blockers:  o.Blockers,
+		Sections:  o.Sections,
+		FilePath:  o.FilePath,
+		CommitSHA: o.CommitSHA,
+
+		AlreadySubmitted: o.AlreadySubmitted,
+	}
+
+	if kind == KindStandupPullRequest {
+		data := StandupPullRequestData{
+			StandupData: standup,
+			PullRequest: PullRequestData{Number: o.PRNumber, URL: o.PRUrl, AutoMerge: o.AutoMerge},
+		}
+		return Envelope{APIVersion: APIVersion, Kind: kind, Data: data}
+	}
+	return Envelope{APIVersion: APIVersion, Kind: KindStandup, Data: standup}
+}
+
+// FormatEnvelope formats a versioned output as JSON
+func FormatEnvelope(envelope Envelope) (string, error) {
+	jsonBytes, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON output: %w", err)
+	}
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,71 @@
+package standup
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnvelope(t *testing.T) {
+	output := JSONOutput{
+		Success:   true,
+		Message:   "Standup recorded",
+		Date:      "2025-07-31",
+		User:      "alice",
+		Yesterday: []string{"Task A"},
+		Today:     []string{"Task B"},
+		Blockers:  "None",
+		FilePath:  "/path/to/alice.md",
+		PRNumber:  "42",
+		PRUrl:     "https://github.com/org/repo/pull/42",
+	}
+
+	jsonStr, err := FormatEnvelope(output.Envelope(KindStandupPullRequest))
+	if err != nil {
+		t.Fatalf("FormatEnvelope() error = %v", err)
+	}
+
+	var parsed struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Data       struct {
+			User        string `json:"user"`
+			FilePath    string `json:"filePath"`
+			PullRequest struct {
+				Number string `json:"number"`
+				URL    string `json:"url"`
+			} `json:"pullRequest"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &parsed); err != nil {
+		t.Fatalf("failed to parse output JSON: %v", err)
+	}
+
+	if parsed.APIVersion != APIVersion || parsed.Kind != KindStandupPullRequest {
+		t.Errorf("got apiVersion %q kind %q", parsed.APIVersion, parsed.Kind)
+	}
+	if parsed.Data.User != "alice" || parsed.Data.FilePath != "/path/to/alice.md" {
+		t.Errorf("unexpected standup data: %+v", parsed.Data)
+	}
+	if parsed.Data.PullRequest.Number != "42" || parsed.Data.PullRequest.URL != output.PRUrl {
+		t.Errorf("unexpected pull request data: %+v", parsed.Data.PullRequest)
+	}
+
+	// A direct commit has no pull request
+	if data, ok := output.Envelope(KindStandup).Data.(StandupData); !ok || data.User != "alice" {
+		t.Errorf("Envelope(KindStandup).Data = %#v, want StandupData", output.Envelope(KindStandup).Data)
+	}
+}
+
+func TestEnvelopeError(t *testing.T) {
+	output := JSONOutput{Success: false, Error: "push failed", Date: "2025-07-31"}
+
+	// Failures are errors regardless of the requested kind
+	envelope := output.Envelope(KindStandup)
+	if envelope.Kind != KindError {
+		t.Errorf("Kind = %q, want %q", envelope.Kind, KindError)
+	}
+	data, ok := envelope.Data.(ErrorData)
+	if !ok || data.Message != "push failed" {
+		t.Errorf("Data = %#v, want ErrorData with the message", envelope.Data)
+	}
+}
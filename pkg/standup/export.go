@@ -0,0 +1,67 @@
+package standup
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExportHeader names the columns of an export
+var ExportHeader = []string{"user", "date", "section", "item"}
+
+// ExportRows flattens entries dated within [since, until] into one row per
+// user, date, section and item, ordered by date then user. Zero bounds are
+// open. Placeholders for empty answers and "None" blockers are left out so
+// counts in a spreadsheet are real work.
+func ExportRows(entries map[string][]Entry, since, until time.Time) [][]string {
+	var rows [][]string
+	for user, userEntries := range entries {
+		for _, entry := range userEntries {
+			day := entry.Date.Format("2006-01-02")
+			if (!since.IsZero() && day < since.Format("2006-01-02")) || (!until.IsZero() && day > until.Format("2006-01-02")) {
+				continue
+			}
+
+			add := func(section string, items []string) {
+				for _, item := range items {
+					item = strings.TrimSpace(item)
+					if item != "" && !placeholderItems[strings.ToLower(item)] {
+						rows = append(rows, []string{user, day, section, item})
+					}
+				}
+			}
+			add("Yesterday", entry.Yesterday)
+			add("Today", entry.Today)
+			if blocker := strings.TrimSpace(entry.Blockers); !noBlockers[strings.ToLower(blocker)] {
+				rows = append(rows, []string{user, day, "Blockers", blocker})
+			}
+			for _, section := range entry.Sections {
+				add(section.Name, section.Items)
+			}
+		}
+	}
+
+	// Items keep their order within a day
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i][1] != rows[j][1] {
+			return rows[i][1] < rows[j][1]
+		}
+		return rows[i][0] < rows[j][0]
+	})
+	return rows
+}
+
+// WriteCSV writes the export rows with a header as CSV
+func WriteCSV(w io.Writer, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(ExportHeader); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
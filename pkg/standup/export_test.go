@@ -0,0 +1,50 @@
+package standup
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportRows(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	entries := map[string][]Entry{
+		"bob": {
+			{Date: day(15), Yesterday: []string{"Reviews"}, Today: []string{"Nothing planned"}, Blockers: "Waiting, on \"keys\""},
+		},
+		"alice": {
+			{Date: day(16), Yesterday: []string{"Second"}, Blockers: "None"},
+			{Date: day(15), Yesterday: []string{"B", "A"}, Today: []string{"C"}, Blockers: "none",
+				Sections: []Section{{Name: "Wins", Items: []string{"Closed bugs"}}}},
+			{Date: day(10), Yesterday: []string{"Too early"}},
+		},
+	}
+
+	rows := ExportRows(entries, day(15), time.Time{})
+	want := [][]string{
+		{"alice", "2024-01-15", "Yesterday", "B"},
+		{"alice", "2024-01-15", "Yesterday", "A"},
+		{"alice", "2024-01-15", "Today", "C"},
+		{"alice", "2024-01-15", "Wins", "Closed bugs"},
+		{"bob", "2024-01-15", "Yesterday", "Reviews"},
+		{"bob", "2024-01-15", "Blockers", "Waiting, on \"keys\""},
+		{"alice", "2024-01-16", "Yesterday", "Second"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("ExportRows() = %v, want %v", rows, want)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+	}
+
+	var b strings.Builder
+	if err := WriteCSV(&b, rows[4:6]); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	wantCSV := "user,date,section,item\nbob,2024-01-15,Yesterday,Reviews\nbob,2024-01-15,Blockers,\"Waiting, on \"\"keys\"\"\"\n"
+	if b.String() != wantCSV {
+		t.Errorf("WriteCSV() = %q, want %q", b.String(), wantCSV)
+	}
+}
@@ -0,0 +1,513 @@
+package standup
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// Archive formats that can be imported
+const (
+	ImportSlack    = "slack"
+	ImportCSV      = "csv"
+	ImportMarkdown = "markdown"
+)
+
+// ImportedEntry is a standup read from an archive, with its author
+type ImportedEntry struct {
+	User  string
+	Entry *Entry
+}
+
+// DetectImportFormat guesses the format of an archive from its path: a
+// directory or .json file is a Slack export, a .csv file a Geekbot or
+// Standuply export, and a .md file markdown
+func DetectImportFormat(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return ImportSlack, nil
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ImportSlack, nil
+	case ".csv":
+		return ImportCSV, nil
+	case ".md", ".markdown", ".txt":
+		return ImportMarkdown, nil
+	}
+	return "", fmt.Errorf("can't tell the format of %s; pass --format %s, %s or %s", path, ImportSlack, ImportCSV, ImportMarkdown)
+}
+
+// slackMessage is a message in a Slack channel export
+type slackMessage struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype"`
+	User        string `json:"user"`
+	Text        string `json:"text"`
+	TS          string `json:"ts"`
+	UserProfile struct {
+		Name string `json:"name"`
+	} `json:"user_profile"`
+}
+
+// slackUser is a member in the users.json of a Slack export
+type slackUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ImportSlack reads the standups posted to a channel of a Slack export: the
+// channel's directory of daily JSON files, or one of the files. Members are
+// named by their Slack username, from users.json at the root of the export.
+// Messages without a yesterday or today section are chat, and left out.
+func (m *Manager) ImportSlack(path string) ([]ImportedEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	channelDir := filepath.Dir(path)
+	if info.IsDir() {
+		channelDir = path
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+	names, err := slackUsers(filepath.Join(filepath.Dir(channelDir), "users.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var imported []ImportedEntry
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var messages []slackMessage
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("%s isn't a Slack channel export: %w", filepath.Base(file), err)
+		}
+		for _, msg := range messages {
+			// Joins, bot posts and the like aren't standups
+			if msg.Type != "message" || (msg.Subtype != "" && msg.Subtype != "thread_broadcast") {
+				continue
+			}
+			seconds, _, _ := strings.Cut(msg.TS, ".")
+			unix, err := strconv.ParseInt(seconds, 10, 64)
+			if err != nil {
+				continue
+			}
+			entry, ok := m.parseStandupText(slackText(msg.Text, names))
+			if !ok {
+				continue
+			}
+			entry.Date = m.standupDay(time.Unix(unix, 0))
+
+			user := names[msg.User]
+			if user == "" {
+				user = msg.UserProfile.Name
+			}
+			if user == "" {
+				user = msg.User
+			}
+			imported = append(imported, ImportedEntry{User: user, Entry: entry})
+		}
+	}
+	return combineImported(imported), nil
+}
+
+// slackUsers maps member IDs to usernames from a Slack export's users.json.
+// An export without one names members by their message profiles.
+func slackUsers(path string) (map[string]string, error) {
+	names := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var users []slackUser
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+	return names, nil
+}
+
+// slackMarkup matches Slack's <@U123>, <#C123|channel> and <url|label> markup
+var slackMarkup = regexp.MustCompile(`<([@#!]?)([^|>]+)(?:\|([^>]*))?>`)
+
+// slackText turns Slack message markup into plain text: mentions become
+// @username and links their label or address
+func slackText(text string, names map[string]string) string {
+	text = slackMarkup.ReplaceAllStringFunc(text, func(s string) string {
+		parts := slackMarkup.FindStringSubmatch(s)
+		kind, target, label := parts[1], parts[2], parts[3]
+		switch {
+		case kind == "@" && names[target] != "":
+			return "@" + names[target]
+		case label != "":
+			if kind == "#" {
+				return "#" + label
+			}
+			return label
+		}
+		return target
+	})
+	return html.UnescapeString(text)
+}
+
+// csvDateLayouts are the date formats accepted in CSV exports
+var csvDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"1/2/2006 15:04",
+	"01/02/2006",
+	"1/2/2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+// ImportCSV reads a CSV export from Geekbot, Standuply or a spreadsheet.
+// The header names the columns: the member (Member, User, Username or
+// Name), the day (Date or Timestamp), and either one column per question or
+// Question and Answer columns with a row per answer. Questions are matched
+// to sections by their wording, e.g. "What did you do yesterday?".
+func (m *Manager) ImportCSV(r io.Reader) ([]ImportedEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(rows) < 2 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	userCol, dateCol, questionCol, answerCol := -1, -1, -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "member", "user", "username", "user name", "name", "respondent":
+			userCol = i
+		case "date", "timestamp", "time", "submitted", "submitted at", "created at":
+			dateCol = i
+		case "question":
+			questionCol = i
+		case "answer", "response":
+			answerCol = i
+		}
+	}
+	if userCol < 0 || dateCol < 0 {
+		return nil, fmt.Errorf("the CSV header needs a member column (Member, User or Name) and a Date column")
+	}
+
+	// Rows are grouped by member and day, since the Question and Answer
+	// layout has a row per answer
+	type standup struct {
+		user    string
+		date    time.Time
+		answers map[string][]string
+	}
+	var standups []*standup
+	byDay := make(map[string]*standup)
+	for n, row := range rows[1:] {
+		cell := func(i int) string {
+			if i < 0 || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+		user := cell(userCol)
+		if user == "" {
+			continue
+		}
+		date, err := m.parseImportDate(cell(dateCol))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", n+2, err)
+		}
+
+		key := strings.ToLower(user) + "/" + date.Format("2006-01-02")
+		day, ok := byDay[key]
+		if !ok {
+			day = &standup{user: user, date: date, answers: make(map[string][]string)}
+			byDay[key] = day
+			standups = append(standups, day)
+		}
+		if questionCol >= 0 && answerCol >= 0 {
+			if section := m.importSection(cell(questionCol)); section != "" {
+				day.answers[section] = append(day.answers[section], importItems(cell(answerCol))...)
+			}
+			continue
+		}
+		for i, question := range header {
+			if section := m.importSection(question); section != "" && i != userCol && i != dateCol {
+				day.answers[section] = append(day.answers[section], importItems(cell(i))...)
+			}
+		}
+	}
+
+	var imported []ImportedEntry
+	for _, day := range standups {
+		if entry := m.importEntry(day.answers); entry != nil {
+			entry.Date = day.date
+			imported = append(imported, ImportedEntry{User: day.user, Entry: entry})
+		}
+	}
+	return combineImported(imported), nil
+}
+
+// parseImportDate reads a date in one of csvDateLayouts. A timestamp is
+// dated like a submission made at that time.
+func (m *Manager) parseImportDate(value string) (time.Time, error) {
+	location := m.location
+	if location == nil {
+		location = time.Local
+	}
+	for _, layout := range csvDateLayouts {
+		t, err := time.ParseInLocation(layout, value, location)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(layout, "15") {
+			return m.standupDay(t), nil
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+}
+
+// markdownDate finds a date in a markdown heading
+var markdownDate = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// ImportMarkdown reads standups kept by hand in a markdown file: each
+// starts with a heading holding its date (YYYY-MM-DD), followed by
+// sections such as "Yesterday:" or "### Today". They belong to user, or to
+// the name in a "# Name's Standups" title like standup-bot's own files.
+func (m *Manager) ImportMarkdown(r io.Reader, user string) ([]ImportedEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var imported []ImportedEntry
+	var date time.Time
+	var text strings.Builder
+	flush := func() {
+		if date.IsZero() {
+			return
+		}
+		if entry, ok := m.parseStandupText(text.String()); ok {
+			entry.Date = date
+			imported = append(imported, ImportedEntry{User: user, Entry: entry})
+		}
+		text.Reset()
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if title, ok := strings.CutPrefix(trimmed, "# "); ok && date.IsZero() {
+			if name, ok := strings.CutSuffix(strings.TrimSpace(title), "'s Standups"); ok && user == "" {
+				user = name
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			if day := markdownDate.FindString(trimmed); day != "" {
+				parsed, err := time.Parse("2006-01-02", day)
+				if err != nil {
+					return nil, fmt.Errorf("invalid date in %q: %w", trimmed, err)
+				}
+				flush()
+				date = parsed
+				continue
+			}
+		}
+		text.WriteString(line)
+		text.WriteString("\n")
+	}
+	flush()
+
+	if user == "" && len(imported) > 0 {
+		return nil, fmt.Errorf("the markdown has no \"# Name's Standups\" title; pass --user to say whose standups they are")
+	}
+	return combineImported(imported), nil
+}
+
+// parseStandupText reads the sections of a standup written as free text,
+// with headings such as "*Yesterday*", "Today:" or "### Blockers" and an
+// item per line. It reports false when there is no yesterday or today.
+func (m *Manager) parseStandupText(text string) (*Entry, bool) {
+	answers := make(map[string][]string)
+	var current string
+	for _, line := range strings.Split(text, "\n") {
+		if heading, rest, ok := splitImportHeading(line); ok {
+			if section := m.importSection(heading); section != "" {
+				current = section
+				answers[current] = append(answers[current], importItems(rest)...)
+				continue
+			}
+		}
+		if current != "" {
+			answers[current] = append(answers[current], importItems(line)...)
+		}
+	}
+	entry := m.importEntry(answers)
+	return entry, entry != nil
+}
+
+// splitImportHeading splits a line that may be a section heading into the
+// heading and any answer on the same line, as in "Blockers: none"
+func splitImportHeading(line string) (heading, rest string, ok bool) {
+	s := strings.TrimSpace(line)
+	if s == "" || strings.HasPrefix(s, "- ") || strings.HasPrefix(s, "* ") || strings.HasPrefix(s, "• ") {
+		return "", "", false
+	}
+	if strings.HasPrefix(s, "#") {
+		return strings.TrimSuffix(strings.TrimSpace(strings.TrimLeft(s, "#")), ":"), "", true
+	}
+	for _, mark := range []string{"**", "__", "*", "_"} {
+		if inner, ok := strings.CutPrefix(s, mark); ok {
+			if heading, rest, ok := strings.Cut(inner, mark); ok {
+				heading = strings.TrimSuffix(strings.TrimSpace(heading), ":")
+				return heading, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ":")), true
+			}
+		}
+	}
+	if heading, rest, ok := strings.Cut(s, ":"); ok && len(heading) <= 80 {
+		return strings.TrimSpace(heading), strings.TrimSpace(rest), true
+	}
+	return "", "", false
+}
+
+// importSection returns the section a heading or question stands for: a
+// section of the team's template by name, or a standard one by its wording.
+// It is empty for anything else.
+func (m *Manager) importSection(heading string) string {
+	heading = strings.TrimSpace(heading)
+	for _, spec := range m.template {
+		if !types.IsStandardSection(spec.Name) && strings.EqualFold(spec.Name, heading) {
+			return spec.Name
+		}
+	}
+
+	lower := strings.ToLower(heading)
+	containsAny := func(words ...string) bool {
+		for _, w := range words {
+			if strings.Contains(lower, w) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case containsAny("block", "imped", "obstacle", "stuck"):
+		return "Blockers"
+	case containsAny("yesterday", "did you", "done", "accomplish", "completed"):
+		return "Yesterday"
+	case containsAny("today", "will you", "plan", "next"):
+		return "Today"
+	}
+	return ""
+}
+
+// importItems splits an answer into items, one per line, without their
+// bullets. Separator lines such as "---" aren't items.
+func importItems(answer string) []string {
+	var items []string
+	for _, line := range strings.Split(answer, "\n") {
+		item := strings.TrimSpace(line)
+		if strings.Trim(item, "-*_= ") == "" {
+			continue
+		}
+		for _, bullet := range []string{"- ", "* ", "• ", "◦ "} {
+			item = strings.TrimPrefix(item, bullet)
+		}
+		if n := strings.IndexAny(item, ".)"); n > 0 && n <= 3 && strings.HasPrefix(item[n+1:], " ") {
+			if _, err := strconv.Atoi(item[:n]); err == nil {
+				item = item[n+1:]
+			}
+		}
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// importEntry builds an entry from the items of each section, or returns
+// nil when there is no yesterday or today
+func (m *Manager) importEntry(answers map[string][]string) *Entry {
+	if len(answers["Yesterday"]) == 0 && len(answers["Today"]) == 0 {
+		return nil
+	}
+	entry := &Entry{
+		Yesterday: answers["Yesterday"],
+		Today:     answers["Today"],
+		Blockers:  strings.Join(answers["Blockers"], " "),
+	}
+	if entry.Blockers == "" {
+		entry.Blockers = "None"
+	}
+	for _, spec := range m.template {
+		if items := answers[spec.Name]; len(items) > 0 && !types.IsStandardSection(spec.Name) {
+			entry.Sections = append(entry.Sections, Section{Name: spec.Name, Items: items})
+		}
+	}
+	return entry
+}
+
+// standupDay returns the day a standup posted at t is for
+func (m *Manager) standupDay(t time.Time) time.Time {
+	if m.location != nil {
+		t = t.In(m.location)
+	}
+	day := types.StandupDay(t, m.cutoff)
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+}
+
+// combineImported merges the standups a member posted more than once on a
+// day, such as a correction, and sorts them oldest first, so that saving
+// them in order leaves each file newest first
+func combineImported(imported []ImportedEntry) []ImportedEntry {
+	var combined []ImportedEntry
+	seen := make(map[string]int)
+	for _, item := range imported {
+		key := strings.ToLower(item.User) + "/" + item.Entry.Date.Format("2006-01-02")
+		if i, ok := seen[key]; ok {
+			combined[i].Entry.Merge(item.Entry)
+			continue
+		}
+		seen[key] = len(combined)
+		combined = append(combined, item)
+	}
+	sort.SliceStable(combined, func(i, j int) bool {
+		return combined[i].Entry.Date.Before(combined[j].Entry.Date)
+	})
+	return combined
+}
@@ -0,0 +1,171 @@
+package standup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+func TestImportSlack(t *testing.T) {
+	export := t.TempDir()
+	channel := filepath.Join(export, "standup")
+	if err := os.Mkdir(channel, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(export, "users.json"): `[{"id": "U1", "name": "alice"}, {"id": "U2", "name": "bob"}]`,
+		filepath.Join(channel, "2024-01-15.json"): `[
+			{"type": "message", "subtype": "channel_join", "user": "U2", "text": "<@U2> has joined the channel", "ts": "1705305600.000100"},
+			{"type": "message", "user": "U1", "text": "*Yesterday*\n• Fixed the login bug\n*Today*\n• Pair with <@U2> on <https://example.com/pr/1|the release>\n*Blockers*\nNone", "ts": "1705312800.000200"},
+			{"type": "message", "user": "U2", "text": "Morning all &amp; happy Monday", "ts": "1705312900.000300"}
+		]`,
+		filepath.Join(channel, "2024-01-16.json"): `[
+			{"type": "message", "user": "U2", "user_profile": {"name": "bob"}, "text": "Yesterday: Reviewed docs\nToday: Write tests\nBlockers: Waiting on CI", "ts": "1705399200.000100"}
+		]`,
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := NewManager(t.TempDir())
+	manager.SetLocation(time.UTC)
+	imported, err := manager.ImportSlack(channel)
+	if err != nil {
+		t.Fatalf("ImportSlack() error = %v", err)
+	}
+	if len(imported) != 2 {
+		t.Fatalf("ImportSlack() = %d standups, want 2 (chat and joins left out)", len(imported))
+	}
+
+	alice := imported[0]
+	if alice.User != "alice" || alice.Entry.Date.Format("2006-01-02") != "2024-01-15" {
+		t.Errorf("first standup = %s on %s, want alice on 2024-01-15", alice.User, alice.Entry.Date.Format("2006-01-02"))
+	}
+	if want := "Pair with @bob on the release"; len(alice.Entry.Today) != 1 || alice.Entry.Today[0] != want {
+		t.Errorf("Today = %q, want %q", alice.Entry.Today, want)
+	}
+	if alice.Entry.Blockers != "None" {
+		t.Errorf("Blockers = %q, want None", alice.Entry.Blockers)
+	}
+
+	bob := imported[1]
+	if bob.User != "bob" || bob.Entry.Blockers != "Waiting on CI" || bob.Entry.Yesterday[0] != "Reviewed docs" {
+		t.Errorf("second standup = %s %+v", bob.User, bob.Entry)
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{
+			name: "a column per question",
+			csv: "Timestamp,Member,What did you do yesterday?,What will you do today?,Anything blocking your progress?\n" +
+				"2024-01-15 09:30:00,alice,\"- Fixed the login bug\n- Reviewed PRs\",Ship the release,No\n",
+		},
+		{
+			name: "a row per answer",
+			csv: "Date,User,Question,Answer\n" +
+				"01/15/2024,alice,What did you do yesterday?,\"1. Fixed the login bug\n2. Reviewed PRs\"\n" +
+				"01/15/2024,alice,What will you do today?,Ship the release\n" +
+				"01/15/2024,alice,Any blockers?,No\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewManager(t.TempDir())
+			manager.SetLocation(time.UTC)
+			imported, err := manager.ImportCSV(strings.NewReader(tt.csv))
+			if err != nil {
+				t.Fatalf("ImportCSV() error = %v", err)
+			}
+			if len(imported) != 1 {
+				t.Fatalf("ImportCSV() = %d standups, want 1", len(imported))
+			}
+			got := imported[0]
+			if got.User != "alice" || got.Entry.Date.Format("2006-01-02") != "2024-01-15" {
+				t.Errorf("standup = %s on %s", got.User, got.Entry.Date.Format("2006-01-02"))
+			}
+			if !slicesEqual(got.Entry.Yesterday, []string{"Fixed the login bug", "Reviewed PRs"}) {
+				t.Errorf("Yesterday = %q", got.Entry.Yesterday)
+			}
+			if !slicesEqual(got.Entry.Today, []string{"Ship the release"}) || got.Entry.Blockers != "No" {
+				t.Errorf("Today = %q, Blockers = %q", got.Entry.Today, got.Entry.Blockers)
+			}
+		})
+	}
+
+	if _, err := NewManager(t.TempDir()).ImportCSV(strings.NewReader("Who,When\nalice,today\n")); err == nil {
+		t.Error("ImportCSV() without member and date columns succeeded")
+	}
+}
+
+func TestImportMarkdown(t *testing.T) {
+	markdown := `# Standup notes
+
+## Mon 2024-01-15
+Yesterday:
+- Fixed the login bug
+
+Today:
+- Ship the release
+Wins: Closed the epic
+
+### 2024-01-16
+**Done**
+* Shipped it
+**Next**
+* Retro
+`
+	manager := NewManager(t.TempDir())
+	manager.SetTemplate([]types.SectionSpec{{Name: "Yesterday"}, {Name: "Today"}, {Name: "Wins"}, {Name: "Blockers"}})
+	imported, err := manager.ImportMarkdown(strings.NewReader(markdown), "alice")
+	if err != nil {
+		t.Fatalf("ImportMarkdown() error = %v", err)
+	}
+	if len(imported) != 2 {
+		t.Fatalf("ImportMarkdown() = %d standups, want 2", len(imported))
+	}
+	first := imported[0].Entry
+	if !slicesEqual(first.Today, []string{"Ship the release"}) || len(first.Sections) != 1 || first.Sections[0].Items[0] != "Closed the epic" {
+		t.Errorf("first standup = %+v", first)
+	}
+	if second := imported[1].Entry; !slicesEqual(second.Yesterday, []string{"Shipped it"}) || !slicesEqual(second.Today, []string{"Retro"}) {
+		t.Errorf("second standup = %+v", second)
+	}
+
+	if _, err := manager.ImportMarkdown(strings.NewReader(markdown), ""); err == nil {
+		t.Error("ImportMarkdown() without an author succeeded")
+	}
+}
+
+func TestImportMarkdownRoundTrip(t *testing.T) {
+	source := NewManager(t.TempDir())
+	for _, day := range []int{15, 16} {
+		entry := &Entry{Date: time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), Yesterday: []string{"Item"}, Today: []string{"Plan"}, Blockers: "None"}
+		if err := source.SaveEntry(entry, "Alice"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := os.Open(source.UserPath("Alice"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	imported, err := NewManager(t.TempDir()).ImportMarkdown(f, "")
+	if err != nil {
+		t.Fatalf("ImportMarkdown() error = %v", err)
+	}
+	if len(imported) != 2 || imported[0].User != "Alice" || imported[0].Entry.Date.Day() != 15 {
+		t.Errorf("ImportMarkdown() = %+v, want Alice's two standups oldest first", imported)
+	}
+}
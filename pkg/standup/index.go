@@ -0,0 +1,228 @@
+package standup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// indexVersion is bumped when the layout of the index or of Entry changes,
+// so an index written by an older version is rebuilt rather than misread
+const indexVersion = 1
+
+// index caches every user's entries, parsed, in a local file. A user's
+// entries are read again only when one of their standup files has changed
+// size or modification time since they were indexed.
+type index struct {
+	Version int                    `json:"version"`
+	Format  string                 `json:"format"`
+	Users   map[string]indexedUser `json:"users"`
+}
+
+// indexedUser is a user's entries, newest first, and the files they were
+// read from
+type indexedUser struct {
+	Files   []indexedFile `json:"files"`
+	Entries []Entry       `json:"entries"`
+}
+
+// indexedFile identifies the version of a standup file an index was built
+// from
+type indexedFile struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// SetIndex keeps an index of the standups at path, which history, stats and
+// team queries read instead of parsing every file. The index is local, and
+// brought up to date as it is read; an empty path reads the files directly.
+func (m *Manager) SetIndex(path string) {
+	m.indexPath = path
+}
+
+// RebuildIndex discards the index and builds it again from every user's
+// standup files. It returns the number of entries indexed.
+func (m *Manager) RebuildIndex() (int, error) {
+	if m.indexPath == "" {
+		return 0, fmt.Errorf("no index is configured")
+	}
+	if err := os.Remove(m.indexPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("failed to remove the index: %w", err)
+	}
+	all, err := m.LoadAllEntries()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, entries := range all {
+		count += len(entries)
+	}
+	return count, nil
+}
+
+// indexedEntries returns the entries of users from the index, reading the
+// files of those whose standups changed since they were indexed, and saves
+// the index when it was brought up to date
+func (m *Manager) indexedEntries(users []string) (map[string][]Entry, error) {
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+
+	idx := m.readIndex()
+	fresh := make([]indexedUser, len(users))
+	stale := make([]bool, len(users))
+	err := forEachUser(users, func(i int, user string) error {
+		files, err := m.userFiles(user)
+		if err != nil {
+			return fmt.Errorf("failed to load standups for %s: %w", user, err)
+		}
+		if cached, ok := idx.Users[user]; ok && sameFiles(cached.Files, files) {
+			fresh[i] = cached
+			return nil
+		}
+		entries, err := m.readEntries(user)
+		if err != nil {
+			return fmt.Errorf("failed to load standups for %s: %w", user, err)
+		}
+		fresh[i] = indexedUser{Files: files, Entries: entries}
+		stale[i] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	all := make(map[string][]Entry, len(users))
+	changed := false
+	for i, user := range users {
+		all[user] = fresh[i].Entries
+		if stale[i] {
+			idx.Users[user] = fresh[i]
+			changed = true
+		}
+	}
+	if changed {
+		// The index only saves work; reading goes on without it
+		_ = m.writeIndex(idx)
+	}
+	return all, nil
+}
+
+// readIndex reads the index, or starts an empty one when there is none or
+// it was written for another format or version
+func (m *Manager) readIndex() *index {
+	empty := &index{Version: indexVersion, Format: string(m.format), Users: map[string]indexedUser{}}
+	data, err := os.ReadFile(m.indexPath)
+	if err != nil {
+		return empty
+	}
+	var idx index
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Version != indexVersion || idx.Format != string(m.format) || idx.Users == nil {
+		return empty
+	}
+	return &idx
+}
+
+// writeIndex saves the index through a temporary file, so a reader never
+// sees it half written
+func (m *Manager) writeIndex(idx *index) error {
+	if err := os.MkdirAll(filepath.Dir(m.indexPath), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(m.indexPath), ".index-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), m.indexPath)
+}
+
+// userFiles returns the size and modification time of every file holding a
+// user's standups
+func (m *Manager) userFiles(userName string) ([]indexedFile, error) {
+	var files []indexedFile
+	if m.format.Structured() {
+		entries, err := m.fs.ReadDir(m.userDir(userName))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to read standup directory: %w", err)
+		}
+		for _, f := range entries {
+			if f.IsDir() || filepath.Ext(f.Name()) != m.format.Extension() {
+				continue
+			}
+			info, err := f.Info()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read standup entry: %w", err)
+			}
+			files = append(files, indexedFile{Path: f.Name(), Size: info.Size(), ModTime: info.ModTime()})
+		}
+		return files, nil
+	}
+
+	paths, err := m.markdownFiles(userName)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		info, err := m.fs.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read standup file: %w", err)
+		}
+		rel, err := filepath.Rel(m.repoPath, path)
+		if err != nil {
+			rel = path
+		}
+		files = append(files, indexedFile{Path: rel, Size: info.Size(), ModTime: info.ModTime()})
+	}
+	return files, nil
+}
+
+// sameFiles reports whether two listings of a user's files match
+func sameFiles(a, b []indexedFile) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Path != b[i].Path || a[i].Size != b[i].Size || !a[i].ModTime.Equal(b[i].ModTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// indexedTeamEntries returns every user's entry for date from the index,
+// sorted by user
+func (m *Manager) indexedTeamEntries(users []string, date time.Time) ([]StoredEntry, error) {
+	all, err := m.indexedEntries(users)
+	if err != nil {
+		return nil, err
+	}
+	day := date.Format("2006-01-02")
+	var team []StoredEntry
+	for _, user := range users {
+		for _, entry := range all[user] {
+			if entry.Date.Format("2006-01-02") == day {
+				team = append(team, NewStoredEntry(&entry, user))
+				break
+			}
+		}
+	}
+	return team, nil
+}
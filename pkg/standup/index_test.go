@@ -0,0 +1,104 @@
+package standup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+func TestIndex(t *testing.T) {
+	for _, format := range []types.StorageFormat{types.StorageMarkdown, types.StorageYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			repoPath := t.TempDir()
+			indexPath := filepath.Join(t.TempDir(), "index.json")
+			manager := NewManagerWithFormat(repoPath, format)
+			manager.SetIndex(indexPath)
+
+			monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+			tuesday := monday.AddDate(0, 0, 1)
+			for _, user := range []string{"alice", "bob"} {
+				if err := manager.SaveEntry(&Entry{Date: monday, Today: []string{"Plan the sprint"}, Blockers: "None"}, user); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			all, err := manager.LoadAllEntries()
+			if err != nil {
+				t.Fatalf("LoadAllEntries() error = %v", err)
+			}
+			if len(all["alice"]) != 1 || len(all["bob"]) != 1 {
+				t.Fatalf("LoadAllEntries() = %+v, want an entry each", all)
+			}
+			if _, err := os.Stat(indexPath); err != nil {
+				t.Fatalf("index not written: %v", err)
+			}
+
+			// An entry saved since is picked up from the changed files
+			if err := manager.SaveEntry(&Entry{Date: tuesday, Today: []string{"Ship it"}, Blockers: "Waiting on review"}, "alice"); err != nil {
+				t.Fatal(err)
+			}
+			entries, err := manager.LoadEntries("alice")
+			if err != nil {
+				t.Fatalf("LoadEntries() error = %v", err)
+			}
+			if len(entries) != 2 || !entries[0].Date.Equal(tuesday) || entries[0].Blockers != "Waiting on review" {
+				t.Errorf("LoadEntries() = %+v, want Tuesday's entry first", entries)
+			}
+
+			team, err := manager.LoadTeamEntries(tuesday)
+			if err != nil {
+				t.Fatalf("LoadTeamEntries() error = %v", err)
+			}
+			if len(team) != 1 || team[0].User != "alice" {
+				t.Errorf("LoadTeamEntries() = %+v, want only alice", team)
+			}
+
+			count, err := manager.RebuildIndex()
+			if err != nil || count != 3 {
+				t.Errorf("RebuildIndex() = %d, %v, want 3", count, err)
+			}
+		})
+	}
+}
+
+func TestIndexReused(t *testing.T) {
+	repoPath := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+	manager := NewManagerWithFormat(repoPath, types.StorageMarkdown)
+	manager.SetIndex(indexPath)
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	if err := manager.SaveEntry(&Entry{Date: date, Today: []string{"Plan the sprint"}, Blockers: "None"}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.LoadAllEntries(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unchanged files are answered from the index, without parsing them
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	marked := strings.Replace(string(data), "Plan the sprint", "From the index", 1)
+	if err := os.WriteFile(indexPath, []byte(marked), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := manager.LoadEntries("alice")
+	if err != nil || len(entries) != 1 || entries[0].Today[0] != "From the index" {
+		t.Errorf("LoadEntries() = %+v, %v, want the indexed entry", entries, err)
+	}
+
+	// A corrupt index is rebuilt
+	if err := os.WriteFile(indexPath, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = manager.LoadEntries("alice")
+	if err != nil || len(entries) != 1 || entries[0].Today[0] != "Plan the sprint" {
+		t.Errorf("LoadEntries() = %+v, %v, want the entry read from its file", entries, err)
+	}
+}
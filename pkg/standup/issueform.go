@@ -0,0 +1,201 @@
+package standup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IssueFormPath is where GitHub looks for the standup issue form
+const IssueFormPath = ".github/ISSUE_TEMPLATE/standup.yml"
+
+// IssueLabel marks issues created from the standup form
+const IssueLabel = "standup"
+
+// DailyIssueLabel marks the daily issues teammates comment their standups on
+const DailyIssueLabel = "standup-daily"
+
+// dailyIssueTitlePrefix starts the title of a daily issue, which ends with
+// its day
+const dailyIssueTitlePrefix = "Standups for "
+
+// IssueNameField is the form field for a standup name that differs from the
+// submitter's GitHub username
+const IssueNameField = "Name"
+
+// issueNoResponse is what GitHub writes for fields left empty
+const issueNoResponse = "_No response_"
+
+// issueForm is a GitHub issue form
+type issueForm struct {
+	Name        string           `yaml:"name"`
+	Description string           `yaml:"description"`
+	Title       string           `yaml:"title"`
+	Labels      []string         `yaml:"labels"`
+	Body        []issueFormField `yaml:"body"`
+}
+
+// issueFormField is an input or textarea in an issue form
+type issueFormField struct {
+	Type        string                `yaml:"type"`
+	ID          string                `yaml:"id"`
+	Attributes  issueFormAttributes   `yaml:"attributes"`
+	Validations *issueFormValidations `yaml:"validations,omitempty"`
+}
+
+type issueFormAttributes struct {
+	Label       string `yaml:"label"`
+	Description string `yaml:"description,omitempty"`
+	Placeholder string `yaml:"placeholder,omitempty"`
+}
+
+type issueFormValidations struct {
+	Required bool `yaml:"required"`
+}
+
+// IssueForm returns a GitHub issue form asking the same questions as the
+// interactive prompt, including the template's sections, so standups can be
+// submitted from a browser or the GitHub mobile app
+func (m *Manager) IssueForm() ([]byte, error) {
+	form := issueForm{
+		Name:        "Standup",
+		Description: "Submit your daily standup",
+		Title:       "Standup",
+		Labels:      []string{IssueLabel},
+	}
+
+	for _, q := range m.questions() {
+		field := issueFormField{
+			Type:       "textarea",
+			ID:         issueFieldID(q.name),
+			Attributes: issueFormAttributes{Label: q.name, Description: q.prompt},
+		}
+		if q.single {
+			field.Type = "input"
+		} else {
+			field.Attributes.Description += " One item per line."
+		}
+		if q.name == "Blockers" {
+			field.Attributes.Placeholder = "None"
+		}
+		if q.required {
+			field.Validations = &issueFormValidations{Required: true}
+		}
+		form.Body = append(form.Body, field)
+	}
+
+	form.Body = append(form.Body, issueFormField{
+		Type: "input",
+		ID:   "name",
+		Attributes: issueFormAttributes{
+			Label:       IssueNameField,
+			Description: "Your standup name, if it isn't your GitHub username",
+		},
+	})
+
+	data, err := yaml.Marshal(form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode issue form: %w", err)
+	}
+	return append([]byte("# Generated by 'standup-bot issues template'\n"), data...), nil
+}
+
+// issueFieldID turns a section name into a form field ID
+func issueFieldID(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), "-")
+}
+
+// ParseIssueForm converts the body of an issue created from the standup form
+// into an entry, validated like JSON input. It also returns the Name field,
+// which is empty unless the submitter filled it in.
+func (m *Manager) ParseIssueForm(body string) (string, *Entry, error) {
+	answers := parseIssueFormBody(body)
+
+	input := JSONInput{
+		Yesterday: answers["yesterday"],
+		Today:     answers["today"],
+		Blockers:  strings.Join(answers["blockers"], " "),
+	}
+	for _, q := range m.questions() {
+		if standardName(q.name) != "" {
+			continue
+		}
+		if items := answers[strings.ToLower(q.name)]; len(items) > 0 {
+			if input.Sections == nil {
+				input.Sections = make(map[string]SectionItems)
+			}
+			input.Sections[q.name] = items
+		}
+	}
+
+	entry, err := input.Entry(m.template)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var name string
+	if items := answers[strings.ToLower(IssueNameField)]; len(items) > 0 {
+		name = items[0]
+	}
+	return name, entry, nil
+}
+
+// DailyIssueTitle returns the title of the daily issue for a day
+func DailyIssueTitle(date time.Time) string {
+	return dailyIssueTitlePrefix + date.Format("2006-01-02")
+}
+
+// ParseDailyIssueTitle returns the day of a daily issue from its title, or
+// false if it isn't a daily issue's
+func ParseDailyIssueTitle(title string) (time.Time, bool) {
+	day, ok := strings.CutPrefix(strings.TrimSpace(title), dailyIssueTitlePrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation("2006-01-02", day, time.Local)
+	return date, err == nil
+}
+
+// DailyIssueBody returns the description of a daily issue: how to comment a
+// standup, with an empty form to copy that ParseIssueForm reads
+func (m *Manager) DailyIssueBody(date time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Comment on this issue to submit your standup for %s. Copy the form below into\n", date.Format("2006-01-02"))
+	b.WriteString("your comment and fill it in, one item per line. Add a `### Name` section if\n")
+	b.WriteString("your standup name isn't your GitHub username.\n\n")
+	b.WriteString("```markdown\n")
+	for i, q := range m.questions() {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n", q.name)
+	}
+	b.WriteString("```\n\n")
+	b.WriteString("Post a new comment to replace your standup. It is added to the day's pull request.\n")
+	return b.String()
+}
+
+// parseIssueFormBody splits an issue form body into its answers, keyed by
+// lowercased field label. GitHub renders each field as a "### Label" heading
+// followed by the answer; each non-empty line is an item.
+func parseIssueFormBody(body string) map[string][]string {
+	answers := make(map[string][]string)
+	var current string
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if heading, ok := strings.CutPrefix(line, "### "); ok {
+			current = strings.ToLower(strings.TrimSpace(heading))
+			continue
+		}
+		item := strings.TrimSpace(line)
+		item = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(item, "- "), "* "))
+		if current == "" || item == "" || item == issueNoResponse {
+			continue
+		}
+		answers[current] = append(answers[current], item)
+	}
+	return answers
+}
@@ -0,0 +1,83 @@
+package standup
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+func TestIssueForm(t *testing.T) {
+	manager := NewManager("/repo")
+	manager.SetTemplate([]types.SectionSpec{
+		{Name: "Learnings", Prompt: "What did you learn?"},
+		{Name: "Focus %", Single: true, Required: true},
+	})
+
+	form, err := manager.IssueForm()
+	if err != nil {
+		t.Fatalf("IssueForm() error = %v", err)
+	}
+	text := string(form)
+	for _, want := range []string{"labels:\n    - standup", "label: Yesterday", "label: Learnings", "What did you learn?", "id: focus", "required: true", "label: Name"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("IssueForm() should contain %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestParseIssueForm(t *testing.T) {
+	manager := NewManager("/repo")
+	manager.SetTemplate([]types.SectionSpec{{Name: "Learnings"}})
+
+	body := "### Yesterday\r\n\r\nFixed the login redirect\r\n- Reviewed PRs\r\n\r\n### Today\r\n\r\nShip billing\r\n\r\n" +
+		"### Blockers\r\n\r\n_No response_\r\n\r\n### Learnings\r\n\r\n* Go generics\r\n\r\n### Name\r\n\r\n_No response_\r\n"
+	name, entry, err := manager.ParseIssueForm(body)
+	if err != nil {
+		t.Fatalf("ParseIssueForm() error = %v", err)
+	}
+	if name != "" {
+		t.Errorf("name = %q, want empty when not given", name)
+	}
+	if strings.Join(entry.Yesterday, "|") != "Fixed the login redirect|Reviewed PRs" || strings.Join(entry.Today, "|") != "Ship billing" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if entry.Blockers != "None" {
+		t.Errorf("Blockers = %q, want None", entry.Blockers)
+	}
+	if len(entry.Sections) != 1 || entry.Sections[0].Items[0] != "Go generics" {
+		t.Errorf("Sections = %+v", entry.Sections)
+	}
+
+	name, _, err = manager.ParseIssueForm("### Today\n\nShip it\n\n### Name\n\nAlice Smith\n")
+	if err != nil || name != "Alice Smith" {
+		t.Errorf("ParseIssueForm() name = %q, %v, want Alice Smith", name, err)
+	}
+
+	if _, _, err := manager.ParseIssueForm("### Blockers\n\nNone\n"); err == nil {
+		t.Error("ParseIssueForm() should reject a form without yesterday or today items")
+	}
+}
+
+func TestDailyIssue(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	title := DailyIssueTitle(date)
+	if got, ok := ParseDailyIssueTitle(title); !ok || !got.Equal(date) {
+		t.Errorf("ParseDailyIssueTitle(%q) = %v, %v, want %v", title, got, ok, date)
+	}
+	for _, other := range []string{"Standup", "Standups for next week"} {
+		if _, ok := ParseDailyIssueTitle(other); ok {
+			t.Errorf("ParseDailyIssueTitle(%q) should fail", other)
+		}
+	}
+
+	manager := NewManager("/repo")
+	manager.SetTemplate([]types.SectionSpec{{Name: "Learnings"}})
+	body := manager.DailyIssueBody(date)
+	for _, want := range []string{"2024-01-15", "### Yesterday", "### Today", "### Blockers", "### Learnings", "### Name"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("DailyIssueBody() should contain %q:\n%s", want, body)
+		}
+	}
+}
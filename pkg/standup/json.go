@@ -7,13 +7,38 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
 )
 
 // JSONInput represents the structure for JSON input
 type JSONInput struct {
-	Yesterday []string `json:"yesterday"`
-	Today     []string `json:"today"`
-	Blockers  string   `json:"blockers"`
+	Yesterday []string                `json:"yesterday"`
+	Today     []string                `json:"today"`
+	Blockers  string                  `json:"blockers"`
+	Sections  map[string]SectionItems `json:"sections,omitempty"`
+}
+
+// SectionItems holds the items of a template section. It accepts either a
+// single string or a list of strings in JSON.
+type SectionItems []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *SectionItems) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		if strings.TrimSpace(single) != "" {
+			*s = SectionItems{single}
+		}
+		return nil
+	}
+
+	var items []string
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("section must be a string or a list of strings")
+	}
+	*s = items
+	return nil
 }
 
 // JSONOutput represents the structure for JSON output
@@ -30,6 +55,27 @@ type JSONOutput struct {
 	CommitSHA string    `json:"commit_sha,omitempty"`
 	PRNumber  string    `json:"pr_number,omitempty"`
 	PRUrl     string    `json:"pr_url,omitempty"`
+	// AutoMerge is set when auto-merge was turned on for the PR
+	AutoMerge bool `json:"auto_merge,omitempty"`
+	// Streak is the user's current run of working days with a standup
+	Streak int `json:"streak,omitempty"`
+	// AlreadySubmitted is set when the same standup was already recorded,
+	// so nothing changed
+	AlreadySubmitted bool `json:"already_submitted,omitempty"`
+
+	Sections map[string][]string `json:"sections,omitempty"`
+}
+
+// SectionsMap returns the entry's extra sections keyed by name, or nil if there are none
+func SectionsMap(entry *Entry) map[string][]string {
+	if len(entry.Sections) == 0 {
+		return nil
+	}
+	sections := make(map[string][]string, len(entry.Sections))
+	for _, s := range entry.Sections {
+		sections[s.Name] = s.Items
+	}
+	return sections
 }
 
 
@@ -47,6 +93,12 @@ type CommitInfo struct {
 // - File path to a JSON file
 // - "-" to read from stdin
 func ParseJSONInput(jsonStr string) (*Entry, error) {
+	return ParseJSONInputWithTemplate(jsonStr, nil)
+}
+
+// ParseJSONInputWithTemplate parses JSON input, accepting the extra sections
+// defined by the template
+func ParseJSONInputWithTemplate(jsonStr string, template []types.SectionSpec) (*Entry, error) {
 	var jsonData []byte
 	var err error
 
@@ -72,23 +124,103 @@ func ParseJSONInput(jsonStr string) (*Entry, error) {
 	if err := json.Unmarshal(jsonData, &input); err != nil {
 		return nil, fmt.Errorf("invalid JSON input: %w", err)
 	}
+	return input.Entry(template)
+}
 
+// Entry validates the input against the template and converts it to an
+// entry dated now
+func (input JSONInput) Entry(template []types.SectionSpec) (*Entry, error) {
 	// Validate input
 	if len(input.Yesterday) == 0 && len(input.Today) == 0 {
 		return nil, fmt.Errorf("at least one of 'yesterday' or 'today' must have entries")
 	}
 
+	// Enforce questions the template marks as required
+	if err := checkRequired(input, template); err != nil {
+		return nil, err
+	}
+
 	// Set default blockers if empty
 	if input.Blockers == "" {
 		input.Blockers = "None"
 	}
 
-	return &Entry{
+	entry := &Entry{
 		Date:      time.Now(),
 		Yesterday: input.Yesterday,
 		Today:     input.Today,
 		Blockers:  input.Blockers,
-	}, nil
+	}
+
+	sections, err := templateSections(input.Sections, template)
+	if err != nil {
+		return nil, err
+	}
+	entry.Sections = sections
+
+	return entry, nil
+}
+
+// templateSections orders the input sections by the template and rejects
+// sections the template doesn't define
+func templateSections(input map[string]SectionItems, template []types.SectionSpec) ([]Section, error) {
+	remaining := make(map[string]SectionItems, len(input))
+	for name, items := range input {
+		remaining[strings.ToLower(name)] = items
+	}
+
+	var sections []Section
+	for _, spec := range template {
+		if types.IsStandardSection(spec.Name) {
+			continue
+		}
+		key := strings.ToLower(spec.Name)
+		if items, ok := remaining[key]; ok {
+			if len(items) > 0 {
+				sections = append(sections, Section{Name: spec.Name, Items: items})
+			}
+			delete(remaining, key)
+		}
+	}
+
+	for name := range input {
+		if _, ok := remaining[strings.ToLower(name)]; ok {
+			return nil, fmt.Errorf("unknown section '%s' (not defined in the template)", name)
+		}
+	}
+
+	return sections, nil
+}
+
+// checkRequired returns an error if a required section has no content
+func checkRequired(input JSONInput, template []types.SectionSpec) error {
+	for _, spec := range template {
+		if !spec.Required {
+			continue
+		}
+
+		var empty bool
+		switch standardName(spec.Name) {
+		case "Yesterday":
+			empty = len(input.Yesterday) == 0
+		case "Today":
+			empty = len(input.Today) == 0
+		case "Blockers":
+			empty = strings.TrimSpace(input.Blockers) == ""
+		default:
+			empty = true
+			for name, items := range input.Sections {
+				if strings.EqualFold(name, spec.Name) && len(items) > 0 {
+					empty = false
+				}
+			}
+		}
+
+		if empty {
+			return fmt.Errorf("section '%s' is required", spec.Name)
+		}
+	}
+	return nil
 }
 
 // FormatJSONOutput formats the output as JSON
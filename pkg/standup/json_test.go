@@ -5,6 +5,8 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
 )
 
 func TestParseJSONInput(t *testing.T) {
@@ -164,6 +166,89 @@ func TestParseJSONInput(t *testing.T) {
 	}
 }
 
+func TestParseJSONInputWithTemplate(t *testing.T) {
+	template := []types.SectionSpec{
+		{Name: "Learnings"},
+		{Name: "Focus %", Single: true},
+	}
+
+	tests := []struct {
+		name     string
+		jsonStr  string
+		wantErr  bool
+		errMsg   string
+		sections []Section
+	}{
+		{
+			name:    "list and string sections ordered by template",
+			jsonStr: `{"today": ["Task"], "sections": {"focus %": "80", "Learnings": ["Go", "SQL"]}}`,
+			sections: []Section{
+				{Name: "Learnings", Items: []string{"Go", "SQL"}},
+				{Name: "Focus %", Items: []string{"80"}},
+			},
+		},
+		{
+			name:    "unknown section",
+			jsonStr: `{"today": ["Task"], "sections": {"Shoutouts": ["Bob"]}}`,
+			wantErr: true,
+			errMsg:  "unknown section 'Shoutouts'",
+		},
+		{
+			name:    "invalid section value",
+			jsonStr: `{"today": ["Task"], "sections": {"Learnings": 5}}`,
+			wantErr: true,
+			errMsg:  "invalid JSON input",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := ParseJSONInputWithTemplate(tt.jsonStr, template)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error but got none")
+				}
+				if !contains(err.Error(), tt.errMsg) {
+					t.Errorf("expected error containing '%s', got '%s'", tt.errMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(entry.Sections) != len(tt.sections) {
+				t.Fatalf("got sections %v, want %v", entry.Sections, tt.sections)
+			}
+			for i, s := range tt.sections {
+				if entry.Sections[i].Name != s.Name || !slicesEqual(entry.Sections[i].Items, s.Items) {
+					t.Errorf("section %d = %v, want %v", i, entry.Sections[i], s)
+				}
+			}
+		})
+	}
+
+	// Required sections, including standard ones, must have content
+	required := []types.SectionSpec{
+		{Name: "Blockers", Required: true},
+		{Name: "Learnings", Required: true},
+	}
+	if _, err := ParseJSONInputWithTemplate(`{"today": ["Task"], "sections": {"Learnings": ["Go"]}}`, required); err == nil || !contains(err.Error(), "section 'Blockers' is required") {
+		t.Errorf("expected required blockers error, got %v", err)
+	}
+	if _, err := ParseJSONInputWithTemplate(`{"today": ["Task"], "blockers": "None"}`, required); err == nil || !contains(err.Error(), "section 'Learnings' is required") {
+		t.Errorf("expected required section error, got %v", err)
+	}
+	if _, err := ParseJSONInputWithTemplate(`{"today": ["Task"], "sections": {"today": ["x"]}}`, required[:1]); err == nil {
+		t.Error("standard sections should not be accepted under sections")
+	}
+
+	// Without a template, sections are rejected
+	if _, err := ParseJSONInput(`{"today": ["Task"], "sections": {"Learnings": ["Go"]}}`); err == nil {
+		t.Error("ParseJSONInput() should reject sections when no template is configured")
+	}
+}
+
 func TestFormatJSONOutput(t *testing.T) {
 	output := JSONOutput{
 		Success:   true,
@@ -0,0 +1,139 @@
+package standup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// oooDir holds each user's out-of-office periods, inside stand-ups/ so it is
+// managed like the standups themselves
+const oooDir = ".ooo"
+
+// Absence is a period a user is out of office. Standups aren't expected on
+// its days.
+type Absence struct {
+	// From and To are the first and last days, as YYYY-MM-DD
+	From   string `yaml:"from" json:"from"`
+	To     string `yaml:"to" json:"to"`
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// NewAbsence creates an absence from its first to its last day
+func NewAbsence(from, to time.Time, reason string) (Absence, error) {
+	if to.Before(from) {
+		return Absence{}, fmt.Errorf("the last day (%s) is before the first (%s)", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+	return Absence{From: from.Format("2006-01-02"), To: to.Format("2006-01-02"), Reason: strings.TrimSpace(reason)}, nil
+}
+
+// Covers reports whether day falls within the absence
+func (a Absence) Covers(day time.Time) bool {
+	d := day.Format("2006-01-02")
+	return d >= a.From && d <= a.To
+}
+
+// String describes the absence, e.g. "2024-07-01 to 2024-07-12 (Vacation)"
+func (a Absence) String() string {
+	s := a.From
+	if a.To != a.From {
+		s += " to " + a.To
+	}
+	if a.Reason != "" {
+		s += " (" + a.Reason + ")"
+	}
+	return s
+}
+
+// OutOfOffice returns the absence covering day, if any
+func OutOfOffice(absences []Absence, day time.Time) (Absence, bool) {
+	for _, a := range absences {
+		if a.Covers(day) {
+			return a, true
+		}
+	}
+	return Absence{}, false
+}
+
+// AbsencePath returns the file holding a user's absences
+func (m *Manager) AbsencePath(userName string) string {
+	return filepath.Join(m.repoPath, "stand-ups", oooDir, strings.ToLower(userName)+".yaml")
+}
+
+// LoadAbsences returns a user's absences, earliest first
+func (m *Manager) LoadAbsences(userName string) ([]Absence, error) {
+	data, err := m.fs.ReadFile(m.AbsencePath(userName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read out-of-office file: %w", err)
+	}
+
+	var absences []Absence
+	if err := yaml.Unmarshal(data, &absences); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(m.AbsencePath(userName)), err)
+	}
+	return absences, nil
+}
+
+// AddAbsence records an absence for a user. An absence with the same days
+// is replaced, so running 'ooo' again updates the reason.
+func (m *Manager) AddAbsence(userName string, absence Absence) error {
+	absences, err := m.LoadAbsences(userName)
+	if err != nil {
+		return err
+	}
+
+	kept := []Absence{absence}
+	for _, a := range absences {
+		if a.From != absence.From || a.To != absence.To {
+			kept = append(kept, a)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].From < kept[j].From })
+
+	data, err := yaml.Marshal(kept)
+	if err != nil {
+		return fmt.Errorf("failed to encode out-of-office file: %w", err)
+	}
+	path := m.AbsencePath(userName)
+	if err := m.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create out-of-office directory: %w", err)
+	}
+	data = append([]byte("# Out-of-office periods, written by 'standup-bot ooo'\n"), data...)
+	if err := m.fs.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write out-of-office file: %w", err)
+	}
+	return nil
+}
+
+// TeamAbsences returns everyone's absences by user
+func (m *Manager) TeamAbsences() (map[string][]Absence, error) {
+	files, err := m.fs.ReadDir(filepath.Join(m.repoPath, "stand-ups", oooDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read out-of-office directory: %w", err)
+	}
+
+	team := make(map[string][]Absence)
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".yaml" {
+			continue
+		}
+		user := strings.TrimSuffix(f.Name(), ".yaml")
+		absences, err := m.LoadAbsences(user)
+		if err != nil {
+			return nil, err
+		}
+		team[user] = absences
+	}
+	return team, nil
+}
@@ -0,0 +1,62 @@
+package standup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+func TestAbsences(t *testing.T) {
+	repo := t.TempDir()
+	manager := NewManager(repo)
+	day := func(d int) time.Time { return time.Date(2024, 7, d, 0, 0, 0, 0, time.UTC) }
+
+	if _, err := NewAbsence(day(5), day(1), ""); err == nil {
+		t.Error("NewAbsence() should reject a last day before the first")
+	}
+
+	vacation, _ := NewAbsence(day(8), day(12), "Vacation")
+	sick, _ := NewAbsence(day(2), day(2), "Sick")
+	for _, a := range []Absence{vacation, sick} {
+		if err := manager.AddAbsence("Alice", a); err != nil {
+			t.Fatalf("AddAbsence() error = %v", err)
+		}
+	}
+	// The same days again only update the reason
+	vacation.Reason = "Holiday"
+	if err := manager.AddAbsence("alice", vacation); err != nil {
+		t.Fatalf("AddAbsence() error = %v", err)
+	}
+
+	absences, err := manager.LoadAbsences("alice")
+	if err != nil {
+		t.Fatalf("LoadAbsences() error = %v", err)
+	}
+	if len(absences) != 2 || absences[0].String() != "2024-07-02 (Sick)" || absences[1].String() != "2024-07-08 to 2024-07-12 (Holiday)" {
+		t.Errorf("LoadAbsences() = %v", absences)
+	}
+
+	if a, ok := OutOfOffice(absences, day(10)); !ok || a.Reason != "Holiday" {
+		t.Errorf("OutOfOffice(July 10) = %v, %v", a, ok)
+	}
+	if _, ok := OutOfOffice(absences, day(13)); ok {
+		t.Error("OutOfOffice(July 13) should be false")
+	}
+
+	team, err := manager.TeamAbsences()
+	if err != nil || len(team["alice"]) != 2 {
+		t.Errorf("TeamAbsences() = %v, %v", team, err)
+	}
+
+	// The out-of-office directory isn't a user in structured storage
+	if err := os.MkdirAll(filepath.Join(repo, "stand-ups", "bob"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	users, err := NewManagerWithFormat(repo, types.StorageYAML).Users()
+	if err != nil || len(users) != 1 || users[0] != "bob" {
+		t.Errorf("Users() = %v, %v, want [bob]", users, err)
+	}
+}
@@ -0,0 +1,36 @@
+package standup
+
+import (
+	"errors"
+	"sync"
+)
+
+// maxWorkers bounds how many users' standups are read at once
+const maxWorkers = 8
+
+// forEachUser calls fn for every user on a bounded pool of goroutines, so
+// reading a large team's files doesn't happen one user at a time. fn gets
+// the user's index, for storing results in order. Every failure is
+// returned, joined in user order.
+func forEachUser(users []string, fn func(i int, user string) error) error {
+	errs := make([]error, len(users))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(maxWorkers, len(users)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = fn(i, users[i])
+			}
+		}()
+	}
+	for i := range users {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+package standup
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEachUser(t *testing.T) {
+	users := make([]string, 20)
+	for i := range users {
+		users[i] = fmt.Sprintf("user%02d", i)
+	}
+
+	var running, peak atomic.Int32
+	seen := make([]string, len(users))
+	err := forEachUser(users, func(i int, user string) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		seen[i] = user
+		if user == "user03" || user == "user17" {
+			return fmt.Errorf("%s failed", user)
+		}
+		return nil
+	})
+
+	if peak.Load() > maxWorkers {
+		t.Errorf("ran %d users at once, want at most %d", peak.Load(), maxWorkers)
+	}
+	if fmt.Sprint(seen) != fmt.Sprint(users) {
+		t.Errorf("forEachUser() visited %v", seen)
+	}
+	if err == nil || err.Error() != "user03 failed\nuser17 failed" {
+		t.Errorf("forEachUser() error = %v, want both failures in user order", err)
+	}
+
+	if err := forEachUser(nil, func(int, string) error { return fmt.Errorf("called") }); err != nil {
+		t.Errorf("forEachUser() without users = %v", err)
+	}
+}
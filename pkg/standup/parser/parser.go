@@ -0,0 +1,423 @@
+// Package parser reads and writes standup markdown files using a markdown AST
+// instead of line-prefix heuristics, so hand-edited content survives a save.
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// DateFormat is the date layout used in entry headings
+const DateFormat = "2006-01-02"
+
+// dateRegex finds a date inside an entry heading
+var dateRegex = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// updateRegex matches the heading of an intraday update, e.g. "Update 14:30"
+var updateRegex = regexp.MustCompile(`^Update (\d{2}:\d{2})$`)
+
+// TimeFormat is the time layout used in update headings
+const TimeFormat = "15:04"
+
+// Section is a labelled block within an entry, such as "Yesterday" or "Blockers"
+type Section struct {
+	Title string
+	Items []string
+	Text  string
+}
+
+// Update is an addition to an entry made later in its day, written under
+// the entry as "### Update 14:30" followed by its sections
+type Update struct {
+	// Time is the time of day of the update, in TimeFormat
+	Time     string
+	Sections []Section
+}
+
+// Entry is a single dated standup entry
+type Entry struct {
+	Date     time.Time
+	Heading  string
+	Sections []Section
+	// Updates are the entry's intraday updates, oldest first
+	Updates []Update
+
+	// Raw holds the exact source of a parsed entry, from its heading up to the
+	// next entry. Entries without Raw are rendered with FormatEntry.
+	Raw string
+}
+
+// Section returns the section with the given title (case-insensitive)
+func (e Entry) Section(title string) (Section, bool) {
+	for _, s := range e.Sections {
+		if strings.EqualFold(s.Title, title) {
+			return s, true
+		}
+	}
+	return Section{}, false
+}
+
+// Items returns the items of the named section, or nil if it doesn't exist
+func (e Entry) Items(title string) []string {
+	s, _ := e.Section(title)
+	return s.Items
+}
+
+// Document is a parsed standup file
+type Document struct {
+	// Title is the text of the level-1 heading, e.g. "Alice's Standups"
+	Title string
+	// Preamble is the raw source before the first entry, including the title
+	Preamble string
+	Entries  []Entry
+}
+
+// ParseEntries parses a standup file and returns its entries in file order
+func ParseEntries(src []byte) ([]Entry, error) {
+	doc, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Entries, nil
+}
+
+// Parse parses a standup file into a document that can be serialized back
+// without losing content
+func Parse(src []byte) (*Document, error) {
+	if !utf8.Valid(src) {
+		return nil, fmt.Errorf("standup file is not valid UTF-8")
+	}
+
+	root := goldmark.New().Parser().Parse(text.NewReader(src))
+	b := &builder{src: src, doc: &Document{}}
+
+	for n := root.FirstChild(); n != nil; n = n.NextSibling() {
+		b.visit(n)
+	}
+	b.finish()
+
+	return b.doc, nil
+}
+
+// Find returns the entry for the given date, or nil if there is none
+func (d *Document) Find(date time.Time) *Entry {
+	dateStr := date.Format(DateFormat)
+	for i := range d.Entries {
+		if !d.Entries[i].Date.IsZero() && d.Entries[i].Date.Format(DateFormat) == dateStr {
+			return &d.Entries[i]
+		}
+	}
+	return nil
+}
+
+// Upsert replaces any entry for the same date and places the entry first
+func (d *Document) Upsert(entry Entry) {
+	dateStr := entry.Date.Format(DateFormat)
+	entries := []Entry{entry}
+	for _, e := range d.Entries {
+		if !e.Date.IsZero() && e.Date.Format(DateFormat) == dateStr {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	d.Entries = entries
+}
+
+// Bytes serializes the document. Parsed entries are written back verbatim.
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(d.Preamble)
+	if len(d.Entries) > 0 && d.Preamble != "" && !strings.HasSuffix(d.Preamble, "\n\n") {
+		buf.WriteString(strings.Repeat("\n", 2-trailingNewlines(d.Preamble)))
+	}
+
+	for i, e := range d.Entries {
+		if e.Raw == "" {
+			buf.WriteString(FormatEntry(e))
+			buf.WriteString("\n")
+			continue
+		}
+		buf.WriteString(e.Raw)
+		if i < len(d.Entries)-1 && !strings.HasSuffix(e.Raw, "\n") {
+			buf.WriteString("\n")
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// FormatEntry renders an entry in the canonical standup format
+func FormatEntry(e Entry) string {
+	var content strings.Builder
+
+	heading := e.Heading
+	if heading == "" {
+		heading = e.Date.Format(DateFormat)
+	}
+	fmt.Fprintf(&content, "## %s\n\n", heading)
+	writeSections(&content, e.Sections)
+	for _, u := range e.Updates {
+		fmt.Fprintf(&content, "### Update %s\n\n", u.Time)
+		writeSections(&content, u.Sections)
+	}
+
+	content.WriteString("---\n")
+	return content.String()
+}
+
+// writeSections renders sections as labels followed by their items and text
+func writeSections(content *strings.Builder, sections []Section) {
+	for _, s := range sections {
+		fmt.Fprintf(content, "**%s:**\n", s.Title)
+		for _, item := range s.Items {
+			fmt.Fprintf(content, "- %s\n", item)
+		}
+		if s.Text != "" {
+			fmt.Fprintf(content, "%s\n", s.Text)
+		}
+		content.WriteString("\n")
+	}
+}
+
+// builder accumulates a document while walking the top-level AST nodes
+type builder struct {
+	src     []byte
+	doc     *Document
+	entry   *Entry
+	section *Section
+	start   int
+	closed  bool
+	// inUpdate is set once the entry's first update heading is reached, so
+	// later sections belong to its last update
+	inUpdate bool
+}
+
+// visit processes a single top-level block
+func (b *builder) visit(n ast.Node) {
+	if h, ok := n.(*ast.Heading); ok {
+		if h.Level == 2 && b.isATX(h) {
+			b.startEntry(h)
+			return
+		}
+		if h.Level == 1 && b.entry == nil && b.doc.Title == "" {
+			b.doc.Title = b.linesText(h)
+			return
+		}
+	}
+
+	if b.entry == nil || b.closed {
+		return
+	}
+
+	if h, ok := n.(*ast.Heading); ok && h.Level == 3 {
+		if match := updateRegex.FindStringSubmatch(b.linesText(h)); match != nil {
+			b.closeSection()
+			b.entry.Updates = append(b.entry.Updates, Update{Time: match[1]})
+			b.inUpdate = true
+			return
+		}
+	}
+
+	switch node := n.(type) {
+	case *ast.ThematicBreak:
+		b.closeSection()
+		b.closed = true
+	case *ast.Paragraph:
+		b.visitParagraph(node)
+	case *ast.List:
+		b.visitList(node)
+	default:
+		b.appendText(strings.TrimSpace(b.blockSource(n)))
+	}
+}
+
+// startEntry begins a new entry at the given heading
+func (b *builder) startEntry(h *ast.Heading) {
+	pos := b.lineStart(h.Pos())
+	if b.entry == nil {
+		b.doc.Preamble = string(b.src[:pos])
+	} else {
+		b.flush(pos)
+	}
+
+	heading := b.linesText(h)
+	entry := &Entry{Heading: heading}
+	if match := dateRegex.FindString(heading); match != "" {
+		if date, err := time.Parse(DateFormat, match); err == nil {
+			entry.Date = date
+		}
+	}
+
+	b.entry = entry
+	b.section = nil
+	b.start = pos
+	b.closed = false
+	b.inUpdate = false
+}
+
+// visitParagraph starts a new section for "**Title:**" labels, otherwise adds text
+func (b *builder) visitParagraph(p *ast.Paragraph) {
+	content := b.linesText(p)
+
+	strong, ok := p.FirstChild().(*ast.Emphasis)
+	if !ok || strong.Level != 2 {
+		b.appendText(content)
+		return
+	}
+
+	label := strings.TrimSpace(b.inlineText(strong))
+	if !strings.HasSuffix(label, ":") {
+		b.appendText(content)
+		return
+	}
+
+	b.closeSection()
+	b.section = &Section{Title: strings.TrimSpace(strings.TrimSuffix(label, ":"))}
+
+	// Anything after the label (same line or following lines) is section text
+	if idx := strings.Index(content, label); idx >= 0 {
+		rest := content[idx+len(label):]
+		rest = strings.TrimLeft(rest, "*_")
+		b.appendText(strings.TrimSpace(rest))
+	}
+}
+
+// visitList adds each list item to the current section
+func (b *builder) visitList(list *ast.List) {
+	if b.section == nil {
+		b.appendText(strings.TrimSpace(b.blockSource(list)))
+		return
+	}
+	b.section.Items = append(b.section.Items, b.listItems(list)...)
+}
+
+// listItems flattens a list, including nested lists, into item strings
+func (b *builder) listItems(list *ast.List) []string {
+	var items []string
+	for li := list.FirstChild(); li != nil; li = li.NextSibling() {
+		for c := li.FirstChild(); c != nil; c = c.NextSibling() {
+			if nested, ok := c.(*ast.List); ok {
+				items = append(items, b.listItems(nested)...)
+				continue
+			}
+			if item := strings.TrimSpace(b.linesText(c)); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// appendText adds free text to the current section
+func (b *builder) appendText(s string) {
+	if s == "" || b.section == nil {
+		return
+	}
+	if b.section.Text != "" {
+		b.section.Text += "\n\n"
+	}
+	b.section.Text += s
+}
+
+// closeSection stores the current section on the entry, or on its last
+// update
+func (b *builder) closeSection() {
+	if b.section == nil {
+		return
+	}
+	if b.inUpdate {
+		last := &b.entry.Updates[len(b.entry.Updates)-1]
+		last.Sections = append(last.Sections, *b.section)
+	} else {
+		b.entry.Sections = append(b.entry.Sections, *b.section)
+	}
+	b.section = nil
+}
+
+// flush stores the current entry, whose source ends at the given offset
+func (b *builder) flush(end int) {
+	b.closeSection()
+	b.entry.Raw = string(b.src[b.start:end])
+	b.doc.Entries = append(b.doc.Entries, *b.entry)
+}
+
+// finish stores the last entry, or the whole file as preamble if there are none
+func (b *builder) finish() {
+	if b.entry == nil {
+		b.doc.Preamble = string(b.src)
+		return
+	}
+	b.flush(len(b.src))
+}
+
+// isATX reports whether a heading uses "##" syntax rather than a setext underline
+func (b *builder) isATX(h *ast.Heading) bool {
+	pos := h.Pos()
+	if pos < 0 {
+		return false
+	}
+	line := strings.TrimLeft(string(b.src[b.lineStart(pos):]), " ")
+	return strings.HasPrefix(line, "#")
+}
+
+// lineStart returns the offset of the start of the line containing pos
+func (b *builder) lineStart(pos int) int {
+	if pos < 0 {
+		return 0
+	}
+	if i := bytes.LastIndexByte(b.src[:pos], '\n'); i >= 0 {
+		return i + 1
+	}
+	return 0
+}
+
+// blockSource returns the source of a block up to the next sibling
+func (b *builder) blockSource(n ast.Node) string {
+	if n.Pos() < 0 {
+		return b.linesText(n)
+	}
+	start := b.lineStart(n.Pos())
+	end := len(b.src)
+	if next := n.NextSibling(); next != nil && next.Pos() >= 0 {
+		end = b.lineStart(next.Pos())
+	}
+	return string(b.src[start:end])
+}
+
+// linesText joins the source lines of a block
+func (b *builder) linesText(n ast.Node) string {
+	lines := n.Lines()
+	var parts []string
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		parts = append(parts, strings.TrimRight(string(seg.Value(b.src)), "\r\n"))
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
+
+// inlineText returns the plain text of an inline node
+func (b *builder) inlineText(n ast.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok {
+			sb.Write(t.Segment.Value(b.src))
+			continue
+		}
+		sb.WriteString(b.inlineText(c))
+	}
+	return sb.String()
+}
+
+// trailingNewlines counts the newlines at the end of s
+func trailingNewlines(s string) int {
+	return len(s) - len(strings.TrimRight(s, "\n"))
+}
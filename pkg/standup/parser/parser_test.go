@@ -0,0 +1,273 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleFile = `# Alice's Standups
+
+## 2024-02-01
+
+**Yesterday:**
+- Finished frontend work
+- Reviewed PRs
+
+**Today:**
+- Code review
+
+**Blockers:**
+Waiting for API deployment
+
+---
+
+## 2024-01-31
+
+**Yesterday:**
+- Completed API endpoints
+
+**Today:**
+- Work on frontend
+
+**Blockers:**
+None
+
+---
+`
+
+func TestParseEntries(t *testing.T) {
+	entries, err := ParseEntries([]byte(sampleFile))
+	if err != nil {
+		t.Fatalf("ParseEntries() error = %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("ParseEntries() returned %d entries, want 2", len(entries))
+	}
+
+	first := entries[0]
+	if first.Date.Format(DateFormat) != "2024-02-01" {
+		t.Errorf("Date = %v, want 2024-02-01", first.Date.Format(DateFormat))
+	}
+
+	yesterday := first.Items("Yesterday")
+	if len(yesterday) != 2 || yesterday[0] != "Finished frontend work" || yesterday[1] != "Reviewed PRs" {
+		t.Errorf("Yesterday = %v, want [Finished frontend work Reviewed PRs]", yesterday)
+	}
+
+	if today := first.Items("today"); len(today) != 1 || today[0] != "Code review" {
+		t.Errorf("Today = %v, want [Code review]", today)
+	}
+
+	blockers, ok := first.Section("Blockers")
+	if !ok {
+		t.Fatal("Blockers section not found")
+	}
+	if blockers.Text != "Waiting for API deployment" {
+		t.Errorf("Blockers = %q, want %q", blockers.Text, "Waiting for API deployment")
+	}
+
+	if entries[1].Date.Format(DateFormat) != "2024-01-31" {
+		t.Errorf("second entry Date = %v, want 2024-01-31", entries[1].Date.Format(DateFormat))
+	}
+}
+
+func TestParseHandEditedContent(t *testing.T) {
+	src := `Some notes the user wrote before the header.
+
+# Bob's Standups
+
+## 2024-03-05 (late)
+
+**Yesterday:**
+- Deployed service
+  - including the migration
+
+A paragraph the user added by hand.
+
+` + "```" + `
+## not a heading
+` + "```" + `
+
+**Today:** Pairing with Carol
+
+**Blockers:**
+None
+`
+
+	doc, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if doc.Title != "Bob's Standups" {
+		t.Errorf("Title = %q, want %q", doc.Title, "Bob's Standups")
+	}
+
+	if len(doc.Entries) != 1 {
+		t.Fatalf("got %d entries, want 1 (code block must not start an entry)", len(doc.Entries))
+	}
+
+	entry := doc.Entries[0]
+	if entry.Date.Format(DateFormat) != "2024-03-05" {
+		t.Errorf("Date = %v, want 2024-03-05", entry.Date.Format(DateFormat))
+	}
+
+	yesterday, _ := entry.Section("Yesterday")
+	if len(yesterday.Items) != 2 {
+		t.Errorf("Yesterday items = %v, want 2 items including the nested one", yesterday.Items)
+	}
+	if !strings.Contains(yesterday.Text, "A paragraph the user added by hand.") {
+		t.Errorf("Yesterday text = %q, should keep the hand-written paragraph", yesterday.Text)
+	}
+	if !strings.Contains(yesterday.Text, "## not a heading") {
+		t.Errorf("Yesterday text = %q, should keep the code block", yesterday.Text)
+	}
+
+	today, _ := entry.Section("Today")
+	if today.Text != "Pairing with Carol" {
+		t.Errorf("Today text = %q, want %q", today.Text, "Pairing with Carol")
+	}
+
+	if got := string(doc.Bytes()); got != src {
+		t.Errorf("round trip changed content:\ngot:\n%s\nwant:\n%s", got, src)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "canonical file", src: sampleFile},
+		{name: "no trailing newline", src: strings.TrimSuffix(sampleFile, "\n")},
+		{name: "header only", src: "# Alice's Standups\n"},
+		{name: "empty", src: ""},
+		{name: "setext heading inside entry", src: "# A\n\n## 2024-01-01\n\nNot a date\n---\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse([]byte(tt.src))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := string(doc.Bytes()); got != tt.src {
+				t.Errorf("Bytes() = %q, want %q", got, tt.src)
+			}
+		})
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	doc, err := Parse([]byte(sampleFile))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	doc.Upsert(Entry{
+		Date: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Sections: []Section{
+			{Title: "Yesterday", Items: []string{"Rewrote entry"}},
+			{Title: "Blockers", Text: "None"},
+		},
+	})
+
+	if len(doc.Entries) != 2 {
+		t.Fatalf("got %d entries after upsert, want 2", len(doc.Entries))
+	}
+
+	if doc.Entries[0].Date.Format(DateFormat) != "2024-01-31" {
+		t.Errorf("upserted entry should be first, got %v", doc.Entries[0].Date.Format(DateFormat))
+	}
+
+	out := string(doc.Bytes())
+	if strings.Count(out, "## 2024-01-31") != 1 {
+		t.Errorf("expected a single 2024-01-31 entry, got:\n%s", out)
+	}
+	if !strings.Contains(out, "- Rewrote entry") {
+		t.Error("upserted entry content missing")
+	}
+	if !strings.Contains(out, "Waiting for API deployment") {
+		t.Error("other entry content was dropped")
+	}
+
+	if found := doc.Find(time.Date(2024, 2, 1, 12, 0, 0, 0, time.Local)); found == nil {
+		t.Error("Find() = nil, want the 2024-02-01 entry")
+	}
+}
+
+func TestFormatEntry(t *testing.T) {
+	entry := Entry{
+		Date: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Sections: []Section{
+			{Title: "Yesterday", Items: []string{"Task A"}},
+			{Title: "Today", Items: []string{"Task B"}},
+			{Title: "Blockers", Text: "None"},
+		},
+	}
+
+	want := "## 2024-01-31\n\n**Yesterday:**\n- Task A\n\n**Today:**\n- Task B\n\n**Blockers:**\nNone\n\n---\n"
+	if got := FormatEntry(entry); got != want {
+		t.Errorf("FormatEntry() = %q, want %q", got, want)
+	}
+
+	// A formatted entry must parse back to the same sections
+	parsed, err := ParseEntries([]byte(FormatEntry(entry)))
+	if err != nil || len(parsed) != 1 {
+		t.Fatalf("ParseEntries() = %v, %v", parsed, err)
+	}
+	if got := parsed[0].Items("Today"); len(got) != 1 || got[0] != "Task B" {
+		t.Errorf("parsed Today = %v, want [Task B]", got)
+	}
+}
+
+func TestParseUpdates(t *testing.T) {
+	entry := Entry{
+		Date: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Sections: []Section{
+			{Title: "Today", Items: []string{"Task B"}},
+			{Title: "Blockers", Text: "None"},
+		},
+		Updates: []Update{
+			{Time: "11:15", Sections: []Section{{Title: "Today", Items: []string{"Task C"}}}},
+			{Time: "14:30", Sections: []Section{{Title: "Blockers", Text: "Waiting for CI"}}},
+		},
+	}
+
+	src := "# Alice's Standups\n\n" + FormatEntry(entry) + "\n" + sampleFile[len("# Alice's Standups\n\n"):]
+	doc, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := string(doc.Bytes()); got != src {
+		t.Errorf("Bytes() = %q, want %q", got, src)
+	}
+	if len(doc.Entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(doc.Entries))
+	}
+
+	parsed := doc.Entries[0]
+	if len(parsed.Sections) != 2 || parsed.Items("Today")[0] != "Task B" {
+		t.Errorf("sections = %+v, want the entry's own", parsed.Sections)
+	}
+	if len(parsed.Updates) != 2 {
+		t.Fatalf("updates = %+v, want 2", parsed.Updates)
+	}
+	if u := parsed.Updates[0]; u.Time != "11:15" || len(u.Sections) != 1 || u.Sections[0].Items[0] != "Task C" {
+		t.Errorf("first update = %+v", u)
+	}
+	if u := parsed.Updates[1]; u.Time != "14:30" || len(u.Sections) != 1 || u.Sections[0].Text != "Waiting for CI" {
+		t.Errorf("second update = %+v", u)
+	}
+	if len(doc.Entries[1].Updates) != 0 {
+		t.Errorf("the next entry got updates: %+v", doc.Entries[1].Updates)
+	}
+}
+
+func TestParseInvalidUTF8(t *testing.T) {
+	if _, err := ParseEntries([]byte{0xff, 0xfe}); err == nil {
+		t.Error("ParseEntries() with invalid UTF-8 should return an error")
+	}
+}
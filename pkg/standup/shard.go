@@ -0,0 +1,124 @@
+package standup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// shardIndex lists a user's monthly files, newest first
+const shardIndex = "index.md"
+
+// shardMonth is the layout of a monthly file's name
+const shardMonth = "2006-01"
+
+// ShardPath returns the file holding a user's markdown entries for the month
+// of date in monthly storage (stand-ups/<user>/<YYYY-MM>.md)
+func (m *Manager) ShardPath(userName string, date time.Time) string {
+	return filepath.Join(m.userDir(userName), date.Format(shardMonth)+".md")
+}
+
+// singleFilePath returns the file holding all of a user's markdown entries
+// in the default storage
+func (m *Manager) singleFilePath(userName string) string {
+	return filepath.Join(m.repoPath, "stand-ups", fmt.Sprintf("%s.md", strings.ToLower(userName)))
+}
+
+// markdownFilesFor returns the files that may hold a user's markdown entry
+// for date. Monthly storage falls back to the single file written before
+// the team switched to it.
+func (m *Manager) markdownFilesFor(userName string, date time.Time) []string {
+	if m.format.Sharded() {
+		return []string{m.ShardPath(userName, date), m.singleFilePath(userName)}
+	}
+	return []string{m.singleFilePath(userName)}
+}
+
+// markdownFiles returns every file holding a user's markdown entries: the
+// monthly files, oldest first, then the single file. Missing files are left
+// out.
+func (m *Manager) markdownFiles(userName string) ([]string, error) {
+	var paths []string
+	if m.format.Sharded() {
+		months, err := m.shardMonths(userName)
+		if err != nil {
+			return nil, err
+		}
+		for _, month := range months {
+			paths = append(paths, filepath.Join(m.userDir(userName), month+".md"))
+		}
+	}
+
+	if _, err := m.fs.Stat(m.singleFilePath(userName)); err == nil {
+		paths = append(paths, m.singleFilePath(userName))
+	}
+	return paths, nil
+}
+
+// shardMonths returns the months a user has a monthly file for, oldest first
+func (m *Manager) shardMonths(userName string) ([]string, error) {
+	files, err := m.fs.ReadDir(m.userDir(userName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read standup directory: %w", err)
+	}
+
+	var months []string
+	for _, f := range files {
+		month, ok := strings.CutSuffix(f.Name(), ".md")
+		if f.IsDir() || !ok {
+			continue
+		}
+		if _, err := time.Parse(shardMonth, month); err != nil {
+			continue
+		}
+		months = append(months, month)
+	}
+	sort.Strings(months)
+	return months, nil
+}
+
+// saveShardedEntry writes the entry to the file of its month and updates the
+// user's index
+func (m *Manager) saveShardedEntry(entry *Entry, userName string) error {
+	if err := m.fs.MkdirAll(m.userDir(userName), 0755); err != nil {
+		return fmt.Errorf("failed to create standup directory: %w", err)
+	}
+
+	filePath := m.ShardPath(userName, entry.Date)
+	existingContent, err := m.readExistingContent(filePath)
+	if err != nil {
+		return err
+	}
+	newContent := m.buildUpdatedContent(existingContent, entry, userName)
+	if err := m.fs.WriteFile(filePath, []byte(newContent), 0644); err != nil {
+		return err
+	}
+
+	return m.writeShardIndex(userName)
+}
+
+// writeShardIndex rewrites the list of a user's monthly files
+func (m *Manager) writeShardIndex(userName string) error {
+	months, err := m.shardMonths(userName)
+	if err != nil {
+		return err
+	}
+
+	var index strings.Builder
+	fmt.Fprintf(&index, "# %s's Standups\n\n", userName)
+	index.WriteString("One file per month, newest first:\n\n")
+	for i := len(months) - 1; i >= 0; i-- {
+		fmt.Fprintf(&index, "- [%s](%s.md)\n", months[i], months[i])
+	}
+
+	if err := m.fs.WriteFile(filepath.Join(m.userDir(userName), shardIndex), []byte(index.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write standup index: %w", err)
+	}
+	return nil
+}
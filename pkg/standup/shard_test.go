@@ -0,0 +1,93 @@
+package standup
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+func TestShardedStorage(t *testing.T) {
+	repo := t.TempDir()
+	manager := NewManagerWithFormat(repo, types.StorageMonthly)
+
+	for _, date := range []time.Time{
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		entry := &Entry{Date: date, Yesterday: []string{"Task " + date.Format("01-02")}, Blockers: "None"}
+		if err := manager.SaveEntry(entry, "Alice"); err != nil {
+			t.Fatalf("SaveEntry() error = %v", err)
+		}
+	}
+
+	userDir := filepath.Join(repo, "stand-ups", "alice")
+	for _, name := range []string{"2024-01.md", "2024-02.md"} {
+		content, err := os.ReadFile(filepath.Join(userDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if strings.Count(string(content), "## ") != 1 {
+			t.Errorf("%s should hold one entry:\n%s", name, content)
+		}
+	}
+
+	index, err := os.ReadFile(filepath.Join(userDir, "index.md"))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if !strings.Contains(string(index), "- [2024-02](2024-02.md)\n- [2024-01](2024-01.md)\n") {
+		t.Errorf("index should list the months newest first:\n%s", index)
+	}
+
+	entry, err := manager.LoadEntry("Alice", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("LoadEntry() error = %v", err)
+	}
+	if !reflect.DeepEqual(entry.Yesterday, []string{"Task 01-31"}) {
+		t.Errorf("Yesterday = %v, want [Task 01-31]", entry.Yesterday)
+	}
+
+	users, err := manager.Users()
+	if err != nil || !reflect.DeepEqual(users, []string{"alice"}) {
+		t.Errorf("Users() = %v, %v; want [alice]", users, err)
+	}
+}
+
+func TestShardedStorageReadsSingleFile(t *testing.T) {
+	repo := t.TempDir()
+	day := time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC)
+
+	// Standups written before the team switched to monthly storage
+	before := &Entry{Date: day, Yesterday: []string{"Old task"}, Blockers: "None"}
+	if err := NewManager(repo).SaveEntry(before, "Alice"); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	manager := NewManagerWithFormat(repo, types.StorageMonthly)
+	after := &Entry{Date: day.AddDate(0, 0, 1), Yesterday: []string{"New task"}, Blockers: "None"}
+	if err := manager.SaveEntry(after, "Alice"); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	if has, err := manager.HasEntry("Alice", day); err != nil || !has {
+		t.Errorf("HasEntry() for a day in the single file = %v, %v; want true, nil", has, err)
+	}
+
+	entries, err := manager.LoadEntries("Alice")
+	if err != nil {
+		t.Fatalf("LoadEntries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Yesterday[0] != "New task" || entries[1].Yesterday[0] != "Old task" {
+		t.Errorf("LoadEntries() = %+v, want the new and old entries", entries)
+	}
+
+	users, err := manager.Users()
+	if err != nil || !reflect.DeepEqual(users, []string{"alice"}) {
+		t.Errorf("Users() = %v, %v; want [alice]", users, err)
+	}
+}
@@ -0,0 +1,233 @@
+package standup
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+)
+
+// siteEntry is an entry as shown on the site
+type siteEntry struct {
+	// Root is the relative path to the site root from the page
+	Root     string
+	User     string
+	Date     string
+	Sections []Section
+	Blocked  bool
+	Blockers string
+}
+
+// siteDay groups the entries of one day
+type siteDay struct {
+	Date    string
+	Entries []siteEntry
+}
+
+// sitePage is the data every page template receives
+type sitePage struct {
+	Title     string
+	Root      string
+	Generated string
+	Users     []string
+	Days      []siteDay
+	Stats     TeamStats
+	User      Stats
+}
+
+// siteTemplates are the page layout and the pages
+var siteTemplates = template.Must(template.New("site").Funcs(template.FuncMap{
+	"percent": func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"themes":  FormatThemes,
+}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<nav>
+<a href="{{.Root}}index.html">By date</a>
+{{range .Users}}<a href="{{$.Root}}people/{{.}}.html">{{.}}</a>
+{{end}}<a href="{{.Root}}blockers.html">Blockers</a>
+</nav>
+<main>
+{{end}}
+
+{{define "footer"}}</main>
+<footer>Generated by standup-bot on {{.Generated}}</footer>
+</body>
+</html>
+{{end}}
+
+{{define "entry"}}<article class="entry{{if .Blocked}} blocked{{end}}">
+<h3><a href="{{.Root}}people/{{.User}}.html">{{.User}}</a> <time>{{.Date}}</time></h3>
+{{range .Sections}}<h4>{{.Name}}</h4>
+<ul>{{range .Items}}<li>{{.}}</li>{{end}}</ul>
+{{end}}<h4>Blockers</h4>
+<p>{{.Blockers}}</p>
+</article>
+{{end}}
+
+{{define "index"}}{{template "header" .}}<h1>{{.Title}}</h1>
+<input id="search" type="search" placeholder="Search standups" autofocus>
+{{range .Days}}<section class="day">
+<h2>{{.Date}}</h2>
+{{range .Entries}}{{template "entry" .}}{{end}}</section>
+{{else}}<p>No standups yet.</p>
+{{end}}<script>
+document.getElementById("search").addEventListener("input", function () {
+  var query = this.value.toLowerCase();
+  document.querySelectorAll(".day").forEach(function (day) {
+    var shown = 0;
+    day.querySelectorAll(".entry").forEach(function (entry) {
+      var match = entry.textContent.toLowerCase().indexOf(query) >= 0;
+      entry.hidden = !match;
+      if (match) shown++;
+    });
+    day.hidden = shown === 0;
+  });
+});
+</script>
+{{template "footer" .}}{{end}}
+
+{{define "person"}}{{template "header" .}}<h1>{{.User.User}}</h1>
+<p class="summary">{{.User.Days}} standups, {{printf "%.1f" .User.ItemsPerDay}} completed items a day,
+blocked {{percent .User.BlockerRate}} of the time. Longest streak: {{.User.LongestStreak}} weekdays.
+{{with themes .User.Themes}}Mostly working on {{.}}.{{end}}</p>
+{{range .Days}}{{range .Entries}}{{template "entry" .}}{{end}}{{end}}
+{{template "footer" .}}{{end}}
+
+{{define "blockers"}}{{template "header" .}}<h1>Blockers</h1>
+<table>
+<thead><tr><th>User</th><th>Standups</th><th>Blocked</th><th>Rate</th></tr></thead>
+<tbody>
+{{range .Stats.Users}}<tr><td><a href="people/{{.User}}.html">{{.User}}</a></td><td>{{.Days}}</td><td>{{.BlockedDays}}</td><td>{{percent .BlockerRate}}</td></tr>
+{{end}}<tr class="team"><td>{{.Stats.Team.User}}</td><td>{{.Stats.Team.Days}}</td><td>{{.Stats.Team.BlockedDays}}</td><td>{{percent .Stats.Team.BlockerRate}}</td></tr>
+</tbody>
+</table>
+{{range .Days}}<section class="day">
+<h2>{{.Date}}</h2>
+<ul>{{range .Entries}}<li><a href="people/{{.User}}.html">{{.User}}</a>: {{.Blockers}}</li>{{end}}</ul>
+</section>
+{{else}}<p>No blockers reported.</p>
+{{end}}{{template "footer" .}}{{end}}
+`))
+
+// siteStyle is the stylesheet of the site
+const siteStyle = `body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 0; color: #1f2328; }
+nav { background: #f6f8fa; border-bottom: 1px solid #d0d7de; padding: 0.75rem 1.5rem; }
+nav a { margin-right: 1rem; }
+main { max-width: 60rem; margin: 0 auto; padding: 1.5rem; }
+#search { width: 100%; padding: 0.5rem; font-size: 1rem; margin-bottom: 1rem; }
+.entry { border: 1px solid #d0d7de; border-radius: 6px; padding: 0 1rem 0.5rem; margin-bottom: 1rem; }
+.entry.blocked { border-left: 4px solid #cf222e; }
+.entry h3 time { color: #656d76; font-weight: normal; font-size: 0.9rem; }
+.entry h4 { margin: 0.75rem 0 0.25rem; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.4rem 0.75rem; text-align: left; }
+tr.team { font-weight: bold; }
+footer { color: #656d76; font-size: 0.85rem; text-align: center; padding: 1.5rem; }
+`
+
+// RenderSite renders the standup history as a static site: an index by date
+// with a search box, a page per person and a blockers dashboard. It returns
+// the files by path relative to the site root.
+func RenderSite(title string, entries map[string][]Entry, absences map[string][]Absence, generated time.Time) (map[string][]byte, error) {
+	stats := ComputeStats(entries, absences, time.Time{}, time.Time{}, generated)
+
+	users := make([]string, 0, len(entries))
+	var all []siteEntry
+	for user, userEntries := range entries {
+		if len(userEntries) == 0 {
+			continue
+		}
+		users = append(users, user)
+		for _, entry := range userEntries {
+			all = append(all, newSiteEntry(user, entry))
+		}
+	}
+	sort.Strings(users)
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Date != all[j].Date {
+			return all[i].Date > all[j].Date
+		}
+		return all[i].User < all[j].User
+	})
+
+	page := sitePage{Title: title, Users: users, Stats: stats, Generated: generated.Format("January 2, 2006")}
+	files := map[string][]byte{
+		"style.css": []byte(siteStyle),
+		// Serve the files as they are on GitHub Pages
+		".nojekyll": {},
+	}
+
+	render := func(path, name string, page sitePage) error {
+		var buf bytes.Buffer
+		if err := siteTemplates.ExecuteTemplate(&buf, name, page); err != nil {
+			return fmt.Errorf("failed to render %s: %w", path, err)
+		}
+		files[path] = buf.Bytes()
+		return nil
+	}
+
+	index := page
+	index.Days = groupByDay(all, "", func(siteEntry) bool { return true })
+	if err := render("index.html", "index", index); err != nil {
+		return nil, err
+	}
+
+	blockers := page
+	blockers.Title = title + ": Blockers"
+	blockers.Days = groupByDay(all, "", func(e siteEntry) bool { return e.Blocked })
+	if err := render("blockers.html", "blockers", blockers); err != nil {
+		return nil, err
+	}
+
+	for _, userStats := range stats.Users {
+		person := page
+		person.Title = title + ": " + userStats.User
+		person.Root = "../"
+		person.User = userStats
+		person.Days = groupByDay(all, person.Root, func(e siteEntry) bool { return e.User == userStats.User })
+		if err := render("people/"+userStats.User+".html", "person", person); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// newSiteEntry lists an entry's sections in the order they are written
+func newSiteEntry(user string, entry Entry) siteEntry {
+	e := siteEntry{User: user, Date: entry.Date.Format("2006-01-02"), Blockers: strings.TrimSpace(entry.Blockers)}
+	e.Blocked = !noBlockers[strings.ToLower(e.Blockers)]
+	if e.Blockers == "" {
+		e.Blockers = "None"
+	}
+	e.Sections = append(e.Sections, Section{Name: "Yesterday", Items: itemsOrDefault(entry.Yesterday, "Nothing to report")})
+	e.Sections = append(e.Sections, Section{Name: "Today", Items: itemsOrDefault(entry.Today, "Nothing planned")})
+	e.Sections = append(e.Sections, entry.Sections...)
+	return e
+}
+
+// groupByDay groups the entries that match keep by date, keeping their
+// order, for a page at root
+func groupByDay(entries []siteEntry, root string, keep func(siteEntry) bool) []siteDay {
+	var days []siteDay
+	for _, e := range entries {
+		if !keep(e) {
+			continue
+		}
+		e.Root = root
+		if len(days) == 0 || days[len(days)-1].Date != e.Date {
+			days = append(days, siteDay{Date: e.Date})
+		}
+		days[len(days)-1].Entries = append(days[len(days)-1].Entries, e)
+	}
+	return days
+}
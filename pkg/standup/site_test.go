@@ -0,0 +1,55 @@
+package standup
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderSite(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	entries := map[string][]Entry{
+		"alice": {
+			{Date: day(16), Yesterday: []string{"Shipped <b>refunds</b>"}, Blockers: "Waiting on keys"},
+			{Date: day(15), Today: []string{"[Billing] Export"}, Blockers: "None",
+				Sections: []Section{{Name: "Wins", Items: []string{"Closed 5 bugs"}}}},
+		},
+		"bob": {{Date: day(16), Yesterday: []string{"Reviews"}}},
+	}
+
+	files, err := RenderSite("Standups", entries, nil, day(17))
+	if err != nil {
+		t.Fatalf("RenderSite() error = %v", err)
+	}
+
+	for _, name := range []string{"index.html", "blockers.html", "people/alice.html", "people/bob.html", "style.css", ".nojekyll"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("RenderSite() is missing %s", name)
+		}
+	}
+
+	index := string(files["index.html"])
+	for _, want := range []string{
+		`<input id="search"`,
+		"Shipped &lt;b&gt;refunds&lt;/b&gt;",
+		"<h4>Wins</h4>",
+		`<a href="people/bob.html">bob</a>`,
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html missing %q", want)
+		}
+	}
+	if strings.Index(index, "2024-01-16") > strings.Index(index, "2024-01-15") {
+		t.Error("index.html should list the newest day first")
+	}
+
+	blockers := string(files["blockers.html"])
+	if !strings.Contains(blockers, "Waiting on keys") || strings.Contains(blockers, "Reviews") {
+		t.Errorf("blockers.html should only list blocked entries:\n%s", blockers)
+	}
+
+	alice := string(files["people/alice.html"])
+	if !strings.Contains(alice, `href="../style.css"`) || strings.Contains(alice, "Reviews") {
+		t.Errorf("people/alice.html should link up to the root and only show alice:\n%s", alice)
+	}
+}
@@ -6,8 +6,14 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/standup/parser"
+	"github.com/standup-bot/standup-bot/pkg/types"
 )
 
 // Entry represents a single standup entry
@@ -16,14 +22,138 @@ type Entry struct {
 	Yesterday []string
 	Today     []string
 	Blockers  string
+
+	// Sections holds extra sections defined by the team's template
+	Sections []Section
+
+	// AIAssisted marks entries drafted by an AI assistant, such as those
+	// submitted through the MCP server
+	AIAssisted bool
+
+	// Updates are additions made later in the day, oldest first. They are
+	// kept apart from the entry's own items; Consolidated merges them in.
+	Updates []Update
 }
 
-// FileSystem interface for file operations (for better testability)
+// Update is an intraday addition to an entry, stored under it with the time
+// it was made
+type Update struct {
+	// At is when the update was made; only the time of day is stored
+	At        time.Time
+	Yesterday []string
+	Today     []string
+	Blockers  string
+	Sections  []Section
+}
+
+// AIAssistedMarker is appended to the heading of AI-assisted markdown entries
+const AIAssistedMarker = "(AI-assisted)"
+
+// Section is an extra named section of an entry
+type Section struct {
+	Name  string
+	Items []string
+}
+
+// Section returns the items of the named extra section (case-insensitive)
+func (e *Entry) Section(name string) []string {
+	for _, s := range e.Sections {
+		if strings.EqualFold(s.Name, name) {
+			return s.Items
+		}
+	}
+	return nil
+}
+
+// Merge adds another entry's items to this one. Yesterday, today and section
+// items are appended unless already present, replacing placeholders such as
+// "Nothing planned". Non-empty blockers replace the current ones, so a
+// resolved blocker can be cleared with "None". An AI-assisted update marks
+// the whole entry as AI-assisted.
+func (e *Entry) Merge(update *Entry) {
+	e.Yesterday = mergeItems(e.Yesterday, update.Yesterday)
+	e.Today = mergeItems(e.Today, update.Today)
+	if strings.TrimSpace(update.Blockers) != "" {
+		e.Blockers = update.Blockers
+	}
+	if update.AIAssisted {
+		e.AIAssisted = true
+	}
+
+	for _, s := range update.Sections {
+		merged := false
+		for i := range e.Sections {
+			if strings.EqualFold(e.Sections[i].Name, s.Name) {
+				e.Sections[i].Items = mergeItems(e.Sections[i].Items, s.Items)
+				merged = true
+			}
+		}
+		if !merged {
+			e.Sections = append(e.Sections, Section{Name: s.Name, Items: s.Items})
+		}
+	}
+}
+
+// AddUpdate appends update to the entry as an intraday update made at at,
+// leaving the entry's own items as they are
+func (e *Entry) AddUpdate(update *Entry, at time.Time) {
+	e.Updates = append(e.Updates, Update{
+		At:        at,
+		Yesterday: update.Yesterday,
+		Today:     update.Today,
+		Blockers:  update.Blockers,
+		Sections:  update.Sections,
+	})
+	if update.AIAssisted {
+		e.AIAssisted = true
+	}
+}
+
+// Consolidated returns the entry with its updates merged in, in order, as
+// the latest view of the day. The entry itself is left unchanged.
+func (e *Entry) Consolidated() *Entry {
+	consolidated := *e
+	consolidated.Yesterday = slices.Clone(e.Yesterday)
+	consolidated.Today = slices.Clone(e.Today)
+	consolidated.Sections = nil
+	for _, s := range e.Sections {
+		consolidated.Sections = append(consolidated.Sections, Section{Name: s.Name, Items: slices.Clone(s.Items)})
+	}
+	consolidated.Updates = nil
+
+	for _, u := range e.Updates {
+		consolidated.Merge(&Entry{Yesterday: u.Yesterday, Today: u.Today, Blockers: u.Blockers, Sections: u.Sections})
+	}
+	return &consolidated
+}
+
+// mergeItems appends new items that aren't already present (ignoring case)
+func mergeItems(existing, items []string) []string {
+	if len(items) == 0 {
+		return existing
+	}
+
+	var merged []string
+	seen := make(map[string]bool)
+	for _, item := range append(append([]string(nil), existing...), items...) {
+		key := strings.ToLower(strings.TrimSpace(item))
+		if key == "" || placeholderItems[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// FileSystem interface for file operations. It is also where standups are
+// stored: the repository's directory by default, or a storage backend.
 type FileSystem interface {
 	ReadFile(filename string) ([]byte, error)
 	WriteFile(filename string, data []byte, perm os.FileMode) error
 	MkdirAll(path string, perm os.FileMode) error
 	Stat(name string) (os.FileInfo, error)
+	ReadDir(name string) ([]os.DirEntry, error)
 }
 
 // OSFileSystem implements FileSystem using os package
@@ -45,10 +175,23 @@ func (fs *OSFileSystem) Stat(name string) (os.FileInfo, error) {
 	return os.Stat(name)
 }
 
+func (fs *OSFileSystem) ReadDir(name string) ([]os.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
 // Manager handles standup operations
 type Manager struct {
 	repoPath string
 	fs       FileSystem
+	format   types.StorageFormat
+	template []types.SectionSpec
+	cutoff   int
+	location *time.Location
+
+	// indexPath is the local index of every user's entries; empty to read
+	// the files directly
+	indexPath string
+	indexMu   sync.Mutex
 }
 
 // NewManager creates a new standup manager
@@ -56,6 +199,7 @@ func NewManager(repoPath string) *Manager {
 	return &Manager{
 		repoPath: repoPath,
 		fs:       &OSFileSystem{},
+		format:   types.StorageMarkdown,
 	}
 }
 
@@ -64,32 +208,83 @@ func NewManagerWithFileSystem(repoPath string, fs FileSystem) *Manager {
 	return &Manager{
 		repoPath: repoPath,
 		fs:       fs,
+		format:   types.StorageMarkdown,
 	}
 }
 
+// NewManagerWithFormat creates a new standup manager using the given storage format
+func NewManagerWithFormat(repoPath string, format types.StorageFormat) *Manager {
+	return &Manager{
+		repoPath: repoPath,
+		fs:       &OSFileSystem{},
+		format:   format,
+	}
+}
+
+// SetFileSystem sets where the manager reads and writes standup files, e.g.
+// a bucket instead of the repository's directory
+func (m *Manager) SetFileSystem(fs FileSystem) {
+	m.fs = fs
+}
+
+// SetTemplate sets the questions collected for each entry
+func (m *Manager) SetTemplate(sections []types.SectionSpec) {
+	m.template = sections
+}
+
+// SetDayCutoff sets the hour before which new entries are dated to the previous day
+func (m *Manager) SetDayCutoff(hour int) {
+	m.cutoff = hour
+}
+
+// SetLocation sets the time zone new entries are dated in; the local one
+// by default
+func (m *Manager) SetLocation(location *time.Location) {
+	m.location = location
+}
+
+// today returns the date new entries are recorded under
+func (m *Manager) today() time.Time {
+	now := time.Now()
+	if m.location != nil {
+		now = now.In(m.location)
+	}
+	return types.StandupDay(now, m.cutoff)
+}
+
 // CollectEntry collects standup information from the user
 func (m *Manager) CollectEntry(reader io.Reader, writer io.Writer) (*Entry, error) {
 	scanner := bufio.NewScanner(reader)
 	entry := &Entry{
-		Date: time.Now(),
+		Date: m.today(),
 	}
 
-	// Yesterday
-	fmt.Fprintln(writer, "What did you do yesterday?")
-	fmt.Fprintln(writer, "(Enter multiple lines, press Enter twice to finish)")
-	entry.Yesterday = m.collectMultiLineInput(scanner, writer)
+	for i, q := range m.questions() {
+		if i > 0 {
+			fmt.Fprintln(writer)
+		}
 
-	// Today
-	fmt.Fprintln(writer, "\nWhat will you do today?")
-	fmt.Fprintln(writer, "(Enter multiple lines, press Enter twice to finish)")
-	entry.Today = m.collectMultiLineInput(scanner, writer)
+		answer, err := m.ask(scanner, writer, q)
+		if err != nil {
+			return nil, err
+		}
 
-	// Blockers
-	fmt.Fprintln(writer, "\nAny blockers?")
-	fmt.Fprint(writer, "> ")
-	if scanner.Scan() {
-		entry.Blockers = strings.TrimSpace(scanner.Text())
+		switch q.name {
+		case "Yesterday":
+			entry.Yesterday = answer
+		case "Today":
+			entry.Today = answer
+		case "Blockers":
+			if len(answer) > 0 {
+				entry.Blockers = answer[0]
+			}
+		default:
+			if len(answer) > 0 {
+				entry.Sections = append(entry.Sections, Section{Name: q.name, Items: answer})
+			}
+		}
 	}
+
 	if entry.Blockers == "" {
 		entry.Blockers = "None"
 	}
@@ -97,21 +292,111 @@ func (m *Manager) CollectEntry(reader io.Reader, writer io.Writer) (*Entry, erro
 	return entry, nil
 }
 
-// collectMultiLineInput collects multiple lines of input until an empty line
-func (m *Manager) collectMultiLineInput(scanner *bufio.Scanner, writer io.Writer) []string {
+// question is a single interactive prompt
+type question struct {
+	name     string
+	prompt   string
+	single   bool
+	required bool
+}
+
+// defaultPrompts holds the wording of the standard questions
+var defaultPrompts = map[string]string{
+	"Yesterday": "What did you do yesterday?",
+	"Today":     "What will you do today?",
+	"Blockers":  "Any blockers?",
+}
+
+// questions returns the questions to ask in order. Standard sections the
+// template doesn't mention come first, followed by the template's sections.
+func (m *Manager) questions() []question {
+	var questions []question
+	for _, name := range types.StandardSections {
+		if !m.inTemplate(name) {
+			questions = append(questions, question{name: name, prompt: defaultPrompts[name], single: name == "Blockers"})
+		}
+	}
+
+	for _, spec := range m.template {
+		q := question{name: spec.Name, prompt: spec.Prompt, single: spec.Single, required: spec.Required}
+		if standard := standardName(spec.Name); standard != "" {
+			q.name = standard
+			q.single = standard == "Blockers"
+			if q.prompt == "" {
+				q.prompt = defaultPrompts[standard]
+			}
+		} else if q.prompt == "" {
+			q.prompt = spec.Name + ":"
+		}
+		questions = append(questions, q)
+	}
+
+	return questions
+}
+
+// inTemplate checks if the template lists the named section
+func (m *Manager) inTemplate(name string) bool {
+	for _, spec := range m.template {
+		if strings.EqualFold(strings.TrimSpace(spec.Name), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// standardName returns the canonical name of a standard section, or ""
+func standardName(name string) string {
+	for _, standard := range types.StandardSections {
+		if strings.EqualFold(strings.TrimSpace(name), standard) {
+			return standard
+		}
+	}
+	return ""
+}
+
+// ask prompts for a single question, repeating required questions until answered
+func (m *Manager) ask(scanner *bufio.Scanner, writer io.Writer, q question) ([]string, error) {
+	for {
+		fmt.Fprintln(writer, q.prompt)
+
+		var answer []string
+		more := true
+		if q.single {
+			fmt.Fprint(writer, "> ")
+			more = scanner.Scan()
+			if more && strings.TrimSpace(scanner.Text()) != "" {
+				answer = []string{strings.TrimSpace(scanner.Text())}
+			}
+		} else {
+			fmt.Fprintln(writer, "(Enter multiple lines, press Enter twice to finish)")
+			answer, more = m.collectMultiLineInput(scanner, writer)
+		}
+
+		if len(answer) > 0 || !q.required {
+			return answer, nil
+		}
+		if !more {
+			return nil, fmt.Errorf("no answer given for required question: %s", q.prompt)
+		}
+		fmt.Fprintln(writer, "This question is required.")
+	}
+}
+
+// collectMultiLineInput collects multiple lines of input until an empty line.
+// It also reports whether more input may follow.
+func (m *Manager) collectMultiLineInput(scanner *bufio.Scanner, writer io.Writer) ([]string, bool) {
 	var lines []string
 	for {
 		fmt.Fprint(writer, "> ")
 		if !scanner.Scan() {
-			break
+			return lines, false
 		}
 		line := scanner.Text()
 		if line == "" {
-			break
+			return lines, true
 		}
 		lines = append(lines, line)
 	}
-	return lines
 }
 
 // EntryWriter handles writing standup entries
@@ -136,6 +421,13 @@ func (w *FileEntryWriter) WriteEntry(entry *Entry, userName string) error {
 
 // SaveEntry saves the standup entry to the user's file
 func (m *Manager) SaveEntry(entry *Entry, userName string) error {
+	if m.format.Structured() {
+		return m.saveStructuredEntry(entry, userName)
+	}
+	if m.format.Sharded() {
+		return m.saveShardedEntry(entry, userName)
+	}
+
 	filePath, err := m.ensureStandupFile(userName)
 	if err != nil {
 		return err
@@ -152,9 +444,28 @@ func (m *Manager) SaveEntry(entry *Entry, userName string) error {
 
 // GetStandupFilePath returns the path to the standup file for a user
 func (m *Manager) GetStandupFilePath(userName string) (string, error) {
-	standupDir := filepath.Join(m.repoPath, "stand-ups")
-	fileName := fmt.Sprintf("%s.md", strings.ToLower(userName))
-	return filepath.Join(standupDir, fileName), nil
+	return m.EntryPath(userName, m.today()), nil
+}
+
+// EntryPath returns the file a user's entry for the given day is saved to
+func (m *Manager) EntryPath(userName string, date time.Time) string {
+	if m.format.Structured() {
+		return m.GetEntryFilePath(userName, date)
+	}
+	if m.format.Sharded() {
+		return m.ShardPath(userName, date)
+	}
+	return m.singleFilePath(userName)
+}
+
+// UserPath returns the file, or for structured and monthly storage the
+// directory, that holds a user's standups. Saving an entry only changes
+// files under it.
+func (m *Manager) UserPath(userName string) string {
+	if m.format.Structured() || m.format.Sharded() {
+		return m.userDir(userName)
+	}
+	return m.singleFilePath(userName)
 }
 
 // ensureStandupFile ensures the standup directory exists and returns the file path
@@ -187,9 +498,17 @@ func (m *Manager) buildUpdatedContent(existingContent string, entry *Entry, user
 		return m.buildNewFileContent(entry, userName)
 	}
 
-	// Parse and update existing content
-	parsedContent := m.parseExistingContent(existingContent, entry.Date)
-	return m.assembleContent(parsedContent, entry, userName)
+	doc, err := parser.Parse([]byte(existingContent))
+	if err != nil {
+		// Unparseable content is kept below the new entry rather than dropped
+		doc = &parser.Document{Preamble: existingContent}
+	}
+	if doc.Title == "" {
+		doc.Preamble = fmt.Sprintf("# %s's Standups\n\n", userName) + doc.Preamble
+	}
+
+	doc.Upsert(MarkdownEntry(entry))
+	return string(doc.Bytes())
 }
 
 // buildNewFileContent creates content for a new standup file
@@ -201,170 +520,150 @@ func (m *Manager) buildNewFileContent(entry *Entry, userName string) string {
 	return content.String()
 }
 
-// parsedStandupContent represents parsed standup file content
-type parsedStandupContent struct {
-	header       string
-	otherEntries []string
-}
-
-// entryParser handles parsing of standup entries
-type entryParser struct {
-	dateStr string
-}
-
-// parseExistingContent parses existing content and removes today's entry if it exists
-func (m *Manager) parseExistingContent(content string, date time.Time) parsedStandupContent {
-	parser := &entryParser{dateStr: date.Format("2006-01-02")}
-	return parser.parse(content)
-}
-
-// parse processes the content and returns parsed standup content
-func (p *entryParser) parse(content string) parsedStandupContent {
-	lines := strings.Split(content, "\n")
-	result := parsedStandupContent{}
-	
-	state := &parseState{
-		currentEntry: &strings.Builder{},
+// MarkdownEntry converts an entry into its markdown representation
+func MarkdownEntry(entry *Entry) parser.Entry {
+	sections := []parser.Section{
+		{Title: "Yesterday", Items: itemsOrDefault(entry.Yesterday, "Nothing to report")},
+		{Title: "Today", Items: itemsOrDefault(entry.Today, "Nothing planned")},
+		{Title: "Blockers", Text: entry.Blockers},
 	}
-
-	for i, line := range lines {
-		p.processLine(line, i, len(lines), state, &result)
-	}
-
-	p.finalizeEntry(state, &result)
-	return result
-}
-
-// parseState holds the current state during parsing
-type parseState struct {
-	currentEntry *strings.Builder
-	inTodayEntry bool
-	headerFound  bool
-}
-
-// processLine processes a single line during parsing
-func (p *entryParser) processLine(line string, index, total int, state *parseState, result *parsedStandupContent) {
-	if p.isHeader(line) && !state.headerFound {
-		state.headerFound = true
-		result.header = line
-		return
+	for _, s := range entry.Sections {
+		sections = append(sections, parser.Section{Title: s.Name, Items: s.Items})
 	}
 
-	if p.shouldSkipLine(line, index, total, state) {
-		return
+	markdown := parser.Entry{
+		Date:     entry.Date,
+		Sections: sections,
 	}
-
-	if p.isTodayEntry(line) {
-		state.inTodayEntry = true
-		return
+	for _, u := range entry.Updates {
+		markdown.Updates = append(markdown.Updates, parser.Update{Time: u.At.Format(parser.TimeFormat), Sections: updateSections(u)})
 	}
-
-	if state.inTodayEntry && p.isEntryEnd(line) {
-		state.inTodayEntry = false
-		if line == "---" {
-			return // Skip the separator
-		}
+	if entry.AIAssisted {
+		markdown.Heading = entry.Date.Format(parser.DateFormat) + " " + AIAssistedMarker
 	}
-
-	if !state.inTodayEntry && state.headerFound {
-		state.currentEntry.WriteString(line)
-		state.currentEntry.WriteString("\n")
-	}
-}
-
-// isHeader checks if a line is a header
-func (p *entryParser) isHeader(line string) bool {
-	return strings.HasPrefix(line, "# ") && strings.Contains(line, "'s Standups")
-}
-
-// shouldSkipLine determines if a line should be skipped
-func (p *entryParser) shouldSkipLine(line string, index, total int, state *parseState) bool {
-	return state.headerFound && index < total-1 && strings.TrimSpace(line) == "" && !state.inTodayEntry
-}
-
-// isTodayEntry checks if a line starts today's entry
-func (p *entryParser) isTodayEntry(line string) bool {
-	return strings.HasPrefix(line, "## ") && strings.Contains(line, p.dateStr)
-}
-
-// isEntryEnd checks if a line marks the end of an entry
-func (p *entryParser) isEntryEnd(line string) bool {
-	return line == "---" || strings.HasPrefix(line, "## ")
+	return markdown
 }
 
-// finalizeEntry completes the parsing and adds any remaining content
-func (p *entryParser) finalizeEntry(state *parseState, result *parsedStandupContent) {
-	if state.currentEntry.Len() > 0 {
-		result.otherEntries = append(result.otherEntries, strings.TrimSpace(state.currentEntry.String()))
+// ConsolidatedMarkdown returns a markdown entry with its intraday updates
+// merged in; an entry without updates is returned as it is
+func ConsolidatedMarkdown(e parser.Entry) parser.Entry {
+	if len(e.Updates) == 0 {
+		return e
 	}
+	consolidated := MarkdownEntry(entryFromMarkdown(e).Consolidated())
+	consolidated.Heading = e.Heading
+	return consolidated
 }
 
-// assembleContent assembles the final content with the new entry
-func (m *Manager) assembleContent(parsed parsedStandupContent, entry *Entry, userName string) string {
-	var content strings.Builder
-	
-	// Write header
-	if parsed.header != "" {
-		content.WriteString(parsed.header)
-	} else {
-		content.WriteString(fmt.Sprintf("# %s's Standups", userName))
-	}
-	content.WriteString("\n\n")
-	
-	// Write today's entry
-	content.WriteString(m.formatEntry(entry))
-	content.WriteString("\n")
-	
-	// Write other entries
-	for _, otherEntry := range parsed.otherEntries {
-		if otherEntry != "" {
-			content.WriteString(otherEntry)
-			content.WriteString("\n")
-		}
+// updateSections returns the sections of an update that have content; an
+// update has no placeholders
+func updateSections(u Update) []parser.Section {
+	var sections []parser.Section
+	if len(u.Yesterday) > 0 {
+		sections = append(sections, parser.Section{Title: "Yesterday", Items: u.Yesterday})
 	}
-	
-	return content.String()
+	if len(u.Today) > 0 {
+		sections = append(sections, parser.Section{Title: "Today", Items: u.Today})
+	}
+	if strings.TrimSpace(u.Blockers) != "" {
+		sections = append(sections, parser.Section{Title: "Blockers", Text: u.Blockers})
+	}
+	for _, s := range u.Sections {
+		sections = append(sections, parser.Section{Title: s.Name, Items: s.Items})
+	}
+	return sections
 }
 
 // formatEntry formats a single standup entry
 func (m *Manager) formatEntry(entry *Entry) string {
-	var content strings.Builder
-	
-	fmt.Fprintf(&content, "## %s\n\n", entry.Date.Format("2006-01-02"))
-	
-	m.formatSection(&content, "Yesterday", entry.Yesterday, "Nothing to report")
-	m.formatSection(&content, "Today", entry.Today, "Nothing planned")
-	
-	fmt.Fprintf(&content, "**Blockers:**\n%s\n\n---\n", entry.Blockers)
-
-	return content.String()
+	return parser.FormatEntry(MarkdownEntry(entry))
 }
 
-// formatSection formats a section with items or a default message
-func (m *Manager) formatSection(w *strings.Builder, title string, items []string, defaultMsg string) {
-	fmt.Fprintf(w, "**%s:**\n", title)
+// itemsOrDefault returns the items, or a single default item if there are none
+func itemsOrDefault(items []string, defaultMsg string) []string {
 	if len(items) == 0 {
-		fmt.Fprintf(w, "- %s\n", defaultMsg)
-	} else {
-		for _, item := range items {
-			fmt.Fprintf(w, "- %s\n", item)
-		}
+		return []string{defaultMsg}
 	}
-	w.WriteString("\n")
+	return items
 }
 
 // FormatCommitMessage formats a commit message for the standup entry
 func (m *Manager) FormatCommitMessage(entry *Entry, userName string) string {
+	message := fmt.Sprintf("[Standup] %s - %s\n\n%s", userName, entry.Date.Format("2006-01-02"), CommitSummary(entry))
+	if entry.AIAssisted {
+		message += "\n\nAI-Assisted: true"
+	}
+	return message
+}
+
+// CommitSummary lists the entry's sections and updates one per line, as in
+// the body of the default commit message
+func CommitSummary(entry *Entry) string {
 	var builder strings.Builder
 
-	fmt.Fprintf(&builder, "[Standup] %s - %s\n\n", userName, entry.Date.Format("2006-01-02"))
 	fmt.Fprintf(&builder, "Yesterday: %s\n", formatItems(entry.Yesterday, "Nothing to report"))
 	fmt.Fprintf(&builder, "Today: %s\n", formatItems(entry.Today, "Nothing planned"))
 	fmt.Fprintf(&builder, "Blockers: %s", entry.Blockers)
-
+	for _, s := range entry.Sections {
+		fmt.Fprintf(&builder, "\n%s: %s", s.Name, strings.Join(s.Items, "; "))
+	}
+	for _, u := range entry.Updates {
+		fmt.Fprintf(&builder, "\nUpdate %s: %s", u.At.Format(parser.TimeFormat), formatUpdate(u))
+	}
 	return builder.String()
 }
 
+// CommitData is what a commit message template is executed with
+type CommitData struct {
+	User string
+	// Date is the standup's date, as 2006-01-02
+	Date  string
+	Entry *Entry
+	// Summary is the body of the default commit message
+	Summary string
+	// Direct is true for commits to the base branch and false for commits
+	// to the daily branch
+	Direct bool
+}
+
+// ExecuteCommitTemplate formats a commit message for the entry with a
+// template from types.ParseCommitTemplate. AI-assisted entries keep their
+// AI-Assisted trailer whatever the template.
+func ExecuteCommitTemplate(tmpl *template.Template, entry *Entry, userName string, direct bool) (string, error) {
+	data := CommitData{
+		User:    userName,
+		Date:    entry.Date.Format("2006-01-02"),
+		Entry:   entry,
+		Summary: CommitSummary(entry),
+		Direct:  direct,
+	}
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+
+	message := strings.TrimSpace(builder.String())
+	if message == "" {
+		return "", fmt.Errorf("the commit message is empty")
+	}
+	if entry.AIAssisted && !strings.Contains(message, "AI-Assisted:") {
+		message += "\n\nAI-Assisted: true"
+	}
+	return message, nil
+}
+
+// formatUpdate summarizes an update's items and blockers on one line
+func formatUpdate(u Update) string {
+	parts := append(append([]string(nil), u.Yesterday...), u.Today...)
+	for _, s := range u.Sections {
+		parts = append(parts, s.Items...)
+	}
+	if strings.TrimSpace(u.Blockers) != "" {
+		parts = append(parts, "Blockers: "+u.Blockers)
+	}
+	return strings.Join(parts, "; ")
+}
+
 // formatItems formats a list of items or returns a default message
 func formatItems(items []string, defaultMsg string) string {
 	if len(items) == 0 {
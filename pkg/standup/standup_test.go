@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
 )
 
 func TestCollectEntry(t *testing.T) {
@@ -100,6 +102,140 @@ Write tests
 	}
 }
 
+func TestCollectEntryWithTemplate(t *testing.T) {
+	input := "Did things\n\nWill do things\n\nNone\nLearned Go generics\nRead a paper\n\n80\n"
+
+	manager := NewManager("/test/repo")
+	manager.SetTemplate([]types.SectionSpec{
+		{Name: "Learnings"},
+		{Name: "Focus %", Single: true},
+		{Name: "Shoutouts"},
+	})
+
+	writer := &bytes.Buffer{}
+	entry, err := manager.CollectEntry(strings.NewReader(input), writer)
+	if err != nil {
+		t.Fatalf("CollectEntry() error = %v", err)
+	}
+
+	if got := entry.Section("Learnings"); !slicesEqual(got, []string{"Learned Go generics", "Read a paper"}) {
+		t.Errorf("Learnings = %v", got)
+	}
+	if got := entry.Section("focus %"); !slicesEqual(got, []string{"80"}) {
+		t.Errorf("Focus %% = %v, want [80]", got)
+	}
+	if len(entry.Sections) != 2 {
+		t.Errorf("got %d sections, want 2 (empty sections are skipped)", len(entry.Sections))
+	}
+
+	if !strings.Contains(writer.String(), "Shoutouts:") {
+		t.Error("Missing prompt for template section")
+	}
+}
+
+func TestCollectEntryCustomPrompts(t *testing.T) {
+	// Blockers is asked first, Today is required and re-prompted after an empty answer
+	input := "Waiting on review\nDid things\n\n\nPlan things\n\n"
+
+	manager := NewManager("/test/repo")
+	manager.SetTemplate([]types.SectionSpec{
+		{Name: "Blockers", Prompt: "Anything in your way?"},
+		{Name: "Yesterday"},
+		{Name: "today", Prompt: "What's the plan?", Required: true},
+	})
+
+	writer := &bytes.Buffer{}
+	entry, err := manager.CollectEntry(strings.NewReader(input), writer)
+	if err != nil {
+		t.Fatalf("CollectEntry() error = %v", err)
+	}
+
+	if entry.Blockers != "Waiting on review" {
+		t.Errorf("Blockers = %q, want %q", entry.Blockers, "Waiting on review")
+	}
+	if !slicesEqual(entry.Yesterday, []string{"Did things"}) {
+		t.Errorf("Yesterday = %v, want [Did things]", entry.Yesterday)
+	}
+	if !slicesEqual(entry.Today, []string{"Plan things"}) {
+		t.Errorf("Today = %v, want [Plan things]", entry.Today)
+	}
+
+	output := writer.String()
+	if !strings.HasPrefix(output, "Anything in your way?") {
+		t.Errorf("output should start with the custom blockers prompt, got %q", output)
+	}
+	if !strings.Contains(output, "What's the plan?") || strings.Contains(output, "What will you do today?") {
+		t.Error("custom prompt should replace the default wording")
+	}
+	if !strings.Contains(output, "This question is required.") {
+		t.Error("empty answer to a required question should re-prompt")
+	}
+}
+
+func TestCollectEntryRequiredAtEOF(t *testing.T) {
+	manager := NewManager("/test/repo")
+	manager.SetTemplate([]types.SectionSpec{{Name: "Learnings", Required: true}})
+
+	_, err := manager.CollectEntry(strings.NewReader("Did things\n\nWill do things\n\nNone\n"), &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("CollectEntry() should fail when input ends before a required question is answered")
+	}
+}
+
+func TestCollectEntryDayCutoff(t *testing.T) {
+	manager := NewManager("/test/repo")
+	// Every hour is before a cutoff of 24, so entries always land on the previous day
+	manager.SetDayCutoff(24)
+
+	entry, err := manager.CollectEntry(strings.NewReader("Did things\n\nWill do things\n\nNone\n"), &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("CollectEntry() error = %v", err)
+	}
+
+	want := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	if got := entry.Date.Format("2006-01-02"); got != want {
+		t.Errorf("Date = %s, want %s", got, want)
+	}
+}
+
+func TestSaveEntryWithSections(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "standup-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	manager := NewManager(tempDir)
+	date := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	entry := &Entry{
+		Date:      date,
+		Yesterday: []string{"Task A"},
+		Today:     []string{"Task B"},
+		Blockers:  "None",
+		Sections:  []Section{{Name: "Learnings", Items: []string{"Something new"}}},
+	}
+
+	if err := manager.SaveEntry(entry, "Alice"); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "stand-ups", "alice.md"))
+	if err != nil {
+		t.Fatalf("Failed to read saved file: %v", err)
+	}
+	if !strings.Contains(string(content), "**Learnings:**\n- Something new") {
+		t.Errorf("saved file missing template section:\n%s", content)
+	}
+
+	loaded, err := manager.LoadEntry("Alice", date)
+	if err != nil {
+		t.Fatalf("LoadEntry() error = %v", err)
+	}
+	if got := loaded.Section("Learnings"); !slicesEqual(got, []string{"Something new"}) {
+		t.Errorf("loaded Learnings = %v", got)
+	}
+}
+
 func TestSaveEntry(t *testing.T) {
 	// Create temp directory
 	tempDir, err := os.MkdirTemp("", "standup-test")
@@ -250,6 +386,107 @@ func TestSaveEntryEmptyItems(t *testing.T) {
 	}
 }
 
+func TestSaveEntryPreservesHandEdits(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "standup-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// A file edited by hand, with notes and blank lines the old parser dropped
+	existing := `Team notes kept above the header.
+
+# Alice's Standups
+
+## 2024-01-30
+
+**Yesterday:**
+- Planning
+
+Extra context written on GitHub.
+
+**Today:**
+- Building
+
+**Blockers:**
+None
+
+---
+`
+	standupDir := filepath.Join(tempDir, "stand-ups")
+	if err := os.MkdirAll(standupDir, 0755); err != nil {
+		t.Fatalf("Failed to create stand-ups dir: %v", err)
+	}
+	filePath := filepath.Join(standupDir, "alice.md")
+	if err := os.WriteFile(filePath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write existing file: %v", err)
+	}
+
+	manager := NewManager(tempDir)
+	entry := &Entry{
+		Date:      time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Yesterday: []string{"Building"},
+		Today:     []string{"Testing"},
+		Blockers:  "None",
+	}
+	if err := manager.SaveEntry(entry, "Alice"); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read saved file: %v", err)
+	}
+	contentStr := string(content)
+
+	for _, want := range []string{
+		"Team notes kept above the header.",
+		"Extra context written on GitHub.",
+		"## 2024-01-31",
+		"- Testing",
+	} {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("saved file missing %q:\n%s", want, contentStr)
+		}
+	}
+
+	if strings.Index(contentStr, "## 2024-01-31") > strings.Index(contentStr, "## 2024-01-30") {
+		t.Error("new entry should be placed above older entries")
+	}
+}
+
+func TestExecuteCommitTemplate(t *testing.T) {
+	entry := &Entry{
+		Date:       time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Today:      []string{"Frontend work", "Tests"},
+		Blockers:   "None",
+		AIAssisted: true,
+	}
+	tmpl, err := types.ParseCommitTemplate("docs(standup): {{lower .User}} for {{.Date}}\n\n{{join .Entry.Today \", \"}}\n\nStandup-Date: {{.Date}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, err := ExecuteCommitTemplate(tmpl, entry, "Alice", true)
+	if err != nil {
+		t.Fatalf("ExecuteCommitTemplate() error = %v", err)
+	}
+	want := "docs(standup): alice for 2024-01-31\n\nFrontend work, Tests\n\nStandup-Date: 2024-01-31\n\nAI-Assisted: true"
+	if message != want {
+		t.Errorf("ExecuteCommitTemplate() = %q, want %q", message, want)
+	}
+
+	for _, text := range []string{"{{.Missing}}", "{{if false}}x{{end}}"} {
+		tmpl, err := types.ParseCommitTemplate(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ExecuteCommitTemplate(tmpl, entry, "Alice", true); err == nil {
+			t.Errorf("ExecuteCommitTemplate(%q) should fail", text)
+		}
+	}
+}
+
 func TestFormatCommitMessage(t *testing.T) {
 	manager := NewManager("/test/repo")
 
@@ -307,6 +544,21 @@ func TestFormatCommitMessage(t *testing.T) {
 				"Blockers: Waiting for review",
 			},
 		},
+		{
+			name: "template sections",
+			entry: &Entry{
+				Date:      time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+				Yesterday: []string{"Worked"},
+				Today:     []string{"More work"},
+				Blockers:  "None",
+				Sections:  []Section{{Name: "Learnings", Items: []string{"Go", "SQL"}}},
+			},
+			userName: "Dana",
+			expected: []string{
+				"Blockers: None",
+				"Learnings: Go; SQL",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -349,4 +601,94 @@ func slicesEqual(a, b []string) bool {
 		}
 	}
 	return true
-}
\ No newline at end of file
+}
+func TestEntryMerge(t *testing.T) {
+	entry := &Entry{
+		Yesterday: []string{"Fixed login bug"},
+		Today:     []string{"Nothing planned"},
+		Blockers:  "Waiting on review",
+		Sections:  []Section{{Name: "Learnings", Items: []string{"Go generics"}}},
+	}
+
+	entry.Merge(&Entry{
+		Yesterday: []string{"fixed login bug", "Deployed hotfix"},
+		Today:     []string{"Write tests"},
+		Sections: []Section{
+			{Name: "learnings", Items: []string{"Fuzzing"}},
+			{Name: "Kudos", Items: []string{"Bob"}},
+		},
+	})
+
+	if !slicesEqual(entry.Yesterday, []string{"Fixed login bug", "Deployed hotfix"}) {
+		t.Errorf("Yesterday = %v", entry.Yesterday)
+	}
+	if !slicesEqual(entry.Today, []string{"Write tests"}) {
+		t.Errorf("Today = %v, placeholder should be replaced", entry.Today)
+	}
+	if entry.Blockers != "Waiting on review" {
+		t.Errorf("Blockers = %q, should be kept when the update has none", entry.Blockers)
+	}
+	if len(entry.Sections) != 2 || !slicesEqual(entry.Section("Learnings"), []string{"Go generics", "Fuzzing"}) {
+		t.Errorf("Sections = %+v", entry.Sections)
+	}
+
+	entry.Merge(&Entry{Blockers: "None"})
+	if entry.Blockers != "None" {
+		t.Errorf("Blockers = %q, want None", entry.Blockers)
+	}
+}
+
+func TestEntryUpdates(t *testing.T) {
+	date := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	entry := &Entry{
+		Date:      date,
+		Yesterday: []string{"Completed API"},
+		Today:     []string{"Write docs"},
+		Blockers:  "Waiting on review",
+		Sections:  []Section{{Name: "Learnings", Items: []string{"Go generics"}}},
+	}
+	entry.AddUpdate(&Entry{Today: []string{"Fixed the flaky test"}, Sections: []Section{{Name: "Learnings", Items: []string{"Fuzzing"}}}}, date.Add(11*time.Hour+15*time.Minute))
+	entry.AddUpdate(&Entry{Blockers: "None"}, date.Add(14*time.Hour+30*time.Minute))
+
+	consolidated := entry.Consolidated()
+	if !slicesEqual(consolidated.Today, []string{"Write docs", "Fixed the flaky test"}) || consolidated.Blockers != "None" {
+		t.Errorf("Consolidated() = %+v", consolidated)
+	}
+	if !slicesEqual(consolidated.Section("Learnings"), []string{"Go generics", "Fuzzing"}) || len(consolidated.Updates) != 0 {
+		t.Errorf("Consolidated() sections = %+v, updates = %+v", consolidated.Sections, consolidated.Updates)
+	}
+	if !slicesEqual(entry.Today, []string{"Write docs"}) || !slicesEqual(entry.Section("Learnings"), []string{"Go generics"}) || entry.Blockers != "Waiting on review" {
+		t.Errorf("Consolidated() changed the entry: %+v", entry)
+	}
+
+	for _, format := range []types.StorageFormat{types.StorageMarkdown, types.StorageYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			manager := NewManagerWithFormat(t.TempDir(), format)
+			if err := manager.SaveEntry(entry, "Alice"); err != nil {
+				t.Fatalf("SaveEntry() error = %v", err)
+			}
+
+			loaded, err := manager.LoadEntry("Alice", date)
+			if err != nil {
+				t.Fatalf("LoadEntry() error = %v", err)
+			}
+			if !slicesEqual(loaded.Today, entry.Today) || len(loaded.Updates) != 2 {
+				t.Fatalf("LoadEntry() = %+v", loaded)
+			}
+			if u := loaded.Updates[0]; u.At.Format("15:04") != "11:15" || !slicesEqual(u.Today, []string{"Fixed the flaky test"}) || !slicesEqual(u.Sections[0].Items, []string{"Fuzzing"}) {
+				t.Errorf("first update = %+v", u)
+			}
+			if u := loaded.Updates[1]; u.At.Format("15:04") != "14:30" || u.Blockers != "None" || len(u.Today) != 0 {
+				t.Errorf("second update = %+v", u)
+			}
+
+			entries, err := manager.LoadEntries("Alice")
+			if err != nil || len(entries) != 1 {
+				t.Fatalf("LoadEntries() = %+v, %v", entries, err)
+			}
+			if !slicesEqual(entries[0].Today, consolidated.Today) || entries[0].Blockers != "None" {
+				t.Errorf("LoadEntries() = %+v, want the consolidated entry", entries[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package standup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// noBlockers are blocker answers that mean there were none
+var noBlockers = map[string]bool{
+	"":        true,
+	"none":    true,
+	"no":      true,
+	"n/a":     true,
+	"nothing": true,
+	"-":       true,
+}
+
+// HasBlockers reports whether a blockers answer names a blocker, rather than
+// being empty or something like "None"
+func HasBlockers(blockers string) bool {
+	return !noBlockers[strings.ToLower(strings.TrimSpace(blockers))]
+}
+
+// topThemes is how many themes are listed per user and for the team
+const topThemes = 3
+
+// Stats are metrics computed from standups
+type Stats struct {
+	User string `json:"user"`
+	// Days is the number of standups submitted
+	Days int `json:"days"`
+	// BlockedDays is the number of standups that reported a blocker
+	BlockedDays int `json:"blockedDays"`
+	// ItemsPerDay is the average number of completed ("Yesterday") items
+	ItemsPerDay float64 `json:"itemsPerDay"`
+	// CurrentStreak is the run of consecutive working days with a standup
+	// that reaches today, or yesterday when today's isn't in yet. Weekends
+	// and out-of-office days don't break it.
+	CurrentStreak int `json:"currentStreak"`
+	// LongestStreak is the longest run of consecutive working days with a standup
+	LongestStreak int `json:"longestStreak"`
+	// Themes are the most mentioned project tags
+	Themes []ThemeCount `json:"themes"`
+}
+
+// BlockerRate returns the share of standups that reported a blocker
+func (s Stats) BlockerRate() float64 {
+	if s.Days == 0 {
+		return 0
+	}
+	return float64(s.BlockedDays) / float64(s.Days)
+}
+
+// ThemeCount is how often a project tag was mentioned
+type ThemeCount struct {
+	Theme string `json:"theme"`
+	Count int    `json:"count"`
+}
+
+// Blocker is a blocker reported in a standup
+type Blocker struct {
+	Date string `json:"date"`
+	User string `json:"user"`
+	Text string `json:"text"`
+}
+
+// TeamStats are the metrics for each user and the whole team
+type TeamStats struct {
+	Since    string    `json:"since,omitempty"`
+	Until    string    `json:"until,omitempty"`
+	Users    []Stats   `json:"users"`
+	Team     Stats     `json:"team"`
+	Blockers []Blocker `json:"blockers"`
+}
+
+// ComputeStats computes metrics from each user's entries dated within
+// [since, until]. Zero bounds are open. Streaks count working days up to
+// today, excusing the days users were out of office.
+func ComputeStats(entries map[string][]Entry, absences map[string][]Absence, since, until, today time.Time) TeamStats {
+	stats := TeamStats{Users: []Stats{}, Blockers: []Blocker{}}
+	if !since.IsZero() {
+		stats.Since = since.Format("2006-01-02")
+	}
+	if !until.IsZero() {
+		stats.Until = until.Format("2006-01-02")
+	}
+
+	users := make([]string, 0, len(entries))
+	for user := range entries {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	team := Stats{User: "Team"}
+	teamThemes := make(map[string]int)
+	teamItems := 0
+
+	for _, user := range users {
+		var inRange []Entry
+		for _, entry := range entries[user] {
+			day := entry.Date.Format("2006-01-02")
+			if (stats.Since != "" && day < stats.Since) || (stats.Until != "" && day > stats.Until) {
+				continue
+			}
+			inRange = append(inRange, entry)
+		}
+		if len(inRange) == 0 {
+			continue
+		}
+		sort.SliceStable(inRange, func(i, j int) bool { return inRange[i].Date.Before(inRange[j].Date) })
+
+		userStats := Stats{User: user, Days: len(inRange)}
+		themes := make(map[string]int)
+		items := 0
+		for _, entry := range inRange {
+			items += countItems(entry.Yesterday)
+			if blocker := strings.TrimSpace(entry.Blockers); !noBlockers[strings.ToLower(blocker)] {
+				userStats.BlockedDays++
+				stats.Blockers = append(stats.Blockers, Blocker{Date: entry.Date.Format("2006-01-02"), User: user, Text: blocker})
+			}
+			for _, item := range append(append([]string(nil), entry.Yesterday...), entry.Today...) {
+				if project, _ := ProjectTag(strings.TrimSpace(item)); project != UntaggedProject {
+					themes[project]++
+					teamThemes[project]++
+				}
+			}
+		}
+		userStats.ItemsPerDay = float64(items) / float64(len(inRange))
+		userAbsences := absences[strings.ToLower(user)]
+		off := func(day time.Time) bool {
+			_, ooo := OutOfOffice(userAbsences, day)
+			return isWeekend(day) || ooo
+		}
+		userStats.CurrentStreak, userStats.LongestStreak = streaks(inRange, today, off)
+		userStats.Themes = rankThemes(themes, topThemes)
+		stats.Users = append(stats.Users, userStats)
+
+		team.Days += userStats.Days
+		team.BlockedDays += userStats.BlockedDays
+		teamItems += items
+	}
+
+	if team.Days > 0 {
+		team.ItemsPerDay = float64(teamItems) / float64(team.Days)
+	}
+	team.Themes = rankThemes(teamThemes, topThemes)
+	stats.Team = team
+
+	sort.SliceStable(stats.Blockers, func(i, j int) bool { return stats.Blockers[i].Date < stats.Blockers[j].Date })
+	return stats
+}
+
+// RankStreaks orders users by current streak, then longest streak, then name
+func RankStreaks(users []Stats) []Stats {
+	ranked := append([]Stats(nil), users...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].CurrentStreak != ranked[j].CurrentStreak {
+			return ranked[i].CurrentStreak > ranked[j].CurrentStreak
+		}
+		if ranked[i].LongestStreak != ranked[j].LongestStreak {
+			return ranked[i].LongestStreak > ranked[j].LongestStreak
+		}
+		return ranked[i].User < ranked[j].User
+	})
+	return ranked
+}
+
+// Streak returns a user's current and longest streaks as of today, excusing
+// their days out of office. Like all stats it is computed from the
+// repository, so it is the same on every machine.
+func (m *Manager) Streak(userName string, today time.Time) (current, longest int, err error) {
+	entries, err := m.LoadEntries(userName)
+	if err != nil {
+		return 0, 0, err
+	}
+	absences, err := m.LoadAbsences(userName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	stats := ComputeStats(map[string][]Entry{userName: entries}, map[string][]Absence{strings.ToLower(userName): absences}, time.Time{}, time.Time{}, today)
+	if len(stats.Users) == 0 {
+		return 0, 0, nil
+	}
+	return stats.Users[0].CurrentStreak, stats.Users[0].LongestStreak, nil
+}
+
+// countItems counts the items that aren't placeholders for an empty answer
+func countItems(items []string) int {
+	count := 0
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item != "" && !placeholderItems[strings.ToLower(item)] {
+			count++
+		}
+	}
+	return count
+}
+
+// streaks returns the current and longest runs of consecutive working days
+// with a standup. off reports the days no standup is expected. entries must
+// be sorted oldest first.
+func streaks(entries []Entry, today time.Time, off func(time.Time) bool) (current, longest int) {
+	days := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		days[entry.Date.Format("2006-01-02")] = true
+	}
+
+	run := 0
+	var previous time.Time
+	for _, entry := range entries {
+		day := dateOnly(entry.Date)
+		// Standups on days off neither extend nor break a streak
+		if off(day) {
+			continue
+		}
+		switch {
+		case !previous.IsZero() && day.Equal(previous):
+			continue
+		case !previous.IsZero() && day.Equal(nextWorkday(previous, off)):
+			run++
+		default:
+			run = 1
+		}
+		previous = day
+		if run > longest {
+			longest = run
+		}
+	}
+
+	// Today's standup may not be in yet, so the current streak can end on
+	// the working day before
+	day := dateOnly(today)
+	if !days[day.Format("2006-01-02")] || off(day) {
+		day = previousWorkday(day, off)
+	}
+	for days[day.Format("2006-01-02")] {
+		current++
+		day = previousWorkday(day, off)
+	}
+	return current, longest
+}
+
+// rankThemes returns the n most counted themes, ties in name order
+func rankThemes(counts map[string]int, n int) []ThemeCount {
+	themes := make([]ThemeCount, 0, len(counts))
+	for theme, count := range counts {
+		themes = append(themes, ThemeCount{Theme: theme, Count: count})
+	}
+	sort.Slice(themes, func(i, j int) bool {
+		if themes[i].Count != themes[j].Count {
+			return themes[i].Count > themes[j].Count
+		}
+		return themes[i].Theme < themes[j].Theme
+	})
+	if len(themes) > n {
+		themes = themes[:n]
+	}
+	return themes
+}
+
+// FormatThemes lists themes as "Billing (4), Auth (2)"
+func FormatThemes(themes []ThemeCount) string {
+	parts := make([]string, len(themes))
+	for i, theme := range themes {
+		parts[i] = fmt.Sprintf("%s (%d)", theme.Theme, theme.Count)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// dateOnly drops the time of day
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// isWeekend reports whether t falls on a Saturday or Sunday
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// maxDaysOff bounds the search for a working day, in case every day is off
+const maxDaysOff = 366
+
+// nextWorkday returns the first day after t that isn't off
+func nextWorkday(t time.Time, off func(time.Time) bool) time.Time {
+	t = t.AddDate(0, 0, 1)
+	for i := 0; i < maxDaysOff && off(t); i++ {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// previousWorkday returns the last day before t that isn't off
+func previousWorkday(t time.Time, off func(time.Time) bool) time.Time {
+	t = t.AddDate(0, 0, -1)
+	for i := 0; i < maxDaysOff && off(t); i++ {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
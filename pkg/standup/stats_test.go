@@ -0,0 +1,133 @@
+package standup
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	// January 1, 2024 is a Monday
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	entries := map[string][]Entry{
+		"alice": {
+			{Date: day(8), Yesterday: []string{"[Billing] Export", "Reviews"}, Blockers: "Waiting on API keys"},
+			{Date: day(5), Yesterday: []string{"[Billing] Invoices"}, Blockers: "None"},
+			{Date: day(4), Yesterday: []string{"Nothing to report"}, Today: []string{"[Auth] Tokens"}},
+			{Date: day(3), Yesterday: []string{"[Billing] Schema", "[Auth] Login"}},
+			{Date: day(1), Yesterday: []string{"Planning"}},
+		},
+		"bob": {
+			{Date: day(2), Yesterday: []string{"Fixed CI #ci"}, Blockers: "Flaky runners"},
+		},
+	}
+
+	// Tuesday, before alice's standup is in
+	stats := ComputeStats(entries, nil, time.Time{}, time.Time{}, day(9))
+
+	if len(stats.Users) != 2 {
+		t.Fatalf("Users = %+v, want alice and bob", stats.Users)
+	}
+	alice := stats.Users[0]
+	if alice.User != "alice" || alice.Days != 5 || alice.BlockedDays != 1 {
+		t.Errorf("alice = %+v, want 5 days with 1 blocked", alice)
+	}
+	if alice.ItemsPerDay != 1.2 {
+		t.Errorf("alice.ItemsPerDay = %v, want 1.2 (placeholders aren't items)", alice.ItemsPerDay)
+	}
+	if alice.CurrentStreak != 4 || alice.LongestStreak != 4 {
+		t.Errorf("alice streaks = %d/%d, want 4/4 across the weekend", alice.CurrentStreak, alice.LongestStreak)
+	}
+	if len(alice.Themes) != 2 || alice.Themes[0] != (ThemeCount{"Billing", 3}) || alice.Themes[1] != (ThemeCount{"Auth", 2}) {
+		t.Errorf("alice.Themes = %+v, want Billing (3), Auth (2)", alice.Themes)
+	}
+
+	bob := stats.Users[1]
+	if bob.CurrentStreak != 0 || bob.LongestStreak != 1 {
+		t.Errorf("bob streaks = %d/%d, want 0/1", bob.CurrentStreak, bob.LongestStreak)
+	}
+
+	if stats.Team.Days != 6 || stats.Team.BlockedDays != 2 || stats.Team.ItemsPerDay != 7.0/6 {
+		t.Errorf("Team = %+v, want 6 days, 2 blocked, 7/6 items per day", stats.Team)
+	}
+	if len(stats.Blockers) != 2 || stats.Blockers[0].User != "bob" || stats.Blockers[1].Text != "Waiting on API keys" {
+		t.Errorf("Blockers = %+v, want bob's then alice's", stats.Blockers)
+	}
+
+	ranged := ComputeStats(entries, nil, day(3), day(5), day(9))
+	if ranged.Since != "2024-01-03" || ranged.Until != "2024-01-05" {
+		t.Errorf("range = %s to %s", ranged.Since, ranged.Until)
+	}
+	if len(ranged.Users) != 1 || ranged.Users[0].Days != 3 || ranged.Users[0].BlockedDays != 0 {
+		t.Errorf("ranged Users = %+v, want only alice's 3 unblocked days", ranged.Users)
+	}
+}
+
+func TestComputeStatsOutOfOffice(t *testing.T) {
+	// January 8-12, 2024 is Monday to Friday; alice was out on Wednesday
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	entries := map[string][]Entry{
+		"alice": {{Date: day(8)}, {Date: day(9)}, {Date: day(11)}, {Date: day(12)}},
+	}
+
+	stats := ComputeStats(entries, nil, time.Time{}, time.Time{}, day(12))
+	if got := stats.Users[0]; got.CurrentStreak != 2 || got.LongestStreak != 2 {
+		t.Errorf("streaks without absences = %d/%d, want 2/2", got.CurrentStreak, got.LongestStreak)
+	}
+
+	absences := map[string][]Absence{"alice": {{From: "2024-01-10", To: "2024-01-10", Reason: "Sick"}}}
+	stats = ComputeStats(entries, absences, time.Time{}, time.Time{}, day(12))
+	if got := stats.Users[0]; got.CurrentStreak != 4 || got.LongestStreak != 4 {
+		t.Errorf("streaks with the day excused = %d/%d, want 4/4", got.CurrentStreak, got.LongestStreak)
+	}
+}
+
+func TestStreak(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	// Friday January 12 and Monday to Wednesday January 15-17, 2024
+	for _, d := range []int{12, 15, 16, 17} {
+		entry := &Entry{Date: time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC), Today: []string{"Work"}, Blockers: "None"}
+		if err := manager.SaveEntry(entry, "Alice"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	current, longest, err := manager.Streak("Alice", time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC))
+	if err != nil || current != 4 || longest != 4 {
+		t.Errorf("Streak() = %d, %d, %v; want 4, 4, nil", current, longest, err)
+	}
+
+	// Nothing on Thursday or Friday breaks it by Monday
+	current, longest, err = manager.Streak("Alice", time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC))
+	if err != nil || current != 0 || longest != 4 {
+		t.Errorf("Streak() after a gap = %d, %d, %v; want 0, 4, nil", current, longest, err)
+	}
+
+	if current, longest, err := manager.Streak("Bob", time.Now()); err != nil || current != 0 || longest != 0 {
+		t.Errorf("Streak() without standups = %d, %d, %v; want 0, 0, nil", current, longest, err)
+	}
+}
+
+func TestRankStreaks(t *testing.T) {
+	ranked := RankStreaks([]Stats{
+		{User: "alice", CurrentStreak: 2, LongestStreak: 9},
+		{User: "bob", CurrentStreak: 5, LongestStreak: 5},
+		{User: "carol", CurrentStreak: 2, LongestStreak: 9},
+		{User: "dave", CurrentStreak: 2, LongestStreak: 3},
+	})
+
+	var order []string
+	for _, s := range ranked {
+		order = append(order, s.User)
+	}
+	if got := strings.Join(order, ","); got != "bob,alice,carol,dave" {
+		t.Errorf("RankStreaks() order = %s, want bob,alice,carol,dave", got)
+	}
+}
+
+func TestFormatThemes(t *testing.T) {
+	got := FormatThemes([]ThemeCount{{"Billing", 4}, {"Auth", 2}})
+	if got != "Billing (4), Auth (2)" {
+		t.Errorf("FormatThemes() = %q", got)
+	}
+}
@@ -0,0 +1,515 @@
+package standup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/standup/parser"
+	"github.com/standup-bot/standup-bot/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrEntryNotFound indicates there is no entry for the requested user and date
+var ErrEntryNotFound = errors.New("standup entry not found")
+
+// StoredEntry is the document written for each day in yaml and json storage
+type StoredEntry struct {
+	Date      string   `json:"date" yaml:"date"`
+	User      string   `json:"user" yaml:"user"`
+	Yesterday []string `json:"yesterday" yaml:"yesterday"`
+	Today     []string `json:"today" yaml:"today"`
+	Blockers  string   `json:"blockers" yaml:"blockers"`
+
+	Sections []StoredSection `json:"sections,omitempty" yaml:"sections,omitempty"`
+
+	AIAssisted bool `json:"aiAssisted,omitempty" yaml:"aiAssisted,omitempty"`
+
+	Updates []StoredUpdate `json:"updates,omitempty" yaml:"updates,omitempty"`
+}
+
+// StoredUpdate is an intraday update in a stored document
+type StoredUpdate struct {
+	// Time is the time of day of the update, e.g. "14:30"
+	Time      string          `json:"time" yaml:"time"`
+	Yesterday []string        `json:"yesterday,omitempty" yaml:"yesterday,omitempty"`
+	Today     []string        `json:"today,omitempty" yaml:"today,omitempty"`
+	Blockers  string          `json:"blockers,omitempty" yaml:"blockers,omitempty"`
+	Sections  []StoredSection `json:"sections,omitempty" yaml:"sections,omitempty"`
+}
+
+// StoredSection is an extra template section in a stored document
+type StoredSection struct {
+	Name  string   `json:"name" yaml:"name"`
+	Items []string `json:"items" yaml:"items"`
+}
+
+// GetEntryFilePath returns the path of a user's document for the given day in
+// structured storage (stand-ups/<user>/<date>.yaml)
+func (m *Manager) GetEntryFilePath(userName string, date time.Time) string {
+	return filepath.Join(m.userDir(userName), date.Format("2006-01-02")+m.format.Extension())
+}
+
+// userDir returns the directory holding a user's structured documents
+func (m *Manager) userDir(userName string) string {
+	return filepath.Join(m.repoPath, "stand-ups", strings.ToLower(userName))
+}
+
+// saveStructuredEntry writes the entry as a yaml or json document
+func (m *Manager) saveStructuredEntry(entry *Entry, userName string) error {
+	if err := m.fs.MkdirAll(m.userDir(userName), 0755); err != nil {
+		return fmt.Errorf("failed to create standup directory: %w", err)
+	}
+
+	data, err := encodeStoredEntry(m.format, NewStoredEntry(entry, userName))
+	if err != nil {
+		return err
+	}
+
+	return m.fs.WriteFile(m.GetEntryFilePath(userName, entry.Date), data, 0644)
+}
+
+// NewStoredEntry converts an entry into the document stored for it
+func NewStoredEntry(entry *Entry, userName string) StoredEntry {
+	stored := StoredEntry{
+		Date:      entry.Date.Format("2006-01-02"),
+		User:      userName,
+		Yesterday: entry.Yesterday,
+		Today:     entry.Today,
+		Blockers:  entry.Blockers,
+
+		AIAssisted: entry.AIAssisted,
+	}
+	for _, s := range entry.Sections {
+		stored.Sections = append(stored.Sections, StoredSection{Name: s.Name, Items: s.Items})
+	}
+	for _, u := range entry.Updates {
+		update := StoredUpdate{Time: u.At.Format(parser.TimeFormat), Yesterday: u.Yesterday, Today: u.Today, Blockers: u.Blockers}
+		for _, s := range u.Sections {
+			update.Sections = append(update.Sections, StoredSection{Name: s.Name, Items: s.Items})
+		}
+		stored.Updates = append(stored.Updates, update)
+	}
+	return stored
+}
+
+// LoadEntry reads a user's entry for the given day in any storage format,
+// with its intraday updates kept apart in Updates
+func (m *Manager) LoadEntry(userName string, date time.Time) (*Entry, error) {
+	if m.format.Structured() {
+		data, err := m.fs.ReadFile(m.GetEntryFilePath(userName, date))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, ErrEntryNotFound
+			}
+			return nil, fmt.Errorf("failed to read standup entry: %w", err)
+		}
+		return decodeStoredEntry(m.format, data)
+	}
+
+	for _, filePath := range m.markdownFilesFor(userName, date) {
+		entry, err := m.loadMarkdownEntry(filePath, date)
+		if !errors.Is(err, ErrEntryNotFound) {
+			return entry, err
+		}
+	}
+	return nil, ErrEntryNotFound
+}
+
+// loadMarkdownEntry reads the entry for the given day from a markdown file
+func (m *Manager) loadMarkdownEntry(filePath string, date time.Time) (*Entry, error) {
+	content, err := m.fs.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to read standup file: %w", err)
+	}
+
+	doc, err := parser.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse standup file: %w", err)
+	}
+
+	found := doc.Find(date)
+	if found == nil {
+		return nil, ErrEntryNotFound
+	}
+	return entryFromMarkdown(*found), nil
+}
+
+// EntrySource returns the source of the entry for date in the contents of
+// one of the user's standup files, as written: the whole document in
+// structured storage, the entry's section of a markdown file. It is empty if
+// the file has no entry for date.
+func (m *Manager) EntrySource(content []byte, date time.Time) (string, error) {
+	if m.format.Structured() {
+		return string(content), nil
+	}
+	doc, err := parser.Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse standup file: %w", err)
+	}
+	found := doc.Find(date)
+	if found == nil {
+		return "", nil
+	}
+	return strings.TrimRight(found.Raw, "\n") + "\n", nil
+}
+
+// LoadEntries reads all of a user's entries in any storage format, newest
+// first, each with its intraday updates merged in. A user without a standup
+// file has no entries.
+func (m *Manager) LoadEntries(userName string) ([]Entry, error) {
+	if m.indexPath != "" {
+		all, err := m.indexedEntries([]string{userName})
+		if err != nil {
+			return nil, err
+		}
+		return all[userName], nil
+	}
+	return m.readEntries(userName)
+}
+
+// readEntries parses all of a user's entries from their standup files
+func (m *Manager) readEntries(userName string) ([]Entry, error) {
+	var entries []Entry
+
+	if m.format.Structured() {
+		files, err := m.fs.ReadDir(m.userDir(userName))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to read standup directory: %w", err)
+		}
+
+		for _, f := range files {
+			if f.IsDir() || filepath.Ext(f.Name()) != m.format.Extension() {
+				continue
+			}
+			data, err := m.fs.ReadFile(filepath.Join(m.userDir(userName), f.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read standup entry: %w", err)
+			}
+			entry, err := decodeStoredEntry(m.format, data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name(), err)
+			}
+			entries = append(entries, *entry.Consolidated())
+		}
+	} else {
+		filePaths, err := m.markdownFiles(userName)
+		if err != nil {
+			return nil, err
+		}
+
+		seen := make(map[time.Time]bool)
+		for _, filePath := range filePaths {
+			content, err := m.fs.ReadFile(filePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read standup file: %w", err)
+			}
+
+			parsed, err := parser.ParseEntries(content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(filePath), err)
+			}
+			for _, e := range parsed {
+				// A day in both a monthly file and the old single file
+				// counts once, as LoadEntry reads it
+				if !e.Date.IsZero() && !seen[e.Date] {
+					seen[e.Date] = true
+					entries = append(entries, *entryFromMarkdown(e).Consolidated())
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.After(entries[j].Date)
+	})
+	return entries, nil
+}
+
+// Users returns the names of everyone with standups in the repository, sorted
+func (m *Manager) Users() ([]string, error) {
+	files, err := m.fs.ReadDir(filepath.Join(m.repoPath, "stand-ups"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read standup directory: %w", err)
+	}
+
+	var users []string
+	for _, f := range files {
+		// Hidden entries, such as the out-of-office directory, aren't users
+		if strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		var user string
+		if m.format.Structured() || m.format.Sharded() {
+			if f.IsDir() {
+				user = f.Name()
+			}
+		}
+		if !m.format.Structured() && !f.IsDir() && filepath.Ext(f.Name()) == ".md" {
+			user = strings.TrimSuffix(f.Name(), ".md")
+		}
+		if user != "" {
+			users = append(users, user)
+		}
+	}
+	sort.Strings(users)
+	// With monthly storage a user can have both a directory and the single
+	// file from before
+	return slices.Compact(users), nil
+}
+
+// LoadAllEntries returns every user's entries, newest first, by user
+func (m *Manager) LoadAllEntries() (map[string][]Entry, error) {
+	users, err := m.Users()
+	if err != nil {
+		return nil, err
+	}
+	if m.indexPath != "" {
+		return m.indexedEntries(users)
+	}
+
+	loaded := make([][]Entry, len(users))
+	err = forEachUser(users, func(i int, user string) error {
+		entries, err := m.LoadEntries(user)
+		if err != nil {
+			return fmt.Errorf("failed to load standups for %s: %w", user, err)
+		}
+		loaded[i] = entries
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	all := make(map[string][]Entry, len(users))
+	for i, user := range users {
+		all[user] = loaded[i]
+	}
+	return all, nil
+}
+
+// LoadTeamEntries returns every user's entry for the given day, with its
+// intraday updates merged in, sorted by user. Users without an entry that
+// day are left out.
+func (m *Manager) LoadTeamEntries(date time.Time) ([]StoredEntry, error) {
+	users, err := m.Users()
+	if err != nil {
+		return nil, err
+	}
+	if m.indexPath != "" {
+		return m.indexedTeamEntries(users, date)
+	}
+
+	loaded := make([]*Entry, len(users))
+	err = forEachUser(users, func(i int, user string) error {
+		entry, err := m.LoadEntry(user, date)
+		if errors.Is(err, ErrEntryNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load standup for %s: %w", user, err)
+		}
+		loaded[i] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var team []StoredEntry
+	for i, user := range users {
+		if loaded[i] != nil {
+			team = append(team, NewStoredEntry(loaded[i].Consolidated(), user))
+		}
+	}
+	return team, nil
+}
+
+// HasEntry checks if a user has an entry for the given day
+func (m *Manager) HasEntry(userName string, date time.Time) (bool, error) {
+	_, err := m.LoadEntry(userName, date)
+	if errors.Is(err, ErrEntryNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// IsDuplicate reports whether the user already has this exact entry for its
+// day, so saving it again would leave their standup files unchanged
+func (m *Manager) IsDuplicate(entry *Entry, userName string) (bool, error) {
+	if m.format.Structured() {
+		existing, err := m.fs.ReadFile(m.GetEntryFilePath(userName, entry.Date))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to read standup entry: %w", err)
+		}
+		data, err := encodeStoredEntry(m.format, NewStoredEntry(entry, userName))
+		if err != nil {
+			return false, err
+		}
+		return string(data) == string(existing), nil
+	}
+
+	filePath := m.singleFilePath(userName)
+	if m.format.Sharded() {
+		filePath = m.ShardPath(userName, entry.Date)
+	}
+	existing, err := m.readExistingContent(filePath)
+	if err != nil || existing == "" {
+		return false, err
+	}
+	return m.buildUpdatedContent(existing, entry, userName) == existing, nil
+}
+
+// ReadEntryFile reads a yaml or json entry document, choosing the decoder
+// from the file extension
+func ReadEntryFile(path string) (*Entry, error) {
+	format := types.StorageYAML
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = types.StorageJSON
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeStoredEntry(format, data)
+}
+
+// encodeStoredEntry marshals a document in the given format
+func encodeStoredEntry(format types.StorageFormat, stored StoredEntry) ([]byte, error) {
+	if format == types.StorageJSON {
+		data, err := json.MarshalIndent(stored, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal standup entry: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+
+	data, err := yaml.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal standup entry: %w", err)
+	}
+	return data, nil
+}
+
+// decodeStoredEntry unmarshals a document in the given format
+func decodeStoredEntry(format types.StorageFormat, data []byte) (*Entry, error) {
+	var stored StoredEntry
+	var err error
+	if format == types.StorageJSON {
+		err = json.Unmarshal(data, &stored)
+	} else {
+		err = yaml.Unmarshal(data, &stored)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse standup entry: %w", err)
+	}
+
+	date, err := time.Parse("2006-01-02", stored.Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date in standup entry: %w", err)
+	}
+
+	entry := &Entry{
+		Date:      date,
+		Yesterday: stored.Yesterday,
+		Today:     stored.Today,
+		Blockers:  stored.Blockers,
+
+		AIAssisted: stored.AIAssisted,
+	}
+	for _, s := range stored.Sections {
+		entry.Sections = append(entry.Sections, Section{Name: s.Name, Items: s.Items})
+	}
+	for _, u := range stored.Updates {
+		at, err := time.Parse(parser.TimeFormat, u.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid update time in standup entry: %w", err)
+		}
+		update := Update{
+			At:        time.Date(date.Year(), date.Month(), date.Day(), at.Hour(), at.Minute(), 0, 0, date.Location()),
+			Yesterday: u.Yesterday,
+			Today:     u.Today,
+			Blockers:  u.Blockers,
+		}
+		for _, s := range u.Sections {
+			update.Sections = append(update.Sections, Section{Name: s.Name, Items: s.Items})
+		}
+		entry.Updates = append(entry.Updates, update)
+	}
+	return entry, nil
+}
+
+// entryFromMarkdown converts a parsed markdown entry into an Entry
+func entryFromMarkdown(e parser.Entry) *Entry {
+	entry := &Entry{
+		Date:      e.Date,
+		Yesterday: e.Items("Yesterday"),
+		Today:     e.Items("Today"),
+
+		AIAssisted: strings.Contains(e.Heading, AIAssistedMarker),
+	}
+	if blockers, ok := e.Section("Blockers"); ok {
+		entry.Blockers = blockers.Text
+	}
+
+	entry.Sections = customSections(e.Sections)
+
+	for _, u := range e.Updates {
+		at, err := time.Parse(parser.TimeFormat, u.Time)
+		if err != nil {
+			continue
+		}
+		update := Update{
+			At:       time.Date(e.Date.Year(), e.Date.Month(), e.Date.Day(), at.Hour(), at.Minute(), 0, 0, e.Date.Location()),
+			Sections: customSections(u.Sections),
+		}
+		for _, s := range u.Sections {
+			switch {
+			case strings.EqualFold(s.Title, "Yesterday"):
+				update.Yesterday = s.Items
+			case strings.EqualFold(s.Title, "Today"):
+				update.Today = s.Items
+			case strings.EqualFold(s.Title, "Blockers"):
+				update.Blockers = s.Text
+			}
+		}
+		entry.Updates = append(entry.Updates, update)
+	}
+	return entry
+}
+
+// customSections returns the sections beyond the standard ones, which came
+// from a template
+func customSections(sections []parser.Section) []Section {
+	var custom []Section
+	for _, s := range sections {
+		if types.IsStandardSection(s.Title) {
+			continue
+		}
+		items := s.Items
+		if s.Text != "" {
+			items = append(items, s.Text)
+		}
+		custom = append(custom, Section{Name: s.Title, Items: items})
+	}
+	return custom
+}
@@ -0,0 +1,262 @@
+package standup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+func TestStructuredStorage(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   types.StorageFormat
+		fileName string
+		contains string
+	}{
+		{name: "yaml", format: types.StorageYAML, fileName: "2024-01-31.yaml", contains: "blockers: Waiting for review"},
+		{name: "json", format: types.StorageJSON, fileName: "2024-01-31.json", contains: `"blockers": "Waiting for review"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, err := os.MkdirTemp("", "standup-test")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			manager := NewManagerWithFormat(tempDir, tt.format)
+			date := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+			entry := &Entry{
+				Date:      date,
+				Yesterday: []string{"Completed API"},
+				Today:     []string{"Write docs"},
+				Blockers:  "Waiting for review",
+			}
+
+			if err := manager.SaveEntry(entry, "Alice"); err != nil {
+				t.Fatalf("SaveEntry() error = %v", err)
+			}
+
+			filePath := filepath.Join(tempDir, "stand-ups", "alice", tt.fileName)
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("Failed to read saved document: %v", err)
+			}
+			if !strings.Contains(string(content), tt.contains) {
+				t.Errorf("document missing %q:\n%s", tt.contains, content)
+			}
+
+			loaded, err := manager.LoadEntry("Alice", date)
+			if err != nil {
+				t.Fatalf("LoadEntry() error = %v", err)
+			}
+			if !slicesEqual(loaded.Yesterday, entry.Yesterday) || !slicesEqual(loaded.Today, entry.Today) {
+				t.Errorf("LoadEntry() = %+v, want %+v", loaded, entry)
+			}
+			if loaded.Blockers != entry.Blockers {
+				t.Errorf("Blockers = %v, want %v", loaded.Blockers, entry.Blockers)
+			}
+
+			fromFile, err := ReadEntryFile(filePath)
+			if err != nil {
+				t.Fatalf("ReadEntryFile() error = %v", err)
+			}
+			if !fromFile.Date.Equal(date) {
+				t.Errorf("ReadEntryFile() date = %v, want %v", fromFile.Date, date)
+			}
+		})
+	}
+}
+
+func TestHasEntry(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "standup-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	manager := NewManager(tempDir)
+	date := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	has, err := manager.HasEntry("Alice", date)
+	if err != nil || has {
+		t.Errorf("HasEntry() before save = %v, %v; want false, nil", has, err)
+	}
+
+	entry := &Entry{Date: date, Today: []string{"Work"}, Blockers: "None"}
+	if err := manager.SaveEntry(entry, "Alice"); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	has, err = manager.HasEntry("Alice", date)
+	if err != nil || !has {
+		t.Errorf("HasEntry() after save = %v, %v; want true, nil", has, err)
+	}
+
+	has, err = manager.HasEntry("Alice", date.AddDate(0, 0, 1))
+	if err != nil || has {
+		t.Errorf("HasEntry() for another day = %v, %v; want false, nil", has, err)
+	}
+}
+
+func TestIsDuplicate(t *testing.T) {
+	formats := []types.StorageFormat{types.StorageMarkdown, types.StorageMonthly, types.StorageYAML, types.StorageJSON}
+	for _, format := range formats {
+		t.Run(string(format), func(t *testing.T) {
+			manager := NewManagerWithFormat(t.TempDir(), format)
+			date := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+			entry := &Entry{Date: date, Yesterday: []string{"Completed API"}, Today: []string{"Write docs"}, Blockers: "None"}
+
+			if dup, err := manager.IsDuplicate(entry, "Alice"); err != nil || dup {
+				t.Errorf("IsDuplicate() before save = %v, %v; want false, nil", dup, err)
+			}
+
+			earlier := &Entry{Date: date.AddDate(0, 0, -1), Today: []string{"Start API"}, Blockers: "None"}
+			for _, e := range []*Entry{earlier, entry} {
+				if err := manager.SaveEntry(e, "Alice"); err != nil {
+					t.Fatalf("SaveEntry() error = %v", err)
+				}
+			}
+
+			same := &Entry{Date: date, Yesterday: []string{"Completed API"}, Today: []string{"Write docs"}, Blockers: "None"}
+			if dup, err := manager.IsDuplicate(same, "Alice"); err != nil || !dup {
+				t.Errorf("IsDuplicate() for the same entry = %v, %v; want true, nil", dup, err)
+			}
+
+			changed := &Entry{Date: date, Yesterday: []string{"Completed API"}, Today: []string{"Write docs", "Review PRs"}, Blockers: "None"}
+			if dup, err := manager.IsDuplicate(changed, "Alice"); err != nil || dup {
+				t.Errorf("IsDuplicate() for a changed entry = %v, %v; want false, nil", dup, err)
+			}
+		})
+	}
+}
+
+func TestLoadEntries(t *testing.T) {
+	for _, format := range []types.StorageFormat{types.StorageMarkdown, types.StorageMonthly, types.StorageYAML} {
+		t.Run(format.String(), func(t *testing.T) {
+			manager := NewManagerWithFormat(t.TempDir(), format)
+
+			if entries, err := manager.LoadEntries("Alice"); err != nil || len(entries) != 0 {
+				t.Fatalf("LoadEntries() without a file = %v, %v", entries, err)
+			}
+
+			for _, d := range []int{30, 31, 29} {
+				entry := &Entry{
+					Date:      time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC),
+					Yesterday: []string{fmt.Sprintf("Task %d", d)},
+					Today:     []string{"Next"},
+					Blockers:  "None",
+				}
+				if err := manager.SaveEntry(entry, "Alice"); err != nil {
+					t.Fatalf("SaveEntry() error = %v", err)
+				}
+			}
+
+			entries, err := manager.LoadEntries("Alice")
+			if err != nil {
+				t.Fatalf("LoadEntries() error = %v", err)
+			}
+			if len(entries) != 3 {
+				t.Fatalf("LoadEntries() returned %d entries, want 3", len(entries))
+			}
+			if entries[0].Date.Day() != 31 || entries[2].Date.Day() != 29 {
+				t.Errorf("entries should be newest first, got %v, %v", entries[0].Date, entries[2].Date)
+			}
+			if !slicesEqual(entries[1].Yesterday, []string{"Task 30"}) {
+				t.Errorf("Yesterday = %v, want [Task 30]", entries[1].Yesterday)
+			}
+		})
+	}
+}
+
+func TestLoadTeamEntries(t *testing.T) {
+	for _, format := range []types.StorageFormat{types.StorageMarkdown, types.StorageJSON} {
+		t.Run(format.String(), func(t *testing.T) {
+			manager := NewManagerWithFormat(t.TempDir(), format)
+			day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+			if team, err := manager.LoadTeamEntries(day); err != nil || len(team) != 0 {
+				t.Fatalf("LoadTeamEntries() without standups = %v, %v", team, err)
+			}
+
+			submissions := []struct {
+				user string
+				date time.Time
+			}{
+				{"Carol", day},
+				{"Alice", day},
+				{"Bob", day.AddDate(0, 0, -1)},
+			}
+			for _, s := range submissions {
+				entry := &Entry{Date: s.date, Yesterday: []string{s.user + " work"}, Today: []string{"Next"}, Blockers: "None"}
+				if err := manager.SaveEntry(entry, s.user); err != nil {
+					t.Fatalf("SaveEntry() error = %v", err)
+				}
+			}
+
+			team, err := manager.LoadTeamEntries(day)
+			if err != nil {
+				t.Fatalf("LoadTeamEntries() error = %v", err)
+			}
+			if len(team) != 2 || team[0].User != "alice" || team[1].User != "carol" {
+				t.Fatalf("LoadTeamEntries() = %+v, want alice and carol", team)
+			}
+			if team[0].Date != "2024-01-15" || !slicesEqual(team[0].Yesterday, []string{"Alice work"}) {
+				t.Errorf("unexpected entry: %+v", team[0])
+			}
+		})
+	}
+}
+
+func TestAIAssistedRoundTrip(t *testing.T) {
+	for _, format := range []types.StorageFormat{types.StorageMarkdown, types.StorageMonthly, types.StorageYAML} {
+		t.Run(format.String(), func(t *testing.T) {
+			manager := NewManagerWithFormat(t.TempDir(), format)
+			day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+			entry := &Entry{Date: day, Yesterday: []string{"Work"}, Today: []string{"More"}, Blockers: "None", AIAssisted: true}
+			if err := manager.SaveEntry(entry, "alice"); err != nil {
+				t.Fatalf("SaveEntry() error = %v", err)
+			}
+
+			loaded, err := manager.LoadEntry("alice", day)
+			if err != nil {
+				t.Fatalf("LoadEntry() error = %v", err)
+			}
+			if !loaded.AIAssisted {
+				t.Error("AI-assisted marker should survive a save")
+			}
+			if !slicesEqual(loaded.Yesterday, []string{"Work"}) {
+				t.Errorf("Yesterday = %v", loaded.Yesterday)
+			}
+		})
+	}
+
+	message := NewManager(t.TempDir()).FormatCommitMessage(&Entry{Blockers: "None", AIAssisted: true}, "alice")
+	if !strings.HasSuffix(message, "\n\nAI-Assisted: true") {
+		t.Errorf("commit message should end with the AI-Assisted trailer:\n%s", message)
+	}
+}
+
+func TestUserPath(t *testing.T) {
+	tests := []struct {
+		format types.StorageFormat
+		want   string
+	}{
+		{format: types.StorageMarkdown, want: filepath.Join("/repo", "stand-ups", "alice.md")},
+		{format: types.StorageMonthly, want: filepath.Join("/repo", "stand-ups", "alice")},
+		{format: types.StorageYAML, want: filepath.Join("/repo", "stand-ups", "alice")},
+	}
+
+	for _, tt := range tests {
+		if got := NewManagerWithFormat("/repo", tt.format).UserPath("Alice"); got != tt.want {
+			t.Errorf("UserPath() with %s = %s, want %s", tt.format, got, tt.want)
+		}
+	}
+}
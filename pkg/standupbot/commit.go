@@ -0,0 +1,46 @@
+package standupbot
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// CommitMessage formats the message of a commit recording a user's standup,
+// directly to the base branch or to the daily branch, with the configured
+// template and the team's redactions. A template that fails is warned about
+// and the default message used, since it shouldn't hold up the standup.
+func CommitMessage(cfg *config.Config, manager *standup.Manager, entry *standup.Entry, userName string, direct bool) string {
+	redact := teamRedactor(cfg.LocalRepoPath)
+	entry = redact.entry(entry)
+	defer redact.warn("the commit message")
+
+	if text := cfg.CommitTemplate(); text != "" {
+		message, err := executeCommitTemplate(text, entry, userName, direct)
+		if err == nil {
+			return message
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  Using the default commit message: invalid commit template: %v\n", err)
+	}
+
+	if direct {
+		return manager.FormatCommitMessage(entry, userName)
+	}
+	message := fmt.Sprintf("[Standup] %s - %s", userName, entry.Date.Format("2006-01-02"))
+	if entry.AIAssisted {
+		message += "\n\nAI-Assisted: true"
+	}
+	return message
+}
+
+// executeCommitTemplate parses and executes a commit message template
+func executeCommitTemplate(text string, entry *standup.Entry, userName string, direct bool) (string, error) {
+	tmpl, err := types.ParseCommitTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	return standup.ExecuteCommitTemplate(tmpl, entry, userName, direct)
+}
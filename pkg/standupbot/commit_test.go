@@ -0,0 +1,43 @@
+package standupbot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestCommitMessage(t *testing.T) {
+	repoPath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoPath, config.TeamConfigFile), []byte("redaction: {emails: true}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := standup.NewManager(repoPath)
+	entry := &standup.Entry{Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Today: []string{"Email bob@customer.com"}, Blockers: "None"}
+
+	cfg := &config.Config{LocalRepoPath: repoPath}
+	if got := CommitMessage(cfg, manager, entry, "Alice", false); got != "[Standup] Alice - 2024-01-15" {
+		t.Errorf("PR workflow message = %q", got)
+	}
+	direct := CommitMessage(cfg, manager, entry, "Alice", true)
+	if !strings.HasPrefix(direct, "[Standup] Alice - 2024-01-15\n\n") || !strings.Contains(direct, "Today: Email [redacted]") {
+		t.Errorf("direct message = %q", direct)
+	}
+
+	cfg.Commit = &config.CommitConfig{Template: "docs(standup): {{.User}} - {{.Date}}{{if .Direct}}\n\n{{.Summary}}{{end}}\n\nStandup-Date: {{.Date}}"}
+	if got := CommitMessage(cfg, manager, entry, "Alice", false); got != "docs(standup): Alice - 2024-01-15\n\nStandup-Date: 2024-01-15" {
+		t.Errorf("templated PR workflow message = %q", got)
+	}
+	if got := CommitMessage(cfg, manager, entry, "Alice", true); !strings.Contains(got, "Today: Email [redacted]\n") || !strings.HasSuffix(got, "Standup-Date: 2024-01-15") {
+		t.Errorf("templated direct message = %q", got)
+	}
+
+	cfg.Commit.Template = "{{.Missing}}"
+	if got := CommitMessage(cfg, manager, entry, "Alice", false); got != "[Standup] Alice - 2024-01-15" {
+		t.Errorf("a failing template should fall back to the default message, got %q", got)
+	}
+}
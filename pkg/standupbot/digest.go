@@ -0,0 +1,150 @@
+package standupbot
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+// sendMail delivers a message; tests replace it
+var sendMail = smtp.SendMail
+
+// SendDigest emails the standups for date, as found in the clone, to the
+// digest's recipients: an HTML version with a plain text fallback. Members
+// in the team's digest.optOut are left out. It returns how many standups
+// were sent, and sends nothing without digest settings or standups.
+func (b *Bot) SendDigest(date time.Time) (int, error) {
+	settings := b.cfg.Digest
+	if settings == nil {
+		return 0, nil
+	}
+
+	team, err := config.LoadTeamConfig(b.cfg.LocalRepoPath)
+	if err != nil {
+		return 0, err
+	}
+	recipients := settings.To
+	if len(team.Digest.To) > 0 {
+		recipients = team.Digest.To
+	}
+	if len(recipients) == 0 {
+		return 0, fmt.Errorf("no digest recipients: list them under digest.to in %s or the config", config.TeamConfigFile)
+	}
+
+	standups, err := loadDailyStandups(b.cfg.LocalRepoPath, date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read standups: %w", err)
+	}
+	optedOut := func(user string) bool {
+		return slices.ContainsFunc(team.Digest.OptOut, func(name string) bool { return strings.EqualFold(name, user) })
+	}
+	standups = slices.DeleteFunc(standups, func(s dailyStandup) bool { return optedOut(s.User) })
+	if len(standups) == 0 {
+		return 0, nil
+	}
+	away := outOfOffice(b.cfg.LocalRepoPath, date)
+	for user := range away {
+		if optedOut(user) {
+			delete(away, user)
+		}
+	}
+	annotateStandups(b.cfg.LocalRepoPath, date, standups, team)
+
+	text := fmt.Sprintf("# Daily Standups - %s\n\n", date.Format("2006-01-02")) + formatStandups(standups, away, team, formatUserStandup)
+	var html bytes.Buffer
+	html.WriteString("<!DOCTYPE html>\n<html>\n<body>\n")
+	// goldmark leaves out raw HTML, so standups can't inject markup
+	if err := goldmark.Convert([]byte(text), &html); err != nil {
+		return 0, fmt.Errorf("failed to render the digest: %w", err)
+	}
+	html.WriteString("</body>\n</html>\n")
+
+	from, err := mail.ParseAddress(settings.From)
+	if err != nil {
+		return 0, fmt.Errorf("invalid digest sender: %w", err)
+	}
+	var to []*mail.Address
+	for _, recipient := range recipients {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return 0, fmt.Errorf("invalid digest recipient: %w", err)
+		}
+		to = append(to, address)
+	}
+
+	subject := fmt.Sprintf("Standups for %s", date.Format("Monday, January 2"))
+	message, err := digestMessage(from, to, subject, text, html.String(), time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	var auth smtp.Auth
+	if settings.SMTP.Username != "" {
+		auth = smtp.PlainAuth("", settings.SMTP.Username, os.Getenv(config.SMTPPasswordEnv), settings.SMTP.Host)
+	}
+	addresses := make([]string, len(to))
+	for i, address := range to {
+		addresses[i] = address.Address
+	}
+	if err := sendMail(settings.SMTP.Addr(), auth, from.Address, addresses, message); err != nil {
+		return 0, fmt.Errorf("failed to send the digest: %w", err)
+	}
+	return len(standups), nil
+}
+
+// digestMessage builds a multipart/alternative email with plain text and
+// HTML versions of the digest
+func digestMessage(from *mail.Address, to []*mail.Address, subject, text, html string, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the digest: %w", err)
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to build the digest: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("failed to build the digest: %w", err)
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build the digest: %w", err)
+	}
+
+	recipients := make([]string, len(to))
+	for i, address := range to {
+		recipients[i] = address.String()
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from.String())
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
@@ -0,0 +1,73 @@
+package standupbot
+
+import (
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+func TestSendDigest(t *testing.T) {
+	repoPath := t.TempDir()
+	standupDir := filepath.Join(repoPath, "stand-ups")
+	if err := os.MkdirAll(standupDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range []string{"alice", "bob"} {
+		content := "# " + user + "\n\n## 2024-01-15\n\n**Yesterday:**\n- Work by " + user + " <script>\n\n**Blockers:** None\n"
+		if err := os.WriteFile(filepath.Join(standupDir, user+".md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	team := "digest:\n  to: [\"Team <team@acme.com>\"]\n  optOut: [Bob]\n"
+	if err := os.WriteFile(filepath.Join(repoPath, config.TeamConfigFile), []byte(team), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent struct {
+		addr, from string
+		to         []string
+		message    string
+	}
+	sendMail = func(addr string, auth smtp.Auth, from string, to []string, message []byte) error {
+		sent.addr, sent.from, sent.to, sent.message = addr, from, to, string(message)
+		return nil
+	}
+	t.Cleanup(func() { sendMail = smtp.SendMail })
+
+	cfg := &config.Config{LocalRepoPath: repoPath, Digest: &config.DigestConfig{
+		From: "Standup Bot <standups@acme.com>",
+		To:   []string{"ignored@acme.com"},
+		SMTP: config.SMTPConfig{Host: "smtp.acme.com", Port: 2525},
+	}}
+	count, err := New(cfg).SendDigest(time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("SendDigest() error = %v", err)
+	}
+
+	if count != 1 || sent.addr != "smtp.acme.com:2525" || sent.from != "standups@acme.com" || strings.Join(sent.to, ",") != "team@acme.com" {
+		t.Errorf("sent %d standups via %s from %s to %v", count, sent.addr, sent.from, sent.to)
+	}
+	for _, want := range []string{"Subject: Standups for Monday, January 15", "multipart/alternative", "text/plain; charset=utf-8", "text/html; charset=utf-8", "Work by alice", "<strong>alice</strong>"} {
+		if !strings.Contains(sent.message, want) {
+			t.Errorf("digest should contain %q:\n%s", want, sent.message)
+		}
+	}
+	if strings.Contains(sent.message, "bob") {
+		t.Error("digest should leave out members who opted out")
+	}
+	if _, html, _ := strings.Cut(sent.message, "text/html"); strings.Contains(html, "<script>") {
+		t.Error("the HTML version should not carry raw HTML from standups")
+	}
+}
+
+func TestSendDigestWithoutSettings(t *testing.T) {
+	count, err := New(&config.Config{LocalRepoPath: t.TempDir()}).SendDigest(time.Now())
+	if count != 0 || err != nil {
+		t.Errorf("SendDigest() = %d, %v, want nothing sent", count, err)
+	}
+}
@@ -0,0 +1,187 @@
+package standupbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+// slackTimeout bounds a post to the Slack webhook
+const slackTimeout = 10 * time.Second
+
+// EscalateBlockers raises the blockers of a pushed entry as the team's
+// escalation settings ask: opening, updating or closing the user's issue in
+// the tracker repository, and posting to Slack. Failures are reported on
+// stderr, since the standup itself is already recorded.
+func EscalateBlockers(cfg *config.Config, gitClient *git.Client, manager *standup.Manager, userName string, entry *standup.Entry) {
+	// Only the latest standup says whether someone is still blocked
+	if entry.Date.Before(cfg.Today()) {
+		return
+	}
+
+	team, err := config.LoadTeamConfig(cfg.LocalRepoPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Not escalating blockers: %v\n", err)
+		return
+	}
+	settings := team.Escalation
+	redact := newRedactor(team.Redaction)
+
+	if settings.Repository != "" {
+		if err := escalateToTracker(gitClient, cfg.LocalRepoPath, settings, redact, userName, entry); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not update the blocker issue: %v\n", err)
+		}
+		redact.warn("the blocker issue")
+	}
+	if settings.Slack {
+		if err := escalateToSlack(os.Getenv(config.SlackWebhookEnv), settings, redact, manager, userName, entry); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not post the blocker to Slack: %v\n", err)
+		}
+		redact.warn("the Slack post")
+	}
+}
+
+// escalateToTracker keeps one open issue per blocked user in the tracker:
+// it is opened when they report a blocker, updated when the blocker
+// changes and closed when they report none. Blockers are quoted with the
+// team's redactions applied and teammates mentioned.
+func escalateToTracker(gitClient *git.Client, repoPath string, settings config.EscalationSettings, redact *redactor, userName string, entry *standup.Entry) error {
+	issues, err := gitClient.ListRepoIssues(repoPath, settings.Repository, settings.BlockerLabel())
+	if err != nil {
+		return err
+	}
+	var open *git.Issue
+	prefix := strings.ToLower(blockerIssuePrefix(userName))
+	for i := range issues {
+		if strings.HasPrefix(strings.ToLower(issues[i].Title), prefix) {
+			open = &issues[i]
+			break
+		}
+	}
+
+	date := entry.Date.Format("2006-01-02")
+	if !standup.HasBlockers(entry.Blockers) {
+		if open == nil {
+			return nil
+		}
+		comment := fmt.Sprintf("Resolved: %s reported no blockers in their standup for %s.", userName, date)
+		return gitClient.CloseRepoIssue(repoPath, settings.Repository, open.Number, comment)
+	}
+
+	quote := quoteBlockers(mentionTeammates(redact.text(entry.Blockers), settings.Mentions, "%s"))
+	body := fmt.Sprintf("**%s**%s reported a blocker in their standup for %s:\n\n%s\n\nThis issue is closed when their standup reports no blockers.",
+		userName, mention(settings.Mentions, userName, " (%s)"), date, quote)
+	if open == nil {
+		title := fmt.Sprintf("%s(%s)", blockerIssuePrefix(userName), date)
+		return gitClient.CreateRepoIssue(repoPath, settings.Repository, title, body, settings.BlockerLabel())
+	}
+	if strings.Contains(open.Body, quote) {
+		return nil
+	}
+	comment := fmt.Sprintf("Update from the standup for %s:\n\n%s", date, quote)
+	return gitClient.UpdateRepoIssue(repoPath, settings.Repository, open.Number, body, comment)
+}
+
+// blockerIssuePrefix starts the title of a user's blocker issue, which is
+// followed by the date it was first reported
+func blockerIssuePrefix(userName string) string {
+	return fmt.Sprintf("Blocker: %s ", userName)
+}
+
+// quoteBlockers renders blockers as a markdown quote
+func quoteBlockers(blockers string) string {
+	lines := strings.Split(strings.TrimSpace(blockers), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// mention formats the handle configured for a user with layout, or returns
+// "" when there is none. Names match regardless of case.
+func mention(handles map[string]string, userName, layout string) string {
+	for name, handle := range handles {
+		if strings.EqualFold(name, userName) && handle != "" {
+			return fmt.Sprintf(layout, handle)
+		}
+	}
+	return ""
+}
+
+// escalateToSlack posts when a user becomes blocked, their blocker changes,
+// or a blocker from their previous standup is resolved. The blockers are
+// compared as written and posted redacted, mentioning the teammates they
+// name.
+func escalateToSlack(webhookURL string, settings config.EscalationSettings, redact *redactor, manager *standup.Manager, userName string, entry *standup.Entry) error {
+	if webhookURL == "" {
+		return fmt.Errorf("%s is not set", config.SlackWebhookEnv)
+	}
+
+	entries, err := manager.LoadEntries(userName)
+	if err != nil {
+		return err
+	}
+	previous := ""
+	for _, e := range entries {
+		if e.Date.Before(entry.Date) {
+			previous = strings.TrimSpace(e.Blockers)
+			break
+		}
+	}
+
+	who := "*" + userName + "*"
+	if id := mention(settings.SlackMentions, userName, "<@%s>"); id != "" {
+		who = id
+	}
+	date := entry.Date.Format("2006-01-02")
+	blockers := strings.TrimSpace(entry.Blockers)
+
+	var text string
+	switch {
+	case standup.HasBlockers(blockers) && blockers != previous:
+		text = fmt.Sprintf("🚧 %s is blocked (standup for %s):\n%s", who, date, quoteBlockers(mentionTeammates(redact.text(blockers), settings.SlackMentions, "<@%s>")))
+	case !standup.HasBlockers(blockers) && standup.HasBlockers(previous):
+		text = fmt.Sprintf("✅ %s is no longer blocked (standup for %s)", who, date)
+	default:
+		return nil
+	}
+	return postToSlack(webhookURL, text)
+}
+
+// AnnounceMerge posts to the Slack webhook in STANDUP_BOT_SLACK_WEBHOOK that
+// a day's pull request was merged. Without the webhook there is no one to
+// tell, which isn't an error.
+func AnnounceMerge(number, url string, date time.Time) error {
+	webhookURL := os.Getenv(config.SlackWebhookEnv)
+	if webhookURL == "" {
+		return nil
+	}
+	return postToSlack(webhookURL, fmt.Sprintf("✅ The standups for %s were merged: <%s|#%s>", date.Format("2006-01-02"), url, number))
+}
+
+// postToSlack sends a message to a Slack incoming webhook
+func postToSlack(webhookURL, text string) error {
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to encode Slack message: %w", err)
+	}
+
+	client := &http.Client{Timeout: slackTimeout}
+	resp, err := client.Post(webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("Slack request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slack returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package standupbot
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+// recordingRunner answers "gh issue list" with issues and records every
+// other command
+type recordingRunner struct {
+	issues   string
+	commands []string
+}
+
+func (r *recordingRunner) Run(name string, args ...string) ([]byte, error) {
+	return r.RunInDir("", name, args...)
+}
+
+func (r *recordingRunner) RunInDir(dir, name string, args ...string) ([]byte, error) {
+	if len(args) > 1 && args[0] == "issue" && args[1] == "list" {
+		return []byte(r.issues), nil
+	}
+	r.commands = append(r.commands, strings.Join(args[:2], " "))
+	return nil, nil
+}
+
+func TestEscalateToTracker(t *testing.T) {
+	openIssue := `[{"number":4,"title":"Blocker: alice (2024-01-15)","body":"**alice** reported a blocker:\n\n> Waiting on API keys\n"}]`
+	tests := []struct {
+		name     string
+		issues   string
+		blockers string
+		want     []string
+	}{
+		{name: "new blocker", issues: "[]", blockers: "Waiting on API keys", want: []string{"label create", "issue create"}},
+		{name: "same blocker", issues: openIssue, blockers: "Waiting on API keys"},
+		{name: "changed blocker", issues: openIssue, blockers: "Waiting on review", want: []string{"issue edit", "issue comment"}},
+		{name: "resolved", issues: openIssue, blockers: "None", want: []string{"issue close"}},
+		{name: "never blocked", issues: "[]", blockers: "None"},
+	}
+
+	settings := config.EscalationSettings{Repository: "acme/blockers"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &recordingRunner{issues: tt.issues}
+			entry := &standup.Entry{Date: time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), Blockers: tt.blockers}
+
+			if err := escalateToTracker(git.NewClientWithRunner(runner), "/repo", settings, &redactor{}, "Alice", entry); err != nil {
+				t.Fatalf("escalateToTracker() error = %v", err)
+			}
+			if strings.Join(runner.commands, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("commands = %v, want %v", runner.commands, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscalateToSlack(t *testing.T) {
+	var posted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message struct{ Text string }
+		json.NewDecoder(r.Body).Decode(&message)
+		posted = append(posted, message.Text)
+	}))
+	defer server.Close()
+
+	manager := standup.NewManager(t.TempDir())
+	settings := config.EscalationSettings{Slack: true, SlackMentions: map[string]string{"alice": "U024BE7LH", "Bob": "U0G9QF9C6"}}
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	for i, blockers := range []string{"Waiting on API keys from @bob", "Waiting on API keys from @bob", "None", "None"} {
+		entry := &standup.Entry{Date: day.AddDate(0, 0, i), Today: []string{"Work"}, Blockers: blockers}
+		if err := manager.SaveEntry(entry, "Alice"); err != nil {
+			t.Fatal(err)
+		}
+		if err := escalateToSlack(server.URL, settings, &redactor{}, manager, "Alice", entry); err != nil {
+			t.Fatalf("escalateToSlack() error = %v", err)
+		}
+	}
+
+	if len(posted) != 2 {
+		t.Fatalf("posted %d messages, want the blocker and its resolution: %q", len(posted), posted)
+	}
+	if !strings.Contains(posted[0], "<@U024BE7LH> is blocked") || !strings.Contains(posted[0], "> Waiting on API keys from <@U0G9QF9C6>") {
+		t.Errorf("blocker message = %q", posted[0])
+	}
+	if !strings.Contains(posted[1], "no longer blocked (standup for 2024-01-17)") {
+		t.Errorf("resolution message = %q", posted[1])
+	}
+
+	if err := escalateToSlack("", settings, &redactor{}, manager, "Alice", &standup.Entry{Date: day}); err == nil {
+		t.Error("escalateToSlack() without a webhook should fail")
+	}
+}
@@ -0,0 +1,110 @@
+package standupbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/confluence"
+	"github.com/standup-bot/standup-bot/pkg/notion"
+)
+
+// exportTimeout bounds publishing the standups everywhere
+const exportTimeout = 2 * time.Minute
+
+// ExportedPage is a page the standups were published to
+type ExportedPage struct {
+	// Target is Notion or Confluence
+	Target string
+	URL    string
+}
+
+// ExportStandups publishes the standups for date, as found in the clone, to
+// the team's Notion database and Confluence space. With a weekly period the
+// page holds the week up to date. It returns the pages written, and writes
+// nothing without export settings or standups. A target that fails doesn't
+// stop the others.
+func (b *Bot) ExportStandups(date time.Time) ([]ExportedPage, error) {
+	team, err := config.LoadTeamConfig(b.cfg.LocalRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	if !team.Export.Enabled() {
+		return nil, nil
+	}
+
+	title, markdown, err := exportPage(b.cfg.LocalRepoPath, date, team)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read standups: %w", err)
+	}
+	if markdown == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	var pages []ExportedPage
+	var errs []error
+	if settings := team.Export.Notion; settings != nil {
+		client, err := notion.NewClient()
+		var url string
+		if err == nil {
+			url, err = client.Publish(ctx, settings.Database, settings.TitleProperty, title, markdown)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to export to Notion: %w", err))
+		} else {
+			pages = append(pages, ExportedPage{Target: "Notion", URL: url})
+		}
+	}
+	if settings := team.Export.Confluence; settings != nil {
+		client, err := confluence.NewClient(settings.BaseURL, settings.Email)
+		var url string
+		if err == nil {
+			url, err = client.Publish(ctx, settings.Space, settings.Parent, title, markdown)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to export to Confluence: %w", err))
+		} else {
+			pages = append(pages, ExportedPage{Target: "Confluence", URL: url})
+		}
+	}
+	return pages, errors.Join(errs...)
+}
+
+// exportPage renders the page of the export period containing date: that
+// day, or the week from Monday until date with a section per day. The
+// markdown is empty when there are no standups.
+func exportPage(repoPath string, date time.Time, team *config.TeamConfig) (string, string, error) {
+	if team.Export.GetPeriod() == config.PeriodDaily {
+		body, err := dayStandups(repoPath, date, team)
+		return fmt.Sprintf("Daily Standups - %s", date.Format("2006-01-02")), body, err
+	}
+
+	monday := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+	var markdown string
+	for day := monday; !day.After(date); day = day.AddDate(0, 0, 1) {
+		body, err := dayStandups(repoPath, day, team)
+		if err != nil {
+			return "", "", err
+		}
+		if body != "" {
+			markdown += fmt.Sprintf("## %s\n\n%s\n", day.Format("Monday, January 2"), body)
+		}
+	}
+	return fmt.Sprintf("Weekly Standups - week of %s", monday.Format("2006-01-02")), markdown, nil
+}
+
+// dayStandups lays out a day's standups like the daily PR body, or returns
+// "" if there are none
+func dayStandups(repoPath string, date time.Time, team *config.TeamConfig) (string, error) {
+	standups, err := loadDailyStandups(repoPath, date)
+	if err != nil || len(standups) == 0 {
+		return "", err
+	}
+	annotateStandups(repoPath, date, standups, team)
+	return formatStandups(standups, outOfOffice(repoPath, date), team, formatUserStandup), nil
+}
@@ -0,0 +1,59 @@
+package standupbot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+func TestExportPage(t *testing.T) {
+	repoPath := t.TempDir()
+	standupDir := filepath.Join(repoPath, "stand-ups")
+	if err := os.MkdirAll(standupDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "# alice\n\n## 2024-01-17\n\n**Yesterday:**\n- Wednesday work\n\n**Blockers:** None\n\n" +
+		"## 2024-01-15\n\n**Yesterday:**\n- Monday work\n\n**Blockers:** None\n"
+	if err := os.WriteFile(filepath.Join(standupDir, "alice.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wednesday := time.Date(2024, 1, 17, 0, 0, 0, 0, time.Local)
+
+	title, markdown, err := exportPage(repoPath, wednesday, &config.TeamConfig{})
+	if err != nil {
+		t.Fatalf("exportPage() error = %v", err)
+	}
+	if title != "Daily Standups - 2024-01-17" || !strings.Contains(markdown, "Wednesday work") || strings.Contains(markdown, "Monday work") {
+		t.Errorf("daily page %q:\n%s", title, markdown)
+	}
+
+	weekly := &config.TeamConfig{Export: config.ExportSettings{Period: config.PeriodWeekly}}
+	title, markdown, err = exportPage(repoPath, wednesday, weekly)
+	if err != nil {
+		t.Fatalf("exportPage() error = %v", err)
+	}
+	if title != "Weekly Standups - week of 2024-01-15" {
+		t.Errorf("weekly title = %q", title)
+	}
+	monday := strings.Index(markdown, "## Monday, January 15")
+	wednesdayAt := strings.Index(markdown, "## Wednesday, January 17")
+	if monday < 0 || wednesdayAt < monday || strings.Contains(markdown, "Tuesday") {
+		t.Errorf("weekly page should have Monday and Wednesday in order:\n%s", markdown)
+	}
+
+	_, markdown, err = exportPage(repoPath, wednesday.AddDate(0, 0, 1), &config.TeamConfig{})
+	if err != nil || markdown != "" {
+		t.Errorf("a day without standups = %q, %v, want nothing", markdown, err)
+	}
+}
+
+func TestExportStandupsWithoutSettings(t *testing.T) {
+	pages, err := New(&config.Config{LocalRepoPath: t.TempDir()}).ExportStandups(time.Now())
+	if pages != nil || err != nil {
+		t.Errorf("ExportStandups() = %v, %v, want nothing exported", pages, err)
+	}
+}
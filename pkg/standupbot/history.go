@@ -0,0 +1,133 @@
+package standupbot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// Revision is a version of a user's standup for a day
+type Revision struct {
+	// Commit made this version; nil for edits that aren't committed yet
+	Commit *git.Revision
+	// Source is the standup as written in the file; empty once it was
+	// removed
+	Source string
+}
+
+// EntryHistory returns the versions of the user's standup for date, oldest
+// first, from the git history of the file holding it. Commits that didn't
+// change that day's standup are left out. The history is read from the
+// day's standup branch when the clone has it, otherwise from the checked out
+// branch, whose uncommitted edits are the last version.
+func (b *Bot) EntryHistory(userName string, date time.Time) ([]Revision, error) {
+	repoPath := b.cfg.LocalRepoPath
+	path := b.manager.EntryPath(userName, date)
+	rel, err := filepath.Rel(repoPath, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the standup file: %w", err)
+	}
+	rel = filepath.ToSlash(rel)
+
+	ref := "HEAD"
+	current, _ := b.git.CurrentBranch(repoPath)
+	if branch := standupBranch(date); current != branch && b.git.BranchExists(repoPath, branch) {
+		ref = branch
+	}
+
+	commits, err := b.git.FileHistory(repoPath, ref, rel)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []Revision
+	add := func(commit *git.Revision, content []byte) error {
+		source, err := b.manager.EntrySource(content, date)
+		if err != nil {
+			return err
+		}
+		previous := ""
+		if len(revisions) > 0 {
+			previous = revisions[len(revisions)-1].Source
+		}
+		if source != previous {
+			revisions = append(revisions, Revision{Commit: commit, Source: source})
+		}
+		return nil
+	}
+
+	for i := range commits {
+		content, err := b.git.FileAt(repoPath, commits[i].Hash, rel)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if err := add(&commits[i], content); err != nil {
+			return nil, fmt.Errorf("commit %s: %w", commits[i].ShortHash(), err)
+		}
+	}
+
+	if ref == "HEAD" {
+		content, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read standup file: %w", err)
+		}
+		if err := add(nil, content); err != nil {
+			return nil, err
+		}
+	}
+	return revisions, nil
+}
+
+// DiffLines compares two texts line by line. Each line of the result starts
+// with " " if both have it, "-" if only old has it or "+" if only new does.
+func DiffLines(old, new string) []string {
+	a, b := splitLines(old), splitLines(new)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff = append(diff, " "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "-"+a[i])
+			i++
+		default:
+			diff = append(diff, "+"+b[j])
+			j++
+		}
+	}
+	return diff
+}
+
+// splitLines splits text into lines without their line endings
+func splitLines(text string) []string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,98 @@
+package standupbot
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestEntryHistory(t *testing.T) {
+	repoPath := t.TempDir()
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	gitRun("init", "-q", "-b", "main")
+	gitRun("config", "user.name", "Alice")
+	gitRun("config", "user.email", "alice@example.com")
+
+	bot := New(&config.Config{Name: "alice", LocalRepoPath: repoPath})
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	save := func(entry *standup.Entry, message string) {
+		t.Helper()
+		if err := bot.Manager().SaveEntry(entry, "alice"); err != nil {
+			t.Fatal(err)
+		}
+		if message != "" {
+			gitRun("add", "-A")
+			gitRun("commit", "-q", "-m", message)
+		}
+	}
+
+	save(&standup.Entry{Date: date, Today: []string{"Wrte tests"}, Blockers: "None"}, "[Standup] alice - 2024-01-15")
+	save(&standup.Entry{Date: date.AddDate(0, 0, 1), Today: []string{"Review PRs"}, Blockers: "None"}, "[Standup] alice - 2024-01-16")
+	save(&standup.Entry{Date: date, Today: []string{"Write tests"}, Blockers: "None"}, "Fix a typo")
+	save(&standup.Entry{Date: date, Today: []string{"Write tests"}, Blockers: "Waiting for CI"}, "")
+
+	revisions, err := bot.EntryHistory("alice", date)
+	if err != nil {
+		t.Fatalf("EntryHistory() error = %v", err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("EntryHistory() = %d revisions, want 3: %+v", len(revisions), revisions)
+	}
+	if c := revisions[0].Commit; c == nil || c.Subject != "[Standup] alice - 2024-01-15" || c.Author != "Alice" {
+		t.Errorf("first revision commit = %+v", c)
+	}
+	if c := revisions[1].Commit; c == nil || c.Subject != "Fix a typo" {
+		t.Errorf("second revision commit = %+v, the commit for another day should be left out", c)
+	}
+	if revisions[2].Commit != nil || !strings.Contains(revisions[2].Source, "Waiting for CI") {
+		t.Errorf("last revision = %+v, want the uncommitted edit", revisions[2])
+	}
+	if strings.Contains(revisions[1].Source, "2024-01-16") || !strings.HasPrefix(revisions[1].Source, "## 2024-01-15") {
+		t.Errorf("revision source should hold only the day's standup:\n%s", revisions[1].Source)
+	}
+
+	if err := os.Remove(bot.Manager().EntryPath("alice", date)); err != nil {
+		t.Fatal(err)
+	}
+	revisions, err = bot.EntryHistory("alice", date)
+	if err != nil || len(revisions) != 3 || revisions[2].Source != "" {
+		t.Errorf("EntryHistory() after removing the file = %+v, %v", revisions, err)
+	}
+
+	if revisions, err := bot.EntryHistory("alice", date.AddDate(0, 0, 5)); err != nil || len(revisions) != 0 {
+		t.Errorf("EntryHistory() for a day without a standup = %+v, %v", revisions, err)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{"added", "", "a\nb\n", []string{"+a", "+b"}},
+		{"removed", "a\nb\n", "", []string{"-a", "-b"}},
+		{"unchanged", "a\n", "a\n", []string{" a"}},
+		{"changed line", "a\nb\nc\n", "a\nB\nc\n", []string{" a", "-b", "+B", " c"}},
+		{"inserted line", "a\nc\n", "a\nb\nc\n", []string{" a", "+b", " c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.old, tt.new); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("DiffLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
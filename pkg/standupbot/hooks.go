@@ -0,0 +1,114 @@
+package standupbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+// Hook points for the commands in the config's hooks
+const (
+	HookPreRecord  = "pre_record"
+	HookPostRecord = "post_record"
+	HookPostMerge  = "post_merge"
+)
+
+// mergedPR is the document post_merge commands get on stdin
+type mergedPR struct {
+	Date     string `json:"date"`
+	PRNumber string `json:"pr_number"`
+	PRUrl    string `json:"pr_url"`
+}
+
+// configureHooks runs the config's pre_record commands before the save step;
+// post_record and post_merge commands are run by the workflows themselves
+func (b *Bot) configureHooks() {
+	if b.cfg.Hooks == nil {
+		return
+	}
+	for _, command := range b.cfg.Hooks.PreRecord {
+		b.Before(StepSave, recordHook(HookPreRecord, command))
+	}
+}
+
+// recordHook runs a hook command with the run's entry
+func recordHook(name, command string) Hook {
+	return func(run *Run) error {
+		return run.Bot.runHookCommand(name, command, run.Entry, run.Result.PR)
+	}
+}
+
+// postRecord runs the post_record commands. The standup is already pushed,
+// so failures are reported on stderr.
+func (b *Bot) postRecord(run *Run) {
+	if b.cfg.Hooks == nil {
+		return
+	}
+	for _, command := range b.cfg.Hooks.PostRecord {
+		if err := b.runHookCommand(HookPostRecord, command, run.Entry, run.Result.PR); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s hook failed: %v\n", HookPostRecord, err)
+		}
+	}
+}
+
+// postMerge runs the post_merge commands and returns their failures
+func (b *Bot) postMerge(pr PRInfo) []string {
+	if b.cfg.Hooks == nil {
+		return nil
+	}
+
+	var warnings []string
+	date := b.cfg.Today().Format("2006-01-02")
+	for _, command := range b.cfg.Hooks.PostMerge {
+		stdin, _ := json.Marshal(mergedPR{Date: date, PRNumber: pr.Number, PRUrl: pr.URL})
+		env := []string{
+			"STANDUP_BOT_HOOK=" + HookPostMerge,
+			"STANDUP_BOT_DATE=" + date,
+			"STANDUP_BOT_PR=" + pr.Number,
+			"STANDUP_BOT_PR_URL=" + pr.URL,
+		}
+		if err := runCommand(b.cfg.LocalRepoPath, command, env, stdin, b.out); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s hook failed: %v", HookPostMerge, err))
+		}
+	}
+	return warnings
+}
+
+// runHookCommand runs a record hook with the entry as JSON on stdin and its
+// user, date and pull request in the environment
+func (b *Bot) runHookCommand(name, command string, entry *standup.Entry, pr *PRInfo) error {
+	stdin, err := json.Marshal(standup.NewStoredEntry(entry, b.cfg.Name))
+	if err != nil {
+		return fmt.Errorf("failed to encode standup: %w", err)
+	}
+
+	env := []string{
+		"STANDUP_BOT_HOOK=" + name,
+		"STANDUP_BOT_USER=" + b.cfg.Name,
+		"STANDUP_BOT_DATE=" + entry.Date.Format("2006-01-02"),
+	}
+	if pr != nil {
+		env = append(env, "STANDUP_BOT_PR="+pr.Number, "STANDUP_BOT_PR_URL="+pr.URL)
+	}
+	return runCommand(b.cfg.LocalRepoPath, command, env, stdin, b.out)
+}
+
+// runCommand runs a shell command in dir with extra environment variables
+// and stdin, sending its output to stdout and stderr
+func runCommand(dir, command string, env []string, stdin []byte, stdout io.Writer) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package standupbot
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestRecordHooks(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		hooks   config.HooksConfig
+		wantErr bool
+	}{
+		{name: "both run", hooks: config.HooksConfig{PreRecord: []string{"cat > pre.json"}, PostRecord: []string{`echo "$STANDUP_BOT_HOOK $STANDUP_BOT_PR" > post.txt`}}},
+		{name: "failing pre_record stops the standup", hooks: config.HooksConfig{PreRecord: []string{"exit 1"}}, wantErr: true},
+		{name: "failing post_record is reported", hooks: config.HooksConfig{PostRecord: []string{"exit 1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoPath := t.TempDir()
+			bot := New(&config.Config{Name: "alice", LocalRepoPath: repoPath, Hooks: &tt.hooks})
+			bot.git = git.NewClientWithRunner(&fakeRunner{})
+			bot.ReplaceStep(step{StepValidate, func(run *Run) error {
+				run.Provider = &openingProvider{}
+				return nil
+			}})
+
+			_, err := bot.Submit(&standup.Entry{Date: date, Today: []string{"Write hooks"}, Blockers: "None"}, Options{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Submit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if _, err := bot.Manager().LoadEntry("alice", date); err == nil {
+					t.Error("the standup shouldn't be saved when pre_record fails")
+				}
+				return
+			}
+			if len(tt.hooks.PreRecord) == 0 {
+				return
+			}
+
+			var stored standup.StoredEntry
+			data, err := os.ReadFile(filepath.Join(repoPath, "pre.json"))
+			if err != nil || json.Unmarshal(data, &stored) != nil || stored.User != "alice" || stored.Date != "2024-01-15" {
+				t.Errorf("pre_record got %q, %v", data, err)
+			}
+			if data, err := os.ReadFile(filepath.Join(repoPath, "post.txt")); err != nil || strings.TrimSpace(string(data)) != "post_record 12" {
+				t.Errorf("post_record wrote %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestPostMerge(t *testing.T) {
+	repoPath := t.TempDir()
+	bot := New(&config.Config{Name: "alice", LocalRepoPath: repoPath, Hooks: &config.HooksConfig{
+		PostMerge: []string{"cat > merged.json", "exit 1"},
+	}})
+
+	warnings := bot.postMerge(PRInfo{Number: "7", URL: "https://example.com/pull/7"})
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "post_merge hook failed") {
+		t.Errorf("postMerge() warnings = %q", warnings)
+	}
+	if data, err := os.ReadFile(filepath.Join(repoPath, "merged.json")); err != nil || !strings.Contains(string(data), `"pr_number":"7"`) {
+		t.Errorf("post_merge got %q, %v", data, err)
+	}
+}
@@ -0,0 +1,80 @@
+package standupbot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/jira"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+// jiraLookupTimeout bounds the issue lookups for one entry
+const jiraLookupTimeout = 15 * time.Second
+
+// LinkJiraIssues turns the Jira keys in the entry's items into links when
+// Jira is configured, with each issue's title and status if enrichment is on.
+// Lookups that fail are reported on stderr and leave a plain link, so Jira
+// being down never blocks a standup.
+func LinkJiraIssues(cfg *config.Config, entry *standup.Entry) {
+	if cfg.Jira == nil {
+		return
+	}
+
+	items := []*string{&entry.Blockers}
+	for _, list := range [][]string{entry.Yesterday, entry.Today} {
+		for i := range list {
+			items = append(items, &list[i])
+		}
+	}
+	for _, section := range entry.Sections {
+		for i := range section.Items {
+			items = append(items, &section.Items[i])
+		}
+	}
+
+	linker := jira.Linker{BaseURL: cfg.Jira.BaseURL, Projects: cfg.Jira.Projects}
+	issues := make(map[string]jira.Issue)
+	if cfg.Jira.Enrich {
+		issues = lookupJiraIssues(cfg.Jira, linker, items)
+	}
+	for _, item := range items {
+		*item = linker.Link(*item, issues)
+	}
+}
+
+// lookupJiraIssues fetches the issues whose keys appear in the items
+func lookupJiraIssues(jiraCfg *config.JiraConfig, linker jira.Linker, items []*string) map[string]jira.Issue {
+	issues := make(map[string]jira.Issue)
+
+	var keys []string
+	for _, item := range items {
+		keys = append(keys, linker.Keys(*item)...)
+	}
+	if len(keys) == 0 {
+		return issues
+	}
+
+	client, err := jira.NewClient(jiraCfg.BaseURL, jiraCfg.Email)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Not looking up Jira issues: %v\n", err)
+		return issues
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jiraLookupTimeout)
+	defer cancel()
+	for _, key := range keys {
+		if _, ok := issues[key]; ok {
+			continue
+		}
+		issue, err := client.Issue(ctx, key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not look up %s: %v\n", key, err)
+			continue
+		}
+		issues[key] = issue
+	}
+	return issues
+}
@@ -0,0 +1,56 @@
+package standupbot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/jira"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestLinkJiraIssues(t *testing.T) {
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		if r.URL.Path != "/rest/api/2/issue/ABC-1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"fields":{"summary":"Login broken","status":{"name":"Done"}}}`))
+	}))
+	defer server.Close()
+	t.Setenv(jira.TokenEnv, "tok")
+
+	entry := &standup.Entry{
+		Yesterday: []string{"Fixed ABC-1"},
+		Today:     []string{"Start ABC-404", "Reviews"},
+		Blockers:  "Waiting on ABC-1",
+		Sections:  []standup.Section{{Name: "Wins", Items: []string{"Closed ABC-1"}}},
+	}
+	cfg := &config.Config{Jira: &config.JiraConfig{BaseURL: server.URL, Enrich: true}}
+
+	LinkJiraIssues(cfg, entry)
+
+	enriched := "[ABC-1](" + server.URL + "/browse/ABC-1) (Login broken, Done)"
+	if entry.Yesterday[0] != "Fixed "+enriched {
+		t.Errorf("Yesterday = %q", entry.Yesterday[0])
+	}
+	if entry.Today[0] != "Start [ABC-404]("+server.URL+"/browse/ABC-404)" || entry.Today[1] != "Reviews" {
+		t.Errorf("Today = %q, want a plain link when the lookup fails", entry.Today)
+	}
+	if entry.Blockers != "Waiting on "+enriched || entry.Sections[0].Items[0] != "Closed "+enriched {
+		t.Errorf("Blockers = %q, Wins = %q", entry.Blockers, entry.Sections[0].Items)
+	}
+	if lookups != 2 {
+		t.Errorf("looked up %d issues, want each key once", lookups)
+	}
+
+	// Without Jira configured nothing changes
+	plain := &standup.Entry{Yesterday: []string{"Fixed ABC-1"}}
+	LinkJiraIssues(&config.Config{}, plain)
+	if plain.Yesterday[0] != "Fixed ABC-1" {
+		t.Errorf("Yesterday = %q, want it unchanged", plain.Yesterday[0])
+	}
+}
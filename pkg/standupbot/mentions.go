@@ -0,0 +1,46 @@
+package standupbot
+
+import (
+	"regexp"
+	"strings"
+)
+
+// teammatePattern matches @name references to teammates in blockers, but
+// not the @ of an email address
+var teammatePattern = regexp.MustCompile(`(^|[^\w@.])@([\p{L}\p{N}][\p{L}\p{N}_-]*)`)
+
+// mentionTeammates replaces @name references in text with the handle
+// configured for the member, formatted with layout, so the person a blocker
+// is waiting on gets notified. Names without a handle are left as written.
+func mentionTeammates(text string, handles map[string]string, layout string) string {
+	if len(handles) == 0 {
+		return text
+	}
+	return teammatePattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := teammatePattern.FindStringSubmatch(match)
+		handle := mention(handles, parts[2], layout)
+		if handle == "" {
+			return match
+		}
+		return parts[1] + handle
+	})
+}
+
+// mentionBlockers replaces the @name references in the blockers of the
+// standups with their GitHub handles
+func mentionBlockers(standups []dailyStandup, handles map[string]string) {
+	for i := range standups {
+		for j, section := range standups[i].Entry.Sections {
+			if !strings.EqualFold(section.Title, "Blockers") {
+				continue
+			}
+			section.Text = mentionTeammates(section.Text, handles, "%s")
+			items := make([]string, len(section.Items))
+			for k, item := range section.Items {
+				items[k] = mentionTeammates(item, handles, "%s")
+			}
+			section.Items = items
+			standups[i].Entry.Sections[j] = section
+		}
+	}
+}
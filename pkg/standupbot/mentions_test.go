@@ -0,0 +1,51 @@
+package standupbot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/standup/parser"
+)
+
+func TestMentionTeammates(t *testing.T) {
+	handles := map[string]string{"Alice": "@alice-gh", "bob": "@bobby"}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Waiting on @alice", "Waiting on @alice-gh"},
+		{"@Bob and @alice to review", "@bobby and @alice-gh to review"},
+		{"Need (@bob), then @carol", "Need (@bobby), then @carol"},
+		{"Mail alice@bob.com", "Mail alice@bob.com"},
+		{"None", "None"},
+	}
+	for _, tt := range tests {
+		if got := mentionTeammates(tt.text, handles, "%s"); got != tt.want {
+			t.Errorf("mentionTeammates(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	if got := mentionTeammates("Waiting on @alice", map[string]string{"alice": "U024BE7LH"}, "<@%s>"); got != "Waiting on <@U024BE7LH>" {
+		t.Errorf("Slack mention = %q", got)
+	}
+}
+
+func TestMentionBlockers(t *testing.T) {
+	standups := []dailyStandup{
+		{User: "carol", Entry: parser.Entry{Sections: []parser.Section{
+			{Title: "Today", Items: []string{"Pair with @alice"}},
+			{Title: "Blockers", Text: "Waiting on @alice"},
+		}}},
+		testDailyStandup("dave", time.Time{}, "Work"),
+	}
+
+	mentionBlockers(standups, map[string]string{"alice": "@alice-gh"})
+	entry := standups[0].Entry
+	if entry.Sections[1].Text != "Waiting on @alice-gh" {
+		t.Errorf("Blockers = %q", entry.Sections[1].Text)
+	}
+	if entry.Sections[0].Items[0] != "Pair with @alice" {
+		t.Errorf("only blockers should mention teammates, got %q", entry.Sections[0].Items[0])
+	}
+}
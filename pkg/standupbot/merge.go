@@ -0,0 +1,303 @@
+package standupbot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// MergeOverrides replace the team's merge settings for one merge. Zero
+// values keep the team's settings.
+type MergeOverrides struct {
+	// Strategy is squash, merge or rebase
+	Strategy     string
+	DeleteBranch *bool
+	Auto         bool
+}
+
+// MergeResult describes a merged daily pull request
+type MergeResult struct {
+	PR PRInfo
+	// Auto is set when auto-merge was turned on instead of merging, so the
+	// pull request merges once its checks and reviews pass
+	Auto bool
+	// Warnings are problems that didn't stop the merge, such as a description
+	// that couldn't be refreshed
+	Warnings []string
+}
+
+// PullRequest returns the daily pull request for date
+func (b *Bot) PullRequest(date time.Time) (*PRInfo, error) {
+	provider, err := ValidateEnvironment(b.git, b.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return b.findPullRequest(provider, date)
+}
+
+// findPullRequest looks up the daily pull request for date
+func (b *Bot) findPullRequest(provider forge.Provider, date time.Time) (*PRInfo, error) {
+	prExists, prNumber := provider.FindPullRequest(b.cfg.LocalRepoPath, standupBranch(date))
+	if !prExists {
+		return nil, fmt.Errorf("no standup PR found for %s", date.Format("2006-01-02"))
+	}
+	return &PRInfo{Number: prNumber, URL: provider.PullRequestURL(prNumber)}, nil
+}
+
+// Merge merges today's daily pull request, refreshing its description from
+// the branch first, and returns the clone to the base branch
+func (b *Bot) Merge(opts Options) (*MergeResult, error) {
+	provider, err := ValidateEnvironment(b.git, b.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merging switches branches and resets, so check before touching anything
+	if err := CheckWorkTree(b.git, b.cfg.LocalRepoPath, opts.Force, opts.Interactive); err != nil {
+		return nil, err
+	}
+
+	today := b.cfg.Today()
+	pr, err := b.findPullRequest(provider, today)
+	if err != nil {
+		return nil, err
+	}
+	result := &MergeResult{PR: *pr}
+
+	b.printf("Refreshing pull request description...\n")
+	if err := refreshPRBody(b.git, provider, b.cfg.LocalRepoPath, standupBranch(today), pr.Number, today); err != nil {
+		// A stale description shouldn't hold up the merge
+		result.Warnings = append(result.Warnings, fmt.Sprintf("could not refresh PR body: %v", err))
+	}
+
+	mergeOpts, err := b.mergeOptions(opts.Merge)
+	if err != nil {
+		return nil, err
+	}
+	result.Auto = mergeOpts.Auto
+
+	b.printf("Checking pull request #%s...\n", pr.Number)
+	state, err := provider.PullRequestState(b.cfg.LocalRepoPath, standupBranch(today))
+	warnings, err := mergeGate(pr.Number, state, err, opts.Force, mergeOpts.Auto)
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = append(result.Warnings, warnings...)
+
+	if mergeOpts.Auto {
+		b.printf("Turning on auto-merge for pull request #%s...\n", pr.Number)
+	} else {
+		b.printf("Merging pull request #%s...\n", pr.Number)
+	}
+	err = b.track("merge", "Merging the pull request", func() error {
+		return mergeStandupPR(provider, b.cfg.LocalRepoPath, pr.Number, mergeOpts)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge pull request: %w", err)
+	}
+
+	b.printf("Switching back to %s branch...\n", b.cfg.GetBaseBranch())
+	cleanupErr := cleanupAfterMerge(b.git, b.cfg.LocalRepoPath, b.cfg.GetBaseBranch())
+	if cleanupErr != nil {
+		result.Warnings = append(result.Warnings, cleanupErr.Error())
+	}
+
+	// With auto-merge the pull request isn't merged yet
+	if !mergeOpts.Auto {
+		result.Warnings = append(result.Warnings, b.postMerge(result.PR)...)
+		// The digest and exports are read from the base branch, so they need
+		// the merge pulled
+		if cleanupErr == nil {
+			if b.cfg.Digest != nil {
+				b.printf("Emailing the digest...\n")
+				if _, err := b.SendDigest(today); err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("could not email the digest: %v", err))
+				}
+			}
+			pages, err := b.ExportStandups(today)
+			for _, page := range pages {
+				b.printf("Exported the standups to %s: %s\n", page.Target, page.URL)
+			}
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("could not export the standups: %v", err))
+			}
+		}
+	}
+	return result, nil
+}
+
+// RequestReview asks the team's approvers to review the daily pull request
+// for date, for members who can't merge to the base branch themselves. It
+// returns the pull request and who was asked.
+func (b *Bot) RequestReview(date time.Time) (*PRInfo, []string, error) {
+	provider, err := ValidateEnvironment(b.git, b.cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	team, err := config.LoadTeamConfig(b.cfg.LocalRepoPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(team.PullRequest.Approvers) == 0 {
+		return nil, nil, fmt.Errorf("no approvers to ask: list them under pullRequest.approvers in %s", config.TeamConfigFile)
+	}
+
+	pr, err := b.findPullRequest(provider, date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b.printf("Requesting a review of pull request #%s...\n", pr.Number)
+	if err := provider.RequestReview(b.cfg.LocalRepoPath, pr.Number, team.PullRequest.Approvers); err != nil {
+		return nil, nil, err
+	}
+	return pr, team.PullRequest.Approvers, nil
+}
+
+// RefreshPullRequest rebuilds the description of the daily pull request for
+// date from the standups on its branch. The description goes stale when
+// someone force-pushes or edits their standup without the bot.
+func (b *Bot) RefreshPullRequest(date time.Time, opts Options) (*PRInfo, error) {
+	provider, err := ValidateEnvironment(b.git, b.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refreshing checks out the day's branch, so check before touching anything
+	if err := CheckWorkTree(b.git, b.cfg.LocalRepoPath, opts.Force, opts.Interactive); err != nil {
+		return nil, err
+	}
+
+	pr, err := b.findPullRequest(provider, date)
+	if err != nil {
+		return nil, err
+	}
+
+	b.printf("Refreshing the description of pull request #%s...\n", pr.Number)
+	if err := refreshPRBody(b.git, provider, b.cfg.LocalRepoPath, standupBranch(date), pr.Number, date); err != nil {
+		return nil, fmt.Errorf("failed to refresh pull request: %w", err)
+	}
+	return pr, nil
+}
+
+// mergeGate decides whether pull request number may be merged, given the
+// state looked up for its branch. Failing checks, missing reviews and a
+// state that couldn't be looked up, or is of another pull request, stop the
+// merge unless force is set; they are returned as warnings when it is.
+// Auto-merge waits for checks and reviews itself, so only a failed lookup
+// stops it.
+func mergeGate(number string, state *forge.PullRequestState, lookupErr error, force, auto bool) ([]string, error) {
+	if lookupErr == nil && (state == nil || state.Number != number) {
+		found := "none"
+		if state != nil {
+			found = "#" + state.Number
+		}
+		lookupErr = fmt.Errorf("the latest pull request for its branch is %s", found)
+	}
+	if lookupErr != nil {
+		if !force {
+			return nil, fmt.Errorf("could not check pull request #%s: %w\nRe-run with --force to merge without checking", number, lookupErr)
+		}
+		return []string{fmt.Sprintf("merged without checking checks: %v", lookupErr)}, nil
+	}
+
+	blockers := mergeBlockers(state)
+	if len(blockers) == 0 || auto {
+		return nil, nil
+	}
+	if !force {
+		return nil, fmt.Errorf("pull request #%s can't be merged yet:\n  %s\nFix them, or re-run with --force to merge anyway", number, strings.Join(blockers, "\n  "))
+	}
+	var warnings []string
+	for _, blocker := range blockers {
+		warnings = append(warnings, "merged despite "+blocker)
+	}
+	return warnings, nil
+}
+
+// mergeBlockers lists what should stop a pull request from being merged:
+// failing checks and missing or rejected reviews
+func mergeBlockers(state *forge.PullRequestState) []string {
+	if state == nil {
+		return nil
+	}
+
+	var blockers []string
+	if failing := state.FailingChecks(); len(failing) > 0 {
+		blockers = append(blockers, "failing checks: "+strings.Join(failing, ", "))
+	}
+	switch state.Review {
+	case forge.ReviewRequired:
+		blockers = append(blockers, "a required review is missing")
+	case forge.ReviewChangesRequested:
+		blockers = append(blockers, "a reviewer requested changes")
+	}
+	return blockers
+}
+
+// refreshPRBody regenerates the PR body from the latest branch contents, so
+// standups that were pushed without updating the body appear in the merged
+// description
+func refreshPRBody(gitClient *git.Client, provider forge.Provider, repoPath, branchName, prNumber string, date time.Time) error {
+	if err := gitClient.CreateOrCheckoutBranch(repoPath, branchName); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", branchName, err)
+	}
+
+	return provider.UpdatePullRequest(repoPath, prNumber, FormatDailyPRBody(repoPath, date))
+}
+
+// mergeOptions combines the team's merge settings with the overrides
+func (b *Bot) mergeOptions(overrides MergeOverrides) (forge.MergeOptions, error) {
+	team, err := config.LoadTeamConfig(b.cfg.LocalRepoPath)
+	if err != nil {
+		return forge.MergeOptions{}, err
+	}
+	if err := config.ValidateMergeStrategy(overrides.Strategy); err != nil {
+		return forge.MergeOptions{}, err
+	}
+
+	settings := team.PullRequest.Merge
+	if overrides.Strategy != "" {
+		settings.Strategy = overrides.Strategy
+	}
+	if overrides.DeleteBranch != nil {
+		settings.DeleteBranch = overrides.DeleteBranch
+	}
+	return forge.MergeOptions{
+		Strategy:     settings.GetStrategy(),
+		DeleteBranch: settings.ShouldDeleteBranch(),
+		Auto:         settings.Auto || overrides.Auto,
+	}, nil
+}
+
+// mergeStandupPR merges the daily PR, first marking it ready if the team
+// opens it as a draft
+func mergeStandupPR(provider forge.Provider, repoPath, prNumber string, opts forge.MergeOptions) error {
+	team, err := config.LoadTeamConfig(repoPath)
+	if err != nil {
+		return err
+	}
+	if team.PullRequest.Draft {
+		if err := provider.MarkReady(repoPath, prNumber); err != nil {
+			return err
+		}
+	}
+	return provider.MergePullRequest(repoPath, prNumber, opts)
+}
+
+// cleanupAfterMerge switches back to the base branch and syncs the repository
+func cleanupAfterMerge(gitClient *git.Client, repoPath, baseBranch string) error {
+	if err := gitClient.SwitchToBranch(repoPath, baseBranch); err != nil {
+		return fmt.Errorf("could not switch to %s branch: %w", baseBranch, err)
+	}
+
+	if err := gitClient.SyncRepository(repoPath, baseBranch); err != nil {
+		return fmt.Errorf("could not sync repository: %w", err)
+	}
+	return nil
+}
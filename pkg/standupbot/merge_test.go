@@ -0,0 +1,181 @@
+package standupbot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// recordingProvider is a forge provider that records PR body updates and
+// the order of ready and merge calls
+type recordingProvider struct {
+	forge.Provider
+	number string
+	body   string
+	calls  []string
+}
+
+func (r *recordingProvider) UpdatePullRequest(repoPath, number, body string) error {
+	r.number, r.body = number, body
+	return nil
+}
+
+func (r *recordingProvider) MarkReady(repoPath, number string) error {
+	r.calls = append(r.calls, "ready "+number)
+	return nil
+}
+
+func (r *recordingProvider) MergePullRequest(repoPath, number string, opts forge.MergeOptions) error {
+	r.calls = append(r.calls, "merge "+number+" "+opts.Strategy)
+	return nil
+}
+
+func TestRefreshPRBody(t *testing.T) {
+	repoPath := t.TempDir()
+	standupDir := filepath.Join(repoPath, "stand-ups")
+	if err := os.MkdirAll(standupDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A late standup that was pushed without updating the PR body
+	content := "# carol\n\n## 2024-01-15\n\n**Yesterday:**\n- Late work\n\n**Blockers:** None\n"
+	if err := os.WriteFile(filepath.Join(standupDir, "carol.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	provider := &recordingProvider{}
+	gitClient := git.NewClientWithRunner(&fakeRunner{})
+
+	if err := refreshPRBody(gitClient, provider, repoPath, "standup/2024-01-15", "42", date); err != nil {
+		t.Fatalf("refreshPRBody() error = %v", err)
+	}
+	if provider.number != "42" || !strings.Contains(provider.body, "<b>carol</b>") || !strings.Contains(provider.body, "Late work") {
+		t.Errorf("refreshPRBody() updated #%s with:\n%s", provider.number, provider.body)
+	}
+
+	failing := git.NewClientWithRunner(&fakeRunner{err: errors.New("exit status 1")})
+	if err := refreshPRBody(failing, &recordingProvider{}, repoPath, "standup/2024-01-15", "42", date); err == nil {
+		t.Error("refreshPRBody() should fail when the branch can't be checked out")
+	}
+}
+
+func TestMergeStandupPR(t *testing.T) {
+	repoPath := t.TempDir()
+
+	provider := &recordingProvider{}
+	if err := mergeStandupPR(provider, repoPath, "7", forge.MergeOptions{Strategy: forge.MergeSquash}); err != nil {
+		t.Fatalf("mergeStandupPR() error = %v", err)
+	}
+	if strings.Join(provider.calls, ",") != "merge 7 squash" {
+		t.Errorf("calls = %v, want just the merge", provider.calls)
+	}
+
+	// Draft PRs are marked ready first
+	if err := os.WriteFile(filepath.Join(repoPath, ".standup-bot.yaml"), []byte("pullRequest: {draft: true}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	provider = &recordingProvider{}
+	if err := mergeStandupPR(provider, repoPath, "7", forge.MergeOptions{Strategy: forge.MergeSquash}); err != nil {
+		t.Fatalf("mergeStandupPR() error = %v", err)
+	}
+	if strings.Join(provider.calls, ",") != "ready 7,merge 7 squash" {
+		t.Errorf("calls = %v, want ready then merge", provider.calls)
+	}
+}
+
+func TestMergeBlockers(t *testing.T) {
+	tests := []struct {
+		name  string
+		state *forge.PullRequestState
+		want  []string
+	}{
+		{name: "unknown", state: nil},
+		{name: "green", state: &forge.PullRequestState{Checks: []forge.Check{{Name: "lint", Status: forge.CheckPass}}, Review: forge.ReviewApproved}},
+		{name: "pending checks don't block", state: &forge.PullRequestState{Checks: []forge.Check{{Name: "lint", Status: forge.CheckPending}}}},
+		{
+			name: "failing checks and missing review",
+			state: &forge.PullRequestState{
+				Checks: []forge.Check{{Name: "lint", Status: forge.CheckFail}, {Name: "test", Status: forge.CheckFail}},
+				Review: forge.ReviewRequired,
+			},
+			want: []string{"failing checks: lint, test", "a required review is missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeBlockers(tt.state); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("mergeBlockers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeGate(t *testing.T) {
+	green := &forge.PullRequestState{Number: "7", State: forge.StateOpen}
+	failing := &forge.PullRequestState{Number: "7", State: forge.StateOpen, Checks: []forge.Check{{Name: "lint", Status: forge.CheckFail}}}
+	tests := []struct {
+		name      string
+		state     *forge.PullRequestState
+		lookupErr error
+		force     bool
+		auto      bool
+		wantErr   string
+		warnings  int
+	}{
+		{name: "green", state: green},
+		{name: "failing checks", state: failing, wantErr: "failing checks: lint"},
+		{name: "failing checks forced", state: failing, force: true, warnings: 1},
+		{name: "failing checks with auto-merge", state: failing, auto: true},
+		{name: "lookup failed", lookupErr: errors.New("gh: not logged in"), wantErr: "gh: not logged in"},
+		{name: "lookup failed with auto-merge", lookupErr: errors.New("gh: not logged in"), auto: true, wantErr: "--force"},
+		{name: "lookup failed forced", lookupErr: errors.New("gh: not logged in"), force: true, warnings: 1},
+		{name: "another pull request", state: &forge.PullRequestState{Number: "6", State: forge.StateMerged}, wantErr: "#6"},
+		{name: "no pull request", wantErr: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := mergeGate("7", tt.state, tt.lookupErr, tt.force, tt.auto)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("mergeGate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || len(warnings) != tt.warnings {
+				t.Errorf("mergeGate() = %q, %v, want %d warnings", warnings, err, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestMergeOptions(t *testing.T) {
+	repoPath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoPath, ".standup-bot.yaml"), []byte("pullRequest:\n  merge: {strategy: merge, deleteBranch: false}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bot := New(&config.Config{Name: "alice", LocalRepoPath: repoPath})
+
+	opts, err := bot.mergeOptions(MergeOverrides{})
+	if err != nil || opts != (forge.MergeOptions{Strategy: forge.MergeCommit}) {
+		t.Errorf("mergeOptions() = %+v, %v, want the team's settings", opts, err)
+	}
+
+	deleteBranch := true
+	opts, err = bot.mergeOptions(MergeOverrides{Strategy: "rebase", DeleteBranch: &deleteBranch, Auto: true})
+	if err != nil || opts != (forge.MergeOptions{Strategy: forge.MergeRebase, DeleteBranch: true, Auto: true}) {
+		t.Errorf("mergeOptions() = %+v, %v, want the overrides", opts, err)
+	}
+
+	if _, err := bot.mergeOptions(MergeOverrides{Strategy: "fast-forward"}); err == nil {
+		t.Error("mergeOptions() should reject an unknown strategy")
+	}
+}
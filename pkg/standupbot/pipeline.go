@@ -0,0 +1,237 @@
+package standupbot
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+// Names of the steps that record a standup, in the order they run
+const (
+	StepValidate = "validate"
+	StepSync     = "sync"
+	StepCollect  = "collect"
+	StepSave     = "save"
+	StepCommit   = "commit"
+	StepPush     = "push"
+	StepPR       = "pr"
+	StepNotify   = "notify"
+)
+
+// stepLabels describe the steps that can be slow, for progress reporting.
+// The collect step may prompt, so it's left out.
+var stepLabels = map[string]string{
+	StepValidate: "Checking the forge",
+	StepSync:     "Syncing repository",
+	StepCommit:   "Committing",
+	StepPush:     "Pushing",
+	StepPR:       "Updating the pull request",
+	StepNotify:   "Notifying the team",
+}
+
+// Step is one stage of recording a standup. Steps share a Run, and the
+// first one to fail stops it.
+type Step interface {
+	Name() string
+	Run(run *Run) error
+}
+
+// Hook runs before or after a step; an error stops the run
+type Hook func(run *Run) error
+
+// Run is the state the steps recording one standup share
+type Run struct {
+	Bot     *Bot
+	Options Options
+	// Step is the name of the step running, or whose hooks are running
+	Step string
+	// Entry is the standup, set by the collect step
+	Entry *standup.Entry
+	// Provider is the forge, set by the validate step for the pull request
+	// workflow
+	Provider forge.Provider
+	// Result is filled in as the steps complete
+	Result *Result
+
+	collect func() (*standup.Entry, error)
+}
+
+// step adapts a function to a Step
+type step struct {
+	name string
+	run  func(run *Run) error
+}
+
+func (s step) Name() string       { return s.name }
+func (s step) Run(run *Run) error { return s.run(run) }
+
+// defaultSteps are the steps every bot starts with
+func defaultSteps() []Step {
+	return []Step{
+		step{StepValidate, validateEnvironment},
+		step{StepSync, syncRepository},
+		step{StepCollect, collectEntry},
+		step{StepSave, saveEntry},
+		step{StepCommit, commitEntry},
+		step{StepPush, pushEntry},
+		step{StepPR, openPullRequest},
+		step{StepNotify, notifyTeam},
+	}
+}
+
+// ReplaceStep swaps in step for the default step of the same name, for
+// example to record standups somewhere else or to fake a step in tests
+func (b *Bot) ReplaceStep(s Step) error {
+	for i := range b.steps {
+		if b.steps[i].Name() == s.Name() {
+			b.steps[i] = s
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown step '%s'", s.Name())
+}
+
+// Skip turns a step off. The collect and save steps can't be skipped.
+func (b *Bot) Skip(name string) error {
+	if err := b.checkStep(name); err != nil {
+		return err
+	}
+	if name == StepCollect || name == StepSave {
+		return fmt.Errorf("the %s step can't be skipped", name)
+	}
+	b.skip[name] = true
+	return nil
+}
+
+// Before adds a hook that runs before the named step
+func (b *Bot) Before(name string, hook Hook) error {
+	if err := b.checkStep(name); err != nil {
+		return err
+	}
+	b.before[name] = append(b.before[name], hook)
+	return nil
+}
+
+// After adds a hook that runs once the named step succeeds
+func (b *Bot) After(name string, hook Hook) error {
+	if err := b.checkStep(name); err != nil {
+		return err
+	}
+	b.after[name] = append(b.after[name], hook)
+	return nil
+}
+
+// checkStep fails for names that aren't a step
+func (b *Bot) checkStep(name string) error {
+	if !slices.ContainsFunc(b.steps, func(s Step) bool { return s.Name() == name }) {
+		return fmt.Errorf("unknown step '%s'", name)
+	}
+	return nil
+}
+
+// gitSteps record a standup in the repository, so they are skipped when
+// standups are stored elsewhere
+var gitSteps = []string{StepValidate, StepSync, StepCommit, StepPush, StepPR}
+
+// configurePipeline applies the skipped steps and commands from the config
+func (b *Bot) configurePipeline() {
+	if b.cfg.Standalone() {
+		for _, name := range gitSteps {
+			b.skip[name] = true
+		}
+	}
+
+	p := b.cfg.Pipeline
+	if p == nil {
+		return
+	}
+	// The config is validated when loaded, so the names are known
+	for _, name := range p.Skip {
+		b.Skip(name)
+	}
+	for name, commands := range p.After {
+		for _, command := range commands {
+			b.After(name, commandHook(command))
+		}
+	}
+}
+
+// runPipeline records the standup returned by collect, and counts it
+func (b *Bot) runPipeline(collect func() (*standup.Entry, error), opts Options) (*Result, error) {
+	result, err := b.runSteps(collect, opts)
+	CountSubmission(err)
+	return result, err
+}
+
+// runSteps runs the steps recording the standup returned by collect
+func (b *Bot) runSteps(collect func() (*standup.Entry, error), opts Options) (*Result, error) {
+	run := &Run{Bot: b, Options: opts, Result: &Result{}, collect: collect}
+
+	for _, s := range b.steps {
+		run.Step = s.Name()
+		if b.skip[run.Step] {
+			continue
+		}
+		for _, hook := range b.before[run.Step] {
+			if err := hook(run); err != nil {
+				return nil, fmt.Errorf("hook before %s failed: %w", run.Step, err)
+			}
+		}
+		if err := b.runStep(s, run); err != nil {
+			return nil, err
+		}
+		for _, hook := range b.after[run.Step] {
+			if err := hook(run); err != nil {
+				return nil, fmt.Errorf("hook after %s failed: %w", run.Step, err)
+			}
+		}
+		if run.Result.AlreadySubmitted && !b.hasUnpushed() {
+			// Submitting the same standup again is a no-op
+			break
+		}
+	}
+
+	result := run.Result
+	result.Entry = run.Entry
+	result.FilePath = b.manager.EntryPath(b.cfg.Name, run.Entry.Date)
+	result.Streak, result.LongestStreak, _ = b.manager.Streak(b.cfg.Name, run.Entry.Date)
+	if !result.AlreadySubmitted {
+		b.postRecord(run)
+	}
+	return result, nil
+}
+
+// hasUnpushed reports whether the current branch may have commits origin
+// lacks, such as a standup whose push failed, so an unchanged standup still
+// goes through the push and pull request steps
+func (b *Bot) hasUnpushed() bool {
+	if b.cfg.Standalone() {
+		return false
+	}
+	pending, err := b.git.UnpushedCommits(b.cfg.LocalRepoPath)
+	return err != nil || pending > 0
+}
+
+// runStep runs a step, reporting the progress of slow ones
+func (b *Bot) runStep(s Step, run *Run) error {
+	label, ok := stepLabels[s.Name()]
+	if !ok {
+		return s.Run(run)
+	}
+	return b.track(s.Name(), label, func() error { return s.Run(run) })
+}
+
+// commandHook runs a shell command in the standup repository, with the step,
+// user and standup date in STANDUP_BOT_STEP, STANDUP_BOT_USER and
+// STANDUP_BOT_DATE
+func commandHook(command string) Hook {
+	return func(run *Run) error {
+		env := []string{"STANDUP_BOT_STEP=" + run.Step, "STANDUP_BOT_USER=" + run.Bot.cfg.Name}
+		if run.Entry != nil {
+			env = append(env, "STANDUP_BOT_DATE="+run.Entry.Date.Format("2006-01-02"))
+		}
+		return runCommand(run.Bot.cfg.LocalRepoPath, command, env, nil, run.Bot.out)
+	}
+}
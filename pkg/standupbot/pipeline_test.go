@@ -0,0 +1,235 @@
+package standupbot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/ui"
+)
+
+// openingProvider is a forge provider with no pull requests until one is
+// created
+type openingProvider struct {
+	forge.Provider
+	created *forge.PullRequestOptions
+}
+
+func (o *openingProvider) FindPullRequest(repoPath, branch string) (bool, string) {
+	return o.created != nil, "12"
+}
+
+func (o *openingProvider) CreatePullRequest(repoPath string, opts forge.PullRequestOptions) error {
+	o.created = &opts
+	return nil
+}
+
+func (o *openingProvider) PullRequestURL(number string) string {
+	return "https://example.com/pull/" + number
+}
+
+func TestPipeline(t *testing.T) {
+	repoPath := t.TempDir()
+	cfg := &config.Config{
+		Name:          "alice",
+		LocalRepoPath: repoPath,
+		Pipeline:      &config.PipelineConfig{After: map[string][]string{StepSave: {`echo "$STANDUP_BOT_STEP $STANDUP_BOT_DATE" > hook.txt`}}},
+	}
+	bot := New(cfg)
+	bot.git = git.NewClientWithRunner(&fakeRunner{})
+
+	provider := &openingProvider{}
+	if err := bot.ReplaceStep(step{StepValidate, func(run *Run) error {
+		run.Provider = provider
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	var ran []string
+	for _, name := range config.PipelineSteps {
+		if err := bot.After(name, func(run *Run) error {
+			ran = append(ran, run.Step)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := bot.Skip(StepNotify); err != nil {
+		t.Fatal(err)
+	}
+	var progress []string
+	bot.SetProgress(func(event ui.Event) {
+		if event.Kind == ui.Started {
+			progress = append(progress, event.Step)
+		}
+	})
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	result, err := bot.Submit(&standup.Entry{Date: date, Today: []string{"Write tests"}, Blockers: "None"}, Options{})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	if got := strings.Join(ran, ","); got != "validate,sync,collect,save,commit,push,pr" {
+		t.Errorf("steps ran = %s", got)
+	}
+	// Collecting may prompt and saving is quick, so they aren't reported
+	if got := strings.Join(progress, ","); got != "validate,sync,commit,push,pr" {
+		t.Errorf("progress reported = %s", got)
+	}
+	if provider.created == nil || provider.created.Head != "standup/2024-01-15" || result.PR == nil || result.PR.Number != "12" {
+		t.Errorf("pull request = %+v, result = %+v", provider.created, result.PR)
+	}
+	if _, err := bot.Manager().LoadEntry("alice", date); err != nil {
+		t.Errorf("entry wasn't saved: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(repoPath, "hook.txt")); err != nil || string(data) != "save 2024-01-15\n" {
+		t.Errorf("hook wrote %q, %v", data, err)
+	}
+}
+
+func TestPipelineSteps(t *testing.T) {
+	bot := New(&config.Config{Name: "alice", LocalRepoPath: t.TempDir()})
+
+	if err := bot.Skip(StepSave); err == nil {
+		t.Error("Skip() should refuse the save step")
+	}
+	if err := bot.Skip("lint"); err == nil {
+		t.Error("Skip() should refuse unknown steps")
+	}
+	if err := bot.Before("lint", func(*Run) error { return nil }); err == nil {
+		t.Error("Before() should refuse unknown steps")
+	}
+	if err := bot.ReplaceStep(step{"lint", nil}); err == nil {
+		t.Error("ReplaceStep() should refuse unknown steps")
+	}
+}
+
+// pushedRunner is a git runner for a clone with everything pushed
+type pushedRunner struct {
+	fakeRunner
+}
+
+func (p *pushedRunner) RunInDir(dir, name string, args ...string) ([]byte, error) {
+	if len(args) > 0 && args[0] == "rev-list" {
+		return []byte("0\n"), nil
+	}
+	return p.fakeRunner.RunInDir(dir, name, args...)
+}
+
+func TestPipelineAlreadySubmitted(t *testing.T) {
+	bot := New(&config.Config{Name: "alice", LocalRepoPath: t.TempDir()})
+	bot.git = git.NewClientWithRunner(&pushedRunner{})
+	if err := bot.ReplaceStep(step{StepValidate, func(run *Run) error {
+		run.Provider = &openingProvider{}
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	var ran []string
+	for _, name := range config.PipelineSteps {
+		if err := bot.After(name, func(run *Run) error {
+			ran = append(ran, run.Step)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	entry := func() *standup.Entry {
+		return &standup.Entry{Date: date, Today: []string{"Write tests"}, Blockers: "None"}
+	}
+	result, err := bot.Submit(entry(), Options{})
+	if err != nil || result.AlreadySubmitted {
+		t.Fatalf("first Submit() = %+v, %v", result, err)
+	}
+
+	ran = nil
+	result, err = bot.Submit(entry(), Options{})
+	if err != nil {
+		t.Fatalf("second Submit() error = %v", err)
+	}
+	if !result.AlreadySubmitted || result.Entry == nil {
+		t.Errorf("second Submit() = %+v, want an already submitted entry", result)
+	}
+	if got := strings.Join(ran, ","); got != "validate,sync,collect,save" {
+		t.Errorf("steps ran = %s", got)
+	}
+}
+
+func TestPipelineStandalone(t *testing.T) {
+	runner := &recordingRunner{}
+	repoPath := t.TempDir()
+	bot := New(&config.Config{Name: "alice", LocalRepoPath: repoPath, Storage: &config.StorageConfig{Backend: config.StorageDir}})
+	bot.git = git.NewClientWithRunner(runner)
+	var ran []string
+	for _, name := range config.PipelineSteps {
+		if err := bot.After(name, func(run *Run) error {
+			ran = append(ran, run.Step)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entry := &standup.Entry{Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Today: []string{"Write tests"}, Blockers: "None"}
+	result, err := bot.Submit(entry, Options{})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if got := strings.Join(ran, ","); got != "collect,save,notify" {
+		t.Errorf("steps ran = %s, want only those that don't need git", got)
+	}
+	if len(runner.commands) != 0 {
+		t.Errorf("git commands = %v, want none", runner.commands)
+	}
+	if entries, err := bot.Manager().LoadEntries("alice"); err != nil || len(entries) != 1 || result.PR != nil {
+		t.Errorf("LoadEntries() = %+v, %v", entries, err)
+	}
+
+	if _, err := bot.Merge(Options{}); err == nil || !strings.Contains(err.Error(), "dir backend") {
+		t.Errorf("Merge() error = %v, want the backend can't merge", err)
+	}
+}
+
+func TestSubmitFilePath(t *testing.T) {
+	// The path is of the entry's own day, which needn't be today
+	cfg := &config.Config{Name: "alice", LocalRepoPath: t.TempDir(), StorageFormat: "yaml", Storage: &config.StorageConfig{Backend: config.StorageDir}}
+	bot := New(cfg)
+	entry := &standup.Entry{Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Today: []string{"Write tests"}, Blockers: "None"}
+	result, err := bot.Submit(entry, Options{})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if !strings.HasSuffix(result.FilePath, filepath.Join("alice", "2024-01-15.yaml")) {
+		t.Errorf("FilePath = %s, want the 2024-01-15 document", result.FilePath)
+	}
+}
+
+func TestSubmissionCounter(t *testing.T) {
+	var outcomes []error
+	SetSubmissionCounter(func(err error) { outcomes = append(outcomes, err) })
+	defer SetSubmissionCounter(nil)
+
+	cfg := &config.Config{Name: "alice", LocalRepoPath: t.TempDir(), Storage: &config.StorageConfig{Backend: config.StorageDir}}
+	bot := New(cfg)
+	entry := &standup.Entry{Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Today: []string{"Write tests"}, Blockers: "None"}
+	if _, err := bot.Submit(entry, Options{}); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	cancelled := errors.New("cancelled")
+	if _, err := bot.SubmitFrom(func() (*standup.Entry, error) { return nil, cancelled }, Options{}); !errors.Is(err, cancelled) {
+		t.Fatalf("SubmitFrom() error = %v, want the collect error", err)
+	}
+	if len(outcomes) != 2 || outcomes[0] != nil || outcomes[1] == nil {
+		t.Errorf("counted %v, want a success and a failure", outcomes)
+	}
+}
@@ -0,0 +1,566 @@
+package standupbot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standup/parser"
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+// dailyStandup is one user's standup for the day
+type dailyStandup struct {
+	User string
+	// Path is the standup file relative to the repository root
+	Path      string
+	Entry     parser.Entry
+	Submitted time.Time
+	// Streak is the user's run of working days with a standup up to the day
+	Streak int
+}
+
+// ungroupedHeading collects standups that belong to no configured group
+const ungroupedHeading = "Other"
+
+// DefaultPRTitleTemplate and DefaultPRBodyTemplate lay out the daily PR
+// when the team config has no templates of its own
+const (
+	DefaultPRTitleTemplate = "[Standup] {{.Date}}"
+	DefaultPRBodyTemplate  = "**Daily Standups - {{.Date}}**\n\n{{.Summary}}{{.Standups}}\n💡 To merge this PR, run: `standup-bot --merge`\n"
+)
+
+// PRData is what the daily PR's title and body templates are executed with
+type PRData struct {
+	// Date is the day of the standups, as 2006-01-02
+	Date string
+	// Roster is the team's roster from the team config
+	Roster []string
+	// Entries are the day's standups in the configured order
+	Entries []PRStandup
+	// Away maps members out of office to their absence
+	Away map[string]string
+	// Summary is a table of how many items each entry has and whether it
+	// reports a blocker
+	Summary string
+	// Standups renders the entries as collapsible sections, grouped as in
+	// the team config, followed by who is out of office
+	Standups string
+}
+
+// PRStandup is one user's standup in PRData
+type PRStandup struct {
+	User string
+	// Streak is the user's run of working days with a standup
+	Streak int
+	Entry  parser.Entry
+	// Markdown renders the standup as a collapsible section, as in the
+	// default body
+	Markdown string
+}
+
+// Section returns the entry's section with the title, regardless of case,
+// or an empty one, e.g. {{(.Section "Today").Items}} in a template
+func (s PRStandup) Section(title string) parser.Section {
+	section, _ := s.Entry.Section(title)
+	return section
+}
+
+// FormatDailyPRBody formats the PR body with all standups for the day, ordered
+// and grouped as set in the team config. The order is always fully
+// determined, so the body only changes where standups did.
+func FormatDailyPRBody(repoPath string, date time.Time) string {
+	_, body := formatDailyPR(repoPath, date)
+	return body
+}
+
+// formatDailyPR formats the title and body of the daily PR with the team's
+// templates
+func formatDailyPR(repoPath string, date time.Time) (string, string) {
+	team, err := config.LoadTeamConfig(repoPath)
+	if err != nil {
+		// A broken team config shouldn't hold up the PR
+		team = &config.TeamConfig{}
+	}
+
+	standups, err := loadDailyStandups(repoPath, date)
+	if err != nil {
+		data := PRData{Date: date.Format("2006-01-02"), Roster: team.Roster}
+		return prTitle(data, team), fmt.Sprintf("**Daily Standups - %s**\n\nError reading standup files\n", data.Date)
+	}
+
+	annotateStandups(repoPath, date, standups, team)
+	redactStandups(standups, newRedactor(team.Redaction))
+	mentionBlockers(standups, team.Escalation.Mentions)
+	data := prData(date, standups, outOfOffice(repoPath, date), team)
+	return prTitle(data, team), prBody(data, team)
+}
+
+// formatDailyPRBody renders the daily PR body from the day's standups
+func formatDailyPRBody(date time.Time, standups []dailyStandup, away map[string]standup.Absence, team *config.TeamConfig) string {
+	return prBody(prData(date, standups, away, team), team)
+}
+
+// prData collects what the PR templates are executed with. It sorts the
+// standups.
+func prData(date time.Time, standups []dailyStandup, away map[string]standup.Absence, team *config.TeamConfig) PRData {
+	data := PRData{
+		Date:     date.Format("2006-01-02"),
+		Roster:   team.Roster,
+		Away:     make(map[string]string, len(away)),
+		Standups: formatStandups(standups, away, team, formatUserDetails),
+		Summary:  formatSummaryTable(standups),
+	}
+	for user, absence := range away {
+		data.Away[user] = absence.String()
+	}
+	for _, s := range standups {
+		data.Entries = append(data.Entries, PRStandup{User: s.User, Streak: s.Streak, Entry: s.Entry, Markdown: formatUserDetails(s)})
+	}
+	return data
+}
+
+// prTitle executes the team's PR title template
+func prTitle(data PRData, team *config.TeamConfig) string {
+	return strings.TrimSpace(executePRTemplate("title", team.PullRequest.Title, DefaultPRTitleTemplate, data))
+}
+
+// prBody executes the team's PR body template
+func prBody(data PRData, team *config.TeamConfig) string {
+	return executePRTemplate("body", team.PRBody.Template, DefaultPRBodyTemplate, data)
+}
+
+// executePRTemplate executes a PR template, or the default one when there
+// is none. A template that fails or renders nothing is warned about and the
+// default used, since it shouldn't hold up the PR.
+func executePRTemplate(name, text, fallback string, data PRData) string {
+	if text != "" {
+		rendered, err := renderPRTemplate(name, text, data)
+		if err == nil {
+			return rendered
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  Using the default PR %s: invalid template: %v\n", name, err)
+	}
+	rendered, _ := renderPRTemplate(name, fallback, data)
+	return rendered
+}
+
+// renderPRTemplate parses and executes a PR template
+func renderPRTemplate(name, text string, data PRData) (string, error) {
+	tmpl, err := types.ParsePRTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(builder.String()) == "" {
+		return "", fmt.Errorf("it renders nothing")
+	}
+	return builder.String(), nil
+}
+
+// annotateStandups adds each standup's streak, and its submission time when
+// the team orders standups by it
+func annotateStandups(repoPath string, date time.Time, standups []dailyStandup, team *config.TeamConfig) {
+	if team.PROrder() == config.OrderSubmission {
+		gitClient := git.NewClient()
+		for i := range standups {
+			// Uncommitted standups keep the zero time and sort last
+			standups[i].Submitted, _ = gitClient.LastCommitTime(repoPath, standups[i].Path)
+		}
+	}
+
+	for i := range standups {
+		manager := standup.NewManagerWithFormat(repoPath, storageFormatOf(standups[i].Path))
+		// A streak that can't be computed is left out of the body
+		standups[i].Streak, _, _ = manager.Streak(standups[i].User, date)
+	}
+}
+
+// redactStandups masks the standups as the team config asks, warning about
+// each one that was redacted
+func redactStandups(standups []dailyStandup, redact *redactor) {
+	for i := range standups {
+		standups[i].Entry = redact.markdown(standups[i].Entry)
+		redact.warn(standups[i].User + "'s standup in the PR body")
+	}
+}
+
+// storageFormatOf tells the storage format from the path of a standup file,
+// since the PR body is built without the submitter's config
+func storageFormatOf(path string) types.StorageFormat {
+	switch filepath.Ext(path) {
+	case ".yaml":
+		return types.StorageYAML
+	case ".json":
+		return types.StorageJSON
+	}
+	if filepath.Dir(path) != "stand-ups" {
+		return types.StorageMonthly
+	}
+	return types.StorageMarkdown
+}
+
+// outOfOffice returns the absences covering date, by user
+func outOfOffice(repoPath string, date time.Time) map[string]standup.Absence {
+	absences, err := standup.NewManager(repoPath).TeamAbsences()
+	if err != nil {
+		// Unreadable out-of-office files shouldn't hold up the PR
+		return nil
+	}
+
+	away := make(map[string]standup.Absence)
+	for user, userAbsences := range absences {
+		if absence, ok := standup.OutOfOffice(userAbsences, date); ok {
+			away[user] = absence
+		}
+	}
+	return away
+}
+
+// loadDailyStandups reads every user's standup for the date
+func loadDailyStandups(repoPath string, date time.Time) ([]dailyStandup, error) {
+	standupDir := filepath.Join(repoPath, "stand-ups")
+	files, err := os.ReadDir(standupDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var standups []dailyStandup
+	seen := make(map[string]bool)
+	for _, file := range files {
+		var standup dailyStandup
+		var found bool
+
+		if strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		if file.IsDir() {
+			// Structured storage keeps one document per day in a user
+			// directory, monthly storage one markdown file per month
+			standup.User = file.Name()
+			standup.Path, standup.Entry, found = extractStructuredStandup(filepath.Join(standupDir, file.Name()), date)
+			if !found {
+				standup.Path, standup.Entry, found = extractShardedStandup(filepath.Join(standupDir, file.Name()), date)
+			}
+			standup.Path = filepath.Join("stand-ups", file.Name(), standup.Path)
+		} else if strings.HasSuffix(file.Name(), ".md") {
+			standup.User = strings.TrimSuffix(file.Name(), ".md")
+			standup.Path = filepath.Join("stand-ups", file.Name())
+
+			content, err := os.ReadFile(filepath.Join(standupDir, file.Name()))
+			if err != nil {
+				continue
+			}
+			standup.Entry, found = extractTodayStandup(string(content), date)
+		}
+
+		// After a switch to monthly storage a user has both a directory
+		// and their old file; the directory comes first
+		if found && len(standup.Entry.Sections) > 0 && !seen[standup.User] {
+			seen[standup.User] = true
+			standups = append(standups, standup)
+		}
+	}
+	return standups, nil
+}
+
+// formatStandups renders the standups in the configured order and grouping,
+// each with render, followed by who is out of office
+func formatStandups(standups []dailyStandup, away map[string]standup.Absence, team *config.TeamConfig, render func(dailyStandup) string) string {
+	var body string
+	sortStandups(standups, team)
+
+	switch team.PRBody.GroupBy {
+	case config.GroupTeam:
+		for _, group := range groupByTeam(standups, team.SubTeams) {
+			body += formatGroup(group.Name, group.Standups, render)
+		}
+	case config.GroupProject:
+		for _, group := range groupByProject(standups) {
+			body += formatGroup(group.Name, group.Standups, render)
+		}
+	default:
+		for _, standup := range standups {
+			body += render(standup)
+		}
+	}
+
+	if len(away) > 0 {
+		users := make([]string, 0, len(away))
+		for user := range away {
+			users = append(users, user)
+		}
+		sort.Strings(users)
+
+		body += "**🌴 Out of office**\n"
+		for _, user := range users {
+			body += fmt.Sprintf("- %s: %s\n", user, away[user])
+		}
+	}
+	return body
+}
+
+// sortStandups orders standups by the configured order, by default the
+// roster's. Ties, and users missing from the roster, fall back to
+// alphabetical order.
+func sortStandups(standups []dailyStandup, team *config.TeamConfig) {
+	rosterIndex := make(map[string]int, len(team.Roster))
+	for i, member := range team.Roster {
+		rosterIndex[strings.ToLower(member)] = i
+	}
+
+	sort.SliceStable(standups, func(i, j int) bool {
+		a, b := standups[i], standups[j]
+		switch team.PROrder() {
+		case config.OrderRoster:
+			ai, aok := rosterIndex[strings.ToLower(a.User)]
+			bi, bok := rosterIndex[strings.ToLower(b.User)]
+			if aok != bok {
+				return aok
+			}
+			if ai != bi {
+				return ai < bi
+			}
+		case config.OrderSubmission:
+			if a.Submitted.IsZero() != b.Submitted.IsZero() {
+				return !a.Submitted.IsZero()
+			}
+			if !a.Submitted.Equal(b.Submitted) {
+				return a.Submitted.Before(b.Submitted)
+			}
+		}
+		if !strings.EqualFold(a.User, b.User) {
+			return strings.ToLower(a.User) < strings.ToLower(b.User)
+		}
+		return a.User < b.User
+	})
+}
+
+// standupGroup is a headed group of standups in the PR body
+type standupGroup struct {
+	Name     string
+	Standups []dailyStandup
+}
+
+// groupByTeam groups standups by sub-team in the configured order. Users in
+// several sub-teams appear under the first.
+func groupByTeam(standups []dailyStandup, subTeams []config.SubTeam) []standupGroup {
+	groups := make([]standupGroup, len(subTeams)+1)
+	teamOf := make(map[string]int)
+	for i, team := range subTeams {
+		groups[i].Name = team.Name
+		for _, member := range team.Members {
+			if _, ok := teamOf[strings.ToLower(member)]; !ok {
+				teamOf[strings.ToLower(member)] = i
+			}
+		}
+	}
+	groups[len(subTeams)].Name = ungroupedHeading
+
+	for _, standup := range standups {
+		i, ok := teamOf[strings.ToLower(standup.User)]
+		if !ok {
+			i = len(subTeams)
+		}
+		groups[i].Standups = append(groups[i].Standups, standup)
+	}
+	return groups
+}
+
+// groupByProject splits each standup's items by project tag and groups them
+// by project, alphabetically with untagged items last. Text sections, such
+// as blockers, have no tag and stay with the untagged items.
+func groupByProject(standups []dailyStandup) []standupGroup {
+	byProject := make(map[string][]dailyStandup)
+	for _, daily := range standups {
+		entries := make(map[string]*parser.Entry)
+		var projects []string
+		entryFor := func(project string) *parser.Entry {
+			if entries[project] == nil {
+				entries[project] = &parser.Entry{Date: daily.Entry.Date, Heading: daily.Entry.Heading}
+				projects = append(projects, project)
+			}
+			return entries[project]
+		}
+
+		for _, section := range daily.Entry.Sections {
+			if section.Text != "" || len(section.Items) == 0 {
+				entry := entryFor(standup.UntaggedProject)
+				entry.Sections = append(entry.Sections, section)
+				continue
+			}
+			for _, item := range section.Items {
+				project, text := standup.ProjectTag(item)
+				entry := entryFor(project)
+				if n := len(entry.Sections); n == 0 || entry.Sections[n-1].Title != section.Title {
+					entry.Sections = append(entry.Sections, parser.Section{Title: section.Title})
+				}
+				last := &entry.Sections[len(entry.Sections)-1]
+				last.Items = append(last.Items, text)
+			}
+		}
+
+		for _, project := range projects {
+			part := daily
+			part.Entry = *entries[project]
+			byProject[project] = append(byProject[project], part)
+		}
+	}
+
+	names := make([]string, 0, len(byProject))
+	for name := range byProject {
+		if name != standup.UntaggedProject {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := strings.ToLower(names[i]), strings.ToLower(names[j])
+		if a != b {
+			return a < b
+		}
+		return names[i] < names[j]
+	})
+	if _, ok := byProject[standup.UntaggedProject]; ok {
+		names = append(names, standup.UntaggedProject)
+	}
+
+	groups := make([]standupGroup, 0, len(names))
+	for _, name := range names {
+		groups = append(groups, standupGroup{Name: name, Standups: byProject[name]})
+	}
+	return groups
+}
+
+// formatGroup renders a group heading followed by its standups
+func formatGroup(name string, standups []dailyStandup, render func(dailyStandup) string) string {
+	if len(standups) == 0 {
+		return ""
+	}
+	body := fmt.Sprintf("### %s\n\n", name)
+	for _, standup := range standups {
+		body += render(standup)
+	}
+	return body
+}
+
+// formatUserStandup renders one user's standup
+func formatUserStandup(standup dailyStandup) string {
+	name := "**" + standup.User + "**"
+	if standup.Streak >= 2 {
+		name += fmt.Sprintf(" 🔥 %d", standup.Streak)
+	}
+	return fmt.Sprintf("%s\n\n%s\n\n---\n\n", name, FormatSlackEntry(standup.Entry))
+}
+
+// formatUserDetails renders one user's standup as a collapsible section,
+// left open when it reports a blocker
+func formatUserDetails(standup dailyStandup) string {
+	details := "<details>"
+	if entryBlocked(standup.Entry) {
+		details = "<details open>"
+	}
+	name := "<b>" + standup.User + "</b>"
+	if standup.Streak >= 2 {
+		name += fmt.Sprintf(" 🔥 %d", standup.Streak)
+	}
+	return fmt.Sprintf("%s\n<summary>%s</summary>\n\n%s\n\n</details>\n\n", details, name, FormatSlackEntry(standup.Entry))
+}
+
+// formatSummaryTable renders a row per standup, in order, with how many
+// items it has for yesterday and today and whether it reports a blocker
+func formatSummaryTable(standups []dailyStandup) string {
+	if len(standups) == 0 {
+		return ""
+	}
+	table := "| Who | Yesterday | Today | Blocked |\n|---|---:|---:|:---:|\n"
+	for _, s := range standups {
+		blocked := ""
+		if entryBlocked(s.Entry) {
+			blocked = "🚧"
+		}
+		table += fmt.Sprintf("| %s | %d | %d | %s |\n", s.User, len(s.Entry.Items("Yesterday")), len(s.Entry.Items("Today")), blocked)
+	}
+	return table + "\n"
+}
+
+// entryBlocked reports whether a parsed entry's blockers section names a
+// blocker
+func entryBlocked(entry parser.Entry) bool {
+	section, ok := entry.Section("Blockers")
+	if !ok {
+		return false
+	}
+	if len(section.Items) > 0 {
+		return standup.HasBlockers(strings.Join(section.Items, "\n"))
+	}
+	return standup.HasBlockers(section.Text)
+}
+
+// extractTodayStandup extracts the date's standup entry from the file
+// content, with its intraday updates merged in
+func extractTodayStandup(content string, date time.Time) (parser.Entry, bool) {
+	doc, err := parser.Parse([]byte(content))
+	if err != nil {
+		return parser.Entry{}, false
+	}
+
+	entry := doc.Find(date)
+	if entry == nil {
+		return parser.Entry{}, false
+	}
+
+	return standup.ConsolidatedMarkdown(*entry), true
+}
+
+// extractStructuredStandup reads the yaml or json document for the date, if
+// any, returning its file name and entry
+func extractStructuredStandup(userDir string, date time.Time) (string, parser.Entry, bool) {
+	for _, ext := range []string{".yaml", ".json"} {
+		name := date.Format("2006-01-02") + ext
+		entry, err := standup.ReadEntryFile(filepath.Join(userDir, name))
+		if err == nil {
+			return name, standup.MarkdownEntry(entry.Consolidated()), true
+		}
+	}
+	return "", parser.Entry{}, false
+}
+
+// extractShardedStandup reads the date's entry from the markdown file of its
+// month, if any, returning the file name and entry
+func extractShardedStandup(userDir string, date time.Time) (string, parser.Entry, bool) {
+	name := date.Format("2006-01") + ".md"
+	content, err := os.ReadFile(filepath.Join(userDir, name))
+	if err != nil {
+		return "", parser.Entry{}, false
+	}
+	entry, found := extractTodayStandup(string(content), date)
+	return name, entry, found
+}
+
+// FormatSlackEntry renders an entry's sections in slack-friendly markdown
+func FormatSlackEntry(entry parser.Entry) string {
+	// Convert markdown section labels to slack-friendly format
+	var sections []string
+	for _, section := range entry.Sections {
+		lines := []string{fmt.Sprintf("*%s:*", section.Title)}
+		for _, item := range section.Items {
+			lines = append(lines, "- "+item)
+		}
+		if section.Text != "" {
+			lines = append(lines, section.Text)
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	return strings.TrimSpace(strings.Join(sections, "\n\n"))
+}
@@ -0,0 +1,273 @@
+package standupbot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standup/parser"
+	"github.com/standup-bot/standup-bot/pkg/types"
+)
+
+func testDailyStandup(user string, submitted time.Time, yesterday ...string) dailyStandup {
+	return dailyStandup{
+		User: user,
+		Entry: parser.Entry{Sections: []parser.Section{
+			{Title: "Yesterday", Items: yesterday},
+			{Title: "Blockers", Text: "None"},
+		}},
+		Submitted: submitted,
+	}
+}
+
+// userOrder returns the users in the order their standups appear in body
+func userOrder(body string, users ...string) []string {
+	var order []string
+	for _, line := range strings.Split(body, "\n") {
+		for _, user := range users {
+			if line == "<summary><b>"+user+"</b></summary>" {
+				order = append(order, user)
+			}
+		}
+	}
+	return order
+}
+
+func TestFormatDailyPRBodyOrder(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	morning := date.Add(9 * time.Hour)
+
+	tests := []struct {
+		name  string
+		team  config.TeamConfig
+		order []string
+	}{
+		{"default is alphabetical", config.TeamConfig{}, []string{"alice", "bob", "carol", "dave"}},
+		{"default follows the roster", config.TeamConfig{
+			Roster: []string{"dave", "bob"},
+		}, []string{"dave", "bob", "alice", "carol"}},
+		{"roster, unlisted users last", config.TeamConfig{
+			Roster: []string{"Carol", "alice"},
+			PRBody: config.PRBodySettings{Order: config.OrderRoster},
+		}, []string{"carol", "alice", "bob", "dave"}},
+		{"submission, ties alphabetical, uncommitted last", config.TeamConfig{
+			PRBody: config.PRBodySettings{Order: config.OrderSubmission},
+		}, []string{"bob", "dave", "carol", "alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standups := []dailyStandup{
+				testDailyStandup("dave", morning, "Work"),
+				testDailyStandup("carol", morning.Add(time.Hour), "Work"),
+				testDailyStandup("bob", morning, "Work"),
+				testDailyStandup("alice", time.Time{}, "Work"),
+			}
+			body := formatDailyPRBody(date, standups, nil, &tt.team)
+			got := userOrder(body, "alice", "bob", "carol", "dave")
+			if strings.Join(got, ",") != strings.Join(tt.order, ",") {
+				t.Errorf("order = %v, want %v", got, tt.order)
+			}
+		})
+	}
+}
+
+func TestFormatDailyPRBodySummary(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	blocked := dailyStandup{User: "bob", Entry: parser.Entry{Sections: []parser.Section{
+		{Title: "Yesterday", Items: []string{"Deploy"}},
+		{Title: "Today", Items: []string{"Rollback", "Postmortem"}},
+		{Title: "Blockers", Text: "Waiting on the DBA"},
+	}}}
+	standups := []dailyStandup{testDailyStandup("alice", time.Time{}, "Reviews", "Docs"), blocked}
+
+	body := formatDailyPRBody(date, standups, nil, &config.TeamConfig{})
+	table := "| Who | Yesterday | Today | Blocked |\n|---|---:|---:|:---:|\n| alice | 2 | 0 |  |\n| bob | 1 | 2 | 🚧 |\n\n"
+	if !strings.HasPrefix(body, "**Daily Standups - 2024-01-15**\n\n"+table+"<details>\n<summary><b>alice</b></summary>") {
+		t.Errorf("body should start with the summary table, then each standup:\n%s", body)
+	}
+	if !strings.Contains(body, "<details open>\n<summary><b>bob</b></summary>\n\n*Yesterday:*") || !strings.Contains(body, "Waiting on the DBA\n\n</details>\n") {
+		t.Errorf("a blocked standup should be expanded:\n%s", body)
+	}
+}
+
+func TestFormatDailyPRBodyGroupByTeam(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	team := &config.TeamConfig{
+		SubTeams: []config.SubTeam{
+			{Name: "Platform", Members: []string{"bob"}},
+			{Name: "Mobile", Members: []string{"alice"}},
+			{Name: "Data", Members: []string{"erin"}},
+		},
+		PRBody: config.PRBodySettings{GroupBy: config.GroupTeam},
+	}
+	standups := []dailyStandup{
+		testDailyStandup("alice", time.Time{}, "Work"),
+		testDailyStandup("bob", time.Time{}, "Work"),
+		testDailyStandup("carol", time.Time{}, "Work"),
+	}
+
+	body := formatDailyPRBody(date, standups, nil, team)
+	platform := strings.Index(body, "### Platform\n\n<details>\n<summary><b>bob</b>")
+	mobile := strings.Index(body, "### Mobile\n\n<details>\n<summary><b>alice</b>")
+	other := strings.Index(body, "### Other\n\n<details>\n<summary><b>carol</b>")
+	if platform < 0 || mobile < platform || other < mobile {
+		t.Errorf("body should group users by sub-team in config order:\n%s", body)
+	}
+	if strings.Contains(body, "### Data") {
+		t.Error("empty sub-teams should be omitted")
+	}
+}
+
+func TestFormatDailyPRBodyOutOfOffice(t *testing.T) {
+	date := time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC)
+	standups := []dailyStandup{testDailyStandup("alice", time.Time{}, "Work")}
+	away := map[string]standup.Absence{
+		"carol": {From: "2024-07-09", To: "2024-07-09"},
+		"bob":   {From: "2024-07-08", To: "2024-07-12", Reason: "Vacation"},
+	}
+
+	body := formatDailyPRBody(date, standups, away, &config.TeamConfig{})
+	want := "**🌴 Out of office**\n- bob: 2024-07-08 to 2024-07-12 (Vacation)\n- carol: 2024-07-09\n"
+	if !strings.Contains(body, want) {
+		t.Errorf("body should list who is away, sorted:\n%s", body)
+	}
+	if strings.Index(body, "<b>alice</b>") > strings.Index(body, "Out of office") {
+		t.Error("standups should come before the out-of-office list")
+	}
+}
+
+func TestFormatDailyPRBodyGroupByProject(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	team := &config.TeamConfig{PRBody: config.PRBodySettings{GroupBy: config.GroupProject}}
+	standups := []dailyStandup{
+		testDailyStandup("alice", time.Time{}, "[Billing] Fixed invoices", "Reviewed PRs", "Added #infra alerts"),
+		testDailyStandup("bob", time.Time{}, "[Auth] Rotated keys"),
+	}
+
+	body := formatDailyPRBody(date, standups, nil, team)
+	auth := strings.Index(body, "### Auth")
+	billing := strings.Index(body, "### Billing")
+	infra := strings.Index(body, "### infra")
+	other := strings.Index(body, "### Other")
+	if auth < 0 || billing < auth || infra < billing || other < infra {
+		t.Fatalf("projects should be alphabetical with untagged items last:\n%s", body)
+	}
+	if !strings.Contains(body[billing:infra], "- Fixed invoices") || strings.Contains(body[billing:infra], "Reviewed PRs") {
+		t.Errorf("Billing group should hold only its items, without the tag:\n%s", body[billing:infra])
+	}
+	if !strings.Contains(body[other:], "- Reviewed PRs") || !strings.Contains(body[other:], "*Blockers:*\nNone") {
+		t.Errorf("untagged items and blockers belong under Other:\n%s", body[other:])
+	}
+}
+
+func TestFormatDailyPRBodyStable(t *testing.T) {
+	repoPath := t.TempDir()
+	standupDir := filepath.Join(repoPath, "stand-ups")
+	if err := os.MkdirAll(standupDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range []string{"bob", "alice"} {
+		content := "# " + user + "\n\n## 2024-01-15\n\n**Yesterday:**\n- Work\n\n**Blockers:** None\n"
+		if err := os.WriteFile(filepath.Join(standupDir, user+".md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	body := FormatDailyPRBody(repoPath, date)
+	if got := userOrder(body, "alice", "bob"); strings.Join(got, ",") != "alice,bob" {
+		t.Errorf("order = %v, want alice,bob:\n%s", got, body)
+	}
+	if FormatDailyPRBody(repoPath, date) != body {
+		t.Error("FormatDailyPRBody() should be deterministic")
+	}
+}
+
+func TestFormatDailyPRBodyUpdates(t *testing.T) {
+	repoPath := t.TempDir()
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	entry := &standup.Entry{Date: date, Today: []string{"Fix the bug"}, Blockers: "Waiting for CI"}
+	entry.AddUpdate(&standup.Entry{Today: []string{"Shipped the fix"}, Blockers: "None"}, date.Add(14*time.Hour+30*time.Minute))
+	if err := standup.NewManager(repoPath).SaveEntry(entry, "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	body := FormatDailyPRBody(repoPath, date)
+	if !strings.Contains(body, "- Fix the bug\n- Shipped the fix") || !strings.Contains(body, "*Blockers:*\nNone") {
+		t.Errorf("body should show the standup with its updates merged in:\n%s", body)
+	}
+	if strings.Contains(body, "Update 14:30") || strings.Contains(body, "Waiting for CI") {
+		t.Errorf("body should show only the latest view:\n%s", body)
+	}
+}
+
+func TestFormatDailyPRTemplates(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	team := &config.TeamConfig{
+		Roster: []string{"bob", "alice"},
+		PRBody: config.PRBodySettings{
+			Order:    config.OrderRoster,
+			Template: "{{range .Entries}}{{.User}}: {{join (.Section \"yesterday\").Items \", \"}}\n{{end}}## On-call\n{{index .Roster 0}}\n",
+		},
+		PullRequest: config.PullRequestSettings{Title: "docs(standup): {{.Date}} ({{len .Entries}} standups)"},
+	}
+	standups := []dailyStandup{
+		testDailyStandup("alice", time.Time{}, "Reviews", "Docs"),
+		testDailyStandup("bob", time.Time{}, "Deploy"),
+	}
+
+	data := prData(date, standups, nil, team)
+	if got := prBody(data, team); got != "bob: Deploy\nalice: Reviews, Docs\n## On-call\nbob\n" {
+		t.Errorf("prBody() = %q", got)
+	}
+	if got := prTitle(data, team); got != "docs(standup): 2024-01-15 (2 standups)" {
+		t.Errorf("prTitle() = %q", got)
+	}
+
+	// Templates that fail fall back to the defaults
+	team.PRBody.Template = "{{.Missing}}"
+	team.PullRequest.Title = "{{if false}}never{{end}}"
+	if got := prBody(data, team); !strings.HasPrefix(got, "**Daily Standups - 2024-01-15**\n\n| Who |") {
+		t.Errorf("prBody() = %q", got)
+	}
+	if got := prTitle(data, team); got != "[Standup] 2024-01-15" {
+		t.Errorf("prTitle() = %q", got)
+	}
+}
+
+func TestFormatUserStandupStreak(t *testing.T) {
+	standup := testDailyStandup("alice", time.Time{}, "Work")
+	if body := formatUserStandup(standup); !strings.HasPrefix(body, "**alice**\n") {
+		t.Errorf("a standup without a streak should show just the name:\n%s", body)
+	}
+	if body := formatUserDetails(standup); !strings.HasPrefix(body, "<details>\n<summary><b>alice</b></summary>\n") {
+		t.Errorf("a standup without a streak should show just the name:\n%s", body)
+	}
+
+	standup.Streak = 6
+	if body := formatUserStandup(standup); !strings.HasPrefix(body, "**alice** 🔥 6\n") {
+		t.Errorf("a streak should follow the name:\n%s", body)
+	}
+	if body := formatUserDetails(standup); !strings.HasPrefix(body, "<details>\n<summary><b>alice</b> 🔥 6</summary>\n") {
+		t.Errorf("a streak should follow the name:\n%s", body)
+	}
+}
+
+func TestStorageFormatOf(t *testing.T) {
+	tests := map[string]types.StorageFormat{
+		"stand-ups/alice.md":              types.StorageMarkdown,
+		"stand-ups/alice/2024-01.md":      types.StorageMonthly,
+		"stand-ups/alice/2024-01-15.yaml": types.StorageYAML,
+		"stand-ups/alice/2024-01-15.json": types.StorageJSON,
+	}
+	for path, want := range tests {
+		if got := storageFormatOf(filepath.FromSlash(path)); got != want {
+			t.Errorf("storageFormatOf(%s) = %s, want %s", path, got, want)
+		}
+	}
+}
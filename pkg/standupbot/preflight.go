@@ -0,0 +1,141 @@
+package standupbot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// preflight is the state checks share before a command runs
+type preflight struct {
+	gitClient *git.Client
+	cfg       *config.Config
+	// provider is set by requireForge
+	provider forge.Provider
+}
+
+// check is a pre-flight check. Cross-cutting checks are added to the chains
+// below so every command that declares the chain gets them.
+type check func(p *preflight) error
+
+// forgeChecks run before commands that use the forge: submitting through a
+// PR, merging, and the MCP tools that do either
+var forgeChecks = []check{requireGitStorage, requireGit, requireForge, requireRepository, detectBaseBranch, refuseMaintenance}
+
+// gitChecks run before commands that only need git, such as direct commits
+// to a raw git URL, which push with git's own SSH or HTTPS credentials
+var gitChecks = []check{requireGitStorage, requireGit, requireRepository, detectBaseBranch, refuseMaintenance}
+
+// runChecks runs checks in order, stopping at the first failure
+func runChecks(p *preflight, checks []check) error {
+	for _, c := range checks {
+		if err := c(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requireGitStorage checks that standups are kept in the repository, since
+// standups stored elsewhere have no commits or pull requests
+func requireGitStorage(p *preflight) error {
+	if p.cfg.Standalone() {
+		return fmt.Errorf("standups are stored with the %s backend, without a repository to commit to or merge", p.cfg.GetStorageBackend())
+	}
+	return nil
+}
+
+// requireGit checks that git is installed
+func requireGit(p *preflight) error {
+	return p.gitClient.CheckGitInstalled()
+}
+
+// requireForge creates the forge provider and checks its tools are
+// installed and authenticated
+func requireForge(p *preflight) error {
+	provider, err := NewForge(p.gitClient, p.cfg)
+	if err != nil {
+		return err
+	}
+	if err := provider.CheckAvailable(); err != nil {
+		return err
+	}
+	p.provider = provider
+	return nil
+}
+
+// requireRepository checks that the standups repository has been cloned
+func requireRepository(p *preflight) error {
+	if !p.gitClient.RepositoryExists(p.cfg.LocalRepoPath) {
+		return fmt.Errorf("repository not found at %s. Please run 'standup-bot --config' to set up", p.cfg.LocalRepoPath)
+	}
+	return nil
+}
+
+// detectBaseBranch sets the base branch to the remote's default branch
+// unless one is configured. An empty remote keeps the "main" default.
+func detectBaseBranch(p *preflight) error {
+	if p.cfg.BaseBranch != "" {
+		return nil
+	}
+	if branch, err := p.gitClient.DefaultBranch(p.cfg.LocalRepoPath); err == nil {
+		p.cfg.BaseBranch = branch
+	}
+	return nil
+}
+
+// refuseMaintenance stops changes while the team config turns on maintenance
+// mode. The config is read from the remote base branch, so clients that
+// haven't synced yet are stopped too, or from the clone when offline.
+func refuseMaintenance(p *preflight) error {
+	team, err := remoteTeamConfig(p.gitClient, p.cfg)
+	if err != nil {
+		// Commands that use the team config report an invalid one
+		return nil
+	}
+	if !team.Maintenance {
+		return nil
+	}
+
+	message := team.MaintenanceMessage
+	if message == "" {
+		message = "check with your team's admin"
+	}
+	return fmt.Errorf("the standup repository is in maintenance mode: %s", message)
+}
+
+// remoteTeamConfig loads the team config from the remote base branch,
+// falling back to the local clone when the remote can't be read
+func remoteTeamConfig(gitClient *git.Client, cfg *config.Config) (*config.TeamConfig, error) {
+	data, err := gitClient.RemoteFile(cfg.LocalRepoPath, cfg.GetBaseBranch(), config.TeamConfigFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return &config.TeamConfig{}, nil
+	}
+	if err != nil {
+		return config.LoadTeamConfig(cfg.LocalRepoPath)
+	}
+	return config.ParseTeamConfig(data)
+}
+
+// ValidateEnvironment runs the forge checks and returns the provider
+func ValidateEnvironment(gitClient *git.Client, cfg *config.Config) (forge.Provider, error) {
+	p := &preflight{gitClient: gitClient, cfg: cfg}
+	if err := runChecks(p, forgeChecks); err != nil {
+		return nil, err
+	}
+	return p.provider, nil
+}
+
+// ValidateDirectEnvironment checks prerequisites for the direct commit
+// workflow. A repository configured as a raw git URL only needs git.
+func ValidateDirectEnvironment(gitClient *git.Client, cfg *config.Config) error {
+	checks := forgeChecks
+	if cfg.HasRemoteURL() {
+		checks = gitChecks
+	}
+	return runChecks(&preflight{gitClient: gitClient, cfg: cfg}, checks)
+}
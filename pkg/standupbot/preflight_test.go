@@ -0,0 +1,81 @@
+package standupbot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+func TestRunChecks(t *testing.T) {
+	var ran []string
+	record := func(name string, err error) check {
+		return func(p *preflight) error {
+			ran = append(ran, name)
+			return err
+		}
+	}
+
+	if err := runChecks(&preflight{}, []check{record("a", nil), record("b", nil)}); err != nil {
+		t.Fatalf("runChecks() error = %v", err)
+	}
+	if strings.Join(ran, ",") != "a,b" {
+		t.Errorf("ran %v, want every check in order", ran)
+	}
+
+	ran = nil
+	failure := errors.New("maintenance")
+	if err := runChecks(&preflight{}, []check{record("a", failure), record("b", nil)}); !errors.Is(err, failure) {
+		t.Errorf("runChecks() error = %v, want %v", err, failure)
+	}
+	if strings.Join(ran, ",") != "a" {
+		t.Errorf("ran %v, want to stop at the first failure", ran)
+	}
+}
+
+func TestRefuseMaintenance(t *testing.T) {
+	cfg := &config.Config{LocalRepoPath: t.TempDir()}
+
+	// Read from the remote base branch
+	remote := git.NewClientWithRunner(&fakeRunner{output: []byte("maintenance: true\nmaintenanceMessage: Migrating to JSON until Friday\n")})
+	err := refuseMaintenance(&preflight{gitClient: remote, cfg: cfg})
+	if err == nil || !strings.Contains(err.Error(), "Migrating to JSON until Friday") {
+		t.Errorf("refuseMaintenance() error = %v, want the admin's message", err)
+	}
+
+	// Offline, the clone's copy is used
+	offline := git.NewClientWithRunner(&fakeRunner{err: errors.New("exit status 128")})
+	if err := refuseMaintenance(&preflight{gitClient: offline, cfg: cfg}); err != nil {
+		t.Errorf("refuseMaintenance() without a team config error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.LocalRepoPath, config.TeamConfigFile), []byte("maintenance: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := refuseMaintenance(&preflight{gitClient: offline, cfg: cfg}); err == nil {
+		t.Error("refuseMaintenance() should refuse when the clone's team config is in maintenance")
+	}
+}
+
+func TestValidateDirectEnvironmentWithURL(t *testing.T) {
+	repoPath := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repoPath, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Repository: "git@github.com:org/standups.git", LocalRepoPath: repoPath}
+
+	// Only git is run; a gh check would fail against this runner's output
+	gitClient := git.NewClientWithRunner(&fakeRunner{output: []byte("git version 2.43.0")})
+	if err := ValidateDirectEnvironment(gitClient, cfg); err != nil {
+		t.Errorf("ValidateDirectEnvironment() error = %v", err)
+	}
+
+	cfg.LocalRepoPath = filepath.Join(repoPath, "missing")
+	if err := ValidateDirectEnvironment(gitClient, cfg); err == nil {
+		t.Error("ValidateDirectEnvironment() should fail when the clone is missing")
+	}
+}
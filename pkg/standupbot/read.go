@@ -0,0 +1,130 @@
+package standupbot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+// Status says whether the user's standup for a day is in, where the day's
+// pull request stands and who else on the team has submitted
+type Status struct {
+	Date      time.Time
+	Submitted bool
+	// Away is the absence covering the day, if the user is out of office
+	Away *standup.Absence
+	// PR is the day's pull request, open or merged; nil when there is none
+	// or the forge can't be reached
+	PR *PRStatus
+	// Team lists everyone with standups in the repository, the user included
+	Team []Teammate
+}
+
+// PRStatus is the state of a day's pull request
+type PRStatus struct {
+	PRInfo
+	// State is forge.StateOpen, forge.StateMerged or forge.StateClosed
+	State  string
+	Checks []forge.Check
+}
+
+// Merged reports whether the pull request has been merged
+func (p *PRStatus) Merged() bool {
+	return p.State == forge.StateMerged
+}
+
+// Teammate says whether a teammate's standup for a day is in
+type Teammate struct {
+	Name      string
+	Submitted bool
+	// Away is the absence covering the day, if they are out of office
+	Away *standup.Absence
+}
+
+// History returns the user's entries dated from since to until inclusive,
+// newest first. A zero bound leaves that end open.
+func (b *Bot) History(userName string, since, until time.Time) ([]standup.Entry, error) {
+	entries, err := b.manager.LoadEntries(userName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load standups: %w", err)
+	}
+
+	var history []standup.Entry
+	for _, entry := range entries {
+		if !since.IsZero() && entry.Date.Before(since) {
+			continue
+		}
+		if !until.IsZero() && entry.Date.After(until) {
+			continue
+		}
+		history = append(history, entry)
+	}
+	return history, nil
+}
+
+// Status reports whether the user and their teammates have submitted a
+// standup for date, and the state of the day's pull request. It reads the
+// local clone, so the pull request workflow sees standups that aren't merged
+// yet only while on the day's branch.
+func (b *Bot) Status(date time.Time) (*Status, error) {
+	submitted, err := b.manager.HasEntry(b.cfg.Name, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check standup status: %w", err)
+	}
+	status := &Status{Date: date, Submitted: submitted}
+
+	// Every teammate's standup is read at once
+	entries, err := b.manager.LoadTeamEntries(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the team's standups: %w", err)
+	}
+	in := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		in[entry.User] = true
+	}
+
+	absences, err := b.manager.TeamAbsences()
+	if err != nil {
+		return nil, err
+	}
+	if absence, ok := standup.OutOfOffice(absences[b.cfg.Name], date); ok {
+		status.Away = &absence
+	}
+
+	users, err := b.manager.Users()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	for _, user := range users {
+		teammate := Teammate{Name: user, Submitted: in[user]}
+		if absence, ok := standup.OutOfOffice(absences[user], date); ok {
+			teammate.Away = &absence
+		}
+		status.Team = append(status.Team, teammate)
+	}
+
+	if provider, err := NewForge(b.git, b.cfg); err == nil {
+		if pr, err := provider.PullRequestState(b.cfg.LocalRepoPath, standupBranch(date)); err == nil && pr != nil {
+			status.PR = &PRStatus{
+				PRInfo: PRInfo{Number: pr.Number, URL: provider.PullRequestURL(pr.Number)},
+				State:  pr.State,
+				Checks: pr.Checks,
+			}
+		}
+	}
+	return status, nil
+}
+
+// Team returns every user's entry for date
+func (b *Bot) Team(date time.Time) ([]standup.StoredEntry, error) {
+	entries, err := b.manager.LoadTeamEntries(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load team standups: %w", err)
+	}
+	if entries == nil {
+		entries = []standup.StoredEntry{}
+	}
+	return entries, nil
+}
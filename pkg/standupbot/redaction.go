@@ -0,0 +1,127 @@
+package standupbot
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/standup/parser"
+)
+
+// redactor masks the text matched by the team's redaction settings in what
+// is published from a standup
+type redactor struct {
+	patterns []*regexp.Regexp
+	// count is the number of matches masked so far
+	count int
+}
+
+// newRedactor compiles the team's redaction settings. A broken setting
+// shouldn't hold up publishing, but it is reported since nothing is masked.
+func newRedactor(settings config.RedactionSettings) *redactor {
+	patterns, err := settings.Compile()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Not redacting standups: %v\n", err)
+	}
+	return &redactor{patterns: patterns}
+}
+
+// teamRedactor returns the redactor of the team config in a repository
+func teamRedactor(repoPath string) *redactor {
+	team, err := config.LoadTeamConfig(repoPath)
+	if err != nil {
+		return &redactor{}
+	}
+	return newRedactor(team.Redaction)
+}
+
+// text masks the matches in s
+func (r *redactor) text(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllStringFunc(s, func(string) string {
+			r.count++
+			return config.RedactedText
+		})
+	}
+	return s
+}
+
+// items masks the matches in each item
+func (r *redactor) items(items []string) []string {
+	if items == nil {
+		return nil
+	}
+	masked := make([]string, len(items))
+	for i, item := range items {
+		masked[i] = r.text(item)
+	}
+	return masked
+}
+
+// entry returns a copy of the entry, with its updates, masked
+func (r *redactor) entry(entry *standup.Entry) *standup.Entry {
+	masked := *entry
+	masked.Yesterday = r.items(entry.Yesterday)
+	masked.Today = r.items(entry.Today)
+	masked.Blockers = r.text(entry.Blockers)
+	masked.Sections = r.sections(entry.Sections)
+
+	masked.Updates = nil
+	for _, u := range entry.Updates {
+		masked.Updates = append(masked.Updates, standup.Update{
+			At:        u.At,
+			Yesterday: r.items(u.Yesterday),
+			Today:     r.items(u.Today),
+			Blockers:  r.text(u.Blockers),
+			Sections:  r.sections(u.Sections),
+		})
+	}
+	return &masked
+}
+
+// sections masks the items of extra sections
+func (r *redactor) sections(sections []standup.Section) []standup.Section {
+	var masked []standup.Section
+	for _, s := range sections {
+		masked = append(masked, standup.Section{Name: s.Name, Items: r.items(s.Items)})
+	}
+	return masked
+}
+
+// markdown returns a copy of a parsed entry with its sections masked. Raw
+// is dropped, since it still holds the original text.
+func (r *redactor) markdown(entry parser.Entry) parser.Entry {
+	masked := entry
+	masked.Raw = ""
+	masked.Sections = r.markdownSections(entry.Sections)
+	masked.Updates = nil
+	for _, u := range entry.Updates {
+		masked.Updates = append(masked.Updates, parser.Update{Time: u.Time, Sections: r.markdownSections(u.Sections)})
+	}
+	return masked
+}
+
+// markdownSections masks the items and text of parsed sections
+func (r *redactor) markdownSections(sections []parser.Section) []parser.Section {
+	var masked []parser.Section
+	for _, s := range sections {
+		masked = append(masked, parser.Section{Title: s.Title, Items: r.items(s.Items), Text: r.text(s.Text)})
+	}
+	return masked
+}
+
+// warn reports the matches masked so far, in what is being published, and
+// starts counting again
+func (r *redactor) warn(what string) {
+	if r.count == 0 {
+		return
+	}
+	noun := "matches"
+	if r.count == 1 {
+		noun = "match"
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Redacted %d %s from %s\n", r.count, noun, what)
+	r.count = 0
+}
@@ -0,0 +1,86 @@
+package standupbot
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/calendar"
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+// fetchCalendar downloads the meeting's calendar; tests replace it
+var fetchCalendar = calendar.Fetch
+
+// Reminder nudges the team to submit their standups before a meeting
+type Reminder struct {
+	At      time.Time
+	Meeting calendar.Occurrence
+}
+
+// Message is the text of the reminder, with times in loc
+func (r Reminder) Message(loc *time.Location) string {
+	return fmt.Sprintf("⏰ %s starts at %s, in %s. Submit your standup with `standup-bot` before it.",
+		r.Meeting.Summary, r.Meeting.Start.In(loc).Format("15:04"), r.Meeting.Start.Sub(r.At).Round(time.Minute))
+}
+
+// Reminders returns the reminders that fire from from until to, one before
+// each standup meeting in the team's calendar. They follow meetings that are
+// moved, and cancelled meetings have none.
+func (b *Bot) Reminders(from, to time.Time) ([]Reminder, error) {
+	team, err := config.LoadTeamConfig(b.cfg.LocalRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	url := team.Meeting.CalendarURL()
+	if url == "" {
+		return nil, fmt.Errorf("no meeting calendar: set meeting.calendar in %s or %s", config.TeamConfigFile, config.CalendarURLEnv)
+	}
+
+	cal, err := fetchCalendar(url, b.cfg.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	lead := team.Meeting.Lead()
+	var reminders []Reminder
+	for _, meeting := range cal.Occurrences(from.Add(lead), to.Add(lead)) {
+		if !strings.Contains(strings.ToLower(meeting.Summary), strings.ToLower(team.Meeting.Summary)) {
+			continue
+		}
+		reminders = append(reminders, Reminder{At: meeting.Start.Add(-lead), Meeting: meeting})
+	}
+	return reminders, nil
+}
+
+// ReminderFeed writes reminders as an iCalendar feed members can subscribe
+// to, each with a notification when it fires
+func ReminderFeed(w io.Writer, reminders []Reminder, loc *time.Location, stamp time.Time) error {
+	events := make([]calendar.Event, len(reminders))
+	for i, r := range reminders {
+		events[i] = calendar.Event{
+			// Stable across feeds, so subscribers see a moved meeting's
+			// reminder move rather than a new one
+			UID:         fmt.Sprintf("%s-%s@standup-bot", r.Meeting.UID, r.Meeting.Start.UTC().Format("20060102T150405Z")),
+			Summary:     "Submit your standup",
+			Description: r.Message(loc),
+			Start:       r.At,
+			Duration:    5 * time.Minute,
+			Alarm:       true,
+		}
+	}
+	return calendar.Write(w, "Standup reminders", events, stamp)
+}
+
+// PostReminder posts a reminder to the Slack webhook in
+// STANDUP_BOT_SLACK_WEBHOOK. Without the webhook there is nowhere to post,
+// which isn't an error.
+func PostReminder(text string) error {
+	webhookURL := os.Getenv(config.SlackWebhookEnv)
+	if webhookURL == "" {
+		return nil
+	}
+	return postToSlack(webhookURL, text)
+}
@@ -0,0 +1,77 @@
+package standupbot
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/calendar"
+	"github.com/standup-bot/standup-bot/pkg/config"
+)
+
+func TestReminders(t *testing.T) {
+	repoPath := t.TempDir()
+	team := "meeting:\n  calendar: webcal://calendar.acme.com/team.ics\n  summary: standup\n  remindBefore: 10m\n"
+	if err := os.WriteFile(filepath.Join(repoPath, config.TeamConfigFile), []byte(team), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Daily standups at 9:30 UTC, with the one on the 16th moved to 11:00,
+	// and a retro that isn't a standup
+	feed := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\nUID:standup\r\nSUMMARY:Daily Standup\r\nDTSTART:20240115T093000Z\r\nRRULE:FREQ=DAILY\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:standup\r\nSUMMARY:Daily Standup\r\nRECURRENCE-ID:20240116T093000Z\r\nDTSTART:20240116T110000Z\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:retro\r\nSUMMARY:Retro\r\nDTSTART:20240116T093000Z\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	var fetched string
+	fetchCalendar = func(url string, loc *time.Location) (*calendar.Calendar, error) {
+		fetched = url
+		return calendar.Parse([]byte(feed), loc)
+	}
+	t.Cleanup(func() { fetchCalendar = calendar.Fetch })
+
+	bot := New(&config.Config{LocalRepoPath: repoPath, Timezone: "UTC"})
+	from := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	reminders, err := bot.Reminders(from, from.Add(48*time.Hour))
+	if err != nil {
+		t.Fatalf("Reminders() error = %v", err)
+	}
+	if fetched != "webcal://calendar.acme.com/team.ics" {
+		t.Errorf("fetched %q", fetched)
+	}
+
+	var got []string
+	for _, r := range reminders {
+		got = append(got, r.At.UTC().Format("2006-01-02 15:04"))
+	}
+	want := []string{"2024-01-16 10:50", "2024-01-17 09:20"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("reminders at %v, want %v", got, want)
+	}
+
+	message := reminders[0].Message(time.UTC)
+	if !strings.Contains(message, "Daily Standup starts at 11:00, in 10m0s") {
+		t.Errorf("Message() = %q", message)
+	}
+
+	var buf bytes.Buffer
+	if err := ReminderFeed(&buf, reminders, time.UTC, from); err != nil {
+		t.Fatalf("ReminderFeed() error = %v", err)
+	}
+	for _, want := range []string{"UID:standup-20240116T110000Z@standup-bot", "DTSTART:20240116T105000Z", "BEGIN:VALARM"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("feed should contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRemindersWithoutCalendar(t *testing.T) {
+	t.Setenv(config.CalendarURLEnv, "")
+	_, err := New(&config.Config{LocalRepoPath: t.TempDir()}).Reminders(time.Now(), time.Now().Add(time.Hour))
+	if err == nil || !strings.Contains(err.Error(), "no meeting calendar") {
+		t.Errorf("Reminders() error = %v, want no meeting calendar", err)
+	}
+}
@@ -0,0 +1,61 @@
+package standupbot
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// standupDirPrefix is the directory the bot manages inside the standup repository
+const standupDirPrefix = "stand-ups/"
+
+// CheckWorkTree refuses to touch a repository with uncommitted changes outside
+// the stand-ups directory, since sync and branch switches would discard or
+// carry them. Interactive runs may confirm; otherwise force is required.
+func CheckWorkTree(gitClient *git.Client, repoPath string, force, interactive bool) error {
+	if force {
+		return nil
+	}
+
+	changes, err := gitClient.UncommittedChanges(repoPath)
+	if err != nil {
+		return err
+	}
+
+	var atRisk []string
+	for _, path := range changes {
+		if !strings.HasPrefix(path, standupDirPrefix) {
+			atRisk = append(atRisk, path)
+		}
+	}
+
+	if len(atRisk) == 0 {
+		return nil
+	}
+
+	list := "  " + strings.Join(atRisk, "\n  ")
+	if !interactive {
+		return fmt.Errorf("repository at %s has uncommitted changes that could be lost:\n%s\nCommit or stash them, or re-run with --force", repoPath, list)
+	}
+
+	fmt.Printf("⚠️  The standup repository at %s has uncommitted changes that could be lost:\n%s\n", repoPath, list)
+	if !confirm("Continue anyway?") {
+		return fmt.Errorf("aborted: commit or stash your changes, or re-run with --force")
+	}
+	return nil
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	reader := bufio.NewReader(os.Stdin)
+	answer, err := reader.ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
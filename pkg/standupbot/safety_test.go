@@ -0,0 +1,71 @@
+package standupbot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+// fakeRunner returns canned output for every command
+type fakeRunner struct {
+	output []byte
+	err    error
+}
+
+func (f *fakeRunner) Run(name string, args ...string) ([]byte, error) {
+	return f.output, f.err
+}
+
+func (f *fakeRunner) RunInDir(dir, name string, args ...string) ([]byte, error) {
+	return f.output, f.err
+}
+
+func TestCheckWorkTree(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		force    bool
+		wantErr  bool
+		errMatch string
+	}{
+		{
+			name:   "clean tree",
+			status: "",
+		},
+		{
+			name:   "only standup changes",
+			status: " M stand-ups/alice.md\n?? stand-ups/bob.md\n",
+		},
+		{
+			name:     "other changes",
+			status:   " M stand-ups/alice.md\n?? scratch.txt\n M README.md\n",
+			wantErr:  true,
+			errMatch: "scratch.txt",
+		},
+		{
+			name:   "other changes with force",
+			status: "?? scratch.txt\n",
+			force:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitClient := git.NewClientWithRunner(&fakeRunner{output: []byte(tt.status)})
+
+			err := CheckWorkTree(gitClient, "/test/repo", tt.force, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckWorkTree() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.errMatch) || !strings.Contains(err.Error(), "README.md") {
+					t.Errorf("CheckWorkTree() error = %v, should list the files at risk", err)
+				}
+				if strings.Contains(err.Error(), "alice.md") {
+					t.Errorf("CheckWorkTree() error = %v, should not list standup files", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// Package standupbot is the Go API of standup-bot. A Bot submits, updates
+// and merges standups and reads their history and status; the CLI, the MCP
+// server and the issue webhook all go through it, so other Go programs and
+// bots get exactly the same workflow.
+//
+//	bot := standupbot.New(cfg)
+//	result, err := bot.Submit(&standup.Entry{
+//		Date:     cfg.Today(),
+//		Today:    []string{"Review the release notes"},
+//		Blockers: "None",
+//	}, standupbot.Options{})
+package standupbot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+	"github.com/standup-bot/standup-bot/pkg/storage"
+	"github.com/standup-bot/standup-bot/pkg/types"
+	"github.com/standup-bot/standup-bot/pkg/ui"
+)
+
+// Bot runs standup workflows for the configured user and repository
+type Bot struct {
+	cfg      *config.Config
+	git      *git.Client
+	manager  *standup.Manager
+	out      io.Writer
+	progress func(ui.Event)
+	// ctx carries the trace the bot's steps are reported in
+	ctx context.Context
+
+	// steps record a standup, in order; the hooks run around them by name
+	steps  []Step
+	skip   map[string]bool
+	before map[string][]Hook
+	after  map[string][]Hook
+}
+
+// New creates a bot for the configured user and repository, with the
+// pipeline steps skipped and the commands and hooks added in its config. Progress
+// messages are discarded unless SetOutput is called.
+func New(cfg *config.Config) *Bot {
+	b := &Bot{
+		cfg:      cfg,
+		git:      NewGitClient(cfg),
+		manager:  NewManager(cfg),
+		out:      io.Discard,
+		progress: func(ui.Event) {},
+		ctx:      stepTracerContext,
+		steps:    defaultSteps(),
+		skip:     make(map[string]bool),
+		before:   make(map[string][]Hook),
+		after:    make(map[string][]Hook),
+	}
+	b.configurePipeline()
+	b.configureHooks()
+	return b
+}
+
+// SetOutput sets where progress messages such as "Syncing repository..."
+// are written
+func (b *Bot) SetOutput(w io.Writer) {
+	b.out = w
+}
+
+// SetProgress sets a function told when slow operations, such as syncing
+// the repository or opening the pull request, start and end, e.g. to show a
+// spinner
+func (b *Bot) SetProgress(progress func(ui.Event)) {
+	b.progress = progress
+}
+
+// SetContext sets the context the bot's steps and commands are traced
+// with, e.g. that of the server request it serves. A bot serves one request
+// at a time.
+func (b *Bot) SetContext(ctx context.Context) {
+	b.ctx = ctx
+	b.git = b.git.WithContext(ctx)
+}
+
+// StepTracer observes the slow steps of every bot's workflows, e.g. to
+// export them as trace spans. It is called with the bot's context and
+// returns the step's, which the step's commands are traced with, and a
+// function called when the step ends.
+type StepTracer func(ctx context.Context, step string) (context.Context, func(err error))
+
+// stepTracer observes steps; nil unless SetStepTracer was called
+var stepTracer StepTracer
+
+// stepTracerContext is the context of bots not given one with SetContext
+var stepTracerContext = context.Background()
+
+// SetStepTracer makes every bot report its slow steps, such as syncing and
+// pushing, to tracer, with ctx unless the bot is given another with
+// SetContext. Passing nil stops tracing.
+func SetStepTracer(tracer StepTracer, ctx context.Context) {
+	stepTracer = tracer
+	stepTracerContext = ctx
+}
+
+// SubmissionCounter counts the standups every bot records, e.g. to export
+// them as metrics. It is called once per submission, with its error.
+type SubmissionCounter func(err error)
+
+// submissionCounter counts submissions; nil unless SetSubmissionCounter
+// was called
+var submissionCounter SubmissionCounter
+
+// SetSubmissionCounter makes every bot count the standups it submits,
+// updates and appends to with counter. Passing nil stops counting.
+func SetSubmissionCounter(counter SubmissionCounter) {
+	submissionCounter = counter
+}
+
+// CountSubmission counts a standup recorded without a bot, such as one
+// committed from an issue form
+func CountSubmission(err error) {
+	if submissionCounter != nil {
+		submissionCounter(err)
+	}
+}
+
+// track reports the start and end of a slow operation to the progress
+// function and the step tracer
+func (b *Bot) track(step, label string, operation func() error) error {
+	b.progress(ui.Event{Kind: ui.Started, Step: step, Label: label})
+	end := func(error) {}
+	if stepTracer != nil {
+		// The step's commands are traced under it
+		var ctx context.Context
+		ctx, end = stepTracer(b.ctx, step)
+		outer := b.git
+		b.git = b.git.WithContext(ctx)
+		defer func() { b.git = outer }()
+	}
+	err := operation()
+	end(err)
+	kind := ui.Finished
+	if err != nil {
+		kind = ui.Failed
+	}
+	b.progress(ui.Event{Kind: kind, Step: step, Label: label})
+	return err
+}
+
+// Config returns the bot's configuration
+func (b *Bot) Config() *config.Config {
+	return b.cfg
+}
+
+// Git returns the git client the bot commits with
+func (b *Bot) Git() *git.Client {
+	return b.git
+}
+
+// Manager returns the manager the bot reads and saves entries with
+func (b *Bot) Manager() *standup.Manager {
+	return b.manager
+}
+
+// printf writes a progress message
+func (b *Bot) printf(format string, args ...interface{}) {
+	fmt.Fprintf(b.out, format, args...)
+}
+
+// NewManager creates a standup manager for the configured repository, or
+// the configured storage backend
+func NewManager(cfg *config.Config) *standup.Manager {
+	format, err := cfg.GetStorageFormat()
+	if err != nil {
+		format = types.StorageMarkdown
+	}
+	manager := standup.NewManagerWithFormat(cfg.LocalRepoPath, format)
+	if cfg.GetStorageBackend() == config.StorageS3 {
+		s3 := cfg.Storage.S3
+		manager.SetFileSystem(storage.NewS3(storage.S3Options{
+			Bucket:   s3.Bucket,
+			Region:   s3.Region,
+			Endpoint: s3.Endpoint,
+			Prefix:   s3.Prefix,
+		}, cfg.LocalRepoPath, storage.CredentialsFromEnv()))
+	}
+	manager.SetTemplate(cfg.Template)
+	manager.SetDayCutoff(cfg.DayCutoffHour)
+	manager.SetLocation(cfg.Location())
+	manager.SetIndex(IndexPath(cfg))
+	return manager
+}
+
+// IndexPath returns where the local index of the standup history is kept:
+// inside the clone's .git directory, so git never sees it, in a hidden
+// directory of a plain directory store, or in the user's cache directory
+// for a bucket. It is empty when there is nowhere to keep one, e.g. before
+// the repository is cloned.
+func IndexPath(cfg *config.Config) string {
+	switch cfg.GetStorageBackend() {
+	case config.StorageDir:
+		return filepath.Join(cfg.LocalRepoPath, ".standup-bot", "index.json")
+	case config.StorageS3:
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		s3 := cfg.Storage.S3
+		sum := sha256.Sum256([]byte(s3.Endpoint + "/" + s3.Bucket + "/" + s3.Prefix))
+		return filepath.Join(cacheDir, "standup-bot", "s3-"+hex.EncodeToString(sum[:8])+".json")
+	default:
+		gitDir := filepath.Join(cfg.LocalRepoPath, ".git")
+		if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
+			return ""
+		}
+		return filepath.Join(gitDir, "standup-bot", "index.json")
+	}
+}
+
+// NewGitClient creates a git client that commits with the configured
+// author identity and signing
+func NewGitClient(cfg *config.Config) *git.Client {
+	gitClient := git.NewClient()
+	if c := cfg.Commit; c != nil {
+		gitClient.SetCommitOptions(git.CommitOptions{
+			AuthorName:    c.AuthorName,
+			AuthorEmail:   c.AuthorEmail,
+			Sign:          c.Sign,
+			SigningKey:    c.SigningKey,
+			SigningFormat: c.SigningFormat,
+		})
+	}
+	return gitClient
+}
+
+// CloneOptions returns the configured options for cloning the repository
+func CloneOptions(cfg *config.Config) git.CloneOptions {
+	if cfg.Clone == nil {
+		return git.CloneOptions{}
+	}
+	return git.CloneOptions{Depth: cfg.Clone.Depth, Blobless: cfg.Clone.Blobless}
+}
+
+// NewForge creates the provider for the configured forge, detecting it from
+// the repository's remote URL when the config doesn't name one
+func NewForge(gitClient *git.Client, cfg *config.Config) (forge.Provider, error) {
+	kind, err := cfg.GetForge()
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == types.ForgeAuto {
+		url := cfg.Repository
+		if remote, err := gitClient.RemoteURL(cfg.LocalRepoPath); err == nil {
+			url = remote
+		}
+		kind = types.DetectForgeKind(url)
+	}
+
+	repo := cfg.Repository
+	if parsed, err := types.ParseRepositoryURL(repo); err == nil {
+		repo = parsed.String()
+	}
+	return forge.New(kind, repo, git.NewRunner())
+}
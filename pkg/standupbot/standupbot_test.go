@@ -0,0 +1,58 @@
+package standupbot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+)
+
+func TestNewForge(t *testing.T) {
+	tests := []struct {
+		name   string
+		forge  string
+		remote string
+		want   string
+	}{
+		{name: "configured forge wins", forge: "bitbucket", remote: "https://github.com/org/standups.git\n", want: "Bitbucket"},
+		{name: "detected from remote", remote: "git@gitlab.com:org/standups.git\n", want: "GitLab"},
+		{name: "defaults to GitHub", remote: "https://github.com/org/standups.git\n", want: "GitHub"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Repository: "org/standups", LocalRepoPath: "/repo", Forge: tt.forge}
+			gitClient := git.NewClientWithRunner(&fakeRunner{output: []byte(tt.remote)})
+
+			provider, err := NewForge(gitClient, cfg)
+			if err != nil {
+				t.Fatalf("NewForge() error = %v", err)
+			}
+			if provider.Name() != tt.want {
+				t.Errorf("NewForge() = %s, want %s", provider.Name(), tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexPath(t *testing.T) {
+	repoPath := t.TempDir()
+	cfg := &config.Config{LocalRepoPath: repoPath}
+	if got := IndexPath(cfg); got != "" {
+		t.Errorf("IndexPath() = %q before the clone, want none", got)
+	}
+
+	if err := os.Mkdir(filepath.Join(repoPath, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := IndexPath(cfg), filepath.Join(repoPath, ".git", "standup-bot", "index.json"); got != want {
+		t.Errorf("IndexPath() = %q, want %q", got, want)
+	}
+
+	cfg.Storage = &config.StorageConfig{Backend: config.StorageDir}
+	if got, want := IndexPath(cfg), filepath.Join(repoPath, ".standup-bot", "index.json"); got != want {
+		t.Errorf("IndexPath() = %q, want %q", got, want)
+	}
+}
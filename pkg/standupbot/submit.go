@@ -0,0 +1,503 @@
+package standupbot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/forge"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+// Options control how a standup is submitted or merged
+type Options struct {
+	// Direct commits to the base branch instead of the daily pull request
+	Direct bool
+	// Force proceeds even if the repository has uncommitted changes outside
+	// the stand-ups directory
+	Force bool
+	// Interactive lets the bot ask on stdin before touching a repository with
+	// such changes, instead of refusing
+	Interactive bool
+	// Merge overrides the team's merge settings when merging
+	Merge MergeOverrides
+}
+
+// Result describes a recorded standup
+type Result struct {
+	Entry *standup.Entry
+	// FilePath is the file the entry was saved to
+	FilePath string
+	// PR is the daily pull request; nil for direct submissions
+	PR *PRInfo
+	// Pending is set when a direct submission was committed but couldn't be
+	// pushed, and says what will be pushed later
+	Pending string
+	// Streak is the user's run of working days with a standup, and
+	// LongestStreak their best run; both are 0 if they couldn't be computed
+	Streak        int
+	LongestStreak int
+	// AlreadySubmitted is set when the user's standup for the day was
+	// already exactly this one, so nothing was saved or committed
+	AlreadySubmitted bool
+}
+
+// PRInfo holds information about a pull request
+type PRInfo struct {
+	Number string
+	URL    string
+	// AutoMerge is set when auto-merge was turned on as the standup was
+	// recorded, so the pull request merges once its checks and reviews pass
+	AutoMerge bool
+}
+
+// Submit records entry as the user's standup for its day, replacing any
+// earlier one, and pushes it directly or through the daily pull request
+func (b *Bot) Submit(entry *standup.Entry, opts Options) (*Result, error) {
+	return b.SubmitFrom(func() (*standup.Entry, error) { return entry, nil }, opts)
+}
+
+// SubmitFrom is Submit for an entry that is collected, for example
+// interactively, only once the repository has been checked and synced
+func (b *Bot) SubmitFrom(collect func() (*standup.Entry, error), opts Options) (*Result, error) {
+	return b.runPipeline(collect, opts)
+}
+
+// Update adds the items of update to the user's standup for its day, and
+// replaces the blockers if update has any. The standup must exist.
+func (b *Bot) Update(update *standup.Entry, opts Options) (*Result, error) {
+	return b.runPipeline(func() (*standup.Entry, error) {
+		if !opts.Direct {
+			// Today's entry lives on the standup branch until it is merged
+			if err := b.handleBranch(standupBranch(update.Date)); err != nil {
+				return nil, err
+			}
+		}
+
+		// Link the update first, so its items match the linked ones already saved
+		LinkJiraIssues(b.cfg, update)
+		return b.mergeIntoEntry(update)
+	}, opts)
+}
+
+// Append adds update to the user's standup for its day as an intraday
+// update, timestamped now, instead of merging it into the standup. The daily
+// PR shows the standup with its updates merged in. The standup must exist.
+func (b *Bot) Append(update *standup.Entry, opts Options) (*Result, error) {
+	return b.AppendFrom(func() (*standup.Entry, error) { return update, nil }, opts)
+}
+
+// AppendFrom is Append for an update that is collected, for example
+// interactively, only once the repository has been checked and synced
+func (b *Bot) AppendFrom(collect func() (*standup.Entry, error), opts Options) (*Result, error) {
+	return b.runPipeline(func() (*standup.Entry, error) {
+		update, err := collect()
+		if err != nil {
+			return nil, err
+		}
+		if !opts.Direct {
+			if err := b.handleBranch(standupBranch(update.Date)); err != nil {
+				return nil, err
+			}
+		}
+
+		entry, err := b.loadEntryFor(update)
+		if err != nil {
+			return nil, err
+		}
+		LinkJiraIssues(b.cfg, update)
+		entry.AddUpdate(update, time.Now().In(b.cfg.Location()))
+		return entry, nil
+	}, opts)
+}
+
+// validateEnvironment checks the tools and clone the workflow needs, and
+// that the repository has no changes the bot could lose
+func validateEnvironment(run *Run) error {
+	b := run.Bot
+	var err error
+	if run.Options.Direct {
+		err = ValidateDirectEnvironment(b.git, b.cfg)
+	} else {
+		run.Provider, err = ValidateEnvironment(b.git, b.cfg)
+	}
+	if err != nil {
+		return err
+	}
+	return CheckWorkTree(b.git, b.cfg.LocalRepoPath, run.Options.Force, run.Options.Interactive)
+}
+
+// syncRepository pulls the latest standups. The pull request workflow also
+// needs the base branch, which is created in an empty repository.
+func syncRepository(run *Run) error {
+	b := run.Bot
+	b.printf("Syncing repository...\n")
+	if err := b.git.SyncRepository(b.cfg.LocalRepoPath, b.cfg.GetBaseBranch()); err != nil {
+		if !run.Options.Direct {
+			return fmt.Errorf("failed to sync repository: %w", err)
+		}
+		// Offline, the standup is committed locally and pushed later
+		b.printf("⚠️  Could not sync repository, continuing offline: %v\n", err)
+	}
+
+	if run.Options.Direct {
+		return nil
+	}
+	return b.ensureBaseBranch()
+}
+
+// collectEntry gets the standup to record
+func collectEntry(run *Run) error {
+	entry, err := run.collect()
+	if err != nil {
+		return err
+	}
+	run.Entry = entry
+	return nil
+}
+
+// saveEntry writes the standup to the user's file, on the day's standup
+// branch for the pull request workflow
+func saveEntry(run *Run) error {
+	b := run.Bot
+	if !run.Options.Direct {
+		if err := b.handleBranch(standupBranch(run.Entry.Date)); err != nil {
+			return err
+		}
+	}
+
+	LinkJiraIssues(b.cfg, run.Entry)
+	duplicate, err := b.manager.IsDuplicate(run.Entry, b.cfg.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check for an existing standup: %w", err)
+	}
+	if duplicate {
+		b.printf("Standup already recorded, nothing to save.\n")
+		run.Result.AlreadySubmitted = true
+		return nil
+	}
+
+	b.printf("Recording standup...\n")
+	if err := b.manager.SaveEntry(run.Entry, b.cfg.Name); err != nil {
+		return fmt.Errorf("failed to save standup: %w", err)
+	}
+	return nil
+}
+
+// commitEntry commits the user's standups. An unchanged standup has
+// nothing to commit.
+func commitEntry(run *Run) error {
+	b := run.Bot
+	if run.Result.AlreadySubmitted {
+		return nil
+	}
+	if !run.Options.Direct {
+		return b.commitStandupChanges(run.Entry)
+	}
+
+	commitMessage := CommitMessage(b.cfg, b.manager, run.Entry, b.cfg.Name, true)
+	if err := b.git.CommitPaths(b.cfg.LocalRepoPath, commitMessage, b.manager.UserPath(b.cfg.Name)); err != nil {
+		return b.keepTemp(run.Entry, err)
+	}
+	return nil
+}
+
+// pushEntry pushes the commit: to the base branch, where a failed push
+// leaves it pending, or to the standup branch, re-applying it if teammates
+// pushed first
+func pushEntry(run *Run) error {
+	b := run.Bot
+	if run.Options.Direct {
+		b.printf("Pushing changes...\n")
+		err := b.git.Push(b.cfg.LocalRepoPath)
+		if errors.Is(err, git.ErrNotPushed) {
+			// The commit is kept locally and goes out with the next push
+			run.Result.Pending = pendingPushMessage(b.git, b.cfg.LocalRepoPath)
+			return nil
+		}
+		if err != nil {
+			return b.keepTemp(run.Entry, err)
+		}
+		return nil
+	}
+
+	b.printf("Pushing branch...\n")
+	report, err := b.git.SaveBranch(b.cfg.LocalRepoPath, standupBranch(run.Entry.Date), b.manager.UserPath(b.cfg.Name))
+	if err != nil {
+		return b.keepTemp(run.Entry, err)
+	}
+	if report.Reapplied {
+		b.printf("%s\n", describeSave(report))
+	}
+	return nil
+}
+
+// keepTemp saves the entry to a temporary file when it couldn't be recorded
+func (b *Bot) keepTemp(entry *standup.Entry, err error) error {
+	tempFile := saveTempStandup(entry, b.cfg.Name)
+	return fmt.Errorf("failed to push changes: %w\nYour standup has been saved to: %s", err, tempFile)
+}
+
+// openPullRequest creates or updates the daily pull request
+func openPullRequest(run *Run) error {
+	if run.Options.Direct {
+		return nil
+	}
+	pr, err := run.Bot.handlePullRequest(run.Provider, standupBranch(run.Entry.Date), run.Entry.Date, run.Options.Merge)
+	if err != nil {
+		return err
+	}
+	run.Result.PR = pr
+	return nil
+}
+
+// notifyTeam escalates the standup's blockers, as of its latest update,
+// unless they were escalated when it was first submitted
+func notifyTeam(run *Run) error {
+	b := run.Bot
+	if run.Result.AlreadySubmitted {
+		return nil
+	}
+	EscalateBlockers(b.cfg, b.git, b.manager, b.cfg.Name, run.Entry.Consolidated())
+	return nil
+}
+
+// pendingPushMessage describes standups committed locally but not pushed
+func pendingPushMessage(gitClient *git.Client, repoPath string) string {
+	pending, err := gitClient.UnpushedCommits(repoPath)
+	if err != nil || pending <= 1 {
+		return "Standup committed locally; it will be pushed with your next direct standup"
+	}
+	return fmt.Sprintf("Standup committed locally; %d unpushed commits will be pushed with your next direct standup", pending)
+}
+
+// standupBranch names the branch that collects a day's standups
+func standupBranch(date time.Time) string {
+	return fmt.Sprintf("standup/%s", date.Format("2006-01-02"))
+}
+
+// BranchDate returns the day whose standups a branch collects, in the
+// team's time zone, or false if it isn't a daily standup branch
+func BranchDate(branch string, location *time.Location) (time.Time, bool) {
+	day, ok := strings.CutPrefix(branch, "standup/")
+	if !ok {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation("2006-01-02", day, location)
+	return date, err == nil
+}
+
+// describeSave explains a push that had to work around teammates' pushes
+func describeSave(report *git.SaveReport) string {
+	if report.UpToDate {
+		return "The branch already had your standup; nothing more to push."
+	}
+	return fmt.Sprintf("Teammates pushed at the same time; your standup was re-applied on top of theirs (%d push attempts).", report.Attempts)
+}
+
+// handleBranch creates or switches to the standup branch. Standups stored
+// outside a repository have no branches.
+func (b *Bot) handleBranch(branchName string) error {
+	if b.cfg.Standalone() {
+		return nil
+	}
+	b.printf("Setting up standup branch...\n")
+
+	// CreateOrCheckoutBranch creates the branch if it doesn't exist, checks out
+	// and syncs an existing local branch, or checks out a remote-only one
+	if err := b.git.CreateOrCheckoutBranch(b.cfg.LocalRepoPath, branchName); err != nil {
+		return fmt.Errorf("failed to setup branch: %w", err)
+	}
+
+	b.printf("Branch ready!\n")
+	return nil
+}
+
+// commitStandupChanges adds and commits the changes to the user's standups
+func (b *Bot) commitStandupChanges(entry *standup.Entry) error {
+	if _, err := b.git.Add(b.cfg.LocalRepoPath, b.manager.UserPath(b.cfg.Name)); err != nil {
+		return fmt.Errorf("failed to add changes: %w", err)
+	}
+
+	commitMessage := CommitMessage(b.cfg, b.manager, entry, b.cfg.Name, false)
+	if _, err := b.git.Commit(b.cfg.LocalRepoPath, commitMessage); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// handlePullRequest creates or updates the daily pull request
+func (b *Bot) handlePullRequest(provider forge.Provider, branchName string, date time.Time, merge MergeOverrides) (*PRInfo, error) {
+	repoPath := b.cfg.LocalRepoPath
+	prTitle, prBody := formatDailyPR(repoPath, date)
+
+	if prExists, prNumber := provider.FindPullRequest(repoPath, branchName); prExists {
+		b.printf("Updating existing pull request #%s...\n", prNumber)
+		if err := provider.UpdatePullRequest(repoPath, prNumber, prBody); err != nil {
+			b.printf("Warning: Could not update PR body: %v\n", err)
+		}
+		pr := &PRInfo{Number: prNumber, URL: provider.PullRequestURL(prNumber)}
+		// The team's auto-merge is turned on when the PR is opened; --auto
+		// turns it on for an open one too
+		if merge.Auto {
+			b.enableAutoMerge(provider, pr, merge)
+		}
+		return pr, nil
+	}
+
+	b.printf("Creating pull request...\n")
+	team, err := config.LoadTeamConfig(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := forge.PullRequestOptions{
+		Title:     prTitle,
+		Body:      prBody,
+		Base:      b.cfg.GetBaseBranch(),
+		Head:      branchName,
+		Draft:     team.PullRequest.Draft,
+		Labels:    team.PullRequest.Labels,
+		Assignees: team.PullRequest.Assignees,
+		Reviewers: team.PullRequest.Reviewers,
+	}
+	if err := provider.CreatePullRequest(repoPath, opts); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	// Get the PR number of the newly created PR
+	_, prNumber := provider.FindPullRequest(repoPath, branchName)
+	pr := &PRInfo{Number: prNumber, URL: provider.PullRequestURL(prNumber)}
+	if team.PullRequest.Merge.Auto || merge.Auto {
+		b.enableAutoMerge(provider, pr, merge)
+	}
+	return pr, nil
+}
+
+// enableAutoMerge turns on auto-merge for the daily PR, for teams whose
+// members can't merge to the base branch themselves. The standup is already
+// pushed, so a failure is only reported.
+func (b *Bot) enableAutoMerge(provider forge.Provider, pr *PRInfo, merge MergeOverrides) {
+	merge.Auto = true
+	opts, err := b.mergeOptions(merge)
+	if err == nil {
+		b.printf("Turning on auto-merge for pull request #%s...\n", pr.Number)
+		err = provider.MergePullRequest(b.cfg.LocalRepoPath, pr.Number, opts)
+	}
+	if err != nil {
+		b.printf("Warning: Could not turn on auto-merge: %v\n", err)
+		return
+	}
+	pr.AutoMerge = true
+}
+
+// mergeIntoEntry loads the user's entry for the update's day and merges the
+// update into it
+func (b *Bot) mergeIntoEntry(update *standup.Entry) (*standup.Entry, error) {
+	entry, err := b.loadEntryFor(update)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Merge(update)
+	entry.Date = update.Date
+	return entry, nil
+}
+
+// loadEntryFor loads the user's entry for the update's day, which must exist
+func (b *Bot) loadEntryFor(update *standup.Entry) (*standup.Entry, error) {
+	entry, err := b.manager.LoadEntry(b.cfg.Name, update.Date)
+	if errors.Is(err, standup.ErrEntryNotFound) {
+		return nil, fmt.Errorf("no standup found for %s; submit one first", update.Date.Format("2006-01-02"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load standup: %w", err)
+	}
+	return entry, nil
+}
+
+// ensureBaseBranch makes sure the base branch exists, creating it in an
+// empty repository, and switches to it
+func (b *Bot) ensureBaseBranch() error {
+	repoPath, baseBranch := b.cfg.LocalRepoPath, b.cfg.GetBaseBranch()
+	baseExistsLocal := b.git.BranchExists(repoPath, baseBranch)
+	baseExistsRemote := b.git.RemoteBranchExists(repoPath, baseBranch)
+
+	if !baseExistsLocal && !baseExistsRemote {
+		b.printf("Creating initial %s branch...\n", baseBranch)
+		if err := createInitialBaseBranch(repoPath, baseBranch, b.git); err != nil {
+			return fmt.Errorf("failed to create initial %s branch: %w", baseBranch, err)
+		}
+	} else if baseExistsLocal {
+		if err := b.git.SwitchToBranch(repoPath, baseBranch); err != nil {
+			return fmt.Errorf("failed to switch to %s branch: %w", baseBranch, err)
+		}
+	}
+	return nil
+}
+
+// createInitialBaseBranch creates the initial base branch with a README
+func createInitialBaseBranch(repoPath, baseBranch string, gitClient *git.Client) error {
+	readmePath := filepath.Join(repoPath, "README.md")
+	readmeContent := `# Team Standups
+
+This repository contains daily standup updates from the team.
+
+## Structure
+
+Each team member has their own markdown file in the ` + "`stand-ups/`" + ` directory.
+`
+
+	if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
+		return fmt.Errorf("failed to create README: %w", err)
+	}
+
+	standupDir := filepath.Join(repoPath, "stand-ups")
+	if err := os.MkdirAll(standupDir, 0755); err != nil {
+		return fmt.Errorf("failed to create stand-ups directory: %w", err)
+	}
+
+	if _, err := gitClient.Add(repoPath, readmePath); err != nil {
+		return fmt.Errorf("failed to add files: %w", err)
+	}
+	if _, err := gitClient.Commit(repoPath, "Initial repository setup"); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	// Push to create the base branch on remote
+	if err := gitClient.PushBranch(repoPath, baseBranch); err != nil {
+		return fmt.Errorf("failed to push %s branch: %w", baseBranch, err)
+	}
+	return nil
+}
+
+// saveTempStandup saves a standup to a temporary file in case of errors
+func saveTempStandup(entry *standup.Entry, userName string) string {
+	tempFile := fmt.Sprintf("/tmp/standup-%s-%s.txt", userName, entry.Date.Format("2006-01-02"))
+
+	content := fmt.Sprintf("Standup for %s on %s\n\nYesterday:\n", userName, entry.Date.Format("2006-01-02"))
+	for _, item := range entry.Yesterday {
+		content += fmt.Sprintf("- %s\n", item)
+	}
+	content += "\nToday:\n"
+	for _, item := range entry.Today {
+		content += fmt.Sprintf("- %s\n", item)
+	}
+	content += fmt.Sprintf("\nBlockers:\n%s\n", entry.Blockers)
+	for _, section := range entry.Sections {
+		content += fmt.Sprintf("\n%s:\n", section.Name)
+		for _, item := range section.Items {
+			content += fmt.Sprintf("- %s\n", item)
+		}
+	}
+
+	// Ignore error for temp file save
+	_ = os.WriteFile(tempFile, []byte(content), 0644)
+
+	return tempFile
+}
@@ -0,0 +1,85 @@
+package standupbot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/standup-bot/standup-bot/pkg/config"
+	"github.com/standup-bot/standup-bot/pkg/git"
+	"github.com/standup-bot/standup-bot/pkg/standup"
+)
+
+func TestMergeIntoEntry(t *testing.T) {
+	bot := New(&config.Config{Name: "alice", LocalRepoPath: t.TempDir()})
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	update := &standup.Entry{Date: today, Today: []string{"Review PRs"}}
+
+	if _, err := bot.mergeIntoEntry(update); err == nil {
+		t.Error("mergeIntoEntry() should fail without an existing standup")
+	}
+
+	existing := &standup.Entry{Date: today, Yesterday: []string{"Work"}, Today: []string{"Write tests"}, Blockers: "None"}
+	if err := bot.Manager().SaveEntry(existing, "alice"); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+
+	entry, err := bot.mergeIntoEntry(update)
+	if err != nil {
+		t.Fatalf("mergeIntoEntry() error = %v", err)
+	}
+	if len(entry.Today) != 2 || entry.Today[1] != "Review PRs" || len(entry.Yesterday) != 1 {
+		t.Errorf("mergeIntoEntry() = %+v", entry)
+	}
+}
+
+func TestBranchDate(t *testing.T) {
+	if date, ok := BranchDate(standupBranch(time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)), time.Local); !ok || date.Format("2006-01-02") != "2024-01-15" {
+		t.Errorf("BranchDate() = %v, %v, want 2024-01-15", date, ok)
+	}
+	// The day is in the team's time zone, whatever the machine's is
+	tokyo := time.FixedZone("JST", 9*60*60)
+	if date, ok := BranchDate("standup/2024-01-15", tokyo); !ok || !date.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, tokyo)) {
+		t.Errorf("BranchDate() = %v, %v, want midnight on 2024-01-15 in JST", date, ok)
+	}
+	for _, branch := range []string{"main", "standup/latest", "feature/2024-01-15"} {
+		if _, ok := BranchDate(branch, time.Local); ok {
+			t.Errorf("BranchDate(%q) = true, want false", branch)
+		}
+	}
+}
+
+func TestAppend(t *testing.T) {
+	bot := New(&config.Config{Name: "alice", LocalRepoPath: t.TempDir()})
+	bot.git = git.NewClientWithRunner(&pushedRunner{})
+	if err := bot.ReplaceStep(step{StepValidate, func(run *Run) error {
+		run.Provider = &openingProvider{}
+		return nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	update := &standup.Entry{Date: today, Today: []string{"Shipped the fix"}, Blockers: "None"}
+
+	if _, err := bot.Append(update, Options{}); err == nil {
+		t.Error("Append() should fail without an existing standup")
+	}
+
+	existing := &standup.Entry{Date: today, Yesterday: []string{"Work"}, Today: []string{"Fix the bug"}, Blockers: "Waiting for CI"}
+	if err := bot.Manager().SaveEntry(existing, "alice"); err != nil {
+		t.Fatalf("SaveEntry() error = %v", err)
+	}
+	if _, err := bot.Append(update, Options{}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	entry, err := bot.Manager().LoadEntry("alice", today)
+	if err != nil {
+		t.Fatalf("LoadEntry() error = %v", err)
+	}
+	if len(entry.Today) != 1 || entry.Blockers != "Waiting for CI" {
+		t.Errorf("Append() changed the standup itself: %+v", entry)
+	}
+	if len(entry.Updates) != 1 || entry.Updates[0].Today[0] != "Shipped the fix" || entry.Updates[0].Blockers != "None" {
+		t.Errorf("updates = %+v", entry.Updates)
+	}
+}
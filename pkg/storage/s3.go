@@ -0,0 +1,384 @@
+// Package storage keeps standup files outside a git repository. Its
+// backends implement standup.FileSystem, so a standup.Manager reads and
+// writes them like the files of a clone.
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Timeout bounds a request to the bucket
+const s3Timeout = 30 * time.Second
+
+// S3Options are the bucket an S3 file system is kept in
+type S3Options struct {
+	Bucket string
+	// Region is us-east-1 when empty
+	Region string
+	// Endpoint is an S3-compatible service, addressed with the bucket in the
+	// path; the bucket's AWS address when empty
+	Endpoint string
+	// Prefix is prepended to every key
+	Prefix string
+}
+
+// Credentials sign requests to S3
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// CredentialsFromEnv reads the standard AWS credential variables
+func CredentialsFromEnv() Credentials {
+	return Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// S3 is a file system in an S3 bucket. Files below its root are stored under
+// their path relative to the root; directories exist only as key prefixes,
+// so MkdirAll does nothing.
+type S3 struct {
+	opts   S3Options
+	root   string
+	creds  Credentials
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3 creates a file system storing the files below root in the bucket
+func NewS3(opts S3Options, root string, creds Credentials) *S3 {
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	return &S3{
+		opts:   opts,
+		root:   root,
+		creds:  creds,
+		client: &http.Client{Timeout: s3Timeout},
+		now:    time.Now,
+	}
+}
+
+// key returns the object key of a file
+func (s *S3) key(name string) (string, error) {
+	rel, err := filepath.Rel(s.root, name)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the storage root %s", name, s.root)
+	}
+	if rel == "." {
+		return strings.TrimSuffix(s.opts.Prefix, "/"), nil
+	}
+	return s.opts.Prefix + filepath.ToSlash(rel), nil
+}
+
+// ReadFile downloads a file
+func (s *S3) ReadFile(filename string) ([]byte, error) {
+	key, err := s.key(filename)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: filename, Err: err}
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// WriteFile uploads a file
+func (s *S3) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	key, err := s.key(filename)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(http.MethodPut, key, nil, data)
+	if err != nil {
+		return &fs.PathError{Op: "write", Path: filename, Err: err}
+	}
+	return resp.Body.Close()
+}
+
+// MkdirAll does nothing, since directories are implied by the keys below them
+func (s *S3) MkdirAll(path string, perm os.FileMode) error {
+	return nil
+}
+
+// Stat describes a file, or a directory when there are keys below it
+func (s *S3) Stat(name string) (os.FileInfo, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(http.MethodHead, key, nil, nil)
+	if err == nil {
+		resp.Body.Close()
+		modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+		return fileInfo{name: path.Base(key), size: resp.ContentLength, modTime: modTime}, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+
+	entries, err := s.ReadDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	if len(entries) == 0 {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return fileInfo{name: path.Base(key), dir: true}, nil
+}
+
+// listResult is a page of ListObjectsV2
+type listResult struct {
+	Contents []struct {
+		Key          string
+		Size         int64
+		LastModified time.Time
+	}
+	CommonPrefixes []struct {
+		Prefix string
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// ReadDir lists the files and directories directly below a directory,
+// sorted by name like os.ReadDir
+func (s *S3) ReadDir(name string) ([]os.DirEntry, error) {
+	key, err := s.key(name)
+	if err != nil {
+		return nil, err
+	}
+	prefix := key
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	var entries []os.DirEntry
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}, "delimiter": {"/"}}
+	for {
+		resp, err := s.do(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+		}
+		var page listResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the listing of %s: %w", name, err)
+		}
+
+		for _, p := range page.CommonPrefixes {
+			entries = append(entries, fileInfo{name: path.Base(strings.TrimSuffix(p.Prefix, "/")), dir: true})
+		}
+		for _, c := range page.Contents {
+			if c.Key == prefix {
+				continue
+			}
+			entries = append(entries, fileInfo{name: path.Base(c.Key), size: c.Size, modTime: c.LastModified})
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		query.Set("continuation-token", page.NextContinuationToken)
+	}
+
+	if len(entries) == 0 {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// do sends a signed request for an object, or for the bucket when key is
+// empty. A missing object is reported as fs.ErrNotExist.
+func (s *S3) do(method, key string, query url.Values, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.objectURL(key, query), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		req.Body = nil
+	}
+	s.sign(req, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fs.ErrNotExist
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var s3Error struct {
+			Code    string
+			Message string
+		}
+		xml.NewDecoder(resp.Body).Decode(&s3Error)
+		if s3Error.Code != "" {
+			return nil, fmt.Errorf("S3 returned %s: %s: %s", resp.Status, s3Error.Code, s3Error.Message)
+		}
+		return nil, fmt.Errorf("S3 returned %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// objectURL addresses an object in the bucket: by the bucket's own host on
+// AWS, and by path on other services
+func (s *S3) objectURL(key string, query url.Values) string {
+	var base string
+	if s.opts.Endpoint != "" {
+		base = strings.TrimSuffix(s.opts.Endpoint, "/") + "/" + s.opts.Bucket
+	} else {
+		base = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", s.opts.Bucket, s.opts.Region)
+	}
+	u := base + "/" + uriEncode(key, false)
+	if len(query) > 0 {
+		u += "?" + canonicalQuery(query)
+	}
+	return u
+}
+
+// sign adds an AWS Signature Version 4 to the request, covering the host
+// and every header already set on it
+func (s *S3) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.opts.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.creds.SecretAccessKey), day)
+	for _, part := range []string{s.opts.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by name, as signed
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var pairs []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and
+// slashes unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// fileInfo describes an object or a key prefix, as both os.FileInfo and
+// os.DirEntry
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (f fileInfo) Name() string       { return f.name }
+func (f fileInfo) Size() int64        { return f.size }
+func (f fileInfo) ModTime() time.Time { return f.modTime }
+func (f fileInfo) IsDir() bool        { return f.dir }
+func (f fileInfo) Sys() any           { return nil }
+
+func (f fileInfo) Mode() os.FileMode {
+	if f.dir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+func (f fileInfo) Type() os.FileMode          { return f.Mode().Type() }
+func (f fileInfo) Info() (os.FileInfo, error) { return f, nil }
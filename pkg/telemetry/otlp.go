@@ -0,0 +1,268 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// The types below are the parts of the OTLP/JSON encoding standup-bot
+// sends. IDs are hex, and 64-bit integers are strings, as the encoding
+// requires.
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID      string          `json:"traceId"`
+	SpanID       string          `json:"spanId"`
+	ParentSpanID string          `json:"parentSpanId,omitempty"`
+	Name         string          `json:"name"`
+	Kind         int             `json:"kind"`
+	Start        string          `json:"startTimeUnixNano"`
+	End          string          `json:"endTimeUnixNano"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	Status       otlpStatus      `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpNumberPoint struct {
+	Attributes []otlpAttribute `json:"attributes,omitempty"`
+	Start      string          `json:"startTimeUnixNano"`
+	Time       string          `json:"timeUnixNano"`
+	AsInt      string          `json:"asInt"`
+}
+
+type otlpHistogramPoint struct {
+	Attributes     []otlpAttribute `json:"attributes,omitempty"`
+	Start          string          `json:"startTimeUnixNano"`
+	Time           string          `json:"timeUnixNano"`
+	Count          string          `json:"count"`
+	Sum            float64         `json:"sum"`
+	BucketCounts   []string        `json:"bucketCounts"`
+	ExplicitBounds []float64       `json:"explicitBounds"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberPoint `json:"dataPoints"`
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramPoint `json:"dataPoints"`
+	AggregationTemporality int                  `json:"aggregationTemporality"`
+}
+
+type otlpMetric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Unit        string         `json:"unit"`
+	Sum         *otlpSum       `json:"sum,omitempty"`
+	Histogram   *otlpHistogram `json:"histogram,omitempty"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpMetrics struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+// cumulative is OTLP's AGGREGATION_TEMPORALITY_CUMULATIVE
+const cumulative = 2
+
+// otlpStatusError is OTLP's STATUS_CODE_ERROR
+const otlpStatusError = 2
+
+// tracesPayload encodes spans; t.mu must be held
+func (t *Telemetry) tracesPayload(spans []spanData) otlpTraces {
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		span := otlpSpan{
+			TraceID:      s.traceID,
+			SpanID:       s.spanID,
+			ParentSpanID: s.parentID,
+			Name:         s.name,
+			Kind:         s.kind,
+			Start:        unixNano(s.start),
+			End:          unixNano(s.end),
+			Attributes:   attributes(s.attrs),
+		}
+		if s.err != nil {
+			span.Status = otlpStatus{Code: otlpStatusError, Message: s.err.Error()}
+		}
+		encoded = append(encoded, span)
+	}
+
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource:   t.resource(),
+		ScopeSpans: []otlpScopeSpans{{Scope: t.scope(), Spans: encoded}},
+	}}}
+}
+
+// metricsPayload encodes the counters and histograms as of now; t.mu must be held
+func (t *Telemetry) metricsPayload(now time.Time) otlpMetrics {
+	byName := make(map[string]*otlpMetric)
+	metric := func(name, unit string) *otlpMetric {
+		if byName[name] == nil {
+			byName[name] = &otlpMetric{Name: name, Description: descriptions[name], Unit: unit}
+		}
+		return byName[name]
+	}
+
+	for _, key := range sortedKeys(t.sums) {
+		s := t.sums[key]
+		m := metric(s.name, "1")
+		if m.Sum == nil {
+			m.Sum = &otlpSum{AggregationTemporality: cumulative, IsMonotonic: true}
+		}
+		m.Sum.DataPoints = append(m.Sum.DataPoints, otlpNumberPoint{
+			Attributes: attributes(s.attrs),
+			Start:      unixNano(t.started),
+			Time:       unixNano(now),
+			AsInt:      strconv.FormatInt(s.value, 10),
+		})
+	}
+	for _, key := range sortedKeys(t.histograms) {
+		h := t.histograms[key]
+		m := metric(h.name, "s")
+		if m.Histogram == nil {
+			m.Histogram = &otlpHistogram{AggregationTemporality: cumulative}
+		}
+		buckets := make([]string, len(h.buckets))
+		for i, count := range h.buckets {
+			buckets[i] = strconv.FormatUint(count, 10)
+		}
+		m.Histogram.DataPoints = append(m.Histogram.DataPoints, otlpHistogramPoint{
+			Attributes:     attributes(h.attrs),
+			Start:          unixNano(t.started),
+			Time:           unixNano(now),
+			Count:          strconv.FormatUint(h.count, 10),
+			Sum:            h.sum,
+			BucketCounts:   buckets,
+			ExplicitBounds: durationBounds,
+		})
+	}
+
+	metrics := make([]otlpMetric, 0, len(byName))
+	for _, name := range sortedKeys(byName) {
+		metrics = append(metrics, *byName[name])
+	}
+
+	return otlpMetrics{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     t.resource(),
+		ScopeMetrics: []otlpScopeMetrics{{Scope: t.scope(), Metrics: metrics}},
+	}}}
+}
+
+// resource describes the service exporting
+func (t *Telemetry) resource() otlpResource {
+	attrs := map[string]string{"service.name": t.settings.ServiceName}
+	if t.settings.ServiceVersion != "" {
+		attrs["service.version"] = t.settings.ServiceVersion
+	}
+	return otlpResource{Attributes: attributes(attrs)}
+}
+
+// scope names the instrumentation
+func (t *Telemetry) scope() otlpScope {
+	return otlpScope{Name: "github.com/standup-bot/standup-bot", Version: t.settings.ServiceVersion}
+}
+
+// post sends a payload to an OTLP/HTTP endpoint
+func (t *Telemetry) post(endpoint string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid OTLP endpoint %s: %w", endpoint, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range t.settings.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export telemetry to %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to export telemetry to %s: %s: %s", endpoint, resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// attributes encodes attributes, sorted by key
+func attributes(attrs map[string]string) []otlpAttribute {
+	var encoded []otlpAttribute
+	for _, key := range sortedKeys(attrs) {
+		encoded = append(encoded, otlpAttribute{Key: key, Value: otlpValue{StringValue: attrs[key]}})
+	}
+	return encoded
+}
+
+// sortedKeys returns a map's keys in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unixNano encodes a time as OTLP/JSON does
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
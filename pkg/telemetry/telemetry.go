@@ -0,0 +1,434 @@
+// Package telemetry exports traces and metrics of standup-bot's workflows to
+// an OpenTelemetry collector, over OTLP/HTTP with JSON encoding. It's set up
+// from the standard OTEL_* environment variables and stays off unless an
+// OTLP endpoint is configured.
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metric names
+const (
+	// Runs counts commands by name and outcome
+	Runs = "standup_bot.runs"
+	// Submissions counts standups recorded, by outcome
+	Submissions = "standup_bot.submissions"
+	// PushRetries counts pushes rejected because the remote moved on, which
+	// are retried after syncing
+	PushRetries = "standup_bot.push.retries"
+	// RunDuration is how long commands take, in seconds
+	RunDuration = "standup_bot.run.duration"
+	// StepDuration is how long workflow steps such as pushing take, in seconds
+	StepDuration = "standup_bot.step.duration"
+	// CommandDuration is how long git and gh commands take, in seconds
+	CommandDuration = "standup_bot.command.duration"
+)
+
+// descriptions document the metrics for the collector
+var descriptions = map[string]string{
+	Runs:            "standup-bot commands run, by command and outcome",
+	Submissions:     "Standups recorded, by outcome",
+	PushRetries:     "Pushes rejected as non-fast-forward and retried after syncing",
+	RunDuration:     "Duration of standup-bot commands",
+	StepDuration:    "Duration of workflow steps",
+	CommandDuration: "Duration of git and gh commands",
+}
+
+// durationBounds are the histogram buckets for durations, in seconds
+var durationBounds = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// Settings configure where telemetry is exported
+type Settings struct {
+	TracesEndpoint  string
+	MetricsEndpoint string
+	Headers         map[string]string
+	ServiceName     string
+	ServiceVersion  string
+	// Interval is how often long-running servers export
+	Interval time.Duration
+	// Warning explains a setting that was ignored, such as an unsupported
+	// OTEL_EXPORTER_OTLP_PROTOCOL
+	Warning string
+}
+
+// SettingsFromEnv reads the settings from the standard OpenTelemetry
+// variables: OTEL_EXPORTER_OTLP_ENDPOINT (or the _TRACES_ and _METRICS_
+// endpoints), OTEL_EXPORTER_OTLP_HEADERS, OTEL_EXPORTER_OTLP_PROTOCOL,
+// OTEL_SERVICE_NAME, OTEL_METRIC_EXPORT_INTERVAL and OTEL_SDK_DISABLED. It
+// returns false when no endpoint is set or telemetry is disabled. Protocols
+// other than http/json fall back to it, with a Warning.
+func SettingsFromEnv(getenv func(string) string) (Settings, bool, error) {
+	if strings.EqualFold(getenv("OTEL_SDK_DISABLED"), "true") {
+		return Settings{}, false, nil
+	}
+
+	base := strings.TrimRight(getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "/")
+	settings := Settings{
+		TracesEndpoint:  getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"),
+		MetricsEndpoint: getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT"),
+		Headers:         parseHeaders(getenv("OTEL_EXPORTER_OTLP_HEADERS")),
+		ServiceName:     getenv("OTEL_SERVICE_NAME"),
+		Interval:        60 * time.Second,
+	}
+	if base != "" {
+		if settings.TracesEndpoint == "" {
+			settings.TracesEndpoint = base + "/v1/traces"
+		}
+		if settings.MetricsEndpoint == "" {
+			settings.MetricsEndpoint = base + "/v1/metrics"
+		}
+	}
+	if settings.TracesEndpoint == "" && settings.MetricsEndpoint == "" {
+		return Settings{}, false, nil
+	}
+
+	// Collectors accept http/json on the same endpoint as http/protobuf, so
+	// exporting goes on rather than leaving telemetry off
+	if protocol := getenv("OTEL_EXPORTER_OTLP_PROTOCOL"); protocol != "" && protocol != "http/json" {
+		settings.Warning = fmt.Sprintf("OTEL_EXPORTER_OTLP_PROTOCOL=%s isn't supported; exporting OTLP as http/json instead", protocol)
+	}
+	if settings.ServiceName == "" {
+		settings.ServiceName = "standup-bot"
+	}
+	if interval := getenv("OTEL_METRIC_EXPORT_INTERVAL"); interval != "" {
+		ms, err := strconv.Atoi(interval)
+		if err != nil || ms <= 0 {
+			return Settings{}, false, fmt.Errorf("invalid OTEL_METRIC_EXPORT_INTERVAL %q: expected milliseconds", interval)
+		}
+		settings.Interval = time.Duration(ms) * time.Millisecond
+	}
+	return settings, true, nil
+}
+
+// parseHeaders parses "key1=value1,key2=value2"
+func parseHeaders(value string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(key) != "" {
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+	}
+	return headers
+}
+
+// Telemetry collects the spans and metrics of a run, or of a server's
+// requests, and exports them. Spans are carried in a context.Context: one
+// started with a context holding a span is its child, and one started
+// without is the root of a new trace.
+type Telemetry struct {
+	settings Settings
+	client   *http.Client
+	started  time.Time
+
+	mu         sync.Mutex
+	spans      []spanData
+	sums       map[string]*sum
+	histograms map[string]*histogram
+	lastErr    error
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New creates telemetry that exports with settings
+func New(settings Settings) *Telemetry {
+	return &Telemetry{
+		settings:   settings,
+		client:     &http.Client{Timeout: 5 * time.Second},
+		started:    time.Now(),
+		sums:       make(map[string]*sum),
+		histograms: make(map[string]*histogram),
+	}
+}
+
+// Span is an operation in a trace, such as a workflow step or a git command
+type Span struct {
+	t    *Telemetry
+	data spanData
+}
+
+// spanData is a span's exported fields
+type spanData struct {
+	traceID  string
+	spanID   string
+	parentID string
+	name     string
+	kind     int
+	attrs    map[string]string
+	start    time.Time
+	end      time.Time
+	err      error
+}
+
+// OTLP span kinds
+const (
+	spanInternal = 1
+	spanServer   = 2
+	spanClient   = 3
+)
+
+// spanKey is the context key of the current span
+type spanKey struct{}
+
+// ContextWithSpan returns a copy of ctx in which spans are started as
+// children of span
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span in ctx, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// StartSpan starts a span as a child of the one in ctx, or as the root of a
+// new trace when ctx has none. Spans started with the returned context are
+// its children.
+func (t *Telemetry) StartSpan(ctx context.Context, name string, attrs map[string]string) (context.Context, *Span) {
+	span := t.startSpan(SpanFromContext(ctx), name, spanInternal, attrs)
+	return ContextWithSpan(ctx, span), span
+}
+
+// startSpan starts a span under parent, or a new trace when parent is nil
+func (t *Telemetry) startSpan(parent *Span, name string, kind int, attrs map[string]string) *Span {
+	span := &Span{
+		t: t,
+		data: spanData{
+			spanID: randomID(8),
+			name:   name,
+			kind:   kind,
+			attrs:  attrs,
+			start:  time.Now(),
+		},
+	}
+	if parent != nil {
+		span.data.traceID = parent.data.traceID
+		span.data.parentID = parent.data.spanID
+	} else {
+		span.data.traceID = randomID(16)
+	}
+	return span
+}
+
+// End ends the span, marking it failed when err is set
+func (s *Span) End(err error) {
+	t := s.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s.data.end = time.Now()
+	s.data.err = err
+	t.spans = append(t.spans, s.data)
+}
+
+// Add adds to a counter
+func (t *Telemetry) Add(name string, value int64, attrs map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := seriesKey(name, attrs)
+	if t.sums[key] == nil {
+		t.sums[key] = &sum{name: name, attrs: attrs}
+	}
+	t.sums[key].value += value
+}
+
+// RecordDuration adds a duration to a histogram, in seconds
+func (t *Telemetry) RecordDuration(name string, d time.Duration, attrs map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := seriesKey(name, attrs)
+	h := t.histograms[key]
+	if h == nil {
+		h = &histogram{name: name, attrs: attrs, buckets: make([]uint64, len(durationBounds)+1)}
+		t.histograms[key] = h
+	}
+	seconds := d.Seconds()
+	h.count++
+	h.sum += seconds
+	h.buckets[sort.SearchFloat64s(durationBounds, seconds)]++
+}
+
+// sum is a counter's value for one set of attributes
+type sum struct {
+	name  string
+	attrs map[string]string
+	value int64
+}
+
+// histogram is a duration histogram for one set of attributes
+type histogram struct {
+	name    string
+	attrs   map[string]string
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+// seriesKey identifies a metric with its attributes
+func seriesKey(name string, attrs map[string]string) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\x00%s=%s", k, attrs[k])
+	}
+	return b.String()
+}
+
+// Outcome is the outcome attribute for an error: "success" or "failure"
+func Outcome(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
+
+// StartRun traces a standup-bot command, e.g. "standup-bot status", and
+// counts it when the returned function is called with its result. The
+// returned context carries the run's span, for its steps and commands.
+func (t *Telemetry) StartRun(ctx context.Context, command string) (context.Context, func(err error)) {
+	ctx, span := t.StartSpan(ctx, command, map[string]string{"standup_bot.command": command})
+	return ctx, func(err error) {
+		span.End(err)
+		t.Add(Runs, 1, map[string]string{"command": command, "outcome": Outcome(err)})
+		t.RecordDuration(RunDuration, time.Since(span.data.start), map[string]string{"command": command})
+	}
+}
+
+// StartRequest traces a request to a long-running server, or a tick of its
+// background work, as a trace of its own, whatever span ctx carries. The
+// returned context carries the request's span.
+func (t *Telemetry) StartRequest(ctx context.Context, operation string) (context.Context, func(err error)) {
+	span := t.startSpan(nil, operation, spanServer, map[string]string{"standup_bot.operation": operation})
+	return ContextWithSpan(ctx, span), span.End
+}
+
+// CountSubmission counts a standup recorded, by outcome; it suits
+// standupbot.SetSubmissionCounter
+func (t *Telemetry) CountSubmission(err error) {
+	t.Add(Submissions, 1, map[string]string{"outcome": Outcome(err)})
+}
+
+// StartStep traces a workflow step, such as pushing, under the span in ctx
+// and records its duration; it suits standupbot.SetStepTracer
+func (t *Telemetry) StartStep(ctx context.Context, step string) (context.Context, func(err error)) {
+	ctx, span := t.StartSpan(ctx, "step "+step, map[string]string{"standup_bot.step": step})
+	return ctx, func(err error) {
+		span.End(err)
+		t.RecordDuration(StepDuration, time.Since(span.data.start), map[string]string{"step": step, "outcome": Outcome(err)})
+	}
+}
+
+// StartCommand traces a git or gh command under the span in ctx and records
+// its duration. Pushes rejected as non-fast-forward are counted as retries.
+// It suits git.SetTracer.
+func (t *Telemetry) StartCommand(ctx context.Context, dir, name string, args []string) func(output []byte, err error) {
+	command := commandName(name, args)
+	span := t.startSpan(SpanFromContext(ctx), command, spanClient, map[string]string{"process.executable.name": name, "standup_bot.command": command})
+	return func(output []byte, err error) {
+		span.End(err)
+		t.RecordDuration(CommandDuration, time.Since(span.data.start), map[string]string{"command": command, "outcome": Outcome(err)})
+		if command == "git push" && err != nil && (strings.Contains(string(output), "non-fast-forward") || strings.Contains(string(output), "rejected")) {
+			t.Add(PushRetries, 1, nil)
+		}
+	}
+}
+
+// commandName names a command by its subcommand, e.g. "git push" or
+// "gh pr create", leaving out arguments such as pull request bodies
+func commandName(name string, args []string) string {
+	words := []string{name}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") || len(words) == 3 || (name != "gh" && len(words) == 2) {
+			break
+		}
+		words = append(words, arg)
+	}
+	return strings.Join(words, " ")
+}
+
+// Start exports every Interval until Shutdown, for long-running servers
+func (t *Telemetry) Start() {
+	t.stop = make(chan struct{})
+	t.done = make(chan struct{})
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(t.settings.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.stop:
+				return
+			case <-ticker.C:
+				t.export()
+			}
+		}
+	}()
+}
+
+// Shutdown stops exporting periodically and exports what's left. It
+// returns the last export error, if any.
+func (t *Telemetry) Shutdown() error {
+	if t.stop != nil {
+		close(t.stop)
+		<-t.done
+	}
+	t.export()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastErr
+}
+
+// export sends the finished spans and the metrics so far. Metrics are
+// cumulative, so a failed export loses only spans.
+func (t *Telemetry) export() {
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	traces := t.tracesPayload(spans)
+	metrics := t.metricsPayload(time.Now())
+	t.mu.Unlock()
+
+	var err error
+	if t.settings.TracesEndpoint != "" && len(spans) > 0 {
+		err = t.post(t.settings.TracesEndpoint, traces)
+	}
+	if t.settings.MetricsEndpoint != "" {
+		if metricsErr := t.post(t.settings.MetricsEndpoint, metrics); err == nil {
+			err = metricsErr
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err != nil {
+		t.lastErr = err
+	}
+}
+
+// randomID returns n random bytes as hex, as OTLP/JSON encodes IDs
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
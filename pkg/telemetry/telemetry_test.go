@@ -0,0 +1,201 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSettingsFromEnv(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	if _, ok, err := SettingsFromEnv(env(nil)); ok || err != nil {
+		t.Errorf("no endpoint: ok = %v, err = %v, want telemetry off", ok, err)
+	}
+
+	settings, ok, err := SettingsFromEnv(env(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT":         "http://collector:4318/",
+		"OTEL_EXPORTER_OTLP_METRICS_ENDPOINT": "http://metrics:4318/custom",
+		"OTEL_EXPORTER_OTLP_HEADERS":          "Authorization=Bearer abc, X-Team = platform",
+		"OTEL_METRIC_EXPORT_INTERVAL":         "15000",
+	}))
+	if !ok || err != nil {
+		t.Fatalf("SettingsFromEnv() ok = %v, err = %v", ok, err)
+	}
+	if settings.TracesEndpoint != "http://collector:4318/v1/traces" || settings.MetricsEndpoint != "http://metrics:4318/custom" {
+		t.Errorf("endpoints = %q, %q", settings.TracesEndpoint, settings.MetricsEndpoint)
+	}
+	if settings.Headers["Authorization"] != "Bearer abc" || settings.Headers["X-Team"] != "platform" {
+		t.Errorf("headers = %v", settings.Headers)
+	}
+	if settings.ServiceName != "standup-bot" || settings.Interval.Seconds() != 15 {
+		t.Errorf("service = %q, interval = %v", settings.ServiceName, settings.Interval)
+	}
+
+	if _, ok, _ := SettingsFromEnv(env(map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_SDK_DISABLED": "true"})); ok {
+		t.Error("OTEL_SDK_DISABLED should turn telemetry off")
+	}
+	settings, ok, err = SettingsFromEnv(env(map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_EXPORTER_OTLP_PROTOCOL": "http/protobuf"}))
+	if !ok || err != nil || !strings.Contains(settings.Warning, "http/protobuf") {
+		t.Errorf("http/protobuf: ok = %v, err = %v, warning = %q, want a fallback to http/json", ok, err, settings.Warning)
+	}
+}
+
+// collector records the OTLP payloads posted to it
+type collector struct {
+	mu       sync.Mutex
+	payloads map[string][]string
+	headers  http.Header
+}
+
+func newCollector(t *testing.T) (*collector, *httptest.Server) {
+	c := &collector{payloads: make(map[string][]string)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.payloads[r.URL.Path] = append(c.payloads[r.URL.Path], string(body))
+		c.headers = r.Header
+	}))
+	t.Cleanup(server.Close)
+	return c, server
+}
+
+func TestExport(t *testing.T) {
+	c, server := newCollector(t)
+	tel := New(Settings{
+		TracesEndpoint:  server.URL + "/v1/traces",
+		MetricsEndpoint: server.URL + "/v1/metrics",
+		Headers:         map[string]string{"Authorization": "Bearer abc"},
+		ServiceName:     "standup-bot",
+		ServiceVersion:  "1.2.3",
+	})
+
+	ctx, endRun := tel.StartRun(context.Background(), "standup-bot")
+	stepCtx, endStep := tel.StartStep(ctx, "push")
+	tel.StartCommand(stepCtx, "/repo", "git", []string{"push", "-u", "origin", "main"})([]byte("! [rejected] main -> main (non-fast-forward)"), errors.New("exit status 1"))
+	tel.StartCommand(stepCtx, "/repo", "git", []string{"push", "-u", "origin", "main"})(nil, nil)
+	endStep(nil)
+	tel.StartCommand(ctx, "/repo", "gh", []string{"pr", "create", "--body", "private notes"})(nil, nil)
+	endRun(errors.New("failed to create pull request"))
+
+	if err := tel.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if c.headers.Get("Authorization") != "Bearer abc" || c.headers.Get("Content-Type") != "application/json" {
+		t.Errorf("headers = %v", c.headers)
+	}
+
+	var traces otlpTraces
+	if err := json.Unmarshal([]byte(c.payloads["/v1/traces"][0]), &traces); err != nil {
+		t.Fatalf("invalid traces payload: %v", err)
+	}
+	spans := map[string]otlpSpan{}
+	for _, span := range traces.ResourceSpans[0].ScopeSpans[0].Spans {
+		spans[span.Name] = span
+	}
+	run, step, pr := spans["standup-bot"], spans["step push"], spans["gh pr create"]
+	if run.ParentSpanID != "" || step.ParentSpanID != run.SpanID || spans["git push"].ParentSpanID != step.SpanID {
+		t.Errorf("spans aren't nested run > step > command: %+v", spans)
+	}
+	// Commands after the step ends belong to the run
+	if pr.ParentSpanID != run.SpanID {
+		t.Errorf("gh pr create parent = %q, want the run %q", pr.ParentSpanID, run.SpanID)
+	}
+	if run.Status.Code != otlpStatusError || run.Status.Message != "failed to create pull request" {
+		t.Errorf("run status = %+v, want the error", run.Status)
+	}
+	if strings.Contains(c.payloads["/v1/traces"][0], "private notes") {
+		t.Error("spans shouldn't include command arguments")
+	}
+
+	metrics := c.payloads["/v1/metrics"][0]
+	for _, want := range []string{
+		`"name":"standup_bot.push.retries"`,
+		`"name":"standup_bot.runs"`,
+		`{"key":"outcome","value":{"stringValue":"failure"}}`,
+		`"name":"standup_bot.step.duration"`,
+		`"name":"standup_bot.command.duration"`,
+		`{"key":"service.version","value":{"stringValue":"1.2.3"}}`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("metrics missing %s:\n%s", want, metrics)
+		}
+	}
+	var payload otlpMetrics
+	if err := json.Unmarshal([]byte(metrics), &payload); err != nil {
+		t.Fatalf("invalid metrics payload: %v", err)
+	}
+	for _, m := range payload.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		if m.Name == PushRetries && m.Sum.DataPoints[0].AsInt != "1" {
+			t.Errorf("push retries = %s, want 1", m.Sum.DataPoints[0].AsInt)
+		}
+	}
+}
+
+func TestRequestsAreSeparateTraces(t *testing.T) {
+	c, server := newCollector(t)
+	tel := New(Settings{TracesEndpoint: server.URL + "/v1/traces", ServiceName: "standup-bot"})
+
+	// Concurrent requests of a server each get a trace of their own
+	var wg sync.WaitGroup
+	for _, operation := range []string{"mcp submit_standup", "webhook_issue"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, end := tel.StartRequest(context.Background(), operation)
+			stepCtx, endStep := tel.StartStep(ctx, "push")
+			tel.StartCommand(stepCtx, "/repo", "git", []string{"push"})(nil, nil)
+			endStep(nil)
+			end(nil)
+		}()
+	}
+	wg.Wait()
+	if err := tel.Shutdown(); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	var traces otlpTraces
+	if err := json.Unmarshal([]byte(c.payloads["/v1/traces"][0]), &traces); err != nil {
+		t.Fatalf("invalid traces payload: %v", err)
+	}
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	byID := map[string]otlpSpan{}
+	for _, span := range spans {
+		byID[span.SpanID] = span
+	}
+	roots := map[string]string{}
+	for _, span := range spans {
+		if span.ParentSpanID == "" {
+			roots[span.TraceID] = span.Name
+			continue
+		}
+		if parent := byID[span.ParentSpanID]; parent.TraceID != span.TraceID {
+			t.Errorf("%s is in trace %s, its parent %s in %s", span.Name, span.TraceID, parent.Name, parent.TraceID)
+		}
+	}
+	if len(spans) != 6 || len(roots) != 2 {
+		t.Errorf("got %d spans in %d traces, want 6 in 2: %+v", len(spans), len(roots), spans)
+	}
+}
+
+func TestExportFailureIsReported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad token", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	tel := New(Settings{MetricsEndpoint: server.URL + "/v1/metrics", ServiceName: "standup-bot"})
+	tel.Add(Runs, 1, nil)
+	if err := tel.Shutdown(); err == nil || !strings.Contains(err.Error(), "bad token") {
+		t.Errorf("Shutdown() error = %v, want the collector's response", err)
+	}
+}
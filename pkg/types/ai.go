@@ -0,0 +1,39 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AIProviderKind identifies the LLM service used by AI features
+type AIProviderKind string
+
+const (
+	// AINone disables AI features; nothing is sent over the network
+	AINone AIProviderKind = "none"
+	// AIOpenAI uses an OpenAI-compatible chat completions endpoint
+	AIOpenAI AIProviderKind = "openai"
+	// AIAnthropic uses the Anthropic Messages API
+	AIAnthropic AIProviderKind = "anthropic"
+	// AIOllama uses a local Ollama server
+	AIOllama AIProviderKind = "ollama"
+)
+
+// NewAIProviderKind creates a new validated AI provider kind. An empty value
+// means AINone.
+func NewAIProviderKind(kind string) (AIProviderKind, error) {
+	k := AIProviderKind(strings.ToLower(strings.TrimSpace(kind)))
+	if k == "" {
+		return AINone, nil
+	}
+	switch k {
+	case AINone, AIOpenAI, AIAnthropic, AIOllama:
+		return k, nil
+	}
+	return "", fmt.Errorf("invalid AI provider: %s (must be none, openai, anthropic, or ollama)", kind)
+}
+
+// String returns the provider kind as a string
+func (k AIProviderKind) String() string {
+	return string(k)
+}
@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+// StandupDay returns the moment a standup submitted at t is dated to. Before
+// cutoffHour, t still counts as the previous day, so a submission at 1am with
+// a cutoff of 4 lands on the day that just ended.
+func StandupDay(t time.Time, cutoffHour int) time.Time {
+	if t.Hour() < cutoffHour {
+		return t.AddDate(0, 0, -1)
+	}
+	return t
+}
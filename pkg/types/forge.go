@@ -0,0 +1,70 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ForgeKind identifies the service hosting the standup repository
+type ForgeKind string
+
+const (
+	// ForgeAuto detects the forge from the repository URL
+	ForgeAuto ForgeKind = ""
+	// ForgeGitHub uses GitHub pull requests via the gh CLI
+	ForgeGitHub ForgeKind = "github"
+	// ForgeGitLab uses GitLab merge requests via the glab CLI
+	ForgeGitLab ForgeKind = "gitlab"
+	// ForgeBitbucket uses Bitbucket Cloud pull requests via its REST API
+	ForgeBitbucket ForgeKind = "bitbucket"
+)
+
+// NewForgeKind creates a new validated forge kind. An empty value selects
+// detection from the repository URL.
+func NewForgeKind(kind string) (ForgeKind, error) {
+	switch k := ForgeKind(strings.ToLower(strings.TrimSpace(kind))); k {
+	case ForgeAuto, ForgeGitHub, ForgeGitLab, ForgeBitbucket:
+		return k, nil
+	}
+	return "", fmt.Errorf("invalid forge: %s (must be github, gitlab, or bitbucket)", kind)
+}
+
+// String returns the forge kind as a string
+func (k ForgeKind) String() string {
+	return string(k)
+}
+
+// DetectForgeKind guesses the forge from the host of a git remote URL,
+// defaulting to GitHub
+func DetectForgeKind(url string) ForgeKind {
+	host := strings.ToLower(urlHost(url))
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return ForgeGitLab
+	case strings.Contains(host, "bitbucket"):
+		return ForgeBitbucket
+	}
+	return ForgeGitHub
+}
+
+// urlHost returns the host of an https, ssh:// or scp-like git URL, or ""
+// for plain "owner/name" values
+func urlHost(url string) string {
+	url = strings.TrimSpace(url)
+	if idx := strings.Index(url, "://"); idx >= 0 {
+		host := url[idx+3:]
+		if slash := strings.Index(host, "/"); slash >= 0 {
+			host = host[:slash]
+		}
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		return host
+	}
+	if at := strings.Index(url, "@"); at >= 0 {
+		if colon := strings.Index(url[at:], ":"); colon >= 0 {
+			return url[at+1 : at+colon]
+		}
+	}
+	return ""
+}
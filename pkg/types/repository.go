@@ -42,4 +42,41 @@ func (r Repository) Validate() error {
 		return fmt.Errorf("repository name cannot be empty")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// ParseRepositoryURL extracts the repository from a git remote URL such as
+// https://github.com/owner/name.git or git@github.com:owner/name.git
+func ParseRepositoryURL(url string) (Repository, error) {
+	path := strings.TrimSuffix(strings.TrimSpace(url), "/")
+	path = strings.TrimSuffix(path, ".git")
+
+	if idx := strings.Index(path, "://"); idx >= 0 {
+		// https://host/owner/name or ssh://git@host/owner/name
+		path = path[idx+3:]
+		if slash := strings.Index(path, "/"); slash >= 0 {
+			path = path[slash+1:]
+		}
+	} else if colon := strings.Index(path, ":"); colon >= 0 {
+		// scp-like syntax: git@host:owner/name
+		path = path[colon+1:]
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return Repository{}, fmt.Errorf("cannot determine repository from URL '%s'", url)
+	}
+
+	return NewRepository(strings.Join(parts[len(parts)-2:], "/"))
+}
+
+// IsRemoteURL reports whether s is a git URL (https://, ssh:// or scp-like
+// git@host:owner/name) rather than an "owner/name" pair
+func IsRemoteURL(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "://") {
+		return true
+	}
+	at := strings.Index(s, "@")
+	colon := strings.Index(s, ":")
+	return at >= 0 && colon > at
+}
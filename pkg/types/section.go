@@ -0,0 +1,67 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StandardSections are the sections every standup entry has
+var StandardSections = []string{"Yesterday", "Today", "Blockers"}
+
+// SectionSpec describes a standup question defined by a team template. Extra
+// sections are collected after the standard ones; listing a standard section
+// customizes its prompt and position instead.
+type SectionSpec struct {
+	Name string `json:"name"`
+	// Prompt overrides the question asked interactively
+	Prompt string `json:"prompt,omitempty"`
+	// Single collects one line of text instead of a list of items. Ignored
+	// for standard sections, whose shape is fixed.
+	Single bool `json:"single,omitempty"`
+	// Required rejects empty answers
+	Required bool `json:"required,omitempty"`
+}
+
+// Validate checks if the section spec is valid
+func (s SectionSpec) Validate() error {
+	name := strings.TrimSpace(s.Name)
+	if name == "" {
+		return fmt.Errorf("section name cannot be empty")
+	}
+
+	if strings.ContainsAny(name, ":*\n") {
+		return fmt.Errorf("invalid section name: %s (cannot contain ':', '*' or newlines)", s.Name)
+	}
+
+	if strings.Contains(s.Prompt, "\n") {
+		return fmt.Errorf("prompt for section %s cannot contain newlines", s.Name)
+	}
+
+	return nil
+}
+
+// IsStandardSection checks if a section name is one of the standard sections
+func IsStandardSection(name string) bool {
+	for _, standard := range StandardSections {
+		if strings.EqualFold(strings.TrimSpace(name), standard) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTemplate checks that every section is valid and names are unique
+func ValidateTemplate(sections []SectionSpec) error {
+	seen := make(map[string]bool)
+	for _, s := range sections {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		key := strings.ToLower(strings.TrimSpace(s.Name))
+		if seen[key] {
+			return fmt.Errorf("duplicate section name: %s", s.Name)
+		}
+		seen[key] = true
+	}
+	return nil
+}
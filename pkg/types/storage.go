@@ -0,0 +1,59 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StorageFormat represents how standup entries are stored in the repository
+type StorageFormat string
+
+const (
+	// StorageMarkdown keeps one markdown file per user (the default)
+	StorageMarkdown StorageFormat = "markdown"
+	// StorageMonthly keeps one markdown file per user and month
+	StorageMonthly StorageFormat = "monthly"
+	// StorageYAML keeps one YAML document per user and day
+	StorageYAML StorageFormat = "yaml"
+	// StorageJSON keeps one JSON document per user and day
+	StorageJSON StorageFormat = "json"
+)
+
+// NewStorageFormat creates a new validated storage format. An empty value
+// selects markdown.
+func NewStorageFormat(format string) (StorageFormat, error) {
+	switch f := StorageFormat(strings.ToLower(strings.TrimSpace(format))); f {
+	case "", StorageMarkdown:
+		return StorageMarkdown, nil
+	case StorageMonthly, StorageYAML, StorageJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid storage format: %s (must be markdown, monthly, yaml, or json)", format)
+}
+
+// String returns the storage format as a string
+func (f StorageFormat) String() string {
+	return string(f)
+}
+
+// Structured reports whether entries are stored as one document per day
+func (f StorageFormat) Structured() bool {
+	return f == StorageYAML || f == StorageJSON
+}
+
+// Sharded reports whether each user's markdown is split into one file per
+// month
+func (f StorageFormat) Sharded() bool {
+	return f == StorageMonthly
+}
+
+// Extension returns the file extension used by the format
+func (f StorageFormat) Extension() string {
+	switch f {
+	case StorageYAML:
+		return ".yaml"
+	case StorageJSON:
+		return ".json"
+	}
+	return ".md"
+}
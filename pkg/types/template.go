@@ -0,0 +1,30 @@
+package types
+
+import (
+	"strings"
+	"text/template"
+)
+
+// TemplateFuncs are the functions available to the commit message and
+// daily PR templates besides text/template's own
+var TemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+}
+
+// ParseCommitTemplate parses a Go template for standup commit messages
+func ParseCommitTemplate(text string) (*template.Template, error) {
+	return parseTemplate("commit", text)
+}
+
+// ParsePRTemplate parses a Go template for the title or body of the daily PR
+func ParsePRTemplate(name, text string) (*template.Template, error) {
+	return parseTemplate(name, text)
+}
+
+// parseTemplate parses a template that fails on fields its data lacks
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs).Option("missingkey=error").Parse(text)
+}
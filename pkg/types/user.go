@@ -28,6 +28,11 @@ func NewUserName(name string) (UserName, error) {
 		return "", fmt.Errorf("user name contains invalid characters (only letters, numbers, spaces, hyphens, apostrophes, and periods allowed)")
 	}
 
+	// Names become file and directory names, so "." and ".." are unsafe
+	if strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("user name cannot start with a period")
+	}
+
 	return UserName(name), nil
 }
 
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// plainWords stand in for symbols that carry meaning, such as a check's
+// result, so plain output keeps it
+var plainWords = map[rune]string{
+	'✅': "[ok]",
+	'❌': "[fail]",
+	'⚠': "warning:",
+	'⏳': "[pending]",
+	'➖': "[skip]",
+	'→': "->",
+}
+
+// isEmoji reports whether r is an emoji, a pictograph or one of the
+// invisible characters that join or style them
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF,
+		r >= 0x2600 && r <= 0x27BF,
+		r >= 0x2300 && r <= 0x23FF,
+		r >= 0x2B00 && r <= 0x2BFF:
+		return true
+	}
+	return r == 0xFE0F || r == 0x200D
+}
+
+// boxDrawing returns the ASCII stand-in for a box-drawing character
+func boxDrawing(r rune) (byte, bool) {
+	if r < 0x2500 || r > 0x257F {
+		return 0, false
+	}
+	switch r {
+	case '─', '━', '═', '┄', '┅', '┈', '┉', '╌', '╍':
+		return '-', true
+	case '│', '┃', '║', '┆', '┇', '┊', '┋', '╎', '╏':
+		return '|', true
+	}
+	return '+', true
+}
+
+// escapeLen returns the length of the ANSI escape sequence at the start of
+// s, and whether it's complete
+func escapeLen(s string) (int, bool) {
+	if len(s) < 2 {
+		return len(s), false
+	}
+	if s[1] != '[' {
+		return 2, true
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7E {
+			return i + 1, true
+		}
+	}
+	return len(s), false
+}
+
+// Plain makes output readable in CI logs and by screen readers: meaningful
+// symbols become words (✅ is "[ok]"), other emoji are dropped, box-drawing
+// becomes ASCII and ANSI colors and cursor movement are removed
+func Plain(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			n, _ := escapeLen(s[i:])
+			i += n
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if word, ok := plainWords[r]; ok {
+			i = skipJoiners(s, i)
+			b.WriteString(word)
+			if n := leadingSpaces(s[i:]); n > 0 {
+				b.WriteByte(' ')
+				i += n
+			}
+			continue
+		}
+		if c, ok := boxDrawing(r); ok {
+			b.WriteByte(c)
+			continue
+		}
+		if isEmoji(r) {
+			i = skipJoiners(s, i)
+			i += leadingSpaces(s[i:])
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// skipJoiners skips the variation selectors and zero-width joiners at s[i:]
+func skipJoiners(s string, i int) int {
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r != 0xFE0F && r != 0x200D {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// leadingSpaces returns the number of spaces s starts with
+func leadingSpaces(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " "))
+}
+
+// PlainWriter passes what's written through Plain. A character or escape
+// sequence split across writes is held back until it's complete.
+type PlainWriter struct {
+	out     io.Writer
+	pending []byte
+}
+
+// NewPlainWriter creates a writer that writes plain output to out
+func NewPlainWriter(out io.Writer) *PlainWriter {
+	return &PlainWriter{out: out}
+}
+
+// Write writes the plain form of p
+func (w *PlainWriter) Write(p []byte) (int, error) {
+	data := append(w.pending, p...)
+	cut := completeLen(data)
+	w.pending = append([]byte(nil), data[cut:]...)
+	if cut == 0 {
+		return len(p), nil
+	}
+	if _, err := io.WriteString(w.out, Plain(string(data[:cut]))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes anything held back
+func (w *PlainWriter) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(w.out, Plain(string(w.pending)))
+	w.pending = nil
+	return err
+}
+
+// completeLen returns how much of data can be written without splitting a
+// character or an escape sequence
+func completeLen(data []byte) int {
+	// Escape sequences are short; only the last few bytes can be unfinished
+	for i := len(data) - 1; i >= 0 && i >= len(data)-16; i-- {
+		if data[i] == '\033' {
+			if _, ok := escapeLen(string(data[i:])); !ok {
+				return i
+			}
+			break
+		}
+	}
+	for back := 1; back <= utf8.UTFMax && back <= len(data); back++ {
+		i := len(data) - back
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(data)
+}
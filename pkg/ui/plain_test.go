@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPlain(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"check", "✅ Standup submitted!\n", "[ok] Standup submitted!\n"},
+		{"warning with selector", "⚠️  Could not sync\n", "warning: Could not sync\n"},
+		{"decorative emoji", "🧪 Setting up a sandbox\n", "Setting up a sandbox\n"},
+		{"emoji mid-line", "Alice: 🌴 on leave\n", "Alice: on leave\n"},
+		{"joined emoji", "👩‍💻 Coding\n", "Coding\n"},
+		{"arrow", "   → run gh auth login\n", "   -> run gh auth login\n"},
+		{"box drawing", "┌──┐\n│ok│\n└──┘\n", "+--+\n|ok|\n+--+\n"},
+		{"colors", "\033[32mgreen\033[0m and \033[1mbold\033[0m", "green and bold"},
+		{"spinner line", "\r\033[K⠋ 3s", "\r⠋ 3s"},
+		{"plain text", "Nothing to see here", "Nothing to see here"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Plain(tt.in); got != tt.want {
+				t.Errorf("Plain(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainWriterSplitWrites(t *testing.T) {
+	var out bytes.Buffer
+	w := NewPlainWriter(&out)
+
+	// A check mark and a color code, each split across writes
+	check := []byte("✅ Done \033[32mok\033[0m\n")
+	for _, part := range [][]byte{check[:1], check[1:9], check[9:11], check[11:]} {
+		if _, err := w.Write(part); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Write([]byte("tail \033["))
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := out.String(), "[ok] Done ok\ntail "; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
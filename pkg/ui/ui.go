@@ -0,0 +1,143 @@
+// Package ui shows the progress of slow operations, such as syncing the
+// repository or opening the daily pull request, in a terminal.
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// EventKind says what happened to an operation
+type EventKind int
+
+// Kinds of progress events
+const (
+	Started EventKind = iota
+	Finished
+	Failed
+)
+
+// Event reports that a slow operation started or ended
+type Event struct {
+	Kind EventKind
+	// Step names the operation, e.g. a pipeline step such as "sync"
+	Step string
+	// Label describes it for people, e.g. "Syncing repository"
+	Label string
+}
+
+// IsTerminal reports whether f is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// spinnerFrames are drawn in turn while an operation runs
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// clearLine returns the cursor to the start of the line and erases it
+const clearLine = "\r\033[K"
+
+// Spinner animates a line, with the time elapsed, while an operation runs.
+// Progress messages written through it are printed above the spinner, so
+// the spinner sits under the message describing the operation.
+type Spinner struct {
+	mu       sync.Mutex
+	out      io.Writer
+	interval time.Duration
+	started  time.Time
+	frame    int
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewSpinner creates a spinner that draws on out, which should be a terminal
+func NewSpinner(out io.Writer) *Spinner {
+	return &Spinner{
+		out:      out,
+		interval: 100 * time.Millisecond,
+	}
+}
+
+// Handle starts the spinner when an operation starts and stops it when the
+// operation ends
+func (s *Spinner) Handle(event Event) {
+	if event.Kind == Started {
+		s.Start()
+		return
+	}
+	s.Stop()
+}
+
+// Start shows the spinner, restarting it if it's running
+func (s *Spinner) Start() {
+	s.Stop()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = time.Now()
+	s.frame = 0
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	s.draw()
+	go s.spin(s.stop, s.done)
+}
+
+// Stop removes the spinner
+func (s *Spinner) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprint(s.out, clearLine)
+}
+
+// Write prints p above the spinner
+func (s *Spinner) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return s.out.Write(p)
+	}
+
+	fmt.Fprint(s.out, clearLine)
+	n, err := s.out.Write(p)
+	s.draw()
+	return n, err
+}
+
+// spin redraws the spinner until stopped
+func (s *Spinner) spin(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.frame++
+			s.draw()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// draw writes the current frame; s.mu must be held
+func (s *Spinner) draw() {
+	elapsed := time.Since(s.started).Truncate(time.Second)
+	fmt.Fprintf(s.out, "%s%s %s", clearLine, spinnerFrames[s.frame%len(spinnerFrames)], elapsed)
+}
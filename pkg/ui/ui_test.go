@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe to write from the spinner's goroutine
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSpinner(t *testing.T) {
+	var out syncBuffer
+	spinner := NewSpinner(&out)
+	spinner.interval = time.Millisecond
+
+	spinner.Write([]byte("Before\n"))
+	spinner.Handle(Event{Kind: Started, Step: "sync", Label: "Syncing repository"})
+	time.Sleep(20 * time.Millisecond)
+	spinner.Write([]byte("Syncing repository...\n"))
+	spinner.Handle(Event{Kind: Finished, Step: "sync", Label: "Syncing repository"})
+	spinner.Write([]byte("After\n"))
+
+	got := out.String()
+	if !strings.HasPrefix(got, "Before\n"+clearLine+"⠋ 0s") {
+		t.Errorf("spinner didn't start after the message:\n%q", got)
+	}
+	if !strings.Contains(got, "⠙") {
+		t.Errorf("spinner didn't animate:\n%q", got)
+	}
+	// Messages clear the spinner's line, and the spinner is gone once stopped
+	if !strings.Contains(got, clearLine+"Syncing repository...\n") || !strings.HasSuffix(got, clearLine+"After\n") {
+		t.Errorf("unexpected output:\n%q", got)
+	}
+
+	// Stopping a stopped spinner is harmless
+	spinner.Stop()
+}
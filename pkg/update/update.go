@@ -0,0 +1,156 @@
+// Package update checks GitHub Releases for newer standup-bot versions.
+package update
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ReleasesURL is the GitHub API endpoint for the latest release
+	ReleasesURL = "https://api.github.com/repos/livelabs-ventures/go-bot/releases/latest"
+
+	// cacheFile is the name of the cached check result in the cache directory
+	cacheFile = "update-check.json"
+
+	// cacheTTL is how long a check result is reused
+	cacheTTL = 24 * time.Hour
+)
+
+// Release describes a published release
+type Release struct {
+	Version string `json:"tag_name"`
+	URL     string `json:"html_url"`
+}
+
+// cachedRelease is the on-disk cache of the last check
+type cachedRelease struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	Release   Release   `json:"release"`
+}
+
+// Checker fetches the latest release, caching the result on disk
+type Checker struct {
+	url        string
+	cacheDir   string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewChecker creates a checker that caches results in cacheDir
+func NewChecker(cacheDir string) *Checker {
+	return &Checker{
+		url:        ReleasesURL,
+		cacheDir:   cacheDir,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		now:        time.Now,
+	}
+}
+
+// Latest returns the latest release, from the cache if it was checked
+// within the last day
+func (c *Checker) Latest() (Release, error) {
+	cachePath := filepath.Join(c.cacheDir, cacheFile)
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var cached cachedRelease
+		if json.Unmarshal(data, &cached) == nil && c.now().Sub(cached.CheckedAt) < cacheTTL {
+			return cached.Release, nil
+		}
+	}
+
+	release, err := c.fetch()
+	if err != nil {
+		return Release{}, err
+	}
+
+	// Caching is best effort; a failed write only means checking again next time
+	if data, err := json.Marshal(cachedRelease{CheckedAt: c.now(), Release: release}); err == nil {
+		if os.MkdirAll(c.cacheDir, 0755) == nil {
+			os.WriteFile(cachePath, data, 0644)
+		}
+	}
+
+	return release, nil
+}
+
+// fetch queries the GitHub API for the latest release
+func (c *Checker) fetch() (Release, error) {
+	req, err := http.NewRequest(http.MethodGet, c.url, nil)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to read release info: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("failed to check for updates: GitHub returned %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return Release{}, fmt.Errorf("failed to parse release info: %w", err)
+	}
+	if release.Version == "" {
+		return Release{}, fmt.Errorf("failed to parse release info: missing version")
+	}
+	return release, nil
+}
+
+// IsNewer reports whether latest is a newer semantic version than current.
+// Development builds and unparseable versions are never considered outdated.
+func IsNewer(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses "v1.2.3" or "1.2.3", ignoring pre-release and build suffixes
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if idx := strings.IndexAny(version, "-+ "); idx >= 0 {
+		version = version[:idx]
+	}
+
+	fields := strings.Split(version, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
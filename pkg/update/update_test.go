@@ -0,0 +1,79 @@
+package update
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest  string
+		current string
+		want    bool
+	}{
+		{"v1.3.0", "1.2.9", true},
+		{"v1.2.10", "v1.2.9", true},
+		{"v2.0.0", "1.9.9-rc1", true},
+		{"v1.2.0", "1.2.0", false},
+		{"v1.1.0", "1.2.0", false},
+		{"v1.3.0", "dev", false},
+		{"nightly", "1.2.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsNewer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestLatestUsesCache(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"tag_name": "v1.4.0", "html_url": "https://example.com/v1.4.0"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	checker := NewChecker(t.TempDir())
+	checker.url = server.URL
+	checker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		release, err := checker.Latest()
+		if err != nil {
+			t.Fatalf("Latest() error = %v", err)
+		}
+		if release.Version != "v1.4.0" || release.URL != "https://example.com/v1.4.0" {
+			t.Errorf("Latest() = %+v", release)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("made %d requests, want 1 (second check should be cached)", requests)
+	}
+
+	// An expired cache is refreshed
+	now = now.Add(25 * time.Hour)
+	if _, err := checker.Latest(); err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2 after the cache expired", requests)
+	}
+}
+
+func TestLatestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	checker := NewChecker(t.TempDir())
+	checker.url = server.URL
+	if _, err := checker.Latest(); err == nil {
+		t.Error("Latest() should return an error for a failed request")
+	}
+}